@@ -5,23 +5,46 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Cfg struct {
-	LogLevel             string
-	ServerAddress        string
-	ServerPort           string
-	DefaultCommentsLimit int
-	MaxCommentsLimit     int
-	DefaultPostsLimit    int
-	MaxPostsLimit        int
-	DBConnectionString   string
-	InMemoryStorage      bool
-	RedisAddress         string
-	RedisPort            string
-	RedisPassword        string
-	MaxCommentTextLength int
-	DebugMode            bool
+	LogLevel                  string
+	ServerAddress             string
+	ServerPort                string
+	DefaultCommentsLimit      int
+	MaxCommentsLimit          int
+	DefaultPostsLimit         int
+	MaxPostsLimit             int
+	DefaultUsersLimit         int
+	MaxUsersLimit             int
+	DefaultNotificationsLimit int
+	MaxNotificationsLimit     int
+	DefaultTagsLimit          int
+	MaxTagsLimit              int
+	MaxPostTags               int
+	DBConnectionString        string
+	InMemoryStorage           bool
+	RedisAddress              string
+	RedisPort                 string
+	RedisPassword             string
+	MaxCommentTextLength      int
+	MaxPostTitleLength        int
+	MaxPostTextLength         int
+	MaxPinnedComments         int
+	MaxReplyDepth             int
+	ReparentDeepReplies       bool
+	DebugMode                 bool
+	CursorSecret              []byte
+	PublishInterval           time.Duration
+	IdempotencyKeyTTL         time.Duration
+	RenderCacheSize           int
+	ModerationBlocklist       string
+	ModerationReloadInterval  time.Duration
+	ModerationMaxLinks        int
+	ModerationMaxRepeated     int
+	Moderators                []string
 }
 
 // Configure reads values from env and command line args into a Cfg structure.
@@ -90,6 +113,76 @@ func Configure() (*Cfg, error) {
 		cfg.MaxPostsLimit = 100
 	}
 
+	if val := os.Getenv("DEFAULT_USERS_LIMIT"); val != "" {
+		limit, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DEFAULT_USERS_LIMIT: %w", err)
+		}
+		cfg.DefaultUsersLimit = limit
+	} else {
+		cfg.DefaultUsersLimit = 20
+	}
+
+	if val := os.Getenv("MAX_USERS_LIMIT"); val != "" {
+		limit, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAX_USERS_LIMIT: %w", err)
+		}
+		cfg.MaxUsersLimit = limit
+	} else {
+		cfg.MaxUsersLimit = 100
+	}
+
+	if val := os.Getenv("DEFAULT_NOTIFICATIONS_LIMIT"); val != "" {
+		limit, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DEFAULT_NOTIFICATIONS_LIMIT: %w", err)
+		}
+		cfg.DefaultNotificationsLimit = limit
+	} else {
+		cfg.DefaultNotificationsLimit = 20
+	}
+
+	if val := os.Getenv("MAX_NOTIFICATIONS_LIMIT"); val != "" {
+		limit, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAX_NOTIFICATIONS_LIMIT: %w", err)
+		}
+		cfg.MaxNotificationsLimit = limit
+	} else {
+		cfg.MaxNotificationsLimit = 100
+	}
+
+	if val := os.Getenv("DEFAULT_TAGS_LIMIT"); val != "" {
+		limit, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DEFAULT_TAGS_LIMIT: %w", err)
+		}
+		cfg.DefaultTagsLimit = limit
+	} else {
+		cfg.DefaultTagsLimit = 10
+	}
+
+	if val := os.Getenv("MAX_TAGS_LIMIT"); val != "" {
+		limit, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAX_TAGS_LIMIT: %w", err)
+		}
+		cfg.MaxTagsLimit = limit
+	} else {
+		cfg.MaxTagsLimit = 50
+	}
+
+	if val := os.Getenv("MAX_POST_TAGS"); val != "" {
+		maxTags, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAX_POST_TAGS: %w", err)
+		}
+		cfg.MaxPostTags = maxTags
+	} else {
+		cfg.MaxPostTags = 5
+	}
+
 	if dbConnStr := os.Getenv("DB_CONN_STRING"); dbConnStr != "" {
 		cfg.DBConnectionString = dbConnStr
 	} else {
@@ -114,6 +207,7 @@ func Configure() (*Cfg, error) {
 		cfg.RedisPassword = ""
 	}
 
+	//text lengths are measured in characters (grapheme clusters)
 	if val := os.Getenv("MAX_COMMENT_TEXT_LENGTH"); val != "" {
 		maxLength, err := strconv.Atoi(val)
 		if err != nil {
@@ -124,5 +218,150 @@ func Configure() (*Cfg, error) {
 		cfg.MaxCommentTextLength = 2000
 	}
 
+	if val := os.Getenv("MAX_POST_TITLE_LENGTH"); val != "" {
+		maxLength, err := strconv.Atoi(val)
+		if err != nil || maxLength < 1 {
+			return nil, fmt.Errorf("invalid MAX_POST_TITLE_LENGTH: %q", val)
+		}
+		cfg.MaxPostTitleLength = maxLength
+	} else {
+		cfg.MaxPostTitleLength = 255
+	}
+
+	if val := os.Getenv("MAX_POST_TEXT_LENGTH"); val != "" {
+		maxLength, err := strconv.Atoi(val)
+		if err != nil || maxLength < 1 {
+			return nil, fmt.Errorf("invalid MAX_POST_TEXT_LENGTH: %q", val)
+		}
+		cfg.MaxPostTextLength = maxLength
+	} else {
+		cfg.MaxPostTextLength = 40000
+	}
+
+	if val := os.Getenv("MAX_PINNED_COMMENTS"); val != "" {
+		maxPinned, err := strconv.Atoi(val)
+		if err != nil || maxPinned < 1 {
+			return nil, fmt.Errorf("invalid MAX_PINNED_COMMENTS: %q", val)
+		}
+		cfg.MaxPinnedComments = maxPinned
+	} else {
+		cfg.MaxPinnedComments = 3
+	}
+
+	//zero reply depth means unlimited nesting
+	if val := os.Getenv("MAX_REPLY_DEPTH"); val != "" {
+		maxDepth, err := strconv.Atoi(val)
+		if err != nil || maxDepth < 0 {
+			return nil, fmt.Errorf("invalid MAX_REPLY_DEPTH: %q", val)
+		}
+		cfg.MaxReplyDepth = maxDepth
+	} else {
+		cfg.MaxReplyDepth = 10
+	}
+
+	//too deep replies are attached to the deepest allowed ancestor or rejected
+	if val := os.Getenv("REPARENT_DEEP_REPLIES"); val != "" {
+		reparent, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REPARENT_DEEP_REPLIES: %w", err)
+		}
+		cfg.ReparentDeepReplies = reparent
+	} else {
+		cfg.ReparentDeepReplies = true
+	}
+
+	if val := os.Getenv("PUBLISH_INTERVAL"); val != "" {
+		interval, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PUBLISH_INTERVAL: %w", err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("invalid PUBLISH_INTERVAL: must be positive")
+		}
+		cfg.PublishInterval = interval
+	} else {
+		cfg.PublishInterval = 10 * time.Second
+	}
+
+	//results of create mutations are kept for replays with the same clientMutationId during this window
+	if val := os.Getenv("IDEMPOTENCY_KEY_TTL"); val != "" {
+		ttl, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IDEMPOTENCY_KEY_TTL: %w", err)
+		}
+		if ttl <= 0 {
+			return nil, fmt.Errorf("invalid IDEMPOTENCY_KEY_TTL: must be positive")
+		}
+		cfg.IdempotencyKeyTTL = ttl
+	} else {
+		cfg.IdempotencyKeyTTL = 24 * time.Hour
+	}
+
+	if val := os.Getenv("RENDER_CACHE_SIZE"); val != "" {
+		size, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RENDER_CACHE_SIZE: %w", err)
+		}
+		if size <= 0 {
+			return nil, fmt.Errorf("invalid RENDER_CACHE_SIZE: must be positive")
+		}
+		cfg.RenderCacheSize = size
+	} else {
+		cfg.RenderCacheSize = 1000
+	}
+
+	//empty disables the blocklist
+	cfg.ModerationBlocklist = os.Getenv("MODERATION_BLOCKLIST")
+
+	if val := os.Getenv("MODERATION_RELOAD_INTERVAL"); val != "" {
+		interval, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MODERATION_RELOAD_INTERVAL: %w", err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("invalid MODERATION_RELOAD_INTERVAL: must be positive")
+		}
+		cfg.ModerationReloadInterval = interval
+	} else {
+		cfg.ModerationReloadInterval = 5 * time.Second
+	}
+
+	// zero disables the rule
+	if val := os.Getenv("MODERATION_MAX_LINKS"); val != "" {
+		maxLinks, err := strconv.Atoi(val)
+		if err != nil || maxLinks < 0 {
+			return nil, fmt.Errorf("invalid MODERATION_MAX_LINKS: %q", val)
+		}
+		cfg.ModerationMaxLinks = maxLinks
+	} else {
+		cfg.ModerationMaxLinks = 3
+	}
+
+	// zero disables the rule
+	if val := os.Getenv("MODERATION_MAX_REPEATED_CHARS"); val != "" {
+		maxRepeated, err := strconv.Atoi(val)
+		if err != nil || maxRepeated < 0 {
+			return nil, fmt.Errorf("invalid MODERATION_MAX_REPEATED_CHARS: %q", val)
+		}
+		cfg.ModerationMaxRepeated = maxRepeated
+	} else {
+		cfg.ModerationMaxRepeated = 10
+	}
+
+	if val := os.Getenv("MODERATORS"); val != "" {
+		for _, login := range strings.Split(val, ",") {
+			if login = strings.TrimSpace(login); login != "" {
+				cfg.Moderators = append(cfg.Moderators, login)
+			}
+		}
+	}
+
+	// all replicas must sign cursors with the same secret, so there is no random default.
+	if secret := os.Getenv("CURSOR_SECRET"); secret != "" {
+		cfg.CursorSecret = []byte(secret)
+	} else {
+		return nil, fmt.Errorf("CURSOR_SECRET is not set")
+	}
+
 	return cfg, nil
 }
@@ -12,8 +12,15 @@ import (
 	"ozon_test_task/internal/app/graph"
 	"ozon_test_task/internal/app/graph/resolvers"
 	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/internal/app/scheduler"
 	"ozon_test_task/pkg/authUtils"
+	"ozon_test_task/pkg/cursor"
 	"ozon_test_task/pkg/database"
+	"ozon_test_task/pkg/markdown"
+	"ozon_test_task/pkg/moderation"
+	"ozon_test_task/pkg/pubsub"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
@@ -54,6 +61,15 @@ func main() {
 		Logger: sugar,
 	}
 
+	//notifications hub set, it is fed by the storage with notifications added by all replicas
+	resolver.NotificationHub = pubsub.NewHub[int, *models.Notification](16)
+	publishNotification := func(n *models.Notification) {
+		resolver.NotificationHub.Publish(n.UserID, n)
+	}
+	onNotificationsError := func(err error) {
+		sugar.Warnf("Failed to receive notifications: %v", err)
+	}
+
 	//db set
 	if conf.InMemoryStorage {
 		sugar.Infof("Using in-memory storage")
@@ -74,6 +90,31 @@ func main() {
 		resolver.PostRepo = redisStorage
 		resolver.UserRepo = redisStorage
 		resolver.CommentRepo = redisStorage
+		resolver.FollowRepo = redisStorage
+		resolver.BookmarkRepo = redisStorage
+		resolver.TagRepo = redisStorage
+		resolver.SearchRepo = redisStorage
+		resolver.ReactionRepo = redisStorage
+		resolver.NotificationRepo = redisStorage
+		resolver.ModerationRepo = redisStorage
+		resolver.ReportRepo = redisStorage
+		resolver.BlockRepo = redisStorage
+		resolver.IdempotencyRepo = redisStorage
+
+		err = redisStorage.Migrate(ctx)
+		if err != nil {
+			sugar.Fatalf("Failed to migrate Redis storage: %v", err)
+		}
+
+		err = redisStorage.BuildSearchIndex(ctx)
+		if err != nil {
+			sugar.Fatalf("Failed to build search index: %v", err)
+		}
+		//search index sync, every replica applies changes written by all replicas
+		go redisStorage.WatchSearchChanges(ctx, func(err error) {
+			sugar.Warnf("Failed to sync search index: %v", err)
+		})
+		go redisStorage.WatchNotifications(ctx, publishNotification, onNotificationsError)
 	} else {
 		sugar.Infof("Using database")
 
@@ -89,17 +130,67 @@ func main() {
 		resolver.PostRepo = postgresStorage
 		resolver.UserRepo = postgresStorage
 		resolver.CommentRepo = postgresStorage
+		resolver.FollowRepo = postgresStorage
+		resolver.BookmarkRepo = postgresStorage
+		resolver.TagRepo = postgresStorage
+		resolver.SearchRepo = postgresStorage
+		resolver.ReactionRepo = postgresStorage
+		resolver.NotificationRepo = postgresStorage
+		resolver.ModerationRepo = postgresStorage
+		resolver.ReportRepo = postgresStorage
+		resolver.BlockRepo = postgresStorage
+		resolver.IdempotencyRepo = postgresStorage
+
+		go postgresStorage.WatchNotifications(context.Background(), conf.DBConnectionString, publishNotification, onNotificationsError)
 	}
 
 	//jwt manager set
 	resolver.JWTManager = &authUtils.JWTHelper{}
 
+	//cursor codec set
+	resolver.CursorCodec = cursor.NewCodec(conf.CursorSecret)
+
+	//text renderer set
+	resolver.TextRenderer, err = markdown.NewRenderer(conf.RenderCacheSize)
+	if err != nil {
+		sugar.Fatalf("Failed to create text renderer: %v", err)
+	}
+
+	//moderation set
+	var rules []moderation.Rule
+	if conf.ModerationBlocklist != "" {
+		blocklist, err := moderation.NewBlocklist(conf.ModerationBlocklist)
+		if err != nil {
+			sugar.Fatalf("Failed to load moderation blocklist: %v", err)
+		}
+		go blocklist.Watch(context.Background(), conf.ModerationReloadInterval, func(err error) {
+			sugar.Warnf("Failed to reload moderation blocklist, keeping the previous one: %v", err)
+		})
+		rules = append(rules, blocklist)
+	}
+	if conf.ModerationMaxLinks > 0 {
+		rules = append(rules, moderation.LinkLimit{Max: conf.ModerationMaxLinks, Verdict: moderation.Hold})
+	}
+	if conf.ModerationMaxRepeated > 0 {
+		rules = append(rules, moderation.RepeatedChars{MaxRun: conf.ModerationMaxRepeated, Verdict: moderation.Hold})
+	}
+	resolver.Moderator = moderation.NewPipeline(rules...)
+
+	//scheduled posts publisher set, every replica runs it, repositories publish each post once
+	go scheduler.RunPostPublisher(context.Background(), resolver.PostRepo, conf.PublishInterval, sugar)
+
 	//middlewares set
 	authMW := middlewares.GetAuthMiddleware(&authUtils.JWTHelper{}, resolver.UserRepo, sugar)
 
 	//build GraphQL server
 	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
+	srv.SetErrorPresenter(graph.NewErrorPresenter(sugar))
+	srv.SetRecoverFunc(graph.NewRecoverFunc(sugar))
 
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc:              middlewares.GetWebsocketInitFunc(&authUtils.JWTHelper{}, resolver.UserRepo, sugar),
+	})
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
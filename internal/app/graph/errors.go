@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.uber.org/zap"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
+	"ozon_test_task/pkg/cursor"
+	"runtime/debug"
+)
+
+// NewErrorPresenter returns an error presenter setting extensions.code of resolver errors.
+// apperrors.Error keeps its code, repository and cursor errors are mapped to codes, other errors are Internal.
+// Causes of Internal errors are logged and replaced with a generic message.
+// Errors which already have a code (parsing and validation of a query by gqlgen) are returned as is.
+func NewErrorPresenter(logger *zap.SugaredLogger) graphql.ErrorPresenterFunc {
+	return func(ctx context.Context, err error) *gqlerror.Error {
+		gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+		if _, ok := gqlErr.Extensions["code"]; ok {
+			return gqlErr
+		}
+
+		appErr := toAppError(err)
+		if appErr.Code == apperrors.Internal {
+			logger.Errorf("internal error at %v: %v", gqlErr.Path, err)
+		}
+		extensions := make(map[string]any, len(appErr.Extensions)+1)
+		for k, v := range appErr.Extensions {
+			extensions[k] = v
+		}
+		extensions["code"] = appErr.Code
+		return &gqlerror.Error{
+			Err:        err,
+			Message:    appErr.Message,
+			Path:       gqlErr.Path,
+			Locations:  gqlErr.Locations,
+			Extensions: extensions,
+		}
+	}
+}
+
+// toAppError returns an apperrors.Error describing err.
+func toAppError(err error) *apperrors.Error {
+	var appErr *apperrors.Error
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.Is(err, repository.NewErrNotFound()):
+		return apperrors.New(apperrors.NotFound, "not found")
+	case errors.Is(err, repository.NewErrConflict()):
+		return apperrors.New(apperrors.Conflict, "conflict")
+	case errors.Is(err, cursor.NewErrInvalidCursor()):
+		return apperrors.New(apperrors.Validation, "cursor is not valid")
+	}
+	return apperrors.NewInternal(err)
+}
+
+// NewRecoverFunc returns a function turning panics of resolvers into Internal errors, panics are logged with a stack.
+func NewRecoverFunc(logger *zap.SugaredLogger) graphql.RecoverFunc {
+	return func(ctx context.Context, p any) error {
+		logger.Errorf("panic in resolver: %v\n%s", p, debug.Stack())
+		return apperrors.NewInternal(fmt.Errorf("panic: %v", p))
+	}
+}
@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
+	"ozon_test_task/pkg/cursor"
+	"reflect"
+	"testing"
+)
+
+func TestNewErrorPresenter(t *testing.T) {
+	path := ast.Path{ast.PathName("post")}
+	ctx := graphql.WithFieldContext(context.Background(), &graphql.FieldContext{Field: graphql.CollectedField{Field: &ast.Field{Alias: "post"}}})
+	tests := []struct {
+		name string
+		err  error
+		want *gqlerror.Error
+	}{
+		{
+			name: "App error keeps its code and extensions",
+			err: &apperrors.Error{
+				Code:       apperrors.Validation,
+				Message:    "invalid input",
+				Extensions: map[string]any{"fields": []string{"title"}},
+			},
+			want: &gqlerror.Error{
+				Message:    "invalid input",
+				Path:       path,
+				Extensions: map[string]any{"code": apperrors.Validation, "fields": []string{"title"}},
+			},
+		},
+		{
+			name: "Wrapped repository error",
+			err:  fmt.Errorf("failed to get post: %w", repository.NewErrNotFound()),
+			want: &gqlerror.Error{
+				Message:    "not found",
+				Path:       path,
+				Extensions: map[string]any{"code": apperrors.NotFound},
+			},
+		},
+		{
+			name: "Conflict",
+			err:  repository.NewErrConflict(),
+			want: &gqlerror.Error{
+				Message:    "conflict",
+				Path:       path,
+				Extensions: map[string]any{"code": apperrors.Conflict},
+			},
+		},
+		{
+			name: "Invalid cursor",
+			err:  cursor.NewErrInvalidCursor(),
+			want: &gqlerror.Error{
+				Message:    "cursor is not valid",
+				Path:       path,
+				Extensions: map[string]any{"code": apperrors.Validation},
+			},
+		},
+		{
+			name: "Internal error hides its cause",
+			err:  apperrors.NewInternal(fmt.Errorf("pq: connection refused")),
+			want: &gqlerror.Error{
+				Message:    "internal server error",
+				Path:       path,
+				Extensions: map[string]any{"code": apperrors.Internal},
+			},
+		},
+		{
+			name: "Unknown error is internal",
+			err:  fmt.Errorf("dial tcp: connection refused"),
+			want: &gqlerror.Error{
+				Message:    "internal server error",
+				Path:       path,
+				Extensions: map[string]any{"code": apperrors.Internal},
+			},
+		},
+		{
+			name: "Query validation error is kept",
+			err:  &gqlerror.Error{Message: "Cannot query field", Extensions: map[string]any{"code": "GRAPHQL_VALIDATION_FAILED"}},
+			want: &gqlerror.Error{Message: "Cannot query field", Path: path, Extensions: map[string]any{"code": "GRAPHQL_VALIDATION_FAILED"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			presenter := NewErrorPresenter(zaptest.NewLogger(t).Sugar())
+			got := presenter(ctx, graphql.ErrorOnPath(ctx, tt.err))
+			got.Err = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("presenter() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
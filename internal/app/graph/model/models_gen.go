@@ -2,34 +2,90 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type AddCommentResult interface {
+	IsAddCommentResult()
+}
+
+type AddPostResult interface {
+	IsAddPostResult()
+}
+
+type AddReplyResult interface {
+	IsAddReplyResult()
+}
+
+type SearchResult interface {
+	IsSearchResult()
+}
+
 type AddCommentResponse struct {
 	Comment *Comment `json:"comment"`
 	Error   string   `json:"error"`
 }
 
+type AddCommentSuccess struct {
+	Comment     *Comment     `json:"comment,omitempty"`
+	HeldComment *HeldComment `json:"heldComment,omitempty"`
+}
+
+func (AddCommentSuccess) IsAddCommentResult() {}
+
 type AddPostResponse struct {
 	Post  *Post  `json:"post"`
 	Error string `json:"error"`
 }
 
+type AddPostSuccess struct {
+	Post *Post `json:"post"`
+}
+
+func (AddPostSuccess) IsAddPostResult() {}
+
 type AddReplayResponse struct {
 	Comment *Comment `json:"comment"`
 	Error   string   `json:"error"`
 }
 
+type AddReplySuccess struct {
+	Comment     *Comment     `json:"comment,omitempty"`
+	HeldComment *HeldComment `json:"heldComment,omitempty"`
+}
+
+func (AddReplySuccess) IsAddReplyResult() {}
+
 type AuthResponse struct {
 	Token string `json:"token"`
 	Error string `json:"error"`
 }
 
 type Comment struct {
-	ID        string             `json:"id"`
-	Owner     *User              `json:"owner"`
-	Text      string             `json:"text"`
-	CreatedAt string             `json:"createdAt"`
-	Replies   *CommentConnection `json:"replies,omitempty"`
+	ID              string             `json:"id"`
+	Owner           *User              `json:"owner"`
+	Text            string             `json:"text"`
+	TextFormat      TextFormat         `json:"textFormat"`
+	HTML            string             `json:"html"`
+	CreatedAt       time.Time          `json:"createdAt"`
+	ParentCommentID *string            `json:"parentCommentID,omitempty"`
+	Depth           int32              `json:"depth"`
+	ReplyCount      int32              `json:"replyCount"`
+	DescendantCount int32              `json:"descendantCount"`
+	Reactions       []*ReactionCount   `json:"reactions"`
+	Score           int32              `json:"score"`
+	ViewerReaction  *ReactionKind      `json:"viewerReaction,omitempty"`
+	Mentions        []*User            `json:"mentions"`
+	Hidden          bool               `json:"hidden"`
+	Replies         *CommentConnection `json:"replies,omitempty"`
 }
 
+func (Comment) IsSearchResult() {}
+
 type CommentConnection struct {
 	Edges    []*CommentEdge `json:"edges"`
 	PageInfo *PageInfo      `json:"pageInfo"`
@@ -40,9 +96,69 @@ type CommentEdge struct {
 	Node   *Comment `json:"node"`
 }
 
+type CommentNotFound struct {
+	CommentID string `json:"commentID"`
+	Message   string `json:"message"`
+}
+
+func (CommentNotFound) IsAddReplyResult() {}
+
+type CommentsClosed struct {
+	Message string `json:"message"`
+}
+
+func (CommentsClosed) IsAddCommentResult() {}
+
+func (CommentsClosed) IsAddReplyResult() {}
+
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type HeldComment struct {
+	ID              string     `json:"id"`
+	Owner           *User      `json:"owner"`
+	PostID          *string    `json:"postID,omitempty"`
+	ParentCommentID *string    `json:"parentCommentID,omitempty"`
+	Text            string     `json:"text"`
+	TextFormat      TextFormat `json:"textFormat"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	Reasons         []string   `json:"reasons"`
+}
+
+type HeldCommentConnection struct {
+	Edges    []*HeldCommentEdge `json:"edges"`
+	PageInfo *PageInfo          `json:"pageInfo"`
+}
+
+type HeldCommentEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   *HeldComment `json:"node"`
+}
+
 type Mutation struct {
 }
 
+type Notification struct {
+	ID        string           `json:"id"`
+	Type      NotificationType `json:"type"`
+	Actor     *User            `json:"actor"`
+	Comment   *Comment         `json:"comment"`
+	CreatedAt time.Time        `json:"createdAt"`
+	Read      bool             `json:"read"`
+}
+
+type NotificationConnection struct {
+	Edges    []*NotificationEdge `json:"edges"`
+	PageInfo *PageInfo           `json:"pageInfo"`
+}
+
+type NotificationEdge struct {
+	Cursor string        `json:"cursor"`
+	Node   *Notification `json:"node"`
+}
+
 type PageInfo struct {
 	StartCursor *string `json:"startCursor,omitempty"`
 	EndCursor   *string `json:"endCursor,omitempty"`
@@ -50,14 +166,34 @@ type PageInfo struct {
 }
 
 type Post struct {
-	ID              string             `json:"id"`
-	Title           string             `json:"title"`
-	Text            string             `json:"text"`
-	Owner           *User              `json:"owner"`
-	CommentsAllowed bool               `json:"commentsAllowed"`
-	Comments        *CommentConnection `json:"comments"`
+	ID                  string             `json:"id"`
+	Title               string             `json:"title"`
+	Text                string             `json:"text"`
+	TextFormat          TextFormat         `json:"textFormat"`
+	HTML                string             `json:"html"`
+	Owner               *User              `json:"owner"`
+	CommentsAllowed     bool               `json:"commentsAllowed"`
+	CommentPolicy       CommentPolicy      `json:"commentPolicy"`
+	CommentPolicyDays   int32              `json:"commentPolicyDays"`
+	CreatedAt           time.Time          `json:"createdAt"`
+	UpdatedAt           time.Time          `json:"updatedAt"`
+	LastCommentAt       *time.Time         `json:"lastCommentAt,omitempty"`
+	CommentCount        int32              `json:"commentCount"`
+	Tags                []string           `json:"tags"`
+	Reactions           []*ReactionCount   `json:"reactions"`
+	Score               int32              `json:"score"`
+	ViewerReaction      *ReactionKind      `json:"viewerReaction,omitempty"`
+	ViewerHasBookmarked bool               `json:"viewerHasBookmarked"`
+	Status              PostStatus         `json:"status"`
+	PublishAt           *time.Time         `json:"publishAt,omitempty"`
+	ModerationReasons   []string           `json:"moderationReasons"`
+	Hidden              bool               `json:"hidden"`
+	PinnedComments      []*Comment         `json:"pinnedComments"`
+	Comments            *CommentConnection `json:"comments"`
 }
 
+func (Post) IsSearchResult() {}
+
 type PostConnection struct {
 	Edges    []*PostEdge `json:"edges"`
 	PageInfo *PageInfo   `json:"pageInfo"`
@@ -68,10 +204,590 @@ type PostEdge struct {
 	Node   *Post  `json:"node"`
 }
 
+type PostNotFound struct {
+	PostID  string `json:"postID"`
+	Message string `json:"message"`
+}
+
+func (PostNotFound) IsAddCommentResult() {}
+
 type Query struct {
 }
 
+type ReactionCount struct {
+	Kind  ReactionKind `json:"kind"`
+	Count int32        `json:"count"`
+}
+
+type ReactionPayload struct {
+	TargetType     ReactionTargetType `json:"targetType"`
+	TargetID       string             `json:"targetID"`
+	Reactions      []*ReactionCount   `json:"reactions"`
+	Score          int32              `json:"score"`
+	ViewerReaction *ReactionKind      `json:"viewerReaction,omitempty"`
+}
+
+type ReportCase struct {
+	ID             string               `json:"id"`
+	TargetType     ReportTargetType     `json:"targetType"`
+	Post           *Post                `json:"post,omitempty"`
+	Comment        *Comment             `json:"comment,omitempty"`
+	ReportCount    int32                `json:"reportCount"`
+	Reasons        []*ReportReasonCount `json:"reasons"`
+	Details        []string             `json:"details"`
+	LastReportedAt time.Time            `json:"lastReportedAt"`
+}
+
+type ReportCaseConnection struct {
+	Edges    []*ReportCaseEdge `json:"edges"`
+	PageInfo *PageInfo         `json:"pageInfo"`
+}
+
+type ReportCaseEdge struct {
+	Cursor string      `json:"cursor"`
+	Node   *ReportCase `json:"node"`
+}
+
+type ReportReasonCount struct {
+	Reason ReportReason `json:"reason"`
+	Count  int32        `json:"count"`
+}
+
+type SearchConnection struct {
+	Edges    []*SearchEdge `json:"edges"`
+	PageInfo *PageInfo     `json:"pageInfo"`
+}
+
+type SearchEdge struct {
+	Cursor  string       `json:"cursor"`
+	Node    SearchResult `json:"node"`
+	Snippet string       `json:"snippet"`
+	Rank    float64      `json:"rank"`
+}
+
+type Subscription struct {
+}
+
+type Tag struct {
+	Name      string          `json:"name"`
+	PostCount int32           `json:"postCount"`
+	Posts     *PostConnection `json:"posts"`
+}
+
 type User struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
+	ID           string             `json:"id"`
+	Username     string             `json:"username"`
+	DisplayName  *string            `json:"displayName,omitempty"`
+	Bio          *string            `json:"bio,omitempty"`
+	AvatarURL    *string            `json:"avatarURL,omitempty"`
+	RegisteredAt time.Time          `json:"registeredAt"`
+	Posts        *PostConnection    `json:"posts"`
+	Comments     *CommentConnection `json:"comments"`
+	Followers    *UserConnection    `json:"followers"`
+	Following    *UserConnection    `json:"following"`
+	Bookmarks    *PostConnection    `json:"bookmarks"`
+	Drafts       *PostConnection    `json:"drafts"`
+}
+
+type UserConnection struct {
+	Edges    []*UserEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+type UserEdge struct {
+	Cursor     string    `json:"cursor"`
+	Node       *User     `json:"node"`
+	FollowedAt time.Time `json:"followedAt"`
+}
+
+type ValidationError struct {
+	Message string        `json:"message"`
+	Fields  []*FieldError `json:"fields"`
+}
+
+func (ValidationError) IsAddPostResult() {}
+
+func (ValidationError) IsAddCommentResult() {}
+
+func (ValidationError) IsAddReplyResult() {}
+
+type CommentPolicy string
+
+const (
+	CommentPolicyOpen                     CommentPolicy = "OPEN"
+	CommentPolicyClosed                   CommentPolicy = "CLOSED"
+	CommentPolicyFollowersOnly            CommentPolicy = "FOLLOWERS_ONLY"
+	CommentPolicyRegisteredOlderThanNDays CommentPolicy = "REGISTERED_OLDER_THAN_N_DAYS"
+	CommentPolicyApprovalRequired         CommentPolicy = "APPROVAL_REQUIRED"
+)
+
+var AllCommentPolicy = []CommentPolicy{
+	CommentPolicyOpen,
+	CommentPolicyClosed,
+	CommentPolicyFollowersOnly,
+	CommentPolicyRegisteredOlderThanNDays,
+	CommentPolicyApprovalRequired,
+}
+
+func (e CommentPolicy) IsValid() bool {
+	switch e {
+	case CommentPolicyOpen, CommentPolicyClosed, CommentPolicyFollowersOnly, CommentPolicyRegisteredOlderThanNDays, CommentPolicyApprovalRequired:
+		return true
+	}
+	return false
+}
+
+func (e CommentPolicy) String() string {
+	return string(e)
+}
+
+func (e *CommentPolicy) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CommentPolicy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CommentPolicy", str)
+	}
+	return nil
+}
+
+func (e CommentPolicy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type NotificationType string
+
+const (
+	NotificationTypeComment NotificationType = "COMMENT"
+	NotificationTypeReply   NotificationType = "REPLY"
+	NotificationTypeMention NotificationType = "MENTION"
+)
+
+var AllNotificationType = []NotificationType{
+	NotificationTypeComment,
+	NotificationTypeReply,
+	NotificationTypeMention,
+}
+
+func (e NotificationType) IsValid() bool {
+	switch e {
+	case NotificationTypeComment, NotificationTypeReply, NotificationTypeMention:
+		return true
+	}
+	return false
+}
+
+func (e NotificationType) String() string {
+	return string(e)
+}
+
+func (e *NotificationType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NotificationType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NotificationType", str)
+	}
+	return nil
+}
+
+func (e NotificationType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "DRAFT"
+	PostStatusScheduled PostStatus = "SCHEDULED"
+	PostStatusPublished PostStatus = "PUBLISHED"
+	PostStatusHeld      PostStatus = "HELD"
+)
+
+var AllPostStatus = []PostStatus{
+	PostStatusDraft,
+	PostStatusScheduled,
+	PostStatusPublished,
+	PostStatusHeld,
+}
+
+func (e PostStatus) IsValid() bool {
+	switch e {
+	case PostStatusDraft, PostStatusScheduled, PostStatusPublished, PostStatusHeld:
+		return true
+	}
+	return false
+}
+
+func (e PostStatus) String() string {
+	return string(e)
+}
+
+func (e *PostStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PostStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PostStatus", str)
+	}
+	return nil
+}
+
+func (e PostStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ReactionKind string
+
+const (
+	ReactionKindLike    ReactionKind = "LIKE"
+	ReactionKindLove    ReactionKind = "LOVE"
+	ReactionKindLaugh   ReactionKind = "LAUGH"
+	ReactionKindDislike ReactionKind = "DISLIKE"
+)
+
+var AllReactionKind = []ReactionKind{
+	ReactionKindLike,
+	ReactionKindLove,
+	ReactionKindLaugh,
+	ReactionKindDislike,
+}
+
+func (e ReactionKind) IsValid() bool {
+	switch e {
+	case ReactionKindLike, ReactionKindLove, ReactionKindLaugh, ReactionKindDislike:
+		return true
+	}
+	return false
+}
+
+func (e ReactionKind) String() string {
+	return string(e)
+}
+
+func (e *ReactionKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReactionKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReactionKind", str)
+	}
+	return nil
+}
+
+func (e ReactionKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ReactionTargetType string
+
+const (
+	ReactionTargetTypePost    ReactionTargetType = "POST"
+	ReactionTargetTypeComment ReactionTargetType = "COMMENT"
+)
+
+var AllReactionTargetType = []ReactionTargetType{
+	ReactionTargetTypePost,
+	ReactionTargetTypeComment,
+}
+
+func (e ReactionTargetType) IsValid() bool {
+	switch e {
+	case ReactionTargetTypePost, ReactionTargetTypeComment:
+		return true
+	}
+	return false
+}
+
+func (e ReactionTargetType) String() string {
+	return string(e)
+}
+
+func (e *ReactionTargetType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReactionTargetType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReactionTargetType", str)
+	}
+	return nil
+}
+
+func (e ReactionTargetType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ReportAction string
+
+const (
+	ReportActionDismiss     ReportAction = "DISMISS"
+	ReportActionHideContent ReportAction = "HIDE_CONTENT"
+	ReportActionBanAuthor   ReportAction = "BAN_AUTHOR"
+)
+
+var AllReportAction = []ReportAction{
+	ReportActionDismiss,
+	ReportActionHideContent,
+	ReportActionBanAuthor,
+}
+
+func (e ReportAction) IsValid() bool {
+	switch e {
+	case ReportActionDismiss, ReportActionHideContent, ReportActionBanAuthor:
+		return true
+	}
+	return false
+}
+
+func (e ReportAction) String() string {
+	return string(e)
+}
+
+func (e *ReportAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportAction", str)
+	}
+	return nil
+}
+
+func (e ReportAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ReportReason string
+
+const (
+	ReportReasonSpam       ReportReason = "SPAM"
+	ReportReasonAbuse      ReportReason = "ABUSE"
+	ReportReasonHarassment ReportReason = "HARASSMENT"
+	ReportReasonOffTopic   ReportReason = "OFF_TOPIC"
+	ReportReasonOther      ReportReason = "OTHER"
+)
+
+var AllReportReason = []ReportReason{
+	ReportReasonSpam,
+	ReportReasonAbuse,
+	ReportReasonHarassment,
+	ReportReasonOffTopic,
+	ReportReasonOther,
+}
+
+func (e ReportReason) IsValid() bool {
+	switch e {
+	case ReportReasonSpam, ReportReasonAbuse, ReportReasonHarassment, ReportReasonOffTopic, ReportReasonOther:
+		return true
+	}
+	return false
+}
+
+func (e ReportReason) String() string {
+	return string(e)
+}
+
+func (e *ReportReason) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportReason", str)
+	}
+	return nil
+}
+
+func (e ReportReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ReportTargetType string
+
+const (
+	ReportTargetTypePost    ReportTargetType = "POST"
+	ReportTargetTypeComment ReportTargetType = "COMMENT"
+)
+
+var AllReportTargetType = []ReportTargetType{
+	ReportTargetTypePost,
+	ReportTargetTypeComment,
+}
+
+func (e ReportTargetType) IsValid() bool {
+	switch e {
+	case ReportTargetTypePost, ReportTargetTypeComment:
+		return true
+	}
+	return false
+}
+
+func (e ReportTargetType) String() string {
+	return string(e)
+}
+
+func (e *ReportTargetType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportTargetType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportTargetType", str)
+	}
+	return nil
+}
+
+func (e ReportTargetType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SearchType string
+
+const (
+	SearchTypePost    SearchType = "POST"
+	SearchTypeComment SearchType = "COMMENT"
+)
+
+var AllSearchType = []SearchType{
+	SearchTypePost,
+	SearchTypeComment,
+}
+
+func (e SearchType) IsValid() bool {
+	switch e {
+	case SearchTypePost, SearchTypeComment:
+		return true
+	}
+	return false
+}
+
+func (e SearchType) String() string {
+	return string(e)
+}
+
+func (e *SearchType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchType", str)
+	}
+	return nil
+}
+
+func (e SearchType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortOrder string
+
+const (
+	SortOrderNewest         SortOrder = "NEWEST"
+	SortOrderOldest         SortOrder = "OLDEST"
+	SortOrderMostCommented  SortOrder = "MOST_COMMENTED"
+	SortOrderRecentActivity SortOrder = "RECENT_ACTIVITY"
+	SortOrderTop            SortOrder = "TOP"
+)
+
+var AllSortOrder = []SortOrder{
+	SortOrderNewest,
+	SortOrderOldest,
+	SortOrderMostCommented,
+	SortOrderRecentActivity,
+	SortOrderTop,
+}
+
+func (e SortOrder) IsValid() bool {
+	switch e {
+	case SortOrderNewest, SortOrderOldest, SortOrderMostCommented, SortOrderRecentActivity, SortOrderTop:
+		return true
+	}
+	return false
+}
+
+func (e SortOrder) String() string {
+	return string(e)
+}
+
+func (e *SortOrder) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortOrder(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortOrder", str)
+	}
+	return nil
+}
+
+func (e SortOrder) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TextFormat string
+
+const (
+	TextFormatPlain    TextFormat = "PLAIN"
+	TextFormatMarkdown TextFormat = "MARKDOWN"
+)
+
+var AllTextFormat = []TextFormat{
+	TextFormatPlain,
+	TextFormatMarkdown,
+}
+
+func (e TextFormat) IsValid() bool {
+	switch e {
+	case TextFormatPlain, TextFormatMarkdown:
+		return true
+	}
+	return false
+}
+
+func (e TextFormat) String() string {
+	return string(e)
+}
+
+func (e *TextFormat) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TextFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TextFormat", str)
+	}
+	return nil
+}
+
+func (e TextFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
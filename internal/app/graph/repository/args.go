@@ -0,0 +1,93 @@
+package repository
+
+import "time"
+
+// Position is a position in a sorted list: sort key and ID (tiebreaker) of the last seen item.
+type Position struct {
+	Key int64
+	ID  int
+}
+
+// PageArgs describes a page of a cursor-paginated list.
+type PageArgs struct {
+	Limit int
+	// After is a position of the last item of a previous page, nil means the first page.
+	// Item with this position won`t be selected.
+	After *Position
+}
+
+// Order is a sort mode of posts and comments lists.
+type Order string
+
+const (
+	// OrderNewest sorts by ID descending. Position.Key is an ID.
+	OrderNewest Order = "NEWEST"
+	// OrderOldest sorts by ID ascending. Position.Key is an ID.
+	OrderOldest Order = "OLDEST"
+	// OrderMostCommented sorts by an amount of comments (replies for comments) descending. Position.Key is an amount.
+	OrderMostCommented Order = "MOST_COMMENTED"
+	// OrderRecentActivity sorts by a time of the latest activity descending. Position.Key is a unix time in microseconds.
+	OrderRecentActivity Order = "RECENT_ACTIVITY"
+	// OrderTop sorts by a reactions score (models.Reactions.Score) descending. Position.Key is a score.
+	OrderTop Order = "TOP"
+	// OrderMostReported sorts report cases by an amount of reports descending. Position.Key is an amount.
+	OrderMostReported Order = "MOST_REPORTED"
+)
+
+// IsDesc returns true if items are sorted (both by key and ID) in descending order.
+func (o Order) IsDesc() bool {
+	return o != OrderOldest
+}
+
+// PostFilter narrows down a list of posts, zero value means all posts.
+type PostFilter struct {
+	// Tag selects posts with a given tag, empty means any tags.
+	Tag string
+	// OwnerID selects posts of a given user, zero means any owner.
+	OwnerID int
+	// Unpublished selects drafts, scheduled and held posts instead of published ones. OwnerID must be set.
+	Unpublished bool
+	// Held selects posts held for review by moderation instead of published ones. Tag and OwnerID must be empty.
+	Held bool
+}
+
+// CommentFilter narrows down a list of post`s comments, zero value means all top-level comments.
+type CommentFilter struct {
+	// ExcludePinned skips comments pinned by the post owner.
+	ExcludePinned bool
+}
+
+// SearchType is a type of items to search for.
+type SearchType string
+
+const (
+	SearchTypePost    SearchType = "POST"
+	SearchTypeComment SearchType = "COMMENT"
+)
+
+// SearchQuery describes a full-text search request.
+// Hits are sorted by rank descending, Position.Key is a rank and Position.ID is models.SearchHit.SortID.
+type SearchQuery struct {
+	Text string
+	// Types to search for, empty means all types.
+	Types []SearchType
+	// AuthorID filters hits by owner, zero means any author.
+	AuthorID int
+	// From and To filter hits by creation time (inclusive), nil means no limit.
+	From *time.Time
+	To   *time.Time
+}
+
+// TargetType is a type of items readers can react to.
+type TargetType string
+
+const (
+	TargetPost    TargetType = "POST"
+	TargetComment TargetType = "COMMENT"
+)
+
+// Target is a post or a comment.
+type Target struct {
+	Type TargetType
+	ID   int
+}
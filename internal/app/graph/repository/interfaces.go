@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"ozon_test_task/internal/app/models"
+	"time"
 )
 
 //go:generate mockgen -source=interfaces.go -destination=mocks/mock_repositories.go -package=mocks
@@ -10,30 +11,210 @@ import (
 type PostRepo interface {
 	// AddPost adds a new post to a storage and returns it`s ID.
 	AddPost(ctx context.Context, post *models.Post) (int, error)
-	SetCommentsAllowed(ctx context.Context, postID int, commentsAllowed bool) error
+	// SetCommentPolicy updates post`s comment policy with its days, commentsAllowed flag and updatedAt time.
+	// returns repository.NewErrNotFound if not found.
+	SetCommentPolicy(ctx context.Context, postID int, policy models.CommentPolicy, days int, updatedAt time.Time) error
+	// GetPostByID returns a post by its ID.
+	// returns repository.NewErrNotFound if not found.
 	GetPostByID(ctx context.Context, postID int) (*models.Post, error)
-	// GetPosts returns "limit" amount of posts or less, after "after" post`s id (comment with "after" id won`t be selected).
-	// Also returns hasNextPage true if it`s exists more comments in database after last selected one.
-	GetPosts(ctx context.Context, limit int, after int) (posts []*models.Post, hasNextPage bool, err error)
+	// GetPosts returns "page.Limit" amount of posts or less matching "filter" sorted by "order", after "page.After" position.
+	// Also returns hasNextPage true if it`s exists more posts in database after last selected one.
+	GetPosts(ctx context.Context, filter PostFilter, order Order, page PageArgs) (posts []*models.Post, hasNextPage bool, err error)
+	// PublishPost publishes a draft or a scheduled post at publishedAt, publishing a published post is not an error.
+	// returns repository.NewErrNotFound if the post doesn`t exist and repository.NewErrConflict if it is held.
+	PublishPost(ctx context.Context, postID int, publishedAt time.Time) error
+	// SchedulePost makes a draft or a scheduled post be published by PublishDuePosts at publishAt.
+	// returns repository.NewErrNotFound if the post doesn`t exist and repository.NewErrConflict if it is published or held.
+	SchedulePost(ctx context.Context, postID int, publishAt time.Time) error
+	// PublishDuePosts publishes scheduled posts with publish time not after now and returns their IDs.
+	// Every post is published once, even if several app replicas call it concurrently.
+	PublishDuePosts(ctx context.Context, now time.Time) ([]int, error)
+	// ReleaseHeldPost makes a held post a draft and clears its moderation reasons and publish time.
+	// returns repository.NewErrNotFound if the post doesn`t exist and repository.NewErrConflict if it is not held.
+	ReleaseHeldPost(ctx context.Context, postID int) error
+	// SetPostHidden hides or restores a post.
+	// returns repository.NewErrNotFound if not found.
+	SetPostHidden(ctx context.Context, postID int, hidden bool) error
+}
+
+type ModerationRepo interface {
+	// HoldComment saves a comment held for review and returns its ID.
+	HoldComment(ctx context.Context, held *models.HeldComment) (int, error)
+	// GetHeldComments returns "page.Limit" amount of held comments or less, oldest first, after "page.After" position.
+	// Only comments of the post thread are returned if rootPostID is not zero.
+	// Also returns hasNextPage true if it`s exists more held comments after last selected one.
+	GetHeldComments(ctx context.Context, rootPostID int, page PageArgs) (comments []*models.HeldComment, hasNextPage bool, err error)
+	// GetHeldComment returns a held comment.
+	// returns repository.NewErrNotFound if not found.
+	GetHeldComment(ctx context.Context, heldID int) (*models.HeldComment, error)
+	// TakeHeldComment removes a held comment and returns it. Concurrent calls return the comment once.
+	// returns repository.NewErrNotFound if not found.
+	TakeHeldComment(ctx context.Context, heldID int) (*models.HeldComment, error)
+}
+
+type ReportRepo interface {
+	// AddReport adds a report to a case of the reported content, opening the case if there is no open one.
+	// Returns false if the reporter already reported the content in the open case, the report is not added then.
+	AddReport(ctx context.Context, report *models.Report) (bool, error)
+	// GetReportCases returns "page.Limit" amount of open cases or less, most reported first, after "page.After" position.
+	// Position.Key is a report count and Position.ID is a case ID.
+	// Also returns hasNextPage true if it`s exists more cases after last selected one.
+	GetReportCases(ctx context.Context, page PageArgs) (cases []*models.ReportCase, hasNextPage bool, err error)
+	// GetReportCase returns an open case by its ID.
+	// returns repository.NewErrNotFound if not found.
+	GetReportCase(ctx context.Context, caseID int) (*models.ReportCase, error)
+	// CloseReportCase deletes a case with its reports, so the content can be reported again.
+	// returns repository.NewErrNotFound if not found.
+	CloseReportCase(ctx context.Context, caseID int) error
+}
+
+type FollowRepo interface {
+	// Follow makes a follower follow a followee, following twice is not an error.
+	// returns repository.NewErrNotFound if the followee doesn`t exist.
+	Follow(ctx context.Context, followerID, followeeID int, createdAt time.Time) error
+	// Unfollow makes a follower stop following a followee, unfollowing not followed user is not an error.
+	Unfollow(ctx context.Context, followerID, followeeID int) error
+	// GetFollowers returns "page.Limit" amount of user`s followers or less, recently followed first, after "page.After" position.
+	// Position.Key is a follow time in unix microseconds and Position.ID is a follower ID.
+	// Also returns hasNextPage true if it`s exists more followers after last selected one.
+	GetFollowers(ctx context.Context, userID int, page PageArgs) (followers []*models.Follow, hasNextPage bool, err error)
+	// GetFollowing returns "page.Limit" amount of users followed by a user or less, recently followed first, after "page.After" position.
+	// Position.Key is a follow time in unix microseconds and Position.ID is a followee ID.
+	// Also returns hasNextPage true if it`s exists more followees after last selected one.
+	GetFollowing(ctx context.Context, userID int, page PageArgs) (following []*models.Follow, hasNextPage bool, err error)
+	// GetFeed returns "page.Limit" amount of posts of users followed by a user or less, newest first, after "page.After" position.
+	// Also returns hasNextPage true if it`s exists more posts after last selected one.
+	GetFeed(ctx context.Context, userID int, page PageArgs) (posts []*models.Post, hasNextPage bool, err error)
+	// IsFollowing returns true if a follower follows a followee.
+	IsFollowing(ctx context.Context, followerID, followeeID int) (bool, error)
+}
+
+type BlockRepo interface {
+	// BlockCommenter forbids a user to comment posts of an owner, blocking twice is not an error.
+	// returns repository.NewErrNotFound if the user doesn`t exist.
+	BlockCommenter(ctx context.Context, ownerID, userID int, createdAt time.Time) error
+	// UnblockCommenter allows a blocked user to comment posts of an owner again, unblocking not blocked user is not an error.
+	UnblockCommenter(ctx context.Context, ownerID, userID int) error
+	// IsCommenterBlocked returns true if an owner blocked a user from commenting their posts.
+	IsCommenterBlocked(ctx context.Context, ownerID, userID int) (bool, error)
+}
+
+type IdempotencyRepo interface {
+	// ReserveIdempotencyKey saves a record without a result if the user has no unexpired record with its key and returns nil,
+	// otherwise returns the stored record. Concurrent calls reserve a key once.
+	// returns repository.NewErrConflict if the stored record expires or is released during the call.
+	ReserveIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord, now time.Time) (*models.IdempotencyRecord, error)
+	// SaveIdempotencyResult saves a result of a reserved record and extends it to the record expiration time.
+	SaveIdempotencyResult(ctx context.Context, record *models.IdempotencyRecord) error
+	// ReleaseIdempotencyKey removes a user`s record, so a failed request can be retried with the same key.
+	ReleaseIdempotencyKey(ctx context.Context, userID int, key string) error
+}
+
+type BookmarkRepo interface {
+	// AddBookmark saves a post to user`s bookmarks, bookmarking twice is not an error.
+	// returns repository.NewErrNotFound if the post doesn`t exist.
+	AddBookmark(ctx context.Context, userID, postID int, createdAt time.Time) error
+	// RemoveBookmark removes a post from user`s bookmarks, removing absent bookmark is not an error.
+	RemoveBookmark(ctx context.Context, userID, postID int) error
+	// GetBookmarks returns "page.Limit" amount of user`s bookmarks or less, recently bookmarked first, after "page.After" position.
+	// Position.Key is a bookmark time in unix microseconds and Position.ID is a post ID.
+	// Also returns hasNextPage true if it`s exists more bookmarks after last selected one.
+	GetBookmarks(ctx context.Context, userID int, page PageArgs) (bookmarks []*models.Bookmark, hasNextPage bool, err error)
+	// IsBookmarked returns true if a user bookmarked a post.
+	IsBookmarked(ctx context.Context, userID, postID int) (bool, error)
+}
+
+type TagRepo interface {
+	// GetTag returns a tag with its posts count.
+	// returns repository.NewErrNotFound if no post has the tag.
+	GetTag(ctx context.Context, name string) (*models.Tag, error)
+	// GetPopularTags returns "limit" amount of tags or less with the most posts.
+	GetPopularTags(ctx context.Context, limit int) ([]*models.Tag, error)
 }
 
 type CommentRepo interface {
-	// AddComment adds a new comment to a storage and returns it`s ID.
+	// AddComment adds a new comment with its mentions to a storage and returns it`s ID.
+	// Also updates comments counters and activity times of the post and all comment`s ancestors.
 	AddComment(ctx context.Context, comment *models.Comment) (int, error)
-	// GetCommentsByPostID returns "limit" amount of comments or less, after "after" comment`s id (comment with "after" id won`t be selected).
+	// GetCommentsByPostID returns "page.Limit" amount of top-level comments or less matching "filter" sorted by "order", after "page.After" position.
+	// Also returns hasNextPage true if it`s exists more comments in database after last selected one.
+	GetCommentsByPostID(ctx context.Context, postID int, filter CommentFilter, order Order, page PageArgs) (comments []*models.Comment, hasNextPage bool, err error)
+	// GetReplaysByCommentID returns "page.Limit" amount of comments (replays) or less, after "page.After" position.
 	// Also returns hasNextPage true if it`s exists more comments in database after last selected one.
-	GetCommentsByPostID(ctx context.Context, postID int, limit int, after int) (comments []*models.Comment, hasNextPage bool, err error)
-	// GetReplaysByCommentID returns "limit" amount of comments (replays) or less, after "after" comment`s id (comment with "after" id won`t be selected).
+	GetReplaysByCommentID(ctx context.Context, commentID int, page PageArgs) (replays []*models.Comment, hasNextPage bool, err error)
+	// GetCommentsByOwnerID returns "page.Limit" amount of user`s comments and replies or less, newest first, after "page.After" position.
 	// Also returns hasNextPage true if it`s exists more comments in database after last selected one.
-	GetReplaysByCommentID(ctx context.Context, commentID int, limit int, after int) (replays []*models.Comment, hasNextPage bool, err error)
+	GetCommentsByOwnerID(ctx context.Context, ownerID int, page PageArgs) (comments []*models.Comment, hasNextPage bool, err error)
+	// GetCommentByID returns a comment or a reply by its ID.
+	// returns repository.NewErrNotFound if not found.
+	GetCommentByID(ctx context.Context, commentID int) (*models.Comment, error)
+	// GetMentions returns users mentioned in a comment in order of appearance.
+	GetMentions(ctx context.Context, commentID int) ([]*models.User, error)
+	// GetCommentsByMentionedUserID returns "page.Limit" amount of comments and replies mentioning a user or less, newest first, after "page.After" position.
+	// Also returns hasNextPage true if it`s exists more comments in database after last selected one.
+	GetCommentsByMentionedUserID(ctx context.Context, userID int, page PageArgs) (comments []*models.Comment, hasNextPage bool, err error)
+	// SetCommentHidden hides or restores a comment or a reply.
+	// returns repository.NewErrNotFound if not found.
+	SetCommentHidden(ctx context.Context, commentID int, hidden bool) error
+	// GetCommentPostID returns an ID of a post a comment or a reply belongs to.
+	// returns repository.NewErrNotFound if not found.
+	GetCommentPostID(ctx context.Context, commentID int) (int, error)
+	// PinComment pins a top-level comment of a post, pinning a pinned comment is not an error.
+	// returns repository.NewErrNotFound if the comment doesn`t exist and repository.NewErrConflict if the post has maxPinned pinned comments.
+	PinComment(ctx context.Context, postID, commentID int, pinnedAt time.Time, maxPinned int) error
+	// UnpinComment unpins a comment of a post, unpinning not pinned comment is not an error.
+	UnpinComment(ctx context.Context, postID, commentID int) error
+	// GetPinnedComments returns pinned comments of a post, earliest pinned first.
+	GetPinnedComments(ctx context.Context, postID int) ([]*models.Comment, error)
+}
+
+type ReactionRepo interface {
+	// React sets user`s reaction to a target replacing a previous one and returns updated target`s reactions.
+	// returns repository.NewErrNotFound if the target doesn`t exist.
+	React(ctx context.Context, target Target, userID int, kind models.ReactionKind) (models.Reactions, error)
+	// Unreact removes user`s reaction to a target and returns updated target`s reactions, removing absent reaction is not an error.
+	// returns repository.NewErrNotFound if the target doesn`t exist.
+	Unreact(ctx context.Context, target Target, userID int) (models.Reactions, error)
+	// GetReaction returns a kind of user`s reaction to a target, empty if the user didn`t react.
+	GetReaction(ctx context.Context, target Target, userID int) (models.ReactionKind, error)
+}
+
+type NotificationRepo interface {
+	// AddNotifications saves notifications and sets their IDs. Only Comment.ID of a notification comment is saved.
+	AddNotifications(ctx context.Context, notifications []*models.Notification) error
+	// GetNotifications returns "page.Limit" amount of user`s notifications or less, newest first, after "page.After" position.
+	// Only unread notifications are returned if unreadOnly is true.
+	// Also returns hasNextPage true if it`s exists more notifications after last selected one.
+	GetNotifications(ctx context.Context, userID int, unreadOnly bool, page PageArgs) (notifications []*models.Notification, hasNextPage bool, err error)
+	// MarkNotificationsRead marks user`s notifications with given IDs read, all user`s notifications if ids is nil.
+	// IDs of other users` notifications are ignored.
+	MarkNotificationsRead(ctx context.Context, userID int, ids []int) error
+	// CountUnreadNotifications returns an amount of user`s unread notifications.
+	CountUnreadNotifications(ctx context.Context, userID int) (int, error)
 }
 
 type UserRepo interface {
-	AddUser(ctx context.Context, user *models.User) (int, error)
+	// AddUser adds a new user with its credentials to a storage and returns it`s ID.
+	AddUser(ctx context.Context, user *models.User, cred *models.Credentials) (int, error)
 	// GetUserByID returns a user by its ID without password hash and salt.
 	// returns repository.NewErrNotFound if not found.
 	GetUserByID(ctx context.Context, userID int) (*models.User, error)
-	// GetUserByIDWithCred returns a user by its login with credentials (password_hash and password_salt).
+	// GetUserByLogin returns a user by its login without password hash and salt.
+	// returns repository.NewErrNotFound if not found.
+	GetUserByLogin(ctx context.Context, login string) (*models.User, error)
+	// GetUserByLoginWithCred returns a user by its login with its credentials (password hash and salt).
 	// returns repository.NewErrNotFound if not found.
-	GetUserByLoginWithCred(ctx context.Context, login string) (*models.User, error)
+	GetUserByLoginWithCred(ctx context.Context, login string) (*models.User, *models.Credentials, error)
+	// UpdateProfile updates user`s display name, bio and avatar URL.
+	// returns repository.NewErrNotFound if not found.
+	UpdateProfile(ctx context.Context, user *models.User) error
+	// BanUser bans a user, banning a banned user is not an error.
+	// returns repository.NewErrNotFound if not found.
+	BanUser(ctx context.Context, userID int) error
+}
+
+type SearchRepo interface {
+	// Search returns "page.Limit" amount of posts and comments or less matching a query, after "page.After" position.
+	// Also returns hasNextPage true if it`s exists more hits after last selected one.
+	Search(ctx context.Context, query SearchQuery, page PageArgs) (hits []*models.SearchHit, hasNextPage bool, err error)
 }
@@ -11,8 +11,10 @@ package mocks
 
 import (
 	context "context"
+	repository "ozon_test_task/internal/app/graph/repository"
 	models "ozon_test_task/internal/app/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -72,9 +74,9 @@ func (mr *MockPostRepoMockRecorder) GetPostByID(ctx, postID any) *gomock.Call {
 }
 
 // GetPosts mocks base method.
-func (m *MockPostRepo) GetPosts(ctx context.Context, limit, after int) ([]*models.Post, bool, error) {
+func (m *MockPostRepo) GetPosts(ctx context.Context, filter repository.PostFilter, order repository.Order, page repository.PageArgs) ([]*models.Post, bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPosts", ctx, limit, after)
+	ret := m.ctrl.Call(m, "GetPosts", ctx, filter, order, page)
 	ret0, _ := ret[0].([]*models.Post)
 	ret1, _ := ret[1].(bool)
 	ret2, _ := ret[2].(error)
@@ -82,23 +84,649 @@ func (m *MockPostRepo) GetPosts(ctx context.Context, limit, after int) ([]*model
 }
 
 // GetPosts indicates an expected call of GetPosts.
-func (mr *MockPostRepoMockRecorder) GetPosts(ctx, limit, after any) *gomock.Call {
+func (mr *MockPostRepoMockRecorder) GetPosts(ctx, filter, order, page any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockPostRepo)(nil).GetPosts), ctx, limit, after)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockPostRepo)(nil).GetPosts), ctx, filter, order, page)
 }
 
-// SetCommentsAllowed mocks base method.
-func (m *MockPostRepo) SetCommentsAllowed(ctx context.Context, postID int, commentsAllowed bool) error {
+// PublishDuePosts mocks base method.
+func (m *MockPostRepo) PublishDuePosts(ctx context.Context, now time.Time) ([]int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetCommentsAllowed", ctx, postID, commentsAllowed)
+	ret := m.ctrl.Call(m, "PublishDuePosts", ctx, now)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDuePosts indicates an expected call of PublishDuePosts.
+func (mr *MockPostRepoMockRecorder) PublishDuePosts(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDuePosts", reflect.TypeOf((*MockPostRepo)(nil).PublishDuePosts), ctx, now)
+}
+
+// PublishPost mocks base method.
+func (m *MockPostRepo) PublishPost(ctx context.Context, postID int, publishedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishPost", ctx, postID, publishedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishPost indicates an expected call of PublishPost.
+func (mr *MockPostRepoMockRecorder) PublishPost(ctx, postID, publishedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishPost", reflect.TypeOf((*MockPostRepo)(nil).PublishPost), ctx, postID, publishedAt)
+}
+
+// ReleaseHeldPost mocks base method.
+func (m *MockPostRepo) ReleaseHeldPost(ctx context.Context, postID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHeldPost", ctx, postID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseHeldPost indicates an expected call of ReleaseHeldPost.
+func (mr *MockPostRepoMockRecorder) ReleaseHeldPost(ctx, postID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHeldPost", reflect.TypeOf((*MockPostRepo)(nil).ReleaseHeldPost), ctx, postID)
+}
+
+// SchedulePost mocks base method.
+func (m *MockPostRepo) SchedulePost(ctx context.Context, postID int, publishAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchedulePost", ctx, postID, publishAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SchedulePost indicates an expected call of SchedulePost.
+func (mr *MockPostRepoMockRecorder) SchedulePost(ctx, postID, publishAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchedulePost", reflect.TypeOf((*MockPostRepo)(nil).SchedulePost), ctx, postID, publishAt)
+}
+
+// SetCommentPolicy mocks base method.
+func (m *MockPostRepo) SetCommentPolicy(ctx context.Context, postID int, policy models.CommentPolicy, days int, updatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCommentPolicy", ctx, postID, policy, days, updatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCommentPolicy indicates an expected call of SetCommentPolicy.
+func (mr *MockPostRepoMockRecorder) SetCommentPolicy(ctx, postID, policy, days, updatedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCommentPolicy", reflect.TypeOf((*MockPostRepo)(nil).SetCommentPolicy), ctx, postID, policy, days, updatedAt)
+}
+
+// SetPostHidden mocks base method.
+func (m *MockPostRepo) SetPostHidden(ctx context.Context, postID int, hidden bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPostHidden", ctx, postID, hidden)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPostHidden indicates an expected call of SetPostHidden.
+func (mr *MockPostRepoMockRecorder) SetPostHidden(ctx, postID, hidden any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPostHidden", reflect.TypeOf((*MockPostRepo)(nil).SetPostHidden), ctx, postID, hidden)
+}
+
+// MockModerationRepo is a mock of ModerationRepo interface.
+type MockModerationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockModerationRepoMockRecorder is the mock recorder for MockModerationRepo.
+type MockModerationRepoMockRecorder struct {
+	mock *MockModerationRepo
+}
+
+// NewMockModerationRepo creates a new mock instance.
+func NewMockModerationRepo(ctrl *gomock.Controller) *MockModerationRepo {
+	mock := &MockModerationRepo{ctrl: ctrl}
+	mock.recorder = &MockModerationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerationRepo) EXPECT() *MockModerationRepoMockRecorder {
+	return m.recorder
+}
+
+// GetHeldComment mocks base method.
+func (m *MockModerationRepo) GetHeldComment(ctx context.Context, heldID int) (*models.HeldComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeldComment", ctx, heldID)
+	ret0, _ := ret[0].(*models.HeldComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeldComment indicates an expected call of GetHeldComment.
+func (mr *MockModerationRepoMockRecorder) GetHeldComment(ctx, heldID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeldComment", reflect.TypeOf((*MockModerationRepo)(nil).GetHeldComment), ctx, heldID)
+}
+
+// GetHeldComments mocks base method.
+func (m *MockModerationRepo) GetHeldComments(ctx context.Context, rootPostID int, page repository.PageArgs) ([]*models.HeldComment, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeldComments", ctx, rootPostID, page)
+	ret0, _ := ret[0].([]*models.HeldComment)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetHeldComments indicates an expected call of GetHeldComments.
+func (mr *MockModerationRepoMockRecorder) GetHeldComments(ctx, rootPostID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeldComments", reflect.TypeOf((*MockModerationRepo)(nil).GetHeldComments), ctx, rootPostID, page)
+}
+
+// HoldComment mocks base method.
+func (m *MockModerationRepo) HoldComment(ctx context.Context, held *models.HeldComment) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HoldComment", ctx, held)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HoldComment indicates an expected call of HoldComment.
+func (mr *MockModerationRepoMockRecorder) HoldComment(ctx, held any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HoldComment", reflect.TypeOf((*MockModerationRepo)(nil).HoldComment), ctx, held)
+}
+
+// TakeHeldComment mocks base method.
+func (m *MockModerationRepo) TakeHeldComment(ctx context.Context, heldID int) (*models.HeldComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeHeldComment", ctx, heldID)
+	ret0, _ := ret[0].(*models.HeldComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeHeldComment indicates an expected call of TakeHeldComment.
+func (mr *MockModerationRepoMockRecorder) TakeHeldComment(ctx, heldID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeHeldComment", reflect.TypeOf((*MockModerationRepo)(nil).TakeHeldComment), ctx, heldID)
+}
+
+// MockReportRepo is a mock of ReportRepo interface.
+type MockReportRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockReportRepoMockRecorder is the mock recorder for MockReportRepo.
+type MockReportRepoMockRecorder struct {
+	mock *MockReportRepo
+}
+
+// NewMockReportRepo creates a new mock instance.
+func NewMockReportRepo(ctrl *gomock.Controller) *MockReportRepo {
+	mock := &MockReportRepo{ctrl: ctrl}
+	mock.recorder = &MockReportRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportRepo) EXPECT() *MockReportRepoMockRecorder {
+	return m.recorder
+}
+
+// AddReport mocks base method.
+func (m *MockReportRepo) AddReport(ctx context.Context, report *models.Report) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReport", ctx, report)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddReport indicates an expected call of AddReport.
+func (mr *MockReportRepoMockRecorder) AddReport(ctx, report any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReport", reflect.TypeOf((*MockReportRepo)(nil).AddReport), ctx, report)
+}
+
+// CloseReportCase mocks base method.
+func (m *MockReportRepo) CloseReportCase(ctx context.Context, caseID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseReportCase", ctx, caseID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseReportCase indicates an expected call of CloseReportCase.
+func (mr *MockReportRepoMockRecorder) CloseReportCase(ctx, caseID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseReportCase", reflect.TypeOf((*MockReportRepo)(nil).CloseReportCase), ctx, caseID)
+}
+
+// GetReportCase mocks base method.
+func (m *MockReportRepo) GetReportCase(ctx context.Context, caseID int) (*models.ReportCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportCase", ctx, caseID)
+	ret0, _ := ret[0].(*models.ReportCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportCase indicates an expected call of GetReportCase.
+func (mr *MockReportRepoMockRecorder) GetReportCase(ctx, caseID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportCase", reflect.TypeOf((*MockReportRepo)(nil).GetReportCase), ctx, caseID)
+}
+
+// GetReportCases mocks base method.
+func (m *MockReportRepo) GetReportCases(ctx context.Context, page repository.PageArgs) ([]*models.ReportCase, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportCases", ctx, page)
+	ret0, _ := ret[0].([]*models.ReportCase)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetReportCases indicates an expected call of GetReportCases.
+func (mr *MockReportRepoMockRecorder) GetReportCases(ctx, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportCases", reflect.TypeOf((*MockReportRepo)(nil).GetReportCases), ctx, page)
+}
+
+// MockFollowRepo is a mock of FollowRepo interface.
+type MockFollowRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockFollowRepoMockRecorder is the mock recorder for MockFollowRepo.
+type MockFollowRepoMockRecorder struct {
+	mock *MockFollowRepo
+}
+
+// NewMockFollowRepo creates a new mock instance.
+func NewMockFollowRepo(ctrl *gomock.Controller) *MockFollowRepo {
+	mock := &MockFollowRepo{ctrl: ctrl}
+	mock.recorder = &MockFollowRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollowRepo) EXPECT() *MockFollowRepoMockRecorder {
+	return m.recorder
+}
+
+// Follow mocks base method.
+func (m *MockFollowRepo) Follow(ctx context.Context, followerID, followeeID int, createdAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Follow", ctx, followerID, followeeID, createdAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Follow indicates an expected call of Follow.
+func (mr *MockFollowRepoMockRecorder) Follow(ctx, followerID, followeeID, createdAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockFollowRepo)(nil).Follow), ctx, followerID, followeeID, createdAt)
+}
+
+// GetFeed mocks base method.
+func (m *MockFollowRepo) GetFeed(ctx context.Context, userID int, page repository.PageArgs) ([]*models.Post, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", ctx, userID, page)
+	ret0, _ := ret[0].([]*models.Post)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockFollowRepoMockRecorder) GetFeed(ctx, userID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockFollowRepo)(nil).GetFeed), ctx, userID, page)
+}
+
+// GetFollowers mocks base method.
+func (m *MockFollowRepo) GetFollowers(ctx context.Context, userID int, page repository.PageArgs) ([]*models.Follow, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowers", ctx, userID, page)
+	ret0, _ := ret[0].([]*models.Follow)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFollowers indicates an expected call of GetFollowers.
+func (mr *MockFollowRepoMockRecorder) GetFollowers(ctx, userID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowers", reflect.TypeOf((*MockFollowRepo)(nil).GetFollowers), ctx, userID, page)
+}
+
+// GetFollowing mocks base method.
+func (m *MockFollowRepo) GetFollowing(ctx context.Context, userID int, page repository.PageArgs) ([]*models.Follow, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowing", ctx, userID, page)
+	ret0, _ := ret[0].([]*models.Follow)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFollowing indicates an expected call of GetFollowing.
+func (mr *MockFollowRepoMockRecorder) GetFollowing(ctx, userID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowing", reflect.TypeOf((*MockFollowRepo)(nil).GetFollowing), ctx, userID, page)
+}
+
+// IsFollowing mocks base method.
+func (m *MockFollowRepo) IsFollowing(ctx context.Context, followerID, followeeID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsFollowing", ctx, followerID, followeeID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsFollowing indicates an expected call of IsFollowing.
+func (mr *MockFollowRepoMockRecorder) IsFollowing(ctx, followerID, followeeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFollowing", reflect.TypeOf((*MockFollowRepo)(nil).IsFollowing), ctx, followerID, followeeID)
+}
+
+// Unfollow mocks base method.
+func (m *MockFollowRepo) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfollow", ctx, followerID, followeeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unfollow indicates an expected call of Unfollow.
+func (mr *MockFollowRepoMockRecorder) Unfollow(ctx, followerID, followeeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockFollowRepo)(nil).Unfollow), ctx, followerID, followeeID)
+}
+
+// MockBlockRepo is a mock of BlockRepo interface.
+type MockBlockRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockBlockRepoMockRecorder is the mock recorder for MockBlockRepo.
+type MockBlockRepoMockRecorder struct {
+	mock *MockBlockRepo
+}
+
+// NewMockBlockRepo creates a new mock instance.
+func NewMockBlockRepo(ctrl *gomock.Controller) *MockBlockRepo {
+	mock := &MockBlockRepo{ctrl: ctrl}
+	mock.recorder = &MockBlockRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockRepo) EXPECT() *MockBlockRepoMockRecorder {
+	return m.recorder
+}
+
+// BlockCommenter mocks base method.
+func (m *MockBlockRepo) BlockCommenter(ctx context.Context, ownerID, userID int, createdAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockCommenter", ctx, ownerID, userID, createdAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockCommenter indicates an expected call of BlockCommenter.
+func (mr *MockBlockRepoMockRecorder) BlockCommenter(ctx, ownerID, userID, createdAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockCommenter", reflect.TypeOf((*MockBlockRepo)(nil).BlockCommenter), ctx, ownerID, userID, createdAt)
+}
+
+// IsCommenterBlocked mocks base method.
+func (m *MockBlockRepo) IsCommenterBlocked(ctx context.Context, ownerID, userID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsCommenterBlocked", ctx, ownerID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsCommenterBlocked indicates an expected call of IsCommenterBlocked.
+func (mr *MockBlockRepoMockRecorder) IsCommenterBlocked(ctx, ownerID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsCommenterBlocked", reflect.TypeOf((*MockBlockRepo)(nil).IsCommenterBlocked), ctx, ownerID, userID)
+}
+
+// UnblockCommenter mocks base method.
+func (m *MockBlockRepo) UnblockCommenter(ctx context.Context, ownerID, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockCommenter", ctx, ownerID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnblockCommenter indicates an expected call of UnblockCommenter.
+func (mr *MockBlockRepoMockRecorder) UnblockCommenter(ctx, ownerID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockCommenter", reflect.TypeOf((*MockBlockRepo)(nil).UnblockCommenter), ctx, ownerID, userID)
+}
+
+// MockIdempotencyRepo is a mock of IdempotencyRepo interface.
+type MockIdempotencyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyRepoMockRecorder is the mock recorder for MockIdempotencyRepo.
+type MockIdempotencyRepoMockRecorder struct {
+	mock *MockIdempotencyRepo
+}
+
+// NewMockIdempotencyRepo creates a new mock instance.
+func NewMockIdempotencyRepo(ctrl *gomock.Controller) *MockIdempotencyRepo {
+	mock := &MockIdempotencyRepo{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepo) EXPECT() *MockIdempotencyRepoMockRecorder {
+	return m.recorder
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepo) ReleaseIdempotencyKey(ctx context.Context, userID int, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", ctx, userID, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockIdempotencyRepoMockRecorder) ReleaseIdempotencyKey(ctx, userID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepo)(nil).ReleaseIdempotencyKey), ctx, userID, key)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepo) ReserveIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord, now time.Time) (*models.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", ctx, record, now)
+	ret0, _ := ret[0].(*models.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockIdempotencyRepoMockRecorder) ReserveIdempotencyKey(ctx, record, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepo)(nil).ReserveIdempotencyKey), ctx, record, now)
+}
+
+// SaveIdempotencyResult mocks base method.
+func (m *MockIdempotencyRepo) SaveIdempotencyResult(ctx context.Context, record *models.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotencyResult", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotencyResult indicates an expected call of SaveIdempotencyResult.
+func (mr *MockIdempotencyRepoMockRecorder) SaveIdempotencyResult(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotencyResult", reflect.TypeOf((*MockIdempotencyRepo)(nil).SaveIdempotencyResult), ctx, record)
+}
+
+// MockBookmarkRepo is a mock of BookmarkRepo interface.
+type MockBookmarkRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookmarkRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockBookmarkRepoMockRecorder is the mock recorder for MockBookmarkRepo.
+type MockBookmarkRepoMockRecorder struct {
+	mock *MockBookmarkRepo
+}
+
+// NewMockBookmarkRepo creates a new mock instance.
+func NewMockBookmarkRepo(ctrl *gomock.Controller) *MockBookmarkRepo {
+	mock := &MockBookmarkRepo{ctrl: ctrl}
+	mock.recorder = &MockBookmarkRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookmarkRepo) EXPECT() *MockBookmarkRepoMockRecorder {
+	return m.recorder
+}
+
+// AddBookmark mocks base method.
+func (m *MockBookmarkRepo) AddBookmark(ctx context.Context, userID, postID int, createdAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBookmark", ctx, userID, postID, createdAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBookmark indicates an expected call of AddBookmark.
+func (mr *MockBookmarkRepoMockRecorder) AddBookmark(ctx, userID, postID, createdAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBookmark", reflect.TypeOf((*MockBookmarkRepo)(nil).AddBookmark), ctx, userID, postID, createdAt)
+}
+
+// GetBookmarks mocks base method.
+func (m *MockBookmarkRepo) GetBookmarks(ctx context.Context, userID int, page repository.PageArgs) ([]*models.Bookmark, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookmarks", ctx, userID, page)
+	ret0, _ := ret[0].([]*models.Bookmark)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBookmarks indicates an expected call of GetBookmarks.
+func (mr *MockBookmarkRepoMockRecorder) GetBookmarks(ctx, userID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookmarks", reflect.TypeOf((*MockBookmarkRepo)(nil).GetBookmarks), ctx, userID, page)
+}
+
+// IsBookmarked mocks base method.
+func (m *MockBookmarkRepo) IsBookmarked(ctx context.Context, userID, postID int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBookmarked", ctx, userID, postID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBookmarked indicates an expected call of IsBookmarked.
+func (mr *MockBookmarkRepoMockRecorder) IsBookmarked(ctx, userID, postID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBookmarked", reflect.TypeOf((*MockBookmarkRepo)(nil).IsBookmarked), ctx, userID, postID)
+}
+
+// RemoveBookmark mocks base method.
+func (m *MockBookmarkRepo) RemoveBookmark(ctx context.Context, userID, postID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveBookmark", ctx, userID, postID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetCommentsAllowed indicates an expected call of SetCommentsAllowed.
-func (mr *MockPostRepoMockRecorder) SetCommentsAllowed(ctx, postID, commentsAllowed any) *gomock.Call {
+// RemoveBookmark indicates an expected call of RemoveBookmark.
+func (mr *MockBookmarkRepoMockRecorder) RemoveBookmark(ctx, userID, postID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBookmark", reflect.TypeOf((*MockBookmarkRepo)(nil).RemoveBookmark), ctx, userID, postID)
+}
+
+// MockTagRepo is a mock of TagRepo interface.
+type MockTagRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockTagRepoMockRecorder is the mock recorder for MockTagRepo.
+type MockTagRepoMockRecorder struct {
+	mock *MockTagRepo
+}
+
+// NewMockTagRepo creates a new mock instance.
+func NewMockTagRepo(ctrl *gomock.Controller) *MockTagRepo {
+	mock := &MockTagRepo{ctrl: ctrl}
+	mock.recorder = &MockTagRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagRepo) EXPECT() *MockTagRepoMockRecorder {
+	return m.recorder
+}
+
+// GetPopularTags mocks base method.
+func (m *MockTagRepo) GetPopularTags(ctx context.Context, limit int) ([]*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPopularTags", ctx, limit)
+	ret0, _ := ret[0].([]*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPopularTags indicates an expected call of GetPopularTags.
+func (mr *MockTagRepoMockRecorder) GetPopularTags(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPopularTags", reflect.TypeOf((*MockTagRepo)(nil).GetPopularTags), ctx, limit)
+}
+
+// GetTag mocks base method.
+func (m *MockTagRepo) GetTag(ctx context.Context, name string) (*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTag", ctx, name)
+	ret0, _ := ret[0].(*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTag indicates an expected call of GetTag.
+func (mr *MockTagRepoMockRecorder) GetTag(ctx, name any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCommentsAllowed", reflect.TypeOf((*MockPostRepo)(nil).SetCommentsAllowed), ctx, postID, commentsAllowed)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockTagRepo)(nil).GetTag), ctx, name)
 }
 
 // MockCommentRepo is a mock of CommentRepo interface.
@@ -140,10 +768,72 @@ func (mr *MockCommentRepoMockRecorder) AddComment(ctx, comment any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockCommentRepo)(nil).AddComment), ctx, comment)
 }
 
+// GetCommentByID mocks base method.
+func (m *MockCommentRepo) GetCommentByID(ctx context.Context, commentID int) (*models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentByID", ctx, commentID)
+	ret0, _ := ret[0].(*models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentByID indicates an expected call of GetCommentByID.
+func (mr *MockCommentRepoMockRecorder) GetCommentByID(ctx, commentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentByID", reflect.TypeOf((*MockCommentRepo)(nil).GetCommentByID), ctx, commentID)
+}
+
+// GetCommentPostID mocks base method.
+func (m *MockCommentRepo) GetCommentPostID(ctx context.Context, commentID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentPostID", ctx, commentID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentPostID indicates an expected call of GetCommentPostID.
+func (mr *MockCommentRepoMockRecorder) GetCommentPostID(ctx, commentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentPostID", reflect.TypeOf((*MockCommentRepo)(nil).GetCommentPostID), ctx, commentID)
+}
+
+// GetCommentsByMentionedUserID mocks base method.
+func (m *MockCommentRepo) GetCommentsByMentionedUserID(ctx context.Context, userID int, page repository.PageArgs) ([]*models.Comment, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentsByMentionedUserID", ctx, userID, page)
+	ret0, _ := ret[0].([]*models.Comment)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCommentsByMentionedUserID indicates an expected call of GetCommentsByMentionedUserID.
+func (mr *MockCommentRepoMockRecorder) GetCommentsByMentionedUserID(ctx, userID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentsByMentionedUserID", reflect.TypeOf((*MockCommentRepo)(nil).GetCommentsByMentionedUserID), ctx, userID, page)
+}
+
+// GetCommentsByOwnerID mocks base method.
+func (m *MockCommentRepo) GetCommentsByOwnerID(ctx context.Context, ownerID int, page repository.PageArgs) ([]*models.Comment, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentsByOwnerID", ctx, ownerID, page)
+	ret0, _ := ret[0].([]*models.Comment)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCommentsByOwnerID indicates an expected call of GetCommentsByOwnerID.
+func (mr *MockCommentRepoMockRecorder) GetCommentsByOwnerID(ctx, ownerID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentsByOwnerID", reflect.TypeOf((*MockCommentRepo)(nil).GetCommentsByOwnerID), ctx, ownerID, page)
+}
+
 // GetCommentsByPostID mocks base method.
-func (m *MockCommentRepo) GetCommentsByPostID(ctx context.Context, postID, limit, after int) ([]*models.Comment, bool, error) {
+func (m *MockCommentRepo) GetCommentsByPostID(ctx context.Context, postID int, filter repository.CommentFilter, order repository.Order, page repository.PageArgs) ([]*models.Comment, bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCommentsByPostID", ctx, postID, limit, after)
+	ret := m.ctrl.Call(m, "GetCommentsByPostID", ctx, postID, filter, order, page)
 	ret0, _ := ret[0].([]*models.Comment)
 	ret1, _ := ret[1].(bool)
 	ret2, _ := ret[2].(error)
@@ -151,15 +841,45 @@ func (m *MockCommentRepo) GetCommentsByPostID(ctx context.Context, postID, limit
 }
 
 // GetCommentsByPostID indicates an expected call of GetCommentsByPostID.
-func (mr *MockCommentRepoMockRecorder) GetCommentsByPostID(ctx, postID, limit, after any) *gomock.Call {
+func (mr *MockCommentRepoMockRecorder) GetCommentsByPostID(ctx, postID, filter, order, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentsByPostID", reflect.TypeOf((*MockCommentRepo)(nil).GetCommentsByPostID), ctx, postID, filter, order, page)
+}
+
+// GetMentions mocks base method.
+func (m *MockCommentRepo) GetMentions(ctx context.Context, commentID int) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMentions", ctx, commentID)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMentions indicates an expected call of GetMentions.
+func (mr *MockCommentRepoMockRecorder) GetMentions(ctx, commentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMentions", reflect.TypeOf((*MockCommentRepo)(nil).GetMentions), ctx, commentID)
+}
+
+// GetPinnedComments mocks base method.
+func (m *MockCommentRepo) GetPinnedComments(ctx context.Context, postID int) ([]*models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPinnedComments", ctx, postID)
+	ret0, _ := ret[0].([]*models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPinnedComments indicates an expected call of GetPinnedComments.
+func (mr *MockCommentRepoMockRecorder) GetPinnedComments(ctx, postID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentsByPostID", reflect.TypeOf((*MockCommentRepo)(nil).GetCommentsByPostID), ctx, postID, limit, after)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPinnedComments", reflect.TypeOf((*MockCommentRepo)(nil).GetPinnedComments), ctx, postID)
 }
 
 // GetReplaysByCommentID mocks base method.
-func (m *MockCommentRepo) GetReplaysByCommentID(ctx context.Context, commentID, limit, after int) ([]*models.Comment, bool, error) {
+func (m *MockCommentRepo) GetReplaysByCommentID(ctx context.Context, commentID int, page repository.PageArgs) ([]*models.Comment, bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetReplaysByCommentID", ctx, commentID, limit, after)
+	ret := m.ctrl.Call(m, "GetReplaysByCommentID", ctx, commentID, page)
 	ret0, _ := ret[0].([]*models.Comment)
 	ret1, _ := ret[1].(bool)
 	ret2, _ := ret[2].(error)
@@ -167,9 +887,203 @@ func (m *MockCommentRepo) GetReplaysByCommentID(ctx context.Context, commentID,
 }
 
 // GetReplaysByCommentID indicates an expected call of GetReplaysByCommentID.
-func (mr *MockCommentRepoMockRecorder) GetReplaysByCommentID(ctx, commentID, limit, after any) *gomock.Call {
+func (mr *MockCommentRepoMockRecorder) GetReplaysByCommentID(ctx, commentID, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplaysByCommentID", reflect.TypeOf((*MockCommentRepo)(nil).GetReplaysByCommentID), ctx, commentID, page)
+}
+
+// PinComment mocks base method.
+func (m *MockCommentRepo) PinComment(ctx context.Context, postID, commentID int, pinnedAt time.Time, maxPinned int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinComment", ctx, postID, commentID, pinnedAt, maxPinned)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PinComment indicates an expected call of PinComment.
+func (mr *MockCommentRepoMockRecorder) PinComment(ctx, postID, commentID, pinnedAt, maxPinned any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinComment", reflect.TypeOf((*MockCommentRepo)(nil).PinComment), ctx, postID, commentID, pinnedAt, maxPinned)
+}
+
+// SetCommentHidden mocks base method.
+func (m *MockCommentRepo) SetCommentHidden(ctx context.Context, commentID int, hidden bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCommentHidden", ctx, commentID, hidden)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCommentHidden indicates an expected call of SetCommentHidden.
+func (mr *MockCommentRepoMockRecorder) SetCommentHidden(ctx, commentID, hidden any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCommentHidden", reflect.TypeOf((*MockCommentRepo)(nil).SetCommentHidden), ctx, commentID, hidden)
+}
+
+// UnpinComment mocks base method.
+func (m *MockCommentRepo) UnpinComment(ctx context.Context, postID, commentID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinComment", ctx, postID, commentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpinComment indicates an expected call of UnpinComment.
+func (mr *MockCommentRepoMockRecorder) UnpinComment(ctx, postID, commentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinComment", reflect.TypeOf((*MockCommentRepo)(nil).UnpinComment), ctx, postID, commentID)
+}
+
+// MockReactionRepo is a mock of ReactionRepo interface.
+type MockReactionRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockReactionRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockReactionRepoMockRecorder is the mock recorder for MockReactionRepo.
+type MockReactionRepoMockRecorder struct {
+	mock *MockReactionRepo
+}
+
+// NewMockReactionRepo creates a new mock instance.
+func NewMockReactionRepo(ctrl *gomock.Controller) *MockReactionRepo {
+	mock := &MockReactionRepo{ctrl: ctrl}
+	mock.recorder = &MockReactionRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReactionRepo) EXPECT() *MockReactionRepoMockRecorder {
+	return m.recorder
+}
+
+// GetReaction mocks base method.
+func (m *MockReactionRepo) GetReaction(ctx context.Context, target repository.Target, userID int) (models.ReactionKind, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReaction", ctx, target, userID)
+	ret0, _ := ret[0].(models.ReactionKind)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReaction indicates an expected call of GetReaction.
+func (mr *MockReactionRepoMockRecorder) GetReaction(ctx, target, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReaction", reflect.TypeOf((*MockReactionRepo)(nil).GetReaction), ctx, target, userID)
+}
+
+// React mocks base method.
+func (m *MockReactionRepo) React(ctx context.Context, target repository.Target, userID int, kind models.ReactionKind) (models.Reactions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "React", ctx, target, userID, kind)
+	ret0, _ := ret[0].(models.Reactions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// React indicates an expected call of React.
+func (mr *MockReactionRepoMockRecorder) React(ctx, target, userID, kind any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "React", reflect.TypeOf((*MockReactionRepo)(nil).React), ctx, target, userID, kind)
+}
+
+// Unreact mocks base method.
+func (m *MockReactionRepo) Unreact(ctx context.Context, target repository.Target, userID int) (models.Reactions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unreact", ctx, target, userID)
+	ret0, _ := ret[0].(models.Reactions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unreact indicates an expected call of Unreact.
+func (mr *MockReactionRepoMockRecorder) Unreact(ctx, target, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unreact", reflect.TypeOf((*MockReactionRepo)(nil).Unreact), ctx, target, userID)
+}
+
+// MockNotificationRepo is a mock of NotificationRepo interface.
+type MockNotificationRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockNotificationRepoMockRecorder is the mock recorder for MockNotificationRepo.
+type MockNotificationRepoMockRecorder struct {
+	mock *MockNotificationRepo
+}
+
+// NewMockNotificationRepo creates a new mock instance.
+func NewMockNotificationRepo(ctrl *gomock.Controller) *MockNotificationRepo {
+	mock := &MockNotificationRepo{ctrl: ctrl}
+	mock.recorder = &MockNotificationRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationRepo) EXPECT() *MockNotificationRepoMockRecorder {
+	return m.recorder
+}
+
+// AddNotifications mocks base method.
+func (m *MockNotificationRepo) AddNotifications(ctx context.Context, notifications []*models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNotifications", ctx, notifications)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNotifications indicates an expected call of AddNotifications.
+func (mr *MockNotificationRepoMockRecorder) AddNotifications(ctx, notifications any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNotifications", reflect.TypeOf((*MockNotificationRepo)(nil).AddNotifications), ctx, notifications)
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockNotificationRepo) CountUnreadNotifications(ctx context.Context, userID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockNotificationRepoMockRecorder) CountUnreadNotifications(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockNotificationRepo)(nil).CountUnreadNotifications), ctx, userID)
+}
+
+// GetNotifications mocks base method.
+func (m *MockNotificationRepo) GetNotifications(ctx context.Context, userID int, unreadOnly bool, page repository.PageArgs) ([]*models.Notification, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotifications", ctx, userID, unreadOnly, page)
+	ret0, _ := ret[0].([]*models.Notification)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetNotifications indicates an expected call of GetNotifications.
+func (mr *MockNotificationRepoMockRecorder) GetNotifications(ctx, userID, unreadOnly, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockNotificationRepo)(nil).GetNotifications), ctx, userID, unreadOnly, page)
+}
+
+// MarkNotificationsRead mocks base method.
+func (m *MockNotificationRepo) MarkNotificationsRead(ctx context.Context, userID int, ids []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationsRead", ctx, userID, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationsRead indicates an expected call of MarkNotificationsRead.
+func (mr *MockNotificationRepoMockRecorder) MarkNotificationsRead(ctx, userID, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplaysByCommentID", reflect.TypeOf((*MockCommentRepo)(nil).GetReplaysByCommentID), ctx, commentID, limit, after)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationsRead", reflect.TypeOf((*MockNotificationRepo)(nil).MarkNotificationsRead), ctx, userID, ids)
 }
 
 // MockUserRepo is a mock of UserRepo interface.
@@ -197,18 +1111,32 @@ func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
 }
 
 // AddUser mocks base method.
-func (m *MockUserRepo) AddUser(ctx context.Context, user *models.User) (int, error) {
+func (m *MockUserRepo) AddUser(ctx context.Context, user *models.User, cred *models.Credentials) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddUser", ctx, user)
+	ret := m.ctrl.Call(m, "AddUser", ctx, user, cred)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddUser indicates an expected call of AddUser.
-func (mr *MockUserRepoMockRecorder) AddUser(ctx, user any) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) AddUser(ctx, user, cred any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockUserRepo)(nil).AddUser), ctx, user, cred)
+}
+
+// BanUser mocks base method.
+func (m *MockUserRepo) BanUser(ctx context.Context, userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BanUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BanUser indicates an expected call of BanUser.
+func (mr *MockUserRepoMockRecorder) BanUser(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockUserRepo)(nil).AddUser), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BanUser", reflect.TypeOf((*MockUserRepo)(nil).BanUser), ctx, userID)
 }
 
 // GetUserByID mocks base method.
@@ -226,17 +1154,87 @@ func (mr *MockUserRepoMockRecorder) GetUserByID(ctx, userID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserRepo)(nil).GetUserByID), ctx, userID)
 }
 
-// GetUserByLoginWithCred mocks base method.
-func (m *MockUserRepo) GetUserByLoginWithCred(ctx context.Context, login string) (*models.User, error) {
+// GetUserByLogin mocks base method.
+func (m *MockUserRepo) GetUserByLogin(ctx context.Context, login string) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByLoginWithCred", ctx, login)
+	ret := m.ctrl.Call(m, "GetUserByLogin", ctx, login)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetUserByLogin indicates an expected call of GetUserByLogin.
+func (mr *MockUserRepoMockRecorder) GetUserByLogin(ctx, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByLogin", reflect.TypeOf((*MockUserRepo)(nil).GetUserByLogin), ctx, login)
+}
+
+// GetUserByLoginWithCred mocks base method.
+func (m *MockUserRepo) GetUserByLoginWithCred(ctx context.Context, login string) (*models.User, *models.Credentials, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByLoginWithCred", ctx, login)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(*models.Credentials)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // GetUserByLoginWithCred indicates an expected call of GetUserByLoginWithCred.
 func (mr *MockUserRepoMockRecorder) GetUserByLoginWithCred(ctx, login any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByLoginWithCred", reflect.TypeOf((*MockUserRepo)(nil).GetUserByLoginWithCred), ctx, login)
 }
+
+// UpdateProfile mocks base method.
+func (m *MockUserRepo) UpdateProfile(ctx context.Context, user *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserRepoMockRecorder) UpdateProfile(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserRepo)(nil).UpdateProfile), ctx, user)
+}
+
+// MockSearchRepo is a mock of SearchRepo interface.
+type MockSearchRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockSearchRepoMockRecorder is the mock recorder for MockSearchRepo.
+type MockSearchRepoMockRecorder struct {
+	mock *MockSearchRepo
+}
+
+// NewMockSearchRepo creates a new mock instance.
+func NewMockSearchRepo(ctrl *gomock.Controller) *MockSearchRepo {
+	mock := &MockSearchRepo{ctrl: ctrl}
+	mock.recorder = &MockSearchRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchRepo) EXPECT() *MockSearchRepoMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockSearchRepo) Search(ctx context.Context, query repository.SearchQuery, page repository.PageArgs) ([]*models.SearchHit, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, page)
+	ret0, _ := ret[0].([]*models.SearchHit)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSearchRepoMockRecorder) Search(ctx, query, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearchRepo)(nil).Search), ctx, query, page)
+}
@@ -0,0 +1,12 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/pkg/markdown"
+)
+
+// HTML is the resolver for the html field.
+func (r *commentResolver) HTML(ctx context.Context, obj *model.Comment) (string, error) {
+	return r.TextRenderer.Render(markdown.Format(obj.TextFormat), obj.Text), nil
+}
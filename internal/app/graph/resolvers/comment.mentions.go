@@ -0,0 +1,29 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// Mentions is the resolver for the mentions field.
+func (r *commentResolver) Mentions(ctx context.Context, obj *model.Comment) ([]*model.User, error) {
+	id, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		r.Logger.Debugf("cant convert commentID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "commentID is not an int")
+	}
+
+	users, err := r.CommentRepo.GetMentions(ctx, id)
+	if err != nil {
+		r.Logger.Debugf("cant get mentions from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	mentions := make([]*model.User, len(users))
+	for i, user := range users {
+		mentions[i] = newUserModel(user)
+	}
+	return mentions, nil
+}
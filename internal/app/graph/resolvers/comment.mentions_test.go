@@ -0,0 +1,104 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_commentResolver_Mentions(t *testing.T) {
+	type resolverFields struct {
+		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		obj            *model.Comment
+		want           []*model.User
+		wantErr        bool
+	}{
+		{
+			name: "commentID is not int",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+			},
+			obj:     &model.Comment{ID: "abc"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetMentions(gomock.Any(), 5).Return(nil, fmt.Errorf("db error"))
+					return cr
+				},
+			},
+			obj:     &model.Comment{ID: "5"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "No mentions",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetMentions(gomock.Any(), 5).Return(nil, nil)
+					return cr
+				},
+			},
+			obj:     &model.Comment{ID: "5"},
+			want:    []*model.User{},
+			wantErr: false,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetMentions(gomock.Any(), 5).Return([]*models.User{
+						{ID: 2, Login: "bob"},
+						{ID: 3, Login: "alice", DisplayName: "Alice"},
+					}, nil)
+					return cr
+				},
+			},
+			obj: &model.Comment{ID: "5"},
+			want: []*model.User{
+				{ID: "2", Username: "bob"},
+				{ID: "3", Username: "alice", DisplayName: func() *string { v := "Alice"; return &v }()},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &commentResolver{
+				Resolver: &Resolver{
+					Logger:      logger.Sugar(),
+					CommentRepo: tt.resolverFields.getCommentRepo(c),
+				},
+			}
+			got, err := r.Mentions(context.Background(), tt.obj)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Mentions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Mentions() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
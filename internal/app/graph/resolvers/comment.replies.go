@@ -2,8 +2,9 @@ package resolvers
 
 import (
 	"context"
-	"fmt"
 	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
 	"strconv"
 )
 
@@ -12,7 +13,7 @@ func (r *commentResolver) Replies(ctx context.Context, obj *model.Comment, limit
 	id, err := strconv.Atoi(obj.ID)
 	if err != nil {
 		r.Logger.Debugf("commentID is not an int")
-		return nil, fmt.Errorf("commentID is not an int")
+		return nil, apperrors.New(apperrors.Validation, "commentID is not an int")
 	}
 	limitInt := 0
 	if limit == nil {
@@ -23,49 +24,35 @@ func (r *commentResolver) Replies(ctx context.Context, obj *model.Comment, limit
 			limitInt = r.Cfg.MaxCommentsLimit
 		}
 	}
-	var afterInt int
-	if after == nil {
-		afterInt = 0
-	} else {
-		afterInt, err = strconv.Atoi(*after)
-		if err != nil {
-			r.Logger.Debugf("cant convert after to int, err: %v", err)
-			return nil, fmt.Errorf("after is not convertable to int")
-		}
+	afterPos, err := r.decodeAfter(after, idOrder)
+	if err != nil {
+		r.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
 	}
 
 	//get data
-	replays, hasNextPage, err := r.CommentRepo.GetReplaysByCommentID(ctx, id, limitInt, afterInt)
+	replays, hasNextPage, err := r.CommentRepo.GetReplaysByCommentID(ctx, id, repository.PageArgs{Limit: limitInt, After: afterPos})
 	if err != nil {
 		r.Logger.Debugf("cant get replays from db error: %v", err)
-		return nil, fmt.Errorf("failed to get replays")
-	}
-
-	var startCursor string
-	var endCursor string
-	if len(replays) > 0 {
-		startCursor = strconv.Itoa(replays[0].ID)
-		endCursor = strconv.Itoa(replays[len(replays)-1].ID)
+		return nil, apperrors.NewInternal(err)
 	}
 
 	//prepare answer
 	edges := make([]*model.CommentEdge, len(replays))
 	for i, replay := range replays {
 		edges[i] = &model.CommentEdge{
-			Cursor: strconv.Itoa(replay.ID),
-			Node: &model.Comment{
-				ID: strconv.Itoa(replay.ID),
-				Owner: &model.User{
-					ID:       strconv.Itoa(replay.Owner.ID),
-					Username: replay.Owner.Login,
-				},
-				Text:      replay.Text,
-				CreatedAt: replay.CreatedAt.String(),
-				Replies:   nil,
-			},
+			Cursor: r.encodeCursor(idOrder, int64(replay.ID), replay.ID),
+			Node:   newCommentModel(replay),
 		}
 	}
 
+	var startCursor string
+	var endCursor string
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
 	return &model.CommentConnection{
 		Edges: edges,
 		PageInfo: &model.PageInfo{
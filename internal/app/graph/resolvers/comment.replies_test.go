@@ -27,6 +27,7 @@ func Test_commentResolver_Replies(t *testing.T) {
 		cfg            cfg.Cfg
 		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
 	}
+	parentID := "10"
 	tests := []struct {
 		name string
 		//fields  fields
@@ -43,7 +44,7 @@ func Test_commentResolver_Replies(t *testing.T) {
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
 					cr := mocks.NewMockCommentRepo(c)
-					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, 20, 0).Return([]*models.Comment{
+					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, repository.PageArgs{Limit: 20}).Return([]*models.Comment{
 						{
 							ID: 6,
 							Owner: models.User{
@@ -76,7 +77,7 @@ func Test_commentResolver_Replies(t *testing.T) {
 					ID:        "10",
 					Owner:     nil,
 					Text:      "",
-					CreatedAt: "",
+					CreatedAt: time.Time{},
 					Replies:   nil,
 				},
 				limit: nil,
@@ -85,39 +86,41 @@ func Test_commentResolver_Replies(t *testing.T) {
 			want: &model.CommentConnection{
 				Edges: []*model.CommentEdge{
 					{
-						Cursor: "6",
+						Cursor: testCursor(6),
 						Node: &model.Comment{
 							ID: "6",
 							Owner: &model.User{
 								ID:       "1",
 								Username: "qwerty",
 							},
-							Text:      "Hello",
-							CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC).String(),
-							Replies:   nil,
+							Text:            "Hello",
+							CreatedAt:       time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+							ParentCommentID: &parentID,
+							Replies:         nil,
 						},
 					},
 					{
-						Cursor: "7",
+						Cursor: testCursor(7),
 						Node: &model.Comment{
 							ID: "7",
 							Owner: &model.User{
 								ID:       "2",
 								Username: "ytrewq",
 							},
-							Text:      "Hi",
-							CreatedAt: time.Date(2020, 10, 30, 0, 0, 1, 0, time.UTC).String(),
-							Replies:   nil,
+							Text:            "Hi",
+							CreatedAt:       time.Date(2020, 10, 30, 0, 0, 1, 0, time.UTC),
+							ParentCommentID: &parentID,
+							Replies:         nil,
 						},
 					},
 				},
 				PageInfo: &model.PageInfo{
 					StartCursor: func() *string {
-						v := "6"
+						v := testCursor(6)
 						return &v
 					}(),
 					EndCursor: func() *string {
-						v := "7"
+						v := testCursor(7)
 						return &v
 					}(),
 					HasNextPage: false,
@@ -133,7 +136,7 @@ func Test_commentResolver_Replies(t *testing.T) {
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
 					cr := mocks.NewMockCommentRepo(c)
-					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, 2, 0).Return([]*models.Comment{
+					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, repository.PageArgs{Limit: 2}).Return([]*models.Comment{
 						{
 							ID: 6,
 							Owner: models.User{
@@ -166,7 +169,7 @@ func Test_commentResolver_Replies(t *testing.T) {
 					ID:        "10",
 					Owner:     nil,
 					Text:      "",
-					CreatedAt: "",
+					CreatedAt: time.Time{},
 					Replies:   nil,
 				},
 				limit: func() *int32 {
@@ -178,39 +181,41 @@ func Test_commentResolver_Replies(t *testing.T) {
 			want: &model.CommentConnection{
 				Edges: []*model.CommentEdge{
 					{
-						Cursor: "6",
+						Cursor: testCursor(6),
 						Node: &model.Comment{
 							ID: "6",
 							Owner: &model.User{
 								ID:       "1",
 								Username: "qwerty",
 							},
-							Text:      "Hello",
-							CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC).String(),
-							Replies:   nil,
+							Text:            "Hello",
+							CreatedAt:       time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+							ParentCommentID: &parentID,
+							Replies:         nil,
 						},
 					},
 					{
-						Cursor: "7",
+						Cursor: testCursor(7),
 						Node: &model.Comment{
 							ID: "7",
 							Owner: &model.User{
 								ID:       "2",
 								Username: "ytrewq",
 							},
-							Text:      "Hi",
-							CreatedAt: time.Date(2020, 10, 30, 0, 0, 1, 0, time.UTC).String(),
-							Replies:   nil,
+							Text:            "Hi",
+							CreatedAt:       time.Date(2020, 10, 30, 0, 0, 1, 0, time.UTC),
+							ParentCommentID: &parentID,
+							Replies:         nil,
 						},
 					},
 				},
 				PageInfo: &model.PageInfo{
 					StartCursor: func() *string {
-						v := "6"
+						v := testCursor(6)
 						return &v
 					}(),
 					EndCursor: func() *string {
-						v := "7"
+						v := testCursor(7)
 						return &v
 					}(),
 					HasNextPage: false,
@@ -226,7 +231,7 @@ func Test_commentResolver_Replies(t *testing.T) {
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
 					cr := mocks.NewMockCommentRepo(c)
-					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, 20, 5).Return(nil, false, fmt.Errorf("Some test err"))
+					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, repository.PageArgs{Limit: 20, After: &repository.Position{Key: 5, ID: 5}}).Return(nil, false, fmt.Errorf("Some test err"))
 					return cr
 				},
 			},
@@ -236,7 +241,7 @@ func Test_commentResolver_Replies(t *testing.T) {
 					ID:        "10",
 					Owner:     nil,
 					Text:      "",
-					CreatedAt: "",
+					CreatedAt: time.Time{},
 					Replies:   nil,
 				},
 				limit: func() *int32 {
@@ -244,7 +249,7 @@ func Test_commentResolver_Replies(t *testing.T) {
 					return &v
 				}(),
 				after: func() *string {
-					v := "5"
+					v := testCursor(5)
 					return &v
 				}(),
 			},
@@ -259,7 +264,7 @@ func Test_commentResolver_Replies(t *testing.T) {
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
 					cr := mocks.NewMockCommentRepo(c)
-					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, 20, 5).Return([]*models.Comment{
+					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, repository.PageArgs{Limit: 20, After: &repository.Position{Key: 5, ID: 5}}).Return([]*models.Comment{
 						{
 							ID: 6,
 							Owner: models.User{
@@ -292,7 +297,7 @@ func Test_commentResolver_Replies(t *testing.T) {
 					ID:        "10",
 					Owner:     nil,
 					Text:      "",
-					CreatedAt: "",
+					CreatedAt: time.Time{},
 					Replies:   nil,
 				},
 				limit: func() *int32 {
@@ -300,46 +305,48 @@ func Test_commentResolver_Replies(t *testing.T) {
 					return &v
 				}(),
 				after: func() *string {
-					v := "5"
+					v := testCursor(5)
 					return &v
 				}(),
 			},
 			want: &model.CommentConnection{
 				Edges: []*model.CommentEdge{
 					{
-						Cursor: "6",
+						Cursor: testCursor(6),
 						Node: &model.Comment{
 							ID: "6",
 							Owner: &model.User{
 								ID:       "1",
 								Username: "qwerty",
 							},
-							Text:      "Hello",
-							CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC).String(),
-							Replies:   nil,
+							Text:            "Hello",
+							CreatedAt:       time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+							ParentCommentID: &parentID,
+							Replies:         nil,
 						},
 					},
 					{
-						Cursor: "7",
+						Cursor: testCursor(7),
 						Node: &model.Comment{
 							ID: "7",
 							Owner: &model.User{
 								ID:       "2",
 								Username: "ytrewq",
 							},
-							Text:      "Hi",
-							CreatedAt: time.Date(2020, 10, 30, 0, 0, 1, 0, time.UTC).String(),
-							Replies:   nil,
+							Text:            "Hi",
+							CreatedAt:       time.Date(2020, 10, 30, 0, 0, 1, 0, time.UTC),
+							ParentCommentID: &parentID,
+							Replies:         nil,
 						},
 					},
 				},
 				PageInfo: &model.PageInfo{
 					StartCursor: func() *string {
-						v := "6"
+						v := testCursor(6)
 						return &v
 					}(),
 					EndCursor: func() *string {
-						v := "7"
+						v := testCursor(7)
 						return &v
 					}(),
 					HasNextPage: false,
@@ -356,6 +363,7 @@ func Test_commentResolver_Replies(t *testing.T) {
 			r := &commentResolver{
 				Resolver: &Resolver{
 					Logger:      sugar,
+					CursorCodec: testCursorCodec,
 					Cfg:         tt.resolverFields.cfg,
 					CommentRepo: tt.resolverFields.getCommentRepo(c),
 				},
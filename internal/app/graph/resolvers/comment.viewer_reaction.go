@@ -0,0 +1,12 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+)
+
+// ViewerReaction is the resolver for the viewerReaction field.
+func (r *commentResolver) ViewerReaction(ctx context.Context, obj *model.Comment) (*model.ReactionKind, error) {
+	return r.viewerReaction(ctx, repository.TargetComment, obj.ID)
+}
@@ -0,0 +1,119 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+	"time"
+)
+
+// approvalRequiredReason is a reason of comments held by CommentsApprovalRequired policy.
+const approvalRequiredReason = "post owner requires approval of comments"
+
+// errCommentsClosed is a cause of the Forbidden error for posts with closed comments,
+// other refusals of the comment policy have no cause.
+var errCommentsClosed = errors.New("comments are closed")
+
+// checkCommentPolicy checks if a user can comment a post by its comment policy and blocks of the post owner.
+// Returns true if the comment must be held for review.
+func (r *Resolver) checkCommentPolicy(ctx context.Context, user *models.User, post *models.Post) (bool, error) {
+	if !post.CommentsAllowed || post.Hidden {
+		r.Logger.Debugf("comments are not allowed to post \"%v\"", post.ID)
+		return false, &apperrors.Error{Code: apperrors.Forbidden, Message: "Comment is not allowed to this post", Cause: errCommentsClosed}
+	}
+	if user.ID == post.Owner.ID {
+		return false, nil
+	}
+
+	blocked, err := r.BlockRepo.IsCommenterBlocked(ctx, post.Owner.ID, user.ID)
+	if err != nil {
+		r.Logger.Debugf("cant check commenter block, err: %v", err)
+		return false, apperrors.NewInternal(err)
+	}
+	if blocked {
+		r.Logger.Debugf("user \"%v\" is blocked by post owner \"%v\"", user.ID, post.Owner.ID)
+		return false, apperrors.New(apperrors.Forbidden, "post owner blocked you from commenting")
+	}
+
+	switch post.CommentPolicy {
+	case models.CommentsFollowersOnly:
+		following, err := r.FollowRepo.IsFollowing(ctx, user.ID, post.Owner.ID)
+		if err != nil {
+			r.Logger.Debugf("cant check follow, err: %v", err)
+			return false, apperrors.NewInternal(err)
+		}
+		if !following {
+			r.Logger.Debugf("user \"%v\" doesnt follow post owner \"%v\"", user.ID, post.Owner.ID)
+			return false, apperrors.New(apperrors.Forbidden, "only followers of the post owner can comment this post")
+		}
+	case models.CommentsRegisteredOlder:
+		if time.Since(user.RegisteredAt) < time.Duration(post.CommentPolicyDays)*24*time.Hour {
+			r.Logger.Debugf("user \"%v\" is registered less than %d days ago", user.ID, post.CommentPolicyDays)
+			return false, apperrors.New(apperrors.Forbidden, "only users registered more than %d days ago can comment this post", post.CommentPolicyDays)
+		}
+	case models.CommentsApprovalRequired:
+		return true, nil
+	}
+	return false, nil
+}
+
+// commentPostID returns an ID of a post a comment or a reply belongs to.
+func (r *Resolver) commentPostID(ctx context.Context, comment *models.Comment) (int, error) {
+	if comment.PostID != 0 {
+		return comment.PostID, nil
+	}
+	return r.CommentRepo.GetCommentPostID(ctx, comment.ParentID)
+}
+
+// setCommentPolicy sets a comment policy of a post of the current user and returns the updated post.
+func (r *Resolver) setCommentPolicy(ctx context.Context, postID string, policy models.CommentPolicy, days int) (*model.Post, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	postIDInt, err := strconv.Atoi(postID)
+	if err != nil {
+		r.Logger.Debugf("cant convert postID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "post id is not int")
+	}
+
+	//check if user is owner of this post
+	post, err := r.PostRepo.GetPostByID(ctx, postIDInt)
+	if err != nil {
+		r.Logger.Debugf("cant get post from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+
+	if user.ID != post.Owner.ID {
+		r.Logger.Debugf("cant modify this post, user is not an owner. UserID is \"%v\", but ownerID is \"%v\"", user.ID, post.Owner.ID)
+		return nil, apperrors.New(apperrors.Forbidden, "cant modify this post")
+	}
+
+	//set policy
+	updatedAt := time.Now()
+	err = r.PostRepo.SetCommentPolicy(ctx, postIDInt, policy, days, updatedAt)
+	if err != nil {
+		r.Logger.Debugf("cant set comment policy, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+
+	//return response
+	post.CommentPolicy = policy
+	post.CommentPolicyDays = days
+	post.CommentsAllowed = policy != models.CommentsClosed
+	post.UpdatedAt = updatedAt
+	return newPostModel(post), nil
+}
@@ -0,0 +1,115 @@
+package resolvers
+
+import (
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/models"
+	"strconv"
+)
+
+// newUserModel converts a user profile into a GraphQL model.
+func newUserModel(user *models.User) *model.User {
+	return &model.User{
+		ID:           strconv.Itoa(user.ID),
+		Username:     user.Login,
+		DisplayName:  optionalString(user.DisplayName),
+		Bio:          optionalString(user.Bio),
+		AvatarURL:    optionalString(user.AvatarURL),
+		RegisteredAt: user.RegisteredAt,
+	}
+}
+
+// optionalString returns nil for an empty string.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// newPostModel converts a post into a GraphQL model. Post`s comments are resolved separately.
+// Title and text of a hidden post are not exposed.
+func newPostModel(post *models.Post) *model.Post {
+	m := &model.Post{
+		ID:                strconv.Itoa(post.ID),
+		Title:             post.Title,
+		Text:              post.Text,
+		TextFormat:        model.TextFormat(post.TextFormat),
+		Owner:             newUserModel(&post.Owner),
+		CommentsAllowed:   post.CommentsAllowed,
+		CreatedAt:         post.CreatedAt,
+		UpdatedAt:         post.UpdatedAt,
+		LastCommentAt:     post.LastCommentAt,
+		CommentCount:      int32(post.CommentsCount),
+		Tags:              post.Tags,
+		Reactions:         newReactionsModel(post.Reactions),
+		Score:             int32(post.Reactions.Score()),
+		Status:            model.PostStatus(post.Status),
+		PublishAt:         post.PublishAt,
+		ModerationReasons: post.ModerationReasons,
+		Hidden:            post.Hidden,
+		CommentPolicy:     model.CommentPolicy(post.CommentPolicy),
+		CommentPolicyDays: int32(post.CommentPolicyDays),
+	}
+	if post.Hidden {
+		m.Title = ""
+		m.Text = ""
+	}
+	return m
+}
+
+// newNotificationModel converts a notification into a GraphQL model.
+func newNotificationModel(notification *models.Notification) *model.Notification {
+	return &model.Notification{
+		ID:        strconv.Itoa(notification.ID),
+		Type:      model.NotificationType(notification.Type),
+		Actor:     newUserModel(&notification.Comment.Owner),
+		Comment:   newCommentModel(&notification.Comment),
+		CreatedAt: notification.CreatedAt,
+		Read:      notification.Read,
+	}
+}
+
+// newTagModel converts a tag into a GraphQL model. Tag`s posts are resolved separately.
+func newTagModel(tag *models.Tag) *model.Tag {
+	return &model.Tag{
+		Name:      tag.Name,
+		PostCount: int32(tag.PostsCount),
+	}
+}
+
+// newCommentModel converts a comment into a GraphQL model. Comment`s replies are resolved separately.
+// Text of a hidden comment is not exposed.
+func newCommentModel(comment *models.Comment) *model.Comment {
+	m := &model.Comment{
+		ID:              strconv.Itoa(comment.ID),
+		Owner:           newUserModel(&comment.Owner),
+		Text:            comment.Text,
+		TextFormat:      model.TextFormat(comment.TextFormat),
+		CreatedAt:       comment.CreatedAt,
+		Depth:           int32(comment.Depth),
+		ReplyCount:      int32(comment.RepliesCount),
+		DescendantCount: int32(comment.DescendantsCount),
+		Reactions:       newReactionsModel(comment.Reactions),
+		Score:           int32(comment.Reactions.Score()),
+		Hidden:          comment.Hidden,
+	}
+	if comment.ParentID != 0 {
+		parentID := strconv.Itoa(comment.ParentID)
+		m.ParentCommentID = &parentID
+	}
+	if comment.Hidden {
+		m.Text = ""
+	}
+	return m
+}
+
+// newReactionsModel converts reactions into non-zero amounts in display order, nil if there are no reactions.
+func newReactionsModel(reactions models.Reactions) []*model.ReactionCount {
+	var counts []*model.ReactionCount
+	for _, kind := range models.ReactionKinds {
+		if count := reactions[kind]; count > 0 {
+			counts = append(counts, &model.ReactionCount{Kind: model.ReactionKind(kind), Count: int32(count)})
+		}
+	}
+	return counts
+}
@@ -0,0 +1,102 @@
+package resolvers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"time"
+	"unicode/utf8"
+)
+
+// maxClientMutationIDLength is a width of the idempotency_keys.key column in Postgres.
+const maxClientMutationIDLength = 255
+
+// idempotencyLease is how long a key is reserved for a request in progress, so a key of a request lost by a crashed
+// replica is released soon instead of being "in progress" until Cfg.IdempotencyKeyTTL ends.
+const idempotencyLease = time.Minute
+
+// requestFingerprint returns a hash of a mutation name and its arguments.
+func requestFingerprint(request []any) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// idempotent runs create once per clientMutationID of the current user within Cfg.IdempotencyKeyTTL,
+// replays with the same request get the stored result of the first run.
+// request is a mutation name followed by its arguments, a replay with another request is a Conflict error.
+// Failed runs are not stored. create runs as is without clientMutationID or the current user.
+func idempotent[T any](ctx context.Context, r *Resolver, clientMutationID *string, request []any, create func() (*T, error)) (*T, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if clientMutationID == nil || !ok {
+		return create()
+	}
+	if *clientMutationID == "" || utf8.RuneCountInString(*clientMutationID) > maxClientMutationIDLength {
+		r.Logger.Debugf("invalid clientMutationId length")
+		return nil, apperrors.New(apperrors.Validation, "clientMutationId must be from 1 to %d characters long", maxClientMutationIDLength)
+	}
+	fingerprint, err := requestFingerprint(request)
+	if err != nil {
+		r.Logger.Debugf("cant get request fingerprint, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	now := time.Now()
+	record := &models.IdempotencyRecord{
+		UserID:      user.ID,
+		Key:         *clientMutationID,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(idempotencyLease),
+	}
+	stored, err := r.IdempotencyRepo.ReserveIdempotencyKey(ctx, record, now)
+	if err != nil {
+		r.Logger.Debugf("cant reserve idempotency key, err: %v", err)
+		if errors.Is(err, repository.NewErrConflict()) {
+			return nil, apperrors.New(apperrors.Conflict, "request with this clientMutationId is in progress")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	if stored != nil {
+		if stored.Fingerprint != fingerprint {
+			r.Logger.Debugf("clientMutationId is reused with another request")
+			return nil, apperrors.New(apperrors.Conflict, "clientMutationId is already used for another request")
+		}
+		if stored.Result == nil {
+			r.Logger.Debugf("request with the clientMutationId is in progress")
+			return nil, apperrors.New(apperrors.Conflict, "request with this clientMutationId is in progress")
+		}
+		result := new(T)
+		if err := json.Unmarshal(stored.Result, result); err != nil {
+			r.Logger.Debugf("cant decode stored result, err: %v", err)
+			return nil, apperrors.NewInternal(err)
+		}
+		return result, nil
+	}
+
+	result, err := create()
+	if err != nil {
+		if releaseErr := r.IdempotencyRepo.ReleaseIdempotencyKey(ctx, user.ID, *clientMutationID); releaseErr != nil {
+			r.Logger.Warnf("cant release idempotency key, err: %v", releaseErr)
+		}
+		return nil, err
+	}
+	// the result is created, so failing to store it is not an error of the request
+	record.Result, err = json.Marshal(result)
+	record.ExpiresAt = time.Now().Add(r.Cfg.IdempotencyKeyTTL)
+	if err == nil {
+		err = r.IdempotencyRepo.SaveIdempotencyResult(ctx, record)
+	}
+	if err != nil {
+		r.Logger.Warnf("cant save idempotency result, err: %v", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,236 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_idempotent(t *testing.T) {
+	type args struct {
+		ctx              context.Context
+		clientMutationID *string
+		request          []any
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	key := func(v string) *string { return &v }
+	request := []any{"addPost", "Title", "Text"}
+	fingerprint, err := requestFingerprint(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := &model.AddPostResponse{Post: &model.Post{
+		ID:        "42",
+		Title:     "Title",
+		Text:      "Text",
+		Owner:     &model.User{ID: "1", Username: "qwerty"},
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Status:    model.PostStatusPublished,
+	}}
+	createdJSON, err := json.Marshal(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name               string
+		getIdempotencyRepo func(c *gomock.Controller) repository.IdempotencyRepo
+		args               args
+		createErr          error
+		wantCreated        bool
+		want               *model.AddPostResponse
+		wantCode           apperrors.Code
+	}{
+		{
+			name: "Without clientMutationId",
+			getIdempotencyRepo: func(c *gomock.Controller) repository.IdempotencyRepo {
+				return mocks.NewMockIdempotencyRepo(c)
+			},
+			args:        args{ctx: authCtx(), request: request},
+			wantCreated: true,
+			want:        created,
+		},
+		{
+			name: "Empty clientMutationId",
+			getIdempotencyRepo: func(c *gomock.Controller) repository.IdempotencyRepo {
+				return mocks.NewMockIdempotencyRepo(c)
+			},
+			args:     args{ctx: authCtx(), clientMutationID: key(""), request: request},
+			wantCode: apperrors.Validation,
+		},
+		{
+			name: "First request",
+			getIdempotencyRepo: func(c *gomock.Controller) repository.IdempotencyRepo {
+				ir := mocks.NewMockIdempotencyRepo(c)
+				var reservedAt time.Time
+				ir.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, record *models.IdempotencyRecord, now time.Time) (*models.IdempotencyRecord, error) {
+						if record.UserID != 1 || record.Key != "k1" || record.Fingerprint != fingerprint || !record.ExpiresAt.Equal(now.Add(idempotencyLease)) {
+							t.Errorf("unexpected record: %+v", record)
+						}
+						reservedAt = now
+						return nil, nil
+					},
+				)
+				ir.EXPECT().SaveIdempotencyResult(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, record *models.IdempotencyRecord) error {
+						if string(record.Result) != string(createdJSON) {
+							t.Errorf("unexpected result: %s", record.Result)
+						}
+						if record.ExpiresAt.Before(reservedAt.Add(time.Hour)) {
+							t.Errorf("result expires at %v, want TTL after %v", record.ExpiresAt, reservedAt)
+						}
+						return nil
+					},
+				)
+				return ir
+			},
+			args:        args{ctx: authCtx(), clientMutationID: key("k1"), request: request},
+			wantCreated: true,
+			want:        created,
+		},
+		{
+			name: "Failed to save result",
+			getIdempotencyRepo: func(c *gomock.Controller) repository.IdempotencyRepo {
+				ir := mocks.NewMockIdempotencyRepo(c)
+				ir.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				ir.EXPECT().SaveIdempotencyResult(gomock.Any(), gomock.Any()).Return(fmt.Errorf("db error"))
+				return ir
+			},
+			args:        args{ctx: authCtx(), clientMutationID: key("k1"), request: request},
+			wantCreated: true,
+			want:        created,
+		},
+		{
+			name: "Replay",
+			getIdempotencyRepo: func(c *gomock.Controller) repository.IdempotencyRepo {
+				ir := mocks.NewMockIdempotencyRepo(c)
+				ir.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+					&models.IdempotencyRecord{UserID: 1, Key: "k1", Fingerprint: fingerprint, Result: createdJSON}, nil)
+				return ir
+			},
+			args: args{ctx: authCtx(), clientMutationID: key("k1"), request: request},
+			want: created,
+		},
+		{
+			name: "Replay with another payload",
+			getIdempotencyRepo: func(c *gomock.Controller) repository.IdempotencyRepo {
+				ir := mocks.NewMockIdempotencyRepo(c)
+				ir.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+					&models.IdempotencyRecord{UserID: 1, Key: "k1", Fingerprint: "other", Result: createdJSON}, nil)
+				return ir
+			},
+			args:     args{ctx: authCtx(), clientMutationID: key("k1"), request: request},
+			wantCode: apperrors.Conflict,
+		},
+		{
+			name: "First request is in progress",
+			getIdempotencyRepo: func(c *gomock.Controller) repository.IdempotencyRepo {
+				ir := mocks.NewMockIdempotencyRepo(c)
+				ir.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+					&models.IdempotencyRecord{UserID: 1, Key: "k1", Fingerprint: fingerprint}, nil)
+				return ir
+			},
+			args:     args{ctx: authCtx(), clientMutationID: key("k1"), request: request},
+			wantCode: apperrors.Conflict,
+		},
+		{
+			name: "Stored record is released during reservation",
+			getIdempotencyRepo: func(c *gomock.Controller) repository.IdempotencyRepo {
+				ir := mocks.NewMockIdempotencyRepo(c)
+				ir.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, repository.NewErrConflict())
+				return ir
+			},
+			args:     args{ctx: authCtx(), clientMutationID: key("k1"), request: request},
+			wantCode: apperrors.Conflict,
+		},
+		{
+			name: "Failed to reserve",
+			getIdempotencyRepo: func(c *gomock.Controller) repository.IdempotencyRepo {
+				ir := mocks.NewMockIdempotencyRepo(c)
+				ir.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("db error"))
+				return ir
+			},
+			args:     args{ctx: authCtx(), clientMutationID: key("k1"), request: request},
+			wantCode: apperrors.Internal,
+		},
+		{
+			name: "Failed request is released",
+			getIdempotencyRepo: func(c *gomock.Controller) repository.IdempotencyRepo {
+				ir := mocks.NewMockIdempotencyRepo(c)
+				ir.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				ir.EXPECT().ReleaseIdempotencyKey(gomock.Any(), 1, "k1").Return(nil)
+				return ir
+			},
+			args:        args{ctx: authCtx(), clientMutationID: key("k1"), request: request},
+			createErr:   apperrors.New(apperrors.Validation, "invalid input"),
+			wantCreated: true,
+			wantCode:    apperrors.Validation,
+		},
+		{
+			name: "Failed to release failed request",
+			getIdempotencyRepo: func(c *gomock.Controller) repository.IdempotencyRepo {
+				ir := mocks.NewMockIdempotencyRepo(c)
+				ir.EXPECT().ReserveIdempotencyKey(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				ir.EXPECT().ReleaseIdempotencyKey(gomock.Any(), 1, "k1").Return(fmt.Errorf("db error"))
+				return ir
+			},
+			args:        args{ctx: authCtx(), clientMutationID: key("k1"), request: request},
+			createErr:   apperrors.New(apperrors.Validation, "invalid input"),
+			wantCreated: true,
+			wantCode:    apperrors.Validation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			sugar := logger.Sugar()
+			c := gomock.NewController(t)
+			r := &Resolver{
+				Logger:          sugar,
+				Cfg:             cfg.Cfg{IdempotencyKeyTTL: time.Hour},
+				IdempotencyRepo: tt.getIdempotencyRepo(c),
+			}
+			isCreated := false
+			got, err := idempotent(tt.args.ctx, r, tt.args.clientMutationID, tt.args.request, func() (*model.AddPostResponse, error) {
+				isCreated = true
+				if tt.createErr != nil {
+					return nil, tt.createErr
+				}
+				return created, nil
+			})
+			if isCreated != tt.wantCreated {
+				t.Errorf("idempotent() created = %v, want %v", isCreated, tt.wantCreated)
+			}
+			var appErr *apperrors.Error
+			if tt.wantCode != "" {
+				if !errors.As(err, &appErr) || appErr.Code != tt.wantCode {
+					t.Errorf("idempotent() error = %v, wantCode %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("idempotent() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("idempotent() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
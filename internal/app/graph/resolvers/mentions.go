@@ -0,0 +1,71 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxMentions is a maximum amount of users mentioned in a comment, further mentions are left as plain text.
+const maxMentions = 10
+
+// isMentionRune reports whether a rune can be a part of a mentioned login.
+func isMentionRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+// parseMentions returns unique logins mentioned in a text as "@login" in order of appearance, at most max of them.
+// A mention must not follow a letter, digit or "_" (so e-mails are not mentions), trailing dots are not a part of a login.
+func parseMentions(text string, max int) []string {
+	var logins []string
+	seen := make(map[string]bool)
+	prev := ' '
+	for i := 0; i < len(text) && len(logins) < max; {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if r != '@' || unicode.IsLetter(prev) || unicode.IsDigit(prev) || prev == '_' {
+			prev = r
+			continue
+		}
+		prev = r
+
+		end := i
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if !isMentionRune(r) {
+				break
+			}
+			end += size
+		}
+		login := strings.TrimRight(text[i:end], ".")
+		if login != "" && !seen[login] {
+			seen[login] = true
+			logins = append(logins, login)
+		}
+		if end > i {
+			prev, _ = utf8.DecodeLastRuneInString(text[i:end])
+			i = end
+		}
+	}
+	return logins
+}
+
+// resolveMentions returns existing users mentioned in a text, unknown logins are ignored.
+func (r *Resolver) resolveMentions(ctx context.Context, text string) ([]models.User, error) {
+	var users []models.User
+	for _, login := range parseMentions(text, maxMentions) {
+		user, err := r.UserRepo.GetUserByLogin(ctx, login)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	return users, nil
+}
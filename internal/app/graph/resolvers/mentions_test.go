@@ -0,0 +1,71 @@
+package resolvers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		max  int
+		want []string
+	}{
+		{
+			name: "No mentions",
+			text: "Hello, world",
+			max:  10,
+			want: nil,
+		},
+		{
+			name: "Mentions in order of appearance",
+			text: "@bob and @alice_1, look",
+			max:  10,
+			want: []string{"bob", "alice_1"},
+		},
+		{
+			name: "Duplicates",
+			text: "@bob @bob @Bob",
+			max:  10,
+			want: []string{"bob", "Bob"},
+		},
+		{
+			name: "Trailing dots",
+			text: "thanks @john.doe.",
+			max:  10,
+			want: []string{"john.doe"},
+		},
+		{
+			name: "E-mail is not a mention",
+			text: "write to mail@example.com (@admin)",
+			max:  10,
+			want: []string{"admin"},
+		},
+		{
+			name: "Unicode login",
+			text: "привет, @Вася!",
+			max:  10,
+			want: []string{"Вася"},
+		},
+		{
+			name: "Lone at sign",
+			text: "meet @ 5pm, @@bob",
+			max:  10,
+			want: []string{"bob"},
+		},
+		{
+			name: "Max mentions",
+			text: "@a @b @c",
+			max:  2,
+			want: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseMentions(tt.text, tt.max); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMentions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
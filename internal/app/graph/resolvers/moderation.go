@@ -0,0 +1,82 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"ozon_test_task/pkg/moderation"
+	"slices"
+	"strconv"
+)
+
+// moderate checks texts with the moderation pipeline, everything is allowed if moderation is disabled.
+func (r *Resolver) moderate(texts ...string) moderation.Decision {
+	if r.Moderator == nil {
+		return moderation.Decision{Verdict: moderation.Allow}
+	}
+	return r.Moderator.Moderate(texts...)
+}
+
+// isModerator returns true if a user is listed in moderators.
+func (r *Resolver) isModerator(user *models.User) bool {
+	return user != nil && slices.Contains(r.Cfg.Moderators, user.Login)
+}
+
+// checkHeldCommentsReviewer checks that a user can review held comments of a post thread,
+// moderators review all of them and post owners review comments of their posts.
+func (r *Resolver) checkHeldCommentsReviewer(ctx context.Context, user *models.User, rootPostID int) error {
+	if r.isModerator(user) {
+		return nil
+	}
+	if rootPostID == 0 {
+		r.Logger.Debugf("user \"%v\" is not a moderator", user.Login)
+		return apperrors.New(apperrors.Forbidden, "moderators only")
+	}
+	post, err := r.PostRepo.GetPostByID(ctx, rootPostID)
+	if err != nil {
+		r.Logger.Debugf("cant get post from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return apperrors.NewInternal(err)
+	}
+	if user.ID != post.Owner.ID {
+		r.Logger.Debugf("user \"%v\" is neither a moderator nor a post owner, ownerID is \"%v\"", user.ID, post.Owner.ID)
+		return apperrors.New(apperrors.Forbidden, "moderators and the post owner only")
+	}
+	return nil
+}
+
+// newHeldCommentModel converts a held comment into a GraphQL model.
+func newHeldCommentModel(held *models.HeldComment) *model.HeldComment {
+	c := &held.Comment
+	m := &model.HeldComment{
+		ID:         strconv.Itoa(held.ID),
+		Owner:      newUserModel(&c.Owner),
+		Text:       c.Text,
+		TextFormat: model.TextFormat(c.TextFormat),
+		CreatedAt:  c.CreatedAt,
+		Reasons:    held.Reasons,
+	}
+	if c.PostID != 0 {
+		postID := strconv.Itoa(c.PostID)
+		m.PostID = &postID
+	}
+	if c.ParentID != 0 {
+		parentID := strconv.Itoa(c.ParentID)
+		m.ParentCommentID = &parentID
+	}
+	return m
+}
+
+// heldForReviewError is returned by deprecated mutations for comments held by moderation, their responses can`t contain them.
+func heldForReviewError(held *model.HeldComment) error {
+	return &apperrors.Error{
+		Code:       apperrors.Forbidden,
+		Message:    "comment is held for review",
+		Extensions: map[string]any{"heldCommentID": held.ID},
+	}
+}
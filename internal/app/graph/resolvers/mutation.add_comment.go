@@ -2,70 +2,115 @@ package resolvers
 
 import (
 	"context"
-	"fmt"
-	"github.com/vektah/gqlparser/v2/gqlerror"
+	"errors"
 	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
 	"ozon_test_task/internal/app/middlewares"
 	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"ozon_test_task/pkg/moderation"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // AddComment is the resolver for the addComment field.
-func (r *mutationResolver) AddComment(ctx context.Context, postID string, text string) (*model.AddCommentResponse, error) {
+func (r *mutationResolver) AddComment(ctx context.Context, postID string, text string, textFormat model.TextFormat, clientMutationID *string) (*model.AddCommentResponse, error) {
+	result, err := r.addComment(ctx, postID, text, textFormat, clientMutationID)
+	if err != nil {
+		return nil, err
+	}
+	if result.HeldComment != nil {
+		return nil, heldForReviewError(result.HeldComment)
+	}
+	return &model.AddCommentResponse{Comment: result.Comment, Error: ""}, nil
+}
+
+// addComment adds a comment of the current user to a post once per clientMutationID.
+func (r *mutationResolver) addComment(ctx context.Context, postID string, text string, textFormat model.TextFormat, clientMutationID *string) (*model.AddCommentSuccess, error) {
+	return idempotent(ctx, r.Resolver, clientMutationID, []any{"addComment", postID, text, textFormat}, func() (*model.AddCommentSuccess, error) {
+		return r.addCommentOnce(ctx, postID, text, textFormat)
+	})
+}
+
+// addCommentOnce adds a comment of the current user to a post.
+func (r *mutationResolver) addCommentOnce(ctx context.Context, postID string, text string, textFormat model.TextFormat) (*model.AddCommentSuccess, error) {
 	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
 	if !ok {
 		r.Logger.Debugf("Cant get user from context")
-		return nil, gqlerror.Errorf("Not authorized")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
 	}
 
 	postIDInt, err := strconv.Atoi(postID)
 	if err != nil {
 		r.Logger.Debugf("Cant convert postID to int, err: %v", err)
-		return nil, fmt.Errorf("postID is not int")
+		return nil, apperrors.New(apperrors.Validation, "postID is not int")
 	}
 
-	if len(text) > r.Cfg.MaxCommentTextLength {
-		r.Logger.Debugf("Max comment length exceeded, current len is \"%v\", max is \"%v\"", len(text), r.Cfg.MaxCommentTextLength)
-		return nil, fmt.Errorf("comment text too long, max lenght: %d", r.Cfg.MaxCommentTextLength)
+	if err := r.validateCommentText(text); err != nil {
+		r.Logger.Debugf("invalid comment, err: %v", err)
+		return nil, validationError(err)
 	}
 
 	comment := &models.Comment{
-		Owner:     *user,
-		Text:      text,
-		CreatedAt: time.Now(),
-		PostID:    postIDInt,
+		Owner:      *user,
+		Text:       text,
+		TextFormat: models.TextFormat(textFormat),
+		CreatedAt:  time.Now(),
+		PostID:     postIDInt,
 	}
 
 	//check if comments are allowed
 	post, err := r.PostRepo.GetPostByID(ctx, postIDInt)
 	if err != nil {
 		r.Logger.Debugf("Cant get post from db, err: %v", err)
-		return nil, fmt.Errorf("post not found")
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return nil, apperrors.NewInternal(err)
 	}
-	if !post.CommentsAllowed {
-		r.Logger.Debugf("Comments are not allowed to this post")
-		return nil, gqlerror.Errorf("Comment is not allowed to this post")
+	if post.Status != models.PostPublished {
+		r.Logger.Debugf("Post is not published")
+		return nil, apperrors.New(apperrors.NotFound, "post not found")
+	}
+	needsApproval, err := r.checkCommentPolicy(ctx, user, post)
+	if err != nil {
+		return nil, err
 	}
 
-	commentID, err := r.CommentRepo.AddComment(ctx, comment)
+	decision := r.moderate(text)
+	if needsApproval && decision.Verdict != moderation.Reject {
+		decision.Verdict = moderation.Hold
+		decision.Reasons = append(decision.Reasons, approvalRequiredReason)
+	}
+	switch decision.Verdict {
+	case moderation.Reject:
+		r.Logger.Debugf("Comment is rejected by moderation, reasons: %v", decision.Reasons)
+		return nil, apperrors.New(apperrors.Validation, "comment is rejected by moderation: %s", strings.Join(decision.Reasons, "; "))
+	case moderation.Hold:
+		r.Logger.Debugf("Comment is held by moderation, reasons: %v", decision.Reasons)
+		held := &models.HeldComment{Comment: *comment, RootPostID: post.ID, Reasons: decision.Reasons}
+		held.ID, err = r.ModerationRepo.HoldComment(ctx, held)
+		if err != nil {
+			r.Logger.Debugf("Cant hold comment, err: %v", err)
+			return nil, apperrors.NewInternal(err)
+		}
+		return &model.AddCommentSuccess{HeldComment: newHeldCommentModel(held)}, nil
+	}
+
+	comment.Mentions, err = r.resolveMentions(ctx, text)
 	if err != nil {
-		r.Logger.Debugf("Cant add comment to db, err: %v", err)
-		return nil, fmt.Errorf("failed to create a comment")
+		r.Logger.Debugf("Cant resolve mentions, err: %v", err)
+		return nil, apperrors.NewInternal(err)
 	}
 
-	commentModel := &model.Comment{
-		ID: strconv.Itoa(commentID),
-		Owner: &model.User{
-			ID:       strconv.Itoa(comment.Owner.ID),
-			Username: comment.Owner.Login,
-		},
-		Text:      comment.Text,
-		CreatedAt: comment.CreatedAt.String(),
+	commentID, err := r.CommentRepo.AddComment(ctx, comment)
+	if err != nil {
+		r.Logger.Debugf("Cant add comment to db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
 	}
 
-	return &model.AddCommentResponse{
-		Comment: commentModel,
-		Error:   "",
-	}, nil
+	comment.ID = commentID
+	r.notifyAboutComment(ctx, comment, post.Owner.ID, 0)
+	return &model.AddCommentSuccess{Comment: newCommentModel(comment)}, nil
 }
@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap/zaptest"
@@ -11,8 +12,11 @@ import (
 	"ozon_test_task/internal/app/graph/repository/mocks"
 	"ozon_test_task/internal/app/middlewares"
 	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"ozon_test_task/pkg/moderation"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_mutationResolver_AddComment(t *testing.T) {
@@ -25,6 +29,40 @@ func Test_mutationResolver_AddComment(t *testing.T) {
 		cfg            cfg.Cfg
 		getPostRepo    func(c *gomock.Controller) repository.PostRepo
 		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+		getUserRepo    func(c *gomock.Controller) repository.UserRepo
+		getNotifRepo   func(c *gomock.Controller) repository.NotificationRepo
+		getModRepo     func(c *gomock.Controller) repository.ModerationRepo
+		getBlockRepo   func(c *gomock.Controller) repository.BlockRepo
+		getFollowRepo  func(c *gomock.Controller) repository.FollowRepo
+		moderator      *moderation.Pipeline
+	}
+	publishedPostRepo := func(c *gomock.Controller) repository.PostRepo {
+		pr := mocks.NewMockPostRepo(c)
+		pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(&models.Post{
+			ID:              1,
+			Status:          models.PostPublished,
+			Owner:           models.User{ID: 5, Login: "owner"},
+			CommentsAllowed: true,
+		}, nil)
+		return pr
+	}
+	policyPostRepo := func(policy models.CommentPolicy, days int) func(c *gomock.Controller) repository.PostRepo {
+		return func(c *gomock.Controller) repository.PostRepo {
+			pr := mocks.NewMockPostRepo(c)
+			pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(&models.Post{
+				ID:                1,
+				Status:            models.PostPublished,
+				Owner:             models.User{ID: 5, Login: "owner"},
+				CommentsAllowed:   true,
+				CommentPolicy:     policy,
+				CommentPolicyDays: days,
+			}, nil)
+			return pr
+		}
+	}
+	noCommentRepo := func(c *gomock.Controller) repository.CommentRepo { return mocks.NewMockCommentRepo(c) }
+	authCtx := func(user *models.User) context.Context {
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
 	}
 	tests := []struct {
 		name           string
@@ -32,6 +70,8 @@ func Test_mutationResolver_AddComment(t *testing.T) {
 		args           args
 		want           *model.AddCommentResponse
 		wantErr        bool
+		// wantHeldID is an ID of a held comment returned in the error
+		wantHeldID string
 	}{
 		{
 			name: "Not authorized",
@@ -74,6 +114,30 @@ func Test_mutationResolver_AddComment(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Comment text with control characters",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					MaxCommentTextLength: 10,
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					return mocks.NewMockPostRepo(c)
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				postID: "1",
+				text:   "Hi\x00",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Comment text too long",
 			resolverFields: resolverFields{
@@ -106,7 +170,63 @@ func Test_mutationResolver_AddComment(t *testing.T) {
 				},
 				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
 					pr := mocks.NewMockPostRepo(c)
-					pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(nil, fmt.Errorf("not found"))
+					pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(nil, repository.NewErrNotFound())
+					return pr
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				postID: "1",
+				text:   "Hello",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Failed to get post",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					MaxCommentTextLength: 100,
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(nil, fmt.Errorf("db error"))
+					return pr
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				postID: "1",
+				text:   "Hello",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Post is a draft",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					MaxCommentTextLength: 100,
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(&models.Post{
+						ID:              1,
+						Status:          models.PostDraft,
+						CommentsAllowed: true,
+					}, nil)
 					return pr
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
@@ -134,6 +254,7 @@ func Test_mutationResolver_AddComment(t *testing.T) {
 					pr := mocks.NewMockPostRepo(c)
 					pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(&models.Post{
 						ID:              1,
+						Status:          models.PostPublished,
 						CommentsAllowed: false,
 					}, nil)
 					return pr
@@ -153,6 +274,37 @@ func Test_mutationResolver_AddComment(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Post is hidden",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					MaxCommentTextLength: 100,
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(&models.Post{
+						ID:              1,
+						Status:          models.PostPublished,
+						CommentsAllowed: true,
+						Hidden:          true,
+					}, nil)
+					return pr
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				postID: "1",
+				text:   "Hello",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Failed to create comment",
 			resolverFields: resolverFields{
@@ -163,6 +315,7 @@ func Test_mutationResolver_AddComment(t *testing.T) {
 					pr := mocks.NewMockPostRepo(c)
 					pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(&models.Post{
 						ID:              1,
+						Status:          models.PostPublished,
 						CommentsAllowed: true,
 					}, nil)
 					return pr
@@ -194,6 +347,90 @@ func Test_mutationResolver_AddComment(t *testing.T) {
 					pr := mocks.NewMockPostRepo(c)
 					pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(&models.Post{
 						ID:              1,
+						Status:          models.PostPublished,
+						CommentsAllowed: true,
+					}, nil)
+					return pr
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, comment *models.Comment) (int, error) {
+							return 123, nil
+						},
+					)
+					return cr
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				postID: "1",
+				text:   "Hello",
+			},
+			want: &model.AddCommentResponse{
+				Comment: &model.Comment{
+					ID: "123",
+					Owner: &model.User{
+						ID:       "1",
+						Username: "qwerty",
+					},
+					Text:       "Hello",
+					TextFormat: model.TextFormatPlain,
+					CreatedAt:  time.Time{},
+				},
+				Error: "",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failed to resolve mentions",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					MaxCommentTextLength: 100,
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(&models.Post{
+						ID:              1,
+						Status:          models.PostPublished,
+						CommentsAllowed: true,
+					}, nil)
+					return pr
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByLogin(gomock.Any(), "bob").Return(nil, fmt.Errorf("db error"))
+					return ur
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				postID: "1",
+				text:   "Hello @bob",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Mentions",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					MaxCommentTextLength: 100,
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(&models.Post{
+						ID:              1,
+						Status:          models.PostPublished,
 						CommentsAllowed: true,
 					}, nil)
 					return pr
@@ -202,11 +439,89 @@ func Test_mutationResolver_AddComment(t *testing.T) {
 					cr := mocks.NewMockCommentRepo(c)
 					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).DoAndReturn(
 						func(ctx context.Context, comment *models.Comment) (int, error) {
+							if !reflect.DeepEqual(comment.Mentions, []models.User{{ID: 2, Login: "bob"}}) {
+								return 0, fmt.Errorf("unexpected mentions: %v", comment.Mentions)
+							}
 							return 123, nil
 						},
 					)
 					return cr
 				},
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByLogin(gomock.Any(), "bob").Return(&models.User{ID: 2, Login: "bob"}, nil)
+					ur.EXPECT().GetUserByLogin(gomock.Any(), "ghost").Return(nil, repository.NewErrNotFound())
+					return ur
+				},
+				getNotifRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, notifications []*models.Notification) error {
+							if len(notifications) != 1 || notifications[0].UserID != 2 || notifications[0].Type != models.NotificationMention {
+								return fmt.Errorf("unexpected notifications: %v", notifications)
+							}
+							return nil
+						},
+					)
+					return nr
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				postID: "1",
+				text:   "Hello @bob and @ghost",
+			},
+			want: &model.AddCommentResponse{
+				Comment: &model.Comment{
+					ID: "123",
+					Owner: &model.User{
+						ID:       "1",
+						Username: "qwerty",
+					},
+					Text:       "Hello @bob and @ghost",
+					TextFormat: model.TextFormatPlain,
+					CreatedAt:  time.Time{},
+				},
+				Error: "",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Post owner notification failed",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					MaxCommentTextLength: 100,
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(&models.Post{
+						ID:              1,
+						Status:          models.PostPublished,
+						Owner:           models.User{ID: 5, Login: "owner"},
+						CommentsAllowed: true,
+					}, nil)
+					return pr
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).Return(123, nil)
+					return cr
+				},
+				getNotifRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, notifications []*models.Notification) error {
+							if len(notifications) != 1 || notifications[0].UserID != 5 || notifications[0].Type != models.NotificationComment {
+								t.Errorf("unexpected notifications: %v", notifications)
+							}
+							return fmt.Errorf("some db error")
+						},
+					)
+					return nr
+				},
 			},
 			args: args{
 				ctx: func() context.Context {
@@ -223,35 +538,208 @@ func Test_mutationResolver_AddComment(t *testing.T) {
 						ID:       "1",
 						Username: "qwerty",
 					},
-					Text:      "Hello",
-					CreatedAt: "",
+					Text:       "Hello",
+					TextFormat: model.TextFormatPlain,
+					CreatedAt:  time.Time{},
 				},
 				Error: "",
 			},
 			wantErr: false,
 		},
+		{
+			name: "Rejected by moderation",
+			resolverFields: resolverFields{
+				cfg:         cfg.Cfg{MaxCommentTextLength: 100},
+				getPostRepo: publishedPostRepo,
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+				moderator: moderation.NewPipeline(moderation.RepeatedChars{MaxRun: 3, Verdict: moderation.Reject}),
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				postID: "1",
+				text:   "Hellooooo",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Held by moderation",
+			resolverFields: resolverFields{
+				cfg:         cfg.Cfg{MaxCommentTextLength: 100},
+				getPostRepo: publishedPostRepo,
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+				getModRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().HoldComment(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, held *models.HeldComment) (int, error) {
+							if held.Comment.PostID != 1 || held.Comment.Text != "Hellooooo" {
+								t.Errorf("unexpected held comment: %v", held)
+							}
+							return 7, nil
+						},
+					)
+					return mr
+				},
+				moderator: moderation.NewPipeline(moderation.RepeatedChars{MaxRun: 3, Verdict: moderation.Hold}),
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				postID: "1",
+				text:   "Hellooooo",
+			},
+			want:       nil,
+			wantErr:    true,
+			wantHeldID: "7",
+		},
+		{
+			name: "Blocked by post owner",
+			resolverFields: resolverFields{
+				cfg:            cfg.Cfg{MaxCommentTextLength: 100},
+				getPostRepo:    publishedPostRepo,
+				getCommentRepo: noCommentRepo,
+				getBlockRepo: func(c *gomock.Controller) repository.BlockRepo {
+					br := mocks.NewMockBlockRepo(c)
+					br.EXPECT().IsCommenterBlocked(gomock.Any(), 5, 1).Return(true, nil)
+					return br
+				},
+			},
+			args:    args{ctx: authCtx(&models.User{ID: 1, Login: "qwerty"}), postID: "1", text: "Hello"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Failed to check block",
+			resolverFields: resolverFields{
+				cfg:            cfg.Cfg{MaxCommentTextLength: 100},
+				getPostRepo:    publishedPostRepo,
+				getCommentRepo: noCommentRepo,
+				getBlockRepo: func(c *gomock.Controller) repository.BlockRepo {
+					br := mocks.NewMockBlockRepo(c)
+					br.EXPECT().IsCommenterBlocked(gomock.Any(), 5, 1).Return(false, fmt.Errorf("db error"))
+					return br
+				},
+			},
+			args:    args{ctx: authCtx(&models.User{ID: 1, Login: "qwerty"}), postID: "1", text: "Hello"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Followers only, not a follower",
+			resolverFields: resolverFields{
+				cfg:            cfg.Cfg{MaxCommentTextLength: 100},
+				getPostRepo:    policyPostRepo(models.CommentsFollowersOnly, 0),
+				getCommentRepo: noCommentRepo,
+				getFollowRepo: func(c *gomock.Controller) repository.FollowRepo {
+					fr := mocks.NewMockFollowRepo(c)
+					fr.EXPECT().IsFollowing(gomock.Any(), 1, 5).Return(false, nil)
+					return fr
+				},
+			},
+			args:    args{ctx: authCtx(&models.User{ID: 1, Login: "qwerty"}), postID: "1", text: "Hello"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Registered too recently",
+			resolverFields: resolverFields{
+				cfg:            cfg.Cfg{MaxCommentTextLength: 100},
+				getPostRepo:    policyPostRepo(models.CommentsRegisteredOlder, 7),
+				getCommentRepo: noCommentRepo,
+			},
+			args: args{
+				ctx:    authCtx(&models.User{ID: 1, Login: "qwerty", RegisteredAt: time.Now().Add(-6 * 24 * time.Hour)}),
+				postID: "1",
+				text:   "Hello",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Approval required",
+			resolverFields: resolverFields{
+				cfg:            cfg.Cfg{MaxCommentTextLength: 100},
+				getPostRepo:    policyPostRepo(models.CommentsApprovalRequired, 0),
+				getCommentRepo: noCommentRepo,
+				getModRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().HoldComment(gomock.Any(), gomock.Any()).Return(8, nil)
+					return mr
+				},
+			},
+			args:       args{ctx: authCtx(&models.User{ID: 1, Login: "qwerty"}), postID: "1", text: "Hello"},
+			want:       nil,
+			wantErr:    true,
+			wantHeldID: "8",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			logger := zaptest.NewLogger(t)
 			sugar := logger.Sugar()
 			c := gomock.NewController(t)
+			var userRepo repository.UserRepo
+			if tt.resolverFields.getUserRepo != nil {
+				userRepo = tt.resolverFields.getUserRepo(c)
+			}
+			var notificationRepo repository.NotificationRepo
+			if tt.resolverFields.getNotifRepo != nil {
+				notificationRepo = tt.resolverFields.getNotifRepo(c)
+			}
+			var moderationRepo repository.ModerationRepo
+			if tt.resolverFields.getModRepo != nil {
+				moderationRepo = tt.resolverFields.getModRepo(c)
+			}
+			var followRepo repository.FollowRepo
+			if tt.resolverFields.getFollowRepo != nil {
+				followRepo = tt.resolverFields.getFollowRepo(c)
+			}
+			var blockRepo repository.BlockRepo
+			if tt.resolverFields.getBlockRepo != nil {
+				blockRepo = tt.resolverFields.getBlockRepo(c)
+			} else {
+				//nobody is blocked by default
+				br := mocks.NewMockBlockRepo(c)
+				br.EXPECT().IsCommenterBlocked(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+				blockRepo = br
+			}
 			r := &mutationResolver{
 				Resolver: &Resolver{
-					Logger:      sugar,
-					Cfg:         tt.resolverFields.cfg,
-					PostRepo:    tt.resolverFields.getPostRepo(c),
-					CommentRepo: tt.resolverFields.getCommentRepo(c),
+					Logger:           sugar,
+					Cfg:              tt.resolverFields.cfg,
+					PostRepo:         tt.resolverFields.getPostRepo(c),
+					CommentRepo:      tt.resolverFields.getCommentRepo(c),
+					UserRepo:         userRepo,
+					NotificationRepo: notificationRepo,
+					ModerationRepo:   moderationRepo,
+					BlockRepo:        blockRepo,
+					FollowRepo:       followRepo,
+					Moderator:        tt.resolverFields.moderator,
 				},
 			}
-			got, err := r.AddComment(tt.args.ctx, tt.args.postID, tt.args.text)
+			got, err := r.AddComment(tt.args.ctx, tt.args.postID, tt.args.text, model.TextFormatPlain, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("AddComment() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantHeldID != "" {
+				var appErr *apperrors.Error
+				if !errors.As(err, &appErr) || appErr.Code != apperrors.Forbidden || appErr.Extensions["heldCommentID"] != tt.wantHeldID {
+					t.Errorf("AddComment() error = %v, wantHeldID %v", err, tt.wantHeldID)
+				}
+			}
 			if !tt.wantErr {
 				if got.Comment != nil {
-					got.Comment.CreatedAt = ""
+					got.Comment.CreatedAt = time.Time{}
 				}
 			}
 			if !reflect.DeepEqual(got, tt.want) {
@@ -3,50 +3,92 @@ package resolvers
 import (
 	"context"
 	"errors"
-	"fmt"
-	"github.com/vektah/gqlparser/v2/gqlerror"
 	"ozon_test_task/internal/app/graph/model"
 	"ozon_test_task/internal/app/graph/repository"
 	"ozon_test_task/internal/app/middlewares"
 	"ozon_test_task/internal/app/models"
-	"strconv"
+	"ozon_test_task/pkg/apperrors"
+	"ozon_test_task/pkg/moderation"
+	"strings"
+	"time"
 )
 
 // AddPost is the resolver for the addPost field.
-func (r *mutationResolver) AddPost(ctx context.Context, title string, text string, commentsAllowed *bool) (*model.AddPostResponse, error) {
+func (r *mutationResolver) AddPost(ctx context.Context, title string, text string, commentsAllowed *bool, tags []string, status model.PostStatus, publishAt *time.Time, textFormat model.TextFormat, clientMutationID *string) (*model.AddPostResponse, error) {
+	return idempotent(ctx, r.Resolver, clientMutationID, []any{"addPost", title, text, commentsAllowed, tags, status, publishAt, textFormat}, func() (*model.AddPostResponse, error) {
+		return r.addPost(ctx, title, text, commentsAllowed, tags, status, publishAt, textFormat)
+	})
+}
+
+// addPost creates a post of the current user.
+func (r *mutationResolver) addPost(ctx context.Context, title string, text string, commentsAllowed *bool, tags []string, status model.PostStatus, publishAt *time.Time, textFormat model.TextFormat) (*model.AddPostResponse, error) {
 	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
 	if !ok {
 		r.Logger.Debugf("cant get user from ctx")
-		return nil, gqlerror.Errorf("Not authorized")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	if err := r.validatePost(title, text); err != nil {
+		r.Logger.Debugf("invalid post, err: %v", err)
+		return nil, validationError(err)
+	}
+
+	normalizedTags, err := normalizeTags(tags, r.Cfg.MaxPostTags)
+	if err != nil {
+		r.Logger.Debugf("cant normalize tags, err: %v", err)
+		return nil, err
 	}
 
+	//an explicit null is the default
+	allowed := commentsAllowed == nil || *commentsAllowed
+
+	now := time.Now()
+	publishAt, err = newPostPublishAt(status, publishAt, now)
+	if err != nil {
+		r.Logger.Debugf("invalid publishAt, err: %v", err)
+		return nil, err
+	}
 	newPost := &models.Post{
 		Owner:           *user,
 		Title:           title,
 		Text:            text,
-		CommentsAllowed: *commentsAllowed,
+		TextFormat:      models.TextFormat(textFormat),
+		CommentsAllowed: allowed,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		Tags:            normalizedTags,
+		Status:          models.PostStatus(status),
+		PublishAt:       publishAt,
+		CommentPolicy:   models.CommentsOpen,
+	}
+	if !allowed {
+		newPost.CommentPolicy = models.CommentsClosed
+	}
+
+	decision := r.moderate(title, text)
+	switch decision.Verdict {
+	case moderation.Reject:
+		r.Logger.Debugf("post is rejected by moderation, reasons: %v", decision.Reasons)
+		return nil, apperrors.New(apperrors.Validation, "post is rejected by moderation: %s", strings.Join(decision.Reasons, "; "))
+	case moderation.Hold:
+		//publishAt is kept to publish the post as requested when it is approved
+		r.Logger.Debugf("post is held by moderation, reasons: %v", decision.Reasons)
+		newPost.Status = models.PostHeld
+		newPost.ModerationReasons = decision.Reasons
 	}
 
 	postID, err := r.PostRepo.AddPost(ctx, newPost)
 	if err != nil {
 		r.Logger.Debugf("cant add post to a database, err: %v", err)
 		if errors.Is(err, repository.NewErrConflict()) {
-			return nil, fmt.Errorf("conflict")
+			return nil, apperrors.New(apperrors.Conflict, "conflict")
 		}
-		return nil, fmt.Errorf("internal server error")
+		return nil, apperrors.NewInternal(err)
 	}
 
+	newPost.ID = postID
 	return &model.AddPostResponse{
-		Post: &model.Post{
-			ID:    strconv.Itoa(postID),
-			Title: newPost.Title,
-			Text:  newPost.Text,
-			Owner: &model.User{
-				ID:       strconv.Itoa(user.ID),
-				Username: user.Login,
-			},
-			CommentsAllowed: newPost.CommentsAllowed,
-		},
+		Post:  newPostModel(newPost),
 		Error: "",
 	}, nil
 }
@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
 	"ozon_test_task/internal/app/graph/model"
 	"ozon_test_task/internal/app/graph/repository"
 	"ozon_test_task/internal/app/graph/repository/mocks"
 	"ozon_test_task/internal/app/middlewares"
 	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/moderation"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func Test_mutationResolver_AddPost(t *testing.T) {
@@ -20,9 +24,18 @@ func Test_mutationResolver_AddPost(t *testing.T) {
 		title           string
 		text            string
 		commentsAllowed *bool
+		tags            []string
+		status          model.PostStatus
+		publishAt       *time.Time
+		textFormat      model.TextFormat
 	}
 	type resolverFields struct {
 		getPostRepo func(c *gomock.Controller) repository.PostRepo
+		moderator   *moderation.Pipeline
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
 	}
 	tests := []struct {
 		name           string
@@ -47,6 +60,38 @@ func Test_mutationResolver_AddPost(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Empty title",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					return mocks.NewMockPostRepo(c)
+				},
+			},
+			args: args{
+				ctx:             authCtx(),
+				title:           "",
+				text:            "Text",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Title is longer than the title column",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					return mocks.NewMockPostRepo(c)
+				},
+			},
+			args: args{
+				ctx:             authCtx(),
+				title:           strings.Repeat("e\u0301", 128),
+				text:            "Text",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Conflict error",
 			resolverFields: resolverFields{
@@ -116,8 +161,336 @@ func Test_mutationResolver_AddPost(t *testing.T) {
 					ID:              "42",
 					Title:           "Title",
 					Text:            "Text",
+					TextFormat:      model.TextFormatPlain,
 					Owner:           &model.User{ID: "1", Username: "qwerty"},
 					CommentsAllowed: true,
+					CommentPolicy:   model.CommentPolicyOpen,
+					Status:          model.PostStatusPublished,
+				},
+				Error: "",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Too many tags",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					return mocks.NewMockPostRepo(c)
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				title:           "Title",
+				text:            "Text",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+				tags:            []string{"go", "redis", "postgres"},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok with tags",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().AddPost(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, post *models.Post) (int, error) {
+							if !reflect.DeepEqual(post.Tags, []string{"go", "graph-ql"}) {
+								return 0, fmt.Errorf("unexpected tags %v", post.Tags)
+							}
+							return 42, nil
+						},
+					)
+					return pr
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				title:           "Title",
+				text:            "Text",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+				tags:            []string{"#Graph QL", "go", "Go"},
+			},
+			want: &model.AddPostResponse{
+				Post: &model.Post{
+					ID:              "42",
+					Title:           "Title",
+					Text:            "Text",
+					TextFormat:      model.TextFormatPlain,
+					Owner:           &model.User{ID: "1", Username: "qwerty"},
+					CommentsAllowed: true,
+					CommentPolicy:   model.CommentPolicyOpen,
+					Tags:            []string{"go", "graph-ql"},
+					Status:          model.PostStatusPublished,
+				},
+				Error: "",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Scheduled without publishAt",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					return mocks.NewMockPostRepo(c)
+				},
+			},
+			args: args{
+				ctx:             authCtx(),
+				title:           "Title",
+				text:            "Text",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+				status:          model.PostStatusScheduled,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Scheduled in the past",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					return mocks.NewMockPostRepo(c)
+				},
+			},
+			args: args{
+				ctx:             authCtx(),
+				title:           "Title",
+				text:            "Text",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+				status:          model.PostStatusScheduled,
+				publishAt:       func() *time.Time { v := time.Now().Add(-time.Minute); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Draft with publishAt",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					return mocks.NewMockPostRepo(c)
+				},
+			},
+			args: args{
+				ctx:             authCtx(),
+				title:           "Title",
+				text:            "Text",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+				status:          model.PostStatusDraft,
+				publishAt:       func() *time.Time { v := time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Published with publishAt",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					return mocks.NewMockPostRepo(c)
+				},
+			},
+			args: args{
+				ctx:             authCtx(),
+				title:           "Title",
+				text:            "Text",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+				status:          model.PostStatusPublished,
+				publishAt:       func() *time.Time { v := time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Markdown",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().AddPost(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, post *models.Post) (int, error) {
+							if post.TextFormat != models.TextMarkdown {
+								return 0, fmt.Errorf("unexpected text format %v", post.TextFormat)
+							}
+							return 45, nil
+						},
+					)
+					return pr
+				},
+			},
+			args: args{
+				ctx:             authCtx(),
+				title:           "Title",
+				text:            "**Text**",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+				textFormat:      model.TextFormatMarkdown,
+			},
+			want: &model.AddPostResponse{
+				Post: &model.Post{
+					ID:              "45",
+					Title:           "Title",
+					Text:            "**Text**",
+					TextFormat:      model.TextFormatMarkdown,
+					Owner:           &model.User{ID: "1", Username: "qwerty"},
+					CommentsAllowed: true,
+					CommentPolicy:   model.CommentPolicyOpen,
+					Status:          model.PostStatusPublished,
+				},
+				Error: "",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Draft",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().AddPost(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, post *models.Post) (int, error) {
+							if post.Status != models.PostDraft || post.PublishAt != nil {
+								return 0, fmt.Errorf("unexpected status %v, publishAt %v", post.Status, post.PublishAt)
+							}
+							return 43, nil
+						},
+					)
+					return pr
+				},
+			},
+			args: args{
+				ctx:             authCtx(),
+				title:           "Title",
+				text:            "Text",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+				status:          model.PostStatusDraft,
+			},
+			want: &model.AddPostResponse{
+				Post: &model.Post{
+					ID:              "43",
+					Title:           "Title",
+					Text:            "Text",
+					TextFormat:      model.TextFormatPlain,
+					Owner:           &model.User{ID: "1", Username: "qwerty"},
+					CommentsAllowed: true,
+					CommentPolicy:   model.CommentPolicyOpen,
+					Status:          model.PostStatusDraft,
+				},
+				Error: "",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Scheduled",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().AddPost(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, post *models.Post) (int, error) {
+							if post.Status != models.PostScheduled || post.PublishAt == nil || !post.PublishAt.Equal(time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC)) {
+								return 0, fmt.Errorf("unexpected status %v, publishAt %v", post.Status, post.PublishAt)
+							}
+							return 44, nil
+						},
+					)
+					return pr
+				},
+			},
+			args: args{
+				ctx:             authCtx(),
+				title:           "Title",
+				text:            "Text",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+				status:          model.PostStatusScheduled,
+				publishAt:       func() *time.Time { v := time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC); return &v }(),
+			},
+			want: &model.AddPostResponse{
+				Post: &model.Post{
+					ID:              "44",
+					Title:           "Title",
+					Text:            "Text",
+					TextFormat:      model.TextFormatPlain,
+					Owner:           &model.User{ID: "1", Username: "qwerty"},
+					CommentsAllowed: true,
+					CommentPolicy:   model.CommentPolicyOpen,
+					Status:          model.PostStatusScheduled,
+					PublishAt:       func() *time.Time { v := time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC); return &v }(),
+				},
+				Error: "",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Held status",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					return mocks.NewMockPostRepo(c)
+				},
+			},
+			args: args{
+				ctx:             authCtx(),
+				title:           "Title",
+				text:            "Text",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+				status:          model.PostStatusHeld,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Rejected by moderation",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					return mocks.NewMockPostRepo(c)
+				},
+				moderator: moderation.NewPipeline(moderation.LinkLimit{Max: 0, Verdict: moderation.Reject}),
+			},
+			args: args{
+				ctx:             authCtx(),
+				title:           "Title",
+				text:            "see https://example.com",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Held by moderation",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().AddPost(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, post *models.Post) (int, error) {
+							if post.Status != models.PostHeld || post.PublishAt == nil {
+								return 0, fmt.Errorf("unexpected status %v, publishAt %v", post.Status, post.PublishAt)
+							}
+							return 45, nil
+						},
+					)
+					return pr
+				},
+				moderator: moderation.NewPipeline(moderation.LinkLimit{Max: 0, Verdict: moderation.Hold}),
+			},
+			args: args{
+				ctx:             authCtx(),
+				title:           "Title",
+				text:            "see https://example.com",
+				commentsAllowed: func() *bool { v := true; return &v }(),
+				status:          model.PostStatusScheduled,
+				publishAt:       func() *time.Time { v := time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC); return &v }(),
+			},
+			want: &model.AddPostResponse{
+				Post: &model.Post{
+					ID:                "45",
+					Title:             "Title",
+					Text:              "see https://example.com",
+					TextFormat:        model.TextFormatPlain,
+					Owner:             &model.User{ID: "1", Username: "qwerty"},
+					CommentsAllowed:   true,
+					CommentPolicy:     model.CommentPolicyOpen,
+					Status:            model.PostStatusHeld,
+					PublishAt:         func() *time.Time { v := time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC); return &v }(),
+					ModerationReasons: []string{"more than 0 links"},
 				},
 				Error: "",
 			},
@@ -131,15 +504,38 @@ func Test_mutationResolver_AddPost(t *testing.T) {
 			c := gomock.NewController(t)
 			r := &mutationResolver{
 				Resolver: &Resolver{
-					Logger:   sugar,
-					PostRepo: tt.resolverFields.getPostRepo(c),
+					Logger:    sugar,
+					Cfg:       cfg.Cfg{MaxPostTags: 2},
+					PostRepo:  tt.resolverFields.getPostRepo(c),
+					Moderator: tt.resolverFields.moderator,
 				},
 			}
-			got, err := r.AddPost(tt.args.ctx, tt.args.title, tt.args.text, tt.args.commentsAllowed)
+			status := tt.args.status
+			if status == "" {
+				status = model.PostStatusPublished
+			}
+			textFormat := tt.args.textFormat
+			if textFormat == "" {
+				textFormat = model.TextFormatPlain
+			}
+			got, err := r.AddPost(tt.args.ctx, tt.args.title, tt.args.text, tt.args.commentsAllowed, tt.args.tags, status, tt.args.publishAt, textFormat, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("AddPost() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if !tt.wantErr {
+				if got.Post != nil {
+					got.Post.CreatedAt = time.Time{}
+					got.Post.UpdatedAt = time.Time{}
+					// Published posts are published now
+					if status == model.PostStatusPublished {
+						if got.Post.PublishAt == nil {
+							t.Errorf("AddPost() publishAt of a published post is nil")
+						}
+						got.Post.PublishAt = nil
+					}
+				}
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("AddPost() got = %v, want %v", got, tt.want)
 			}
@@ -2,59 +2,137 @@ package resolvers
 
 import (
 	"context"
-	"fmt"
-	"github.com/vektah/gqlparser/v2/gqlerror"
+	"errors"
 	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
 	"ozon_test_task/internal/app/middlewares"
 	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"ozon_test_task/pkg/moderation"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // AddReplay is the resolver for the addReplay field.
-func (r *mutationResolver) AddReplay(ctx context.Context, parentCommentID string, text string) (*model.AddReplayResponse, error) {
+func (r *mutationResolver) AddReplay(ctx context.Context, parentCommentID string, text string, textFormat model.TextFormat, clientMutationID *string) (*model.AddReplayResponse, error) {
+	result, err := r.addReplay(ctx, parentCommentID, text, textFormat, clientMutationID)
+	if err != nil {
+		return nil, err
+	}
+	if result.HeldComment != nil {
+		return nil, heldForReviewError(result.HeldComment)
+	}
+	return &model.AddReplayResponse{Comment: result.Comment, Error: ""}, nil
+}
+
+// addReplay adds a reply of the current user to a comment once per clientMutationID.
+func (r *mutationResolver) addReplay(ctx context.Context, parentCommentID string, text string, textFormat model.TextFormat, clientMutationID *string) (*model.AddReplySuccess, error) {
+	return idempotent(ctx, r.Resolver, clientMutationID, []any{"addReplay", parentCommentID, text, textFormat}, func() (*model.AddReplySuccess, error) {
+		return r.addReplayOnce(ctx, parentCommentID, text, textFormat)
+	})
+}
+
+// addReplayOnce adds a reply of the current user to a comment.
+func (r *mutationResolver) addReplayOnce(ctx context.Context, parentCommentID string, text string, textFormat model.TextFormat) (*model.AddReplySuccess, error) {
 	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
 	if !ok {
 		r.Logger.Debugf("cant get user from ctx")
-		return nil, gqlerror.Errorf("Not authorized")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
 	}
 
 	parentIDInt, err := strconv.Atoi(parentCommentID)
 	if err != nil {
 		r.Logger.Debugf("parentCommentID is not an int, parsing err: %v", err)
-		return nil, fmt.Errorf("parentCommentID is not int")
+		return nil, apperrors.New(apperrors.Validation, "parentCommentID is not int")
 	}
 
-	if len(text) > r.Cfg.MaxCommentTextLength {
-		r.Logger.Debugf("max comment length exceeded, current len is \"%v\", max len is \"%v\"", len(text), r.Cfg.MaxCommentTextLength)
-		return nil, fmt.Errorf("replay text too long, max lenght: %d", r.Cfg.MaxCommentTextLength)
+	if err := r.validateCommentText(text); err != nil {
+		r.Logger.Debugf("invalid replay, err: %v", err)
+		return nil, validationError(err)
+	}
+
+	parent, err := r.CommentRepo.GetCommentByID(ctx, parentIDInt)
+	if err != nil {
+		r.Logger.Debugf("cant get parent comment from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "parent comment not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	parent, err = r.replyParent(ctx, parent)
+	if err != nil {
+		return nil, err
 	}
 
 	comment := &models.Comment{
-		Owner:     *user,
-		PostID:    0, // zero means comment is a sub-comment.
-		ParentID:  parentIDInt,
-		Text:      text,
-		CreatedAt: time.Now(),
+		Owner:      *user,
+		PostID:     0, // zero means comment is a sub-comment.
+		ParentID:   parent.ID,
+		Text:       text,
+		TextFormat: models.TextFormat(textFormat),
+		CreatedAt:  time.Now(),
+		Depth:      parent.Depth + 1,
+	}
+
+	//replies follow the comment policy of the post
+	postID, err := r.commentPostID(ctx, parent)
+	if err != nil {
+		r.Logger.Debugf("cant get parent comment post id, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "parent comment not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	post, err := r.PostRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		r.Logger.Debugf("cant get post from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	needsApproval, err := r.checkCommentPolicy(ctx, user, post)
+	if err != nil {
+		return nil, err
+	}
+
+	decision := r.moderate(text)
+	if needsApproval && decision.Verdict != moderation.Reject {
+		decision.Verdict = moderation.Hold
+		decision.Reasons = append(decision.Reasons, approvalRequiredReason)
+	}
+	switch decision.Verdict {
+	case moderation.Reject:
+		r.Logger.Debugf("replay is rejected by moderation, reasons: %v", decision.Reasons)
+		return nil, apperrors.New(apperrors.Validation, "replay is rejected by moderation: %s", strings.Join(decision.Reasons, "; "))
+	case moderation.Hold:
+		r.Logger.Debugf("replay is held by moderation, reasons: %v", decision.Reasons)
+		held := &models.HeldComment{Comment: *comment, RootPostID: post.ID, Reasons: decision.Reasons}
+		held.ID, err = r.ModerationRepo.HoldComment(ctx, held)
+		if err != nil {
+			r.Logger.Debugf("cant hold replay, err: %v", err)
+			return nil, apperrors.NewInternal(err)
+		}
+		return &model.AddReplySuccess{HeldComment: newHeldCommentModel(held)}, nil
+	}
+
+	comment.Mentions, err = r.resolveMentions(ctx, text)
+	if err != nil {
+		r.Logger.Debugf("cant resolve mentions, err: %v", err)
+		return nil, apperrors.NewInternal(err)
 	}
 
 	id, err := r.CommentRepo.AddComment(ctx, comment)
 	if err != nil {
 		r.Logger.Debugf("cant add comment to a db, err: %v", err)
-		return nil, fmt.Errorf("internal server error")
-	}
-
-	return &model.AddReplayResponse{
-		Comment: &model.Comment{
-			ID: strconv.Itoa(id),
-			Owner: &model.User{
-				ID:       strconv.Itoa(comment.Owner.ID),
-				Username: comment.Owner.Login,
-			},
-			Text:      comment.Text,
-			CreatedAt: comment.CreatedAt.String(),
-			Replies:   nil,
-		},
-		Error: "",
-	}, nil
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "parent comment not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+
+	comment.ID = id
+	r.notifyAboutComment(ctx, comment, 0, parent.Owner.ID)
+	return &model.AddReplySuccess{Comment: newCommentModel(comment)}, nil
 }
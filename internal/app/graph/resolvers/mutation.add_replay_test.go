@@ -11,8 +11,10 @@ import (
 	"ozon_test_task/internal/app/graph/repository/mocks"
 	"ozon_test_task/internal/app/middlewares"
 	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/moderation"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_mutationResolver_AddReplay(t *testing.T) {
@@ -24,6 +26,16 @@ func Test_mutationResolver_AddReplay(t *testing.T) {
 	type resolverFields struct {
 		cfg            cfg.Cfg
 		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+		getUserRepo    func(c *gomock.Controller) repository.UserRepo
+		getNotifRepo   func(c *gomock.Controller) repository.NotificationRepo
+		getModRepo     func(c *gomock.Controller) repository.ModerationRepo
+		getPostRepo    func(c *gomock.Controller) repository.PostRepo
+		getBlockRepo   func(c *gomock.Controller) repository.BlockRepo
+		moderator      *moderation.Pipeline
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
 	}
 	tests := []struct {
 		name           string
@@ -88,6 +100,29 @@ func Test_mutationResolver_AddReplay(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Parent comment not found",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					MaxCommentTextLength: 100,
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(nil, repository.NewErrNotFound())
+					return cr
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				parentCommentID: "10",
+				text:            "Hello",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Internal server error",
 			resolverFields: resolverFields{
@@ -96,6 +131,7 @@ func Test_mutationResolver_AddReplay(t *testing.T) {
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
 					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(&models.Comment{ID: 10, PostID: 1, Owner: models.User{ID: 3, Login: "parent"}}, nil)
 					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).Return(0, fmt.Errorf("some db error"))
 					return cr
 				},
@@ -119,6 +155,7 @@ func Test_mutationResolver_AddReplay(t *testing.T) {
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
 					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(&models.Comment{ID: 10, PostID: 1, Owner: models.User{ID: 3, Login: "parent"}}, nil)
 					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).DoAndReturn(
 						func(ctx context.Context, comment *models.Comment) (int, error) {
 							return 123, nil
@@ -126,6 +163,18 @@ func Test_mutationResolver_AddReplay(t *testing.T) {
 					)
 					return cr
 				},
+				getNotifRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, notifications []*models.Notification) error {
+							if len(notifications) != 1 || notifications[0].UserID != 3 || notifications[0].Type != models.NotificationReply {
+								t.Errorf("unexpected notifications: %v", notifications)
+							}
+							return nil
+						},
+					)
+					return nr
+				},
 			},
 			args: args{
 				ctx: func() context.Context {
@@ -142,35 +191,353 @@ func Test_mutationResolver_AddReplay(t *testing.T) {
 						ID:       "1",
 						Username: "qwerty",
 					},
-					Text:      "Hello",
-					CreatedAt: "",
-					Replies:   nil,
+					Text:            "Hello",
+					TextFormat:      model.TextFormatPlain,
+					CreatedAt:       time.Time{},
+					ParentCommentID: func() *string { v := "10"; return &v }(),
+					Depth:           1,
+					Replies:         nil,
+				},
+				Error: "",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Reply depth limit reached",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxCommentTextLength: 100, MaxReplyDepth: 2},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(&models.Comment{ID: 10, ParentID: 9, Depth: 2, Owner: models.User{ID: 3, Login: "parent"}}, nil)
+					return cr
+				},
+			},
+			args: args{
+				ctx:             authCtx(),
+				parentCommentID: "10",
+				text:            "Hello",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Too deep reply is re-parented",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxCommentTextLength: 100, MaxReplyDepth: 2, ReparentDeepReplies: true},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(&models.Comment{ID: 10, ParentID: 9, Depth: 3, Owner: models.User{ID: 3, Login: "parent"}}, nil)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 9).Return(&models.Comment{ID: 9, ParentID: 4, Depth: 2, Owner: models.User{ID: 4, Login: "middle"}}, nil)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 4).Return(&models.Comment{ID: 4, ParentID: 2, Depth: 1, Owner: models.User{ID: 6, Login: "ancestor"}}, nil)
+					cr.EXPECT().GetCommentPostID(gomock.Any(), 2).Return(1, nil)
+					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, comment *models.Comment) (int, error) {
+							if comment.ParentID != 4 || comment.Depth != 2 {
+								return 0, fmt.Errorf("unexpected parent: %v, depth: %v", comment.ParentID, comment.Depth)
+							}
+							return 123, nil
+						},
+					)
+					return cr
+				},
+				getNotifRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, notifications []*models.Notification) error {
+							if len(notifications) != 1 || notifications[0].UserID != 6 {
+								t.Errorf("unexpected notifications: %v", notifications)
+							}
+							return nil
+						},
+					)
+					return nr
+				},
+			},
+			args: args{
+				ctx:             authCtx(),
+				parentCommentID: "10",
+				text:            "Hello",
+			},
+			want: &model.AddReplayResponse{
+				Comment: &model.Comment{
+					ID:              "123",
+					Owner:           &model.User{ID: "1", Username: "qwerty"},
+					Text:            "Hello",
+					TextFormat:      model.TextFormatPlain,
+					ParentCommentID: func() *string { v := "4"; return &v }(),
+					Depth:           2,
+				},
+				Error: "",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failed to resolve mentions",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					MaxCommentTextLength: 100,
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(&models.Comment{ID: 10, PostID: 1, Owner: models.User{ID: 3, Login: "parent"}}, nil)
+					return cr
+				},
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByLogin(gomock.Any(), "bob").Return(nil, fmt.Errorf("db error"))
+					return ur
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				parentCommentID: "10",
+				text:            "Hello @bob",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Mentions",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					MaxCommentTextLength: 100,
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(&models.Comment{ID: 10, PostID: 1, Owner: models.User{ID: 3, Login: "parent"}}, nil)
+					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, comment *models.Comment) (int, error) {
+							if !reflect.DeepEqual(comment.Mentions, []models.User{{ID: 2, Login: "bob"}}) {
+								return 0, fmt.Errorf("unexpected mentions: %v", comment.Mentions)
+							}
+							return 123, nil
+						},
+					)
+					return cr
+				},
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByLogin(gomock.Any(), "bob").Return(&models.User{ID: 2, Login: "bob"}, nil)
+					ur.EXPECT().GetUserByLogin(gomock.Any(), "ghost").Return(nil, repository.NewErrNotFound())
+					return ur
+				},
+				getNotifRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, notifications []*models.Notification) error {
+							types := map[int]models.NotificationType{}
+							for _, n := range notifications {
+								types[n.UserID] = n.Type
+							}
+							want := map[int]models.NotificationType{3: models.NotificationReply, 2: models.NotificationMention}
+							if !reflect.DeepEqual(types, want) {
+								t.Errorf("unexpected notifications: %v", types)
+							}
+							return nil
+						},
+					)
+					return nr
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				parentCommentID: "10",
+				text:            "Hello @bob and @ghost",
+			},
+			want: &model.AddReplayResponse{
+				Comment: &model.Comment{
+					ID: "123",
+					Owner: &model.User{
+						ID:       "1",
+						Username: "qwerty",
+					},
+					Text:            "Hello @bob and @ghost",
+					TextFormat:      model.TextFormatPlain,
+					CreatedAt:       time.Time{},
+					ParentCommentID: func() *string { v := "10"; return &v }(),
+					Depth:           1,
 				},
 				Error: "",
 			},
 			wantErr: false,
 		},
+		{
+			name: "Rejected by moderation",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxCommentTextLength: 100},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(&models.Comment{ID: 10, PostID: 1, Owner: models.User{ID: 3, Login: "parent"}}, nil)
+					return cr
+				},
+				moderator: moderation.NewPipeline(moderation.RepeatedChars{MaxRun: 3, Verdict: moderation.Reject}),
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				parentCommentID: "10",
+				text:            "Hellooooo",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Held by moderation",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxCommentTextLength: 100},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(&models.Comment{ID: 10, PostID: 1, Owner: models.User{ID: 3, Login: "parent"}}, nil)
+					return cr
+				},
+				getModRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().HoldComment(gomock.Any(), gomock.Any()).Return(8, nil)
+					return mr
+				},
+				moderator: moderation.NewPipeline(moderation.RepeatedChars{MaxRun: 3, Verdict: moderation.Hold}),
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				parentCommentID: "10",
+				text:            "Hellooooo",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Post of a nested parent comment not found",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxCommentTextLength: 100},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(&models.Comment{ID: 10, ParentID: 9, Owner: models.User{ID: 3, Login: "parent"}}, nil)
+					cr.EXPECT().GetCommentPostID(gomock.Any(), 9).Return(0, repository.NewErrNotFound())
+					return cr
+				},
+			},
+			args:    args{ctx: authCtx(), parentCommentID: "10", text: "Hello"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Comments are closed",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxCommentTextLength: 100},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(&models.Comment{ID: 10, ParentID: 9, Owner: models.User{ID: 3, Login: "parent"}}, nil)
+					cr.EXPECT().GetCommentPostID(gomock.Any(), 9).Return(1, nil)
+					return cr
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(&models.Post{
+						ID:            1,
+						Owner:         models.User{ID: 5},
+						Status:        models.PostPublished,
+						CommentPolicy: models.CommentsClosed,
+					}, nil)
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx(), parentCommentID: "10", text: "Hello"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Blocked by post owner",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxCommentTextLength: 100},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(&models.Comment{ID: 10, PostID: 1, Owner: models.User{ID: 3, Login: "parent"}}, nil)
+					return cr
+				},
+				getBlockRepo: func(c *gomock.Controller) repository.BlockRepo {
+					br := mocks.NewMockBlockRepo(c)
+					br.EXPECT().IsCommenterBlocked(gomock.Any(), 5, 1).Return(true, nil)
+					return br
+				},
+			},
+			args:    args{ctx: authCtx(), parentCommentID: "10", text: "Hello"},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			logger := zaptest.NewLogger(t)
 			sugar := logger.Sugar()
 			c := gomock.NewController(t)
+			var userRepo repository.UserRepo
+			if tt.resolverFields.getUserRepo != nil {
+				userRepo = tt.resolverFields.getUserRepo(c)
+			}
+			var notificationRepo repository.NotificationRepo
+			if tt.resolverFields.getNotifRepo != nil {
+				notificationRepo = tt.resolverFields.getNotifRepo(c)
+			}
+			var moderationRepo repository.ModerationRepo
+			if tt.resolverFields.getModRepo != nil {
+				moderationRepo = tt.resolverFields.getModRepo(c)
+			}
+			var postRepo repository.PostRepo
+			if tt.resolverFields.getPostRepo != nil {
+				postRepo = tt.resolverFields.getPostRepo(c)
+			} else {
+				//replies are allowed to the post by default
+				pr := mocks.NewMockPostRepo(c)
+				pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(&models.Post{
+					ID:              1,
+					Owner:           models.User{ID: 5},
+					Status:          models.PostPublished,
+					CommentsAllowed: true,
+					CommentPolicy:   models.CommentsOpen,
+				}, nil).AnyTimes()
+				postRepo = pr
+			}
+			var blockRepo repository.BlockRepo
+			if tt.resolverFields.getBlockRepo != nil {
+				blockRepo = tt.resolverFields.getBlockRepo(c)
+			} else {
+				//nobody is blocked by default
+				br := mocks.NewMockBlockRepo(c)
+				br.EXPECT().IsCommenterBlocked(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+				blockRepo = br
+			}
 			r := &mutationResolver{
 				Resolver: &Resolver{
-					Logger:      sugar,
-					Cfg:         tt.resolverFields.cfg,
-					CommentRepo: tt.resolverFields.getCommentRepo(c),
+					Logger:           sugar,
+					Cfg:              tt.resolverFields.cfg,
+					CommentRepo:      tt.resolverFields.getCommentRepo(c),
+					PostRepo:         postRepo,
+					BlockRepo:        blockRepo,
+					UserRepo:         userRepo,
+					NotificationRepo: notificationRepo,
+					ModerationRepo:   moderationRepo,
+					Moderator:        tt.resolverFields.moderator,
 				},
 			}
-			got, err := r.AddReplay(tt.args.ctx, tt.args.parentCommentID, tt.args.text)
+			got, err := r.AddReplay(tt.args.ctx, tt.args.parentCommentID, tt.args.text, model.TextFormatPlain, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("AddReplay() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !tt.wantErr && got != nil && got.Comment != nil {
 				// Ignore CreatedAt in tests
-				got.Comment.CreatedAt = ""
+				got.Comment.CreatedAt = time.Time{}
 			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("AddReplay() got = %v, want %v", got, tt.want)
@@ -0,0 +1,65 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// ApproveComment is the resolver for the approveComment field.
+func (r *mutationResolver) ApproveComment(ctx context.Context, heldCommentID string) (*model.Comment, error) {
+	held, err := r.takeHeldComment(ctx, heldCommentID)
+	if err != nil {
+		return nil, err
+	}
+	comment := &held.Comment
+
+	//get owners to notify, the commented post or comment could be deleted while the comment was held
+	postOwnerID, parentOwnerID := 0, 0
+	if comment.PostID != 0 {
+		post, err := r.PostRepo.GetPostByID(ctx, comment.PostID)
+		if err != nil {
+			r.Logger.Debugf("cant get post from db, err: %v", err)
+			return nil, r.approveCommentError(ctx, held, err)
+		}
+		postOwnerID = post.Owner.ID
+	} else {
+		parent, err := r.CommentRepo.GetCommentByID(ctx, comment.ParentID)
+		if err != nil {
+			r.Logger.Debugf("cant get parent comment from db, err: %v", err)
+			return nil, r.approveCommentError(ctx, held, err)
+		}
+		parentOwnerID = parent.Owner.ID
+		comment.Depth = parent.Depth + 1
+	}
+
+	comment.Mentions, err = r.resolveMentions(ctx, comment.Text)
+	if err != nil {
+		r.Logger.Debugf("cant resolve mentions, err: %v", err)
+		return nil, r.approveCommentError(ctx, held, err)
+	}
+
+	comment.ID, err = r.CommentRepo.AddComment(ctx, comment)
+	if err != nil {
+		r.Logger.Debugf("cant add comment to db, err: %v", err)
+		return nil, r.approveCommentError(ctx, held, err)
+	}
+
+	r.notifyAboutComment(ctx, comment, postOwnerID, parentOwnerID)
+	return newCommentModel(comment), nil
+}
+
+// approveCommentError returns an error of a failed approval.
+// The comment is held again unless it was commented to a deleted post or comment, so it can be approved later.
+func (r *Resolver) approveCommentError(ctx context.Context, held *models.HeldComment, err error) error {
+	if errors.Is(err, repository.NewErrNotFound()) {
+		return apperrors.New(apperrors.NotFound, "commented post or comment not found")
+	}
+	if _, err := r.ModerationRepo.HoldComment(ctx, held); err != nil {
+		r.Logger.Errorf("failed to hold comment again, comment is lost: %v", err)
+	}
+	return apperrors.NewInternal(err)
+}
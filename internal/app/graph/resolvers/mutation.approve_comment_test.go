@@ -0,0 +1,294 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_mutationResolver_ApproveComment(t *testing.T) {
+	type args struct {
+		ctx           context.Context
+		heldCommentID string
+	}
+	type resolverFields struct {
+		getModerationRepo func(c *gomock.Controller) repository.ModerationRepo
+		getPostRepo       func(c *gomock.Controller) repository.PostRepo
+		getCommentRepo    func(c *gomock.Controller) repository.CommentRepo
+		getNotifRepo      func(c *gomock.Controller) repository.NotificationRepo
+	}
+	authCtx := func(login string) context.Context {
+		user := &models.User{ID: 1, Login: login}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noModerationRepo := func(c *gomock.Controller) repository.ModerationRepo { return mocks.NewMockModerationRepo(c) }
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	newHeld := func(postID, parentID int) *models.HeldComment {
+		return &models.HeldComment{
+			ID:         4,
+			RootPostID: postID,
+			Comment: models.Comment{
+				Owner:      models.User{ID: 2, Login: "author"},
+				PostID:     postID,
+				ParentID:   parentID,
+				Text:       "Hello",
+				TextFormat: models.TextPlain,
+				CreatedAt:  createdAt,
+			},
+			Reasons: []string{"more than 3 links"},
+		}
+	}
+	takeHeld := func(held *models.HeldComment, err error) func(c *gomock.Controller) repository.ModerationRepo {
+		return func(c *gomock.Controller) repository.ModerationRepo {
+			mr := mocks.NewMockModerationRepo(c)
+			mr.EXPECT().TakeHeldComment(gomock.Any(), 4).Return(held, err)
+			return mr
+		}
+	}
+	approved := &model.Comment{
+		ID:         "123",
+		Owner:      &model.User{ID: "2", Username: "author"},
+		Text:       "Hello",
+		TextFormat: model.TextFormatPlain,
+		CreatedAt:  createdAt,
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.Comment
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getModerationRepo: noModerationRepo},
+			args:           args{ctx: context.Background(), heldCommentID: "4"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "Not a moderator nor the post owner",
+			resolverFields: resolverFields{
+				getModerationRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().GetHeldComment(gomock.Any(), 4).Return(newHeld(7, 0), nil)
+					return mr
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 7).Return(&models.Post{ID: 7, Owner: models.User{ID: 3}}, nil)
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx("qwerty"), heldCommentID: "4"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Held comment not found by the post owner",
+			resolverFields: resolverFields{
+				getModerationRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().GetHeldComment(gomock.Any(), 4).Return(nil, repository.NewErrNotFound())
+					return mr
+				},
+			},
+			args:    args{ctx: authCtx("qwerty"), heldCommentID: "4"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:           "heldCommentID is not int",
+			resolverFields: resolverFields{getModerationRepo: noModerationRepo},
+			args:           args{ctx: authCtx("moder"), heldCommentID: "abc"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Held comment not found",
+			resolverFields: resolverFields{getModerationRepo: takeHeld(nil, repository.NewErrNotFound())},
+			args:           args{ctx: authCtx("moder"), heldCommentID: "4"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "Post deleted",
+			resolverFields: resolverFields{
+				getModerationRepo: takeHeld(newHeld(7, 0), nil),
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 7).Return(nil, repository.NewErrNotFound())
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx("moder"), heldCommentID: "4"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Add failed, comment is held again",
+			resolverFields: resolverFields{
+				getModerationRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().TakeHeldComment(gomock.Any(), 4).Return(newHeld(7, 0), nil)
+					mr.EXPECT().HoldComment(gomock.Any(), newHeld(7, 0)).Return(5, nil)
+					return mr
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 7).Return(&models.Post{ID: 7, Owner: models.User{ID: 3}}, nil)
+					return pr
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).Return(0, fmt.Errorf("db error"))
+					return cr
+				},
+			},
+			args:    args{ctx: authCtx("moder"), heldCommentID: "4"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Comment approved",
+			resolverFields: resolverFields{
+				getModerationRepo: takeHeld(newHeld(7, 0), nil),
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 7).Return(&models.Post{ID: 7, Owner: models.User{ID: 3}}, nil)
+					return pr
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).Return(123, nil)
+					return cr
+				},
+				getNotifRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, notifications []*models.Notification) error {
+							if len(notifications) != 1 || notifications[0].UserID != 3 || notifications[0].Type != models.NotificationComment {
+								t.Errorf("unexpected notifications: %v", notifications)
+							}
+							return nil
+						},
+					)
+					return nr
+				},
+			},
+			args:    args{ctx: authCtx("moder"), heldCommentID: "4"},
+			want:    approved,
+			wantErr: false,
+		},
+		{
+			name: "Comment approved by the post owner",
+			resolverFields: resolverFields{
+				getModerationRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().GetHeldComment(gomock.Any(), 4).Return(newHeld(7, 0), nil)
+					mr.EXPECT().TakeHeldComment(gomock.Any(), 4).Return(newHeld(7, 0), nil)
+					return mr
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 7).Return(&models.Post{ID: 7, Owner: models.User{ID: 1}}, nil).Times(2)
+					return pr
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).Return(123, nil)
+					return cr
+				},
+				getNotifRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).Return(nil)
+					return nr
+				},
+			},
+			args:    args{ctx: authCtx("qwerty"), heldCommentID: "4"},
+			want:    approved,
+			wantErr: false,
+		},
+		{
+			name: "Reply approved",
+			resolverFields: resolverFields{
+				getModerationRepo: takeHeld(newHeld(0, 9), nil),
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 9).Return(&models.Comment{ID: 9, Depth: 1, Owner: models.User{ID: 3}}, nil)
+					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).Return(123, nil)
+					return cr
+				},
+				getNotifRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().AddNotifications(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, notifications []*models.Notification) error {
+							if len(notifications) != 1 || notifications[0].UserID != 3 || notifications[0].Type != models.NotificationReply {
+								t.Errorf("unexpected notifications: %v", notifications)
+							}
+							return nil
+						},
+					)
+					return nr
+				},
+			},
+			args: args{ctx: authCtx("moder"), heldCommentID: "4"},
+			want: &model.Comment{
+				ID:              "123",
+				Owner:           &model.User{ID: "2", Username: "author"},
+				Text:            "Hello",
+				TextFormat:      model.TextFormatPlain,
+				CreatedAt:       createdAt,
+				ParentCommentID: func() *string { v := "9"; return &v }(),
+				Depth:           2,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			var postRepo repository.PostRepo
+			if tt.resolverFields.getPostRepo != nil {
+				postRepo = tt.resolverFields.getPostRepo(c)
+			}
+			var commentRepo repository.CommentRepo
+			if tt.resolverFields.getCommentRepo != nil {
+				commentRepo = tt.resolverFields.getCommentRepo(c)
+			}
+			var notificationRepo repository.NotificationRepo
+			if tt.resolverFields.getNotifRepo != nil {
+				notificationRepo = tt.resolverFields.getNotifRepo(c)
+			}
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:           logger.Sugar(),
+					Cfg:              cfg.Cfg{Moderators: []string{"moder"}},
+					ModerationRepo:   tt.resolverFields.getModerationRepo(c),
+					PostRepo:         postRepo,
+					CommentRepo:      commentRepo,
+					NotificationRepo: notificationRepo,
+				},
+			}
+			got, err := r.ApproveComment(tt.args.ctx, tt.args.heldCommentID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApproveComment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApproveComment() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+	"time"
+)
+
+// ApprovePost is the resolver for the approvePost field.
+func (r *mutationResolver) ApprovePost(ctx context.Context, postID string) (*model.Post, error) {
+	post, err := r.releaseHeldPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	//the post is a draft now, publish it as its owner requested
+	now := time.Now()
+	switch {
+	case post.PublishAt == nil:
+		return newPostModel(post), nil
+	case post.PublishAt.After(now):
+		if err = r.PostRepo.SchedulePost(ctx, post.ID, *post.PublishAt); err != nil {
+			r.Logger.Debugf("cant schedule approved post, err: %v", err)
+			return nil, apperrors.NewInternal(err)
+		}
+		post.Status = models.PostScheduled
+	default:
+		if err = r.PostRepo.PublishPost(ctx, post.ID, now); err != nil {
+			r.Logger.Debugf("cant publish approved post, err: %v", err)
+			return nil, apperrors.NewInternal(err)
+		}
+		post.Status = models.PostPublished
+		post.PublishAt = &now
+	}
+	return newPostModel(post), nil
+}
+
+// releaseHeldPost checks that the current user is a moderator and makes a held post a draft.
+// The returned post keeps its requested publish time.
+func (r *Resolver) releaseHeldPost(ctx context.Context, postID string) (*models.Post, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+	if !r.isModerator(user) {
+		r.Logger.Debugf("user \"%v\" is not a moderator", user.Login)
+		return nil, apperrors.New(apperrors.Forbidden, "moderators only")
+	}
+
+	postIDInt, err := strconv.Atoi(postID)
+	if err != nil {
+		r.Logger.Debugf("cant convert postID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "post id is not int")
+	}
+
+	post, err := r.PostRepo.GetPostByID(ctx, postIDInt)
+	if err != nil {
+		r.Logger.Debugf("cant get post from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	if post.Status != models.PostHeld {
+		r.Logger.Debugf("post is not held, status is \"%v\"", post.Status)
+		return nil, apperrors.New(apperrors.Conflict, "post is not held for review")
+	}
+
+	if err = r.PostRepo.ReleaseHeldPost(ctx, postIDInt); err != nil {
+		r.Logger.Debugf("cant release held post, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		if errors.Is(err, repository.NewErrConflict()) {
+			//approved or rejected concurrently
+			return nil, apperrors.New(apperrors.Conflict, "post is not held for review")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	post.Status = models.PostDraft
+	post.ModerationReasons = nil
+	return post, nil
+}
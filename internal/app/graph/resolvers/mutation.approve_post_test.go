@@ -0,0 +1,192 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_mutationResolver_ApprovePost(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		postID string
+	}
+	type resolverFields struct {
+		getPostRepo func(c *gomock.Controller) repository.PostRepo
+	}
+	authCtx := func(login string) context.Context {
+		user := &models.User{ID: 1, Login: login}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	future := time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC)
+	past := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	newPost := func(status models.PostStatus, publishAt *time.Time) *models.Post {
+		return &models.Post{
+			ID:                5,
+			Title:             "Title",
+			Owner:             models.User{ID: 2, Login: "owner"},
+			Status:            status,
+			PublishAt:         publishAt,
+			ModerationReasons: []string{"more than 3 links"},
+		}
+	}
+	getPostRepo := func(post *models.Post, err error) func(c *gomock.Controller) repository.PostRepo {
+		return func(c *gomock.Controller) repository.PostRepo {
+			pr := mocks.NewMockPostRepo(c)
+			pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(post, err)
+			return pr
+		}
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		wantStatus     model.PostStatus
+		wantPublishAt  *time.Time
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: context.Background(), postID: "5"},
+			wantErr:        true,
+		},
+		{
+			name:           "Not a moderator",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx("qwerty"), postID: "5"},
+			wantErr:        true,
+		},
+		{
+			name:           "postID is not int",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx("moder"), postID: "abc"},
+			wantErr:        true,
+		},
+		{
+			name:           "Post not found",
+			resolverFields: resolverFields{getPostRepo: getPostRepo(nil, repository.NewErrNotFound())},
+			args:           args{ctx: authCtx("moder"), postID: "5"},
+			wantErr:        true,
+		},
+		{
+			name:           "Post is not held",
+			resolverFields: resolverFields{getPostRepo: getPostRepo(newPost(models.PostPublished, &past), nil)},
+			args:           args{ctx: authCtx("moder"), postID: "5"},
+			wantErr:        true,
+		},
+		{
+			name: "Released concurrently",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(newPost(models.PostHeld, &past), nil)
+					pr.EXPECT().ReleaseHeldPost(gomock.Any(), 5).Return(repository.NewErrConflict())
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx("moder"), postID: "5"},
+			wantErr: true,
+		},
+		{
+			name: "Draft stays a draft",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(newPost(models.PostHeld, nil), nil)
+					pr.EXPECT().ReleaseHeldPost(gomock.Any(), 5).Return(nil)
+					return pr
+				},
+			},
+			args:       args{ctx: authCtx("moder"), postID: "5"},
+			wantStatus: model.PostStatusDraft,
+			wantErr:    false,
+		},
+		{
+			name: "Scheduled",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(newPost(models.PostHeld, &future), nil)
+					pr.EXPECT().ReleaseHeldPost(gomock.Any(), 5).Return(nil)
+					pr.EXPECT().SchedulePost(gomock.Any(), 5, future).Return(nil)
+					return pr
+				},
+			},
+			args:          args{ctx: authCtx("moder"), postID: "5"},
+			wantStatus:    model.PostStatusScheduled,
+			wantPublishAt: &future,
+			wantErr:       false,
+		},
+		{
+			name: "Published",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(newPost(models.PostHeld, &past), nil)
+					pr.EXPECT().ReleaseHeldPost(gomock.Any(), 5).Return(nil)
+					pr.EXPECT().PublishPost(gomock.Any(), 5, gomock.Any()).Return(nil)
+					return pr
+				},
+			},
+			args:       args{ctx: authCtx("moder"), postID: "5"},
+			wantStatus: model.PostStatusPublished,
+			wantErr:    false,
+		},
+		{
+			name: "Publish failed",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(newPost(models.PostHeld, &past), nil)
+					pr.EXPECT().ReleaseHeldPost(gomock.Any(), 5).Return(nil)
+					pr.EXPECT().PublishPost(gomock.Any(), 5, gomock.Any()).Return(fmt.Errorf("db error"))
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx("moder"), postID: "5"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:   logger.Sugar(),
+					Cfg:      cfg.Cfg{Moderators: []string{"moder"}},
+					PostRepo: tt.resolverFields.getPostRepo(c),
+				},
+			}
+			got, err := r.ApprovePost(tt.args.ctx, tt.args.postID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApprovePost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("ApprovePost() status = %v, want %v", got.Status, tt.wantStatus)
+			}
+			if tt.wantPublishAt != nil && !reflect.DeepEqual(got.PublishAt, tt.wantPublishAt) {
+				t.Errorf("ApprovePost() publishAt = %v, want %v", got.PublishAt, tt.wantPublishAt)
+			}
+			if len(got.ModerationReasons) != 0 {
+				t.Errorf("ApprovePost() moderationReasons = %v, want empty", got.ModerationReasons)
+			}
+		})
+	}
+}
@@ -3,9 +3,9 @@ package resolvers
 import (
 	"context"
 	"errors"
-	"fmt"
 	"ozon_test_task/internal/app/graph/model"
 	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
 	"ozon_test_task/pkg/authUtils"
 )
 
@@ -15,28 +15,32 @@ func (r *mutationResolver) Auth(ctx context.Context, username string, password s
 	//pre-check data
 	if len(username) == 0 {
 		r.Logger.Debugf("username is empty")
-		return nil, fmt.Errorf("username cannot be empty")
+		return nil, apperrors.New(apperrors.Validation, "username cannot be empty")
 	}
 	if len(password) == 0 {
 		r.Logger.Debugf("password cannot be empty")
-		return nil, fmt.Errorf("password cannot be empty")
+		return nil, apperrors.New(apperrors.Validation, "password cannot be empty")
 	}
 
 	//auth
-	user, err := r.UserRepo.GetUserByLoginWithCred(ctx, username)
+	user, cred, err := r.UserRepo.GetUserByLoginWithCred(ctx, username)
 	if err != nil {
 		r.Logger.Debugf("cant get user from db, err: %v", err)
 		if errors.Is(err, repository.NewErrNotFound()) {
-			return nil, fmt.Errorf("user not found")
+			return nil, apperrors.New(apperrors.NotFound, "user not found")
 		}
-		return nil, fmt.Errorf("failed to get user by login: %w", err)
+		return nil, apperrors.NewInternal(err)
 	}
 
-	if authUtils.CheckPassword(password, user.PasswordHash, user.PasswordSalt) {
+	if authUtils.CheckPassword(password, cred.PasswordHash, cred.PasswordSalt) {
+		if user.Banned {
+			r.Logger.Debugf("user \"%v\" is banned", user.Login)
+			return nil, apperrors.New(apperrors.Forbidden, "user is banned")
+		}
 		jwt, err := r.JWTManager.BuildNewJWTString(user.ID)
 		if err != nil {
 			r.Logger.Debugf("cant build jwt string, err: %v", err)
-			return nil, fmt.Errorf("internal server error")
+			return nil, apperrors.NewInternal(err)
 		}
 		return &model.AuthResponse{
 			Token: jwt,
@@ -45,5 +49,5 @@ func (r *mutationResolver) Auth(ctx context.Context, username string, password s
 	}
 
 	r.Logger.Debugf("wrong password, returning \"user not found\" error due to secure reasons")
-	return nil, fmt.Errorf("user not found")
+	return nil, apperrors.New(apperrors.NotFound, "user not found")
 }
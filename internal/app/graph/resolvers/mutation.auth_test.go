@@ -74,7 +74,7 @@ func Test_mutationResolver_Auth(t *testing.T) {
 			resolverFields: resolverFields{
 				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
 					ur := mocks.NewMockUserRepo(c)
-					ur.EXPECT().GetUserByLoginWithCred(gomock.Any(), "notfound").Return(nil, repository.NewErrNotFound())
+					ur.EXPECT().GetUserByLoginWithCred(gomock.Any(), "notfound").Return(nil, nil, repository.NewErrNotFound())
 					return ur
 				},
 				getJWTManager: func(c *gomock.Controller) middlewares.JWTManager {
@@ -94,7 +94,7 @@ func Test_mutationResolver_Auth(t *testing.T) {
 			resolverFields: resolverFields{
 				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
 					ur := mocks.NewMockUserRepo(c)
-					ur.EXPECT().GetUserByLoginWithCred(gomock.Any(), "someuser").Return(nil, fmt.Errorf("db error"))
+					ur.EXPECT().GetUserByLoginWithCred(gomock.Any(), "someuser").Return(nil, nil, fmt.Errorf("db error"))
 					return ur
 				},
 				getJWTManager: func(c *gomock.Controller) middlewares.JWTManager {
@@ -115,8 +115,9 @@ func Test_mutationResolver_Auth(t *testing.T) {
 				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
 					ur := mocks.NewMockUserRepo(c)
 					ur.EXPECT().GetUserByLoginWithCred(gomock.Any(), "someuser").Return(&models.User{
-						ID:           1,
-						Login:        "someuser",
+						ID:    1,
+						Login: "someuser",
+					}, &models.Credentials{
 						PasswordHash: "hash",
 						PasswordSalt: "salt",
 					}, nil)
@@ -134,6 +135,34 @@ func Test_mutationResolver_Auth(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "User is banned",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					passwordHash := authUtils.HashPassword("somepass", "salt")
+					ur.EXPECT().GetUserByLoginWithCred(gomock.Any(), "someuser").Return(&models.User{
+						ID:     1,
+						Login:  "someuser",
+						Banned: true,
+					}, &models.Credentials{
+						PasswordHash: passwordHash,
+						PasswordSalt: "salt",
+					}, nil)
+					return ur
+				},
+				getJWTManager: func(c *gomock.Controller) middlewares.JWTManager {
+					return mwmocks.NewMockJWTManager(c)
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				username: "someuser",
+				password: "somepass",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Ok",
 			resolverFields: resolverFields{
@@ -141,8 +170,9 @@ func Test_mutationResolver_Auth(t *testing.T) {
 					ur := mocks.NewMockUserRepo(c)
 					passwordHash := authUtils.HashPassword("somepass", "salt")
 					ur.EXPECT().GetUserByLoginWithCred(gomock.Any(), "someuser").Return(&models.User{
-						ID:           1,
-						Login:        "someuser",
+						ID:    1,
+						Login: "someuser",
+					}, &models.Credentials{
 						PasswordHash: passwordHash,
 						PasswordSalt: "salt",
 					}, nil)
@@ -0,0 +1,128 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_mutationResolver_BlockCommenter(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		userID string
+	}
+	type resolverFields struct {
+		getBlockRepo func(c *gomock.Controller) repository.BlockRepo
+		getUserRepo  func(c *gomock.Controller) repository.UserRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noBlockRepo := func(c *gomock.Controller) repository.BlockRepo { return mocks.NewMockBlockRepo(c) }
+	noUserRepo := func(c *gomock.Controller) repository.UserRepo { return mocks.NewMockUserRepo(c) }
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.User
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getBlockRepo: noBlockRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: context.Background(), userID: "2"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "userID is not int",
+			resolverFields: resolverFields{getBlockRepo: noBlockRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: authCtx(), userID: "abc"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Block yourself",
+			resolverFields: resolverFields{getBlockRepo: noBlockRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: authCtx(), userID: "1"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "User not found",
+			resolverFields: resolverFields{
+				getBlockRepo: func(c *gomock.Controller) repository.BlockRepo {
+					br := mocks.NewMockBlockRepo(c)
+					br.EXPECT().BlockCommenter(gomock.Any(), 1, 2, gomock.Any()).Return(repository.NewErrNotFound())
+					return br
+				},
+				getUserRepo: noUserRepo,
+			},
+			args:    args{ctx: authCtx(), userID: "2"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getBlockRepo: func(c *gomock.Controller) repository.BlockRepo {
+					br := mocks.NewMockBlockRepo(c)
+					br.EXPECT().BlockCommenter(gomock.Any(), 1, 2, gomock.Any()).Return(fmt.Errorf("db error"))
+					return br
+				},
+				getUserRepo: noUserRepo,
+			},
+			args:    args{ctx: authCtx(), userID: "2"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getBlockRepo: func(c *gomock.Controller) repository.BlockRepo {
+					br := mocks.NewMockBlockRepo(c)
+					br.EXPECT().BlockCommenter(gomock.Any(), 1, 2, gomock.Any()).Return(nil)
+					return br
+				},
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByID(gomock.Any(), 2).Return(&models.User{ID: 2, Login: "blocked"}, nil)
+					return ur
+				},
+			},
+			args:    args{ctx: authCtx(), userID: "2"},
+			want:    &model.User{ID: "2", Username: "blocked"},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:    logger.Sugar(),
+					BlockRepo: tt.resolverFields.getBlockRepo(c),
+					UserRepo:  tt.resolverFields.getUserRepo(c),
+				},
+			}
+			got, err := r.BlockCommenter(tt.args.ctx, tt.args.userID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BlockCommenter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BlockCommenter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+	"time"
+)
+
+// BookmarkPost is the resolver for the bookmarkPost field.
+func (r *mutationResolver) BookmarkPost(ctx context.Context, postID string) (*model.Post, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+	postIDInt, err := strconv.Atoi(postID)
+	if err != nil {
+		r.Logger.Debugf("cant convert postID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "postID is not an int")
+	}
+
+	//only published posts can be bookmarked
+	post, err := r.PostRepo.GetPostByID(ctx, postIDInt)
+	if err != nil {
+		r.Logger.Debugf("cant get post from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	if post.Status != models.PostPublished {
+		r.Logger.Debugf("post is not published")
+		return nil, apperrors.New(apperrors.NotFound, "post not found")
+	}
+
+	if err = r.BookmarkRepo.AddBookmark(ctx, user.ID, postIDInt, time.Now()); err != nil {
+		r.Logger.Debugf("cant bookmark post, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	return newPostModel(post), nil
+}
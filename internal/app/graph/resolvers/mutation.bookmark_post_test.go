@@ -0,0 +1,140 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_mutationResolver_BookmarkPost(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		postID string
+	}
+	type resolverFields struct {
+		getBookmarkRepo func(c *gomock.Controller) repository.BookmarkRepo
+		getPostRepo     func(c *gomock.Controller) repository.PostRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noBookmarkRepo := func(c *gomock.Controller) repository.BookmarkRepo { return mocks.NewMockBookmarkRepo(c) }
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	post := &models.Post{ID: 5, Title: "Title", Owner: models.User{ID: 2, Login: "bob"}, Status: models.PostPublished}
+	draft := &models.Post{ID: 5, Title: "Title", Owner: models.User{ID: 2, Login: "bob"}, Status: models.PostDraft}
+	getPostRepo := func(p *models.Post, err error) func(c *gomock.Controller) repository.PostRepo {
+		return func(c *gomock.Controller) repository.PostRepo {
+			pr := mocks.NewMockPostRepo(c)
+			pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(p, err)
+			return pr
+		}
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.Post
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getBookmarkRepo: noBookmarkRepo, getPostRepo: noPostRepo},
+			args:           args{ctx: context.Background(), postID: "5"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "postID is not int",
+			resolverFields: resolverFields{getBookmarkRepo: noBookmarkRepo, getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx(), postID: "abc"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Post not found",
+			resolverFields: resolverFields{getBookmarkRepo: noBookmarkRepo, getPostRepo: getPostRepo(nil, repository.NewErrNotFound())},
+			args:           args{ctx: authCtx(), postID: "5"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Post is a draft",
+			resolverFields: resolverFields{getBookmarkRepo: noBookmarkRepo, getPostRepo: getPostRepo(draft, nil)},
+			args:           args{ctx: authCtx(), postID: "5"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "Post deleted concurrently",
+			resolverFields: resolverFields{
+				getBookmarkRepo: func(c *gomock.Controller) repository.BookmarkRepo {
+					br := mocks.NewMockBookmarkRepo(c)
+					br.EXPECT().AddBookmark(gomock.Any(), 1, 5, gomock.Any()).Return(repository.NewErrNotFound())
+					return br
+				},
+				getPostRepo: getPostRepo(post, nil),
+			},
+			args:    args{ctx: authCtx(), postID: "5"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getBookmarkRepo: func(c *gomock.Controller) repository.BookmarkRepo {
+					br := mocks.NewMockBookmarkRepo(c)
+					br.EXPECT().AddBookmark(gomock.Any(), 1, 5, gomock.Any()).Return(fmt.Errorf("db error"))
+					return br
+				},
+				getPostRepo: getPostRepo(post, nil),
+			},
+			args:    args{ctx: authCtx(), postID: "5"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getBookmarkRepo: func(c *gomock.Controller) repository.BookmarkRepo {
+					br := mocks.NewMockBookmarkRepo(c)
+					br.EXPECT().AddBookmark(gomock.Any(), 1, 5, gomock.Any()).Return(nil)
+					return br
+				},
+				getPostRepo: getPostRepo(post, nil),
+			},
+			args:    args{ctx: authCtx(), postID: "5"},
+			want:    newPostModel(post),
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:       logger.Sugar(),
+					BookmarkRepo: tt.resolverFields.getBookmarkRepo(c),
+					PostRepo:     tt.resolverFields.getPostRepo(c),
+				},
+			}
+			got, err := r.BookmarkPost(tt.args.ctx, tt.args.postID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookmarkPost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookmarkPost() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// CreateComment is the resolver for the createComment field.
+func (r *mutationResolver) CreateComment(ctx context.Context, postID string, text string, textFormat model.TextFormat, clientMutationID *string) (model.AddCommentResult, error) {
+	result, err := r.addComment(ctx, postID, text, textFormat, clientMutationID)
+	if err == nil {
+		return result, nil
+	}
+
+	var appErr *apperrors.Error
+	if !errors.As(err, &appErr) {
+		return nil, err
+	}
+	switch appErr.Code {
+	case apperrors.Validation:
+		return newValidationErrorResult(appErr), nil
+	case apperrors.NotFound:
+		return &model.PostNotFound{PostID: postID, Message: appErr.Message}, nil
+	case apperrors.Forbidden:
+		if errors.Is(err, errCommentsClosed) {
+			return &model.CommentsClosed{Message: appErr.Message}, nil
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,227 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_mutationResolver_CreateComment(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		postID string
+		text   string
+	}
+	type resolverFields struct {
+		getPostRepo    func(c *gomock.Controller) repository.PostRepo
+		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+		getBlockRepo   func(c *gomock.Controller) repository.BlockRepo
+		getModRepo     func(c *gomock.Controller) repository.ModerationRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	postRepo := func(post *models.Post, err error) func(c *gomock.Controller) repository.PostRepo {
+		return func(c *gomock.Controller) repository.PostRepo {
+			pr := mocks.NewMockPostRepo(c)
+			pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(post, err)
+			return pr
+		}
+	}
+	openPost := &models.Post{ID: 1, Status: models.PostPublished, CommentsAllowed: true}
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	noCommentRepo := func(c *gomock.Controller) repository.CommentRepo { return mocks.NewMockCommentRepo(c) }
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           model.AddCommentResult
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getPostRepo: noPostRepo, getCommentRepo: noCommentRepo},
+			args:           args{ctx: context.Background(), postID: "1", text: "Hello"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Comment text too long",
+			resolverFields: resolverFields{getPostRepo: noPostRepo, getCommentRepo: noCommentRepo},
+			args:           args{ctx: authCtx(), postID: "1", text: "01234567890"},
+			want: &model.ValidationError{
+				Message: "invalid input: text: must be at most 10 characters long",
+				Fields:  []*model.FieldError{{Path: "text", Message: "must be at most 10 characters long"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:           "postID is not int",
+			resolverFields: resolverFields{getPostRepo: noPostRepo, getCommentRepo: noCommentRepo},
+			args:           args{ctx: authCtx(), postID: "abc", text: "Hello"},
+			want:           &model.ValidationError{Message: "postID is not int", Fields: []*model.FieldError{}},
+			wantErr:        false,
+		},
+		{
+			name:           "Post not found",
+			resolverFields: resolverFields{getPostRepo: postRepo(nil, repository.NewErrNotFound()), getCommentRepo: noCommentRepo},
+			args:           args{ctx: authCtx(), postID: "1", text: "Hello"},
+			want:           &model.PostNotFound{PostID: "1", Message: "post not found"},
+			wantErr:        false,
+		},
+		{
+			name: "Comments closed",
+			resolverFields: resolverFields{
+				getPostRepo:    postRepo(&models.Post{ID: 1, Status: models.PostPublished, CommentsAllowed: false}, nil),
+				getCommentRepo: noCommentRepo,
+			},
+			args:    args{ctx: authCtx(), postID: "1", text: "Hello"},
+			want:    &model.CommentsClosed{Message: "Comment is not allowed to this post"},
+			wantErr: false,
+		},
+		{
+			name:           "Failed to get post",
+			resolverFields: resolverFields{getPostRepo: postRepo(nil, fmt.Errorf("db error")), getCommentRepo: noCommentRepo},
+			args:           args{ctx: authCtx(), postID: "1", text: "Hello"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "Blocked by post owner",
+			resolverFields: resolverFields{
+				getPostRepo:    postRepo(&models.Post{ID: 1, Owner: models.User{ID: 5}, Status: models.PostPublished, CommentsAllowed: true}, nil),
+				getCommentRepo: noCommentRepo,
+				getBlockRepo: func(c *gomock.Controller) repository.BlockRepo {
+					br := mocks.NewMockBlockRepo(c)
+					br.EXPECT().IsCommenterBlocked(gomock.Any(), 5, 1).Return(true, nil)
+					return br
+				},
+			},
+			args:    args{ctx: authCtx(), postID: "1", text: "Hello"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Failed to create comment",
+			resolverFields: resolverFields{
+				getPostRepo: postRepo(openPost, nil),
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).Return(0, fmt.Errorf("db error"))
+					return cr
+				},
+			},
+			args:    args{ctx: authCtx(), postID: "1", text: "Hello"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Approval required",
+			resolverFields: resolverFields{
+				getPostRepo: postRepo(&models.Post{
+					ID:              1,
+					Owner:           models.User{ID: 5},
+					Status:          models.PostPublished,
+					CommentsAllowed: true,
+					CommentPolicy:   models.CommentsApprovalRequired,
+				}, nil),
+				getCommentRepo: noCommentRepo,
+				getModRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().HoldComment(gomock.Any(), gomock.Any()).Return(8, nil)
+					return mr
+				},
+			},
+			args: args{ctx: authCtx(), postID: "1", text: "Hello"},
+			want: &model.AddCommentSuccess{
+				HeldComment: &model.HeldComment{
+					ID:         "8",
+					Owner:      &model.User{ID: "1", Username: "qwerty"},
+					PostID:     func() *string { v := "1"; return &v }(),
+					Text:       "Hello",
+					TextFormat: model.TextFormatPlain,
+					Reasons:    []string{approvalRequiredReason},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getPostRepo: postRepo(openPost, nil),
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).Return(123, nil)
+					return cr
+				},
+			},
+			args: args{ctx: authCtx(), postID: "1", text: "Hello"},
+			want: &model.AddCommentSuccess{
+				Comment: &model.Comment{
+					ID:         "123",
+					Owner:      &model.User{ID: "1", Username: "qwerty"},
+					Text:       "Hello",
+					TextFormat: model.TextFormatPlain,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			sugar := logger.Sugar()
+			c := gomock.NewController(t)
+			var blockRepo repository.BlockRepo
+			if tt.resolverFields.getBlockRepo != nil {
+				blockRepo = tt.resolverFields.getBlockRepo(c)
+			} else {
+				//nobody is blocked by default
+				br := mocks.NewMockBlockRepo(c)
+				br.EXPECT().IsCommenterBlocked(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+				blockRepo = br
+			}
+			var moderationRepo repository.ModerationRepo
+			if tt.resolverFields.getModRepo != nil {
+				moderationRepo = tt.resolverFields.getModRepo(c)
+			}
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:         sugar,
+					Cfg:            cfg.Cfg{MaxCommentTextLength: 10},
+					PostRepo:       tt.resolverFields.getPostRepo(c),
+					CommentRepo:    tt.resolverFields.getCommentRepo(c),
+					BlockRepo:      blockRepo,
+					ModerationRepo: moderationRepo,
+				},
+			}
+			got, err := r.CreateComment(tt.args.ctx, tt.args.postID, tt.args.text, model.TextFormatPlain, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateComment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if success, ok := got.(*model.AddCommentSuccess); ok {
+				if success.Comment != nil {
+					success.Comment.CreatedAt = time.Time{}
+				}
+				if success.HeldComment != nil {
+					success.HeldComment.CreatedAt = time.Time{}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateComment() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
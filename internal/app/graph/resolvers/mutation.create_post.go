@@ -0,0 +1,23 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/pkg/apperrors"
+	"time"
+)
+
+// CreatePost is the resolver for the createPost field.
+func (r *mutationResolver) CreatePost(ctx context.Context, title string, text string, commentsAllowed *bool, tags []string, status model.PostStatus, publishAt *time.Time, textFormat model.TextFormat, clientMutationID *string) (model.AddPostResult, error) {
+	resp, err := r.AddPost(ctx, title, text, commentsAllowed, tags, status, publishAt, textFormat, clientMutationID)
+	if err == nil {
+		return &model.AddPostSuccess{Post: resp.Post}, nil
+	}
+
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) && appErr.Code == apperrors.Validation {
+		return newValidationErrorResult(appErr), nil
+	}
+	return nil, err
+}
@@ -0,0 +1,134 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_mutationResolver_CreatePost(t *testing.T) {
+	type args struct {
+		ctx             context.Context
+		title           string
+		text            string
+		commentsAllowed *bool
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	allowed := func(v bool) *bool { return &v }
+	created := &model.AddPostSuccess{
+		Post: &model.Post{
+			ID:              "42",
+			Title:           "Title",
+			Text:            "Text",
+			TextFormat:      model.TextFormatPlain,
+			Owner:           &model.User{ID: "1", Username: "qwerty"},
+			CommentsAllowed: true,
+			CommentPolicy:   model.CommentPolicyOpen,
+			Status:          model.PostStatusPublished,
+		},
+	}
+	tests := []struct {
+		name        string
+		getPostRepo func(c *gomock.Controller) repository.PostRepo
+		args        args
+		want        model.AddPostResult
+		wantErr     bool
+	}{
+		{
+			name:        "Not authorized",
+			getPostRepo: noPostRepo,
+			args:        args{ctx: context.Background(), title: "Title", text: "Text", commentsAllowed: allowed(true)},
+			want:        nil,
+			wantErr:     true,
+		},
+		{
+			name:        "Invalid fields",
+			getPostRepo: noPostRepo,
+			args:        args{ctx: authCtx(), title: "", text: "Text", commentsAllowed: allowed(true)},
+			want: &model.ValidationError{
+				Message: "invalid input: title: must not be empty",
+				Fields:  []*model.FieldError{{Path: "title", Message: "must not be empty"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Failed to create post",
+			getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+				pr := mocks.NewMockPostRepo(c)
+				pr.EXPECT().AddPost(gomock.Any(), gomock.Any()).Return(0, fmt.Errorf("db error"))
+				return pr
+			},
+			args:    args{ctx: authCtx(), title: "Title", text: "Text", commentsAllowed: allowed(true)},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+				pr := mocks.NewMockPostRepo(c)
+				pr.EXPECT().AddPost(gomock.Any(), gomock.Any()).Return(42, nil)
+				return pr
+			},
+			args:    args{ctx: authCtx(), title: "Title", text: "Text", commentsAllowed: allowed(true)},
+			want:    created,
+			wantErr: false,
+		},
+		{
+			name: "Null commentsAllowed allows comments",
+			getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+				pr := mocks.NewMockPostRepo(c)
+				pr.EXPECT().AddPost(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, post *models.Post) (int, error) {
+					if !post.CommentsAllowed || post.CommentPolicy != models.CommentsOpen {
+						t.Errorf("AddPost() got post with CommentsAllowed %v and policy %v, want open comments", post.CommentsAllowed, post.CommentPolicy)
+					}
+					return 42, nil
+				})
+				return pr
+			},
+			args:    args{ctx: authCtx(), title: "Title", text: "Text", commentsAllowed: nil},
+			want:    created,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			sugar := logger.Sugar()
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:   sugar,
+					Cfg:      cfg.Cfg{MaxPostTags: 2},
+					PostRepo: tt.getPostRepo(c),
+				},
+			}
+			got, err := r.CreatePost(tt.args.ctx, tt.args.title, tt.args.text, tt.args.commentsAllowed, nil, model.PostStatusPublished, nil, model.TextFormatPlain, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreatePost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if success, ok := got.(*model.AddPostSuccess); ok {
+				success.Post.CreatedAt = time.Time{}
+				success.Post.UpdatedAt = time.Time{}
+				success.Post.PublishAt = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreatePost() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
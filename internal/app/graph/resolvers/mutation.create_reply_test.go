@@ -0,0 +1,248 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_mutationResolver_CreateReply(t *testing.T) {
+	type args struct {
+		ctx             context.Context
+		parentCommentID string
+		text            string
+	}
+	type resolverFields struct {
+		cfg            cfg.Cfg
+		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+		post           *models.Post
+		getPostErr     error
+		getBlockRepo   func(c *gomock.Controller) repository.BlockRepo
+		getModRepo     func(c *gomock.Controller) repository.ModerationRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	parentRepo := func(c *gomock.Controller) *mocks.MockCommentRepo {
+		cr := mocks.NewMockCommentRepo(c)
+		cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(&models.Comment{ID: 10, PostID: 1, Owner: models.User{ID: 1, Login: "qwerty"}}, nil)
+		return cr
+	}
+	openPost := &models.Post{ID: 1, Owner: models.User{ID: 5}, Status: models.PostPublished, CommentsAllowed: true}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           model.AddReplyResult
+		wantErr        bool
+	}{
+		{
+			name: "Not authorized",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo { return mocks.NewMockCommentRepo(c) },
+			},
+			args:    args{ctx: context.Background(), parentCommentID: "10", text: "Hello"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Reply text too long",
+			resolverFields: resolverFields{
+				cfg:            cfg.Cfg{MaxCommentTextLength: 3},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo { return mocks.NewMockCommentRepo(c) },
+			},
+			args: args{ctx: authCtx(), parentCommentID: "10", text: "Hello"},
+			want: &model.ValidationError{
+				Message: "invalid input: text: must be at most 3 characters long",
+				Fields:  []*model.FieldError{{Path: "text", Message: "must be at most 3 characters long"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Parent comment not found",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxCommentTextLength: 100},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 10).Return(nil, repository.NewErrNotFound())
+					return cr
+				},
+			},
+			args:    args{ctx: authCtx(), parentCommentID: "10", text: "Hello"},
+			want:    &model.CommentNotFound{CommentID: "10", Message: "parent comment not found"},
+			wantErr: false,
+		},
+		{
+			name: "Comments closed",
+			resolverFields: resolverFields{
+				cfg:            cfg.Cfg{MaxCommentTextLength: 100},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo { return parentRepo(c) },
+				post:           &models.Post{ID: 1, Owner: models.User{ID: 5}, Status: models.PostPublished, CommentPolicy: models.CommentsClosed},
+			},
+			args:    args{ctx: authCtx(), parentCommentID: "10", text: "Hello"},
+			want:    &model.CommentsClosed{Message: "Comment is not allowed to this post"},
+			wantErr: false,
+		},
+		{
+			name: "Failed to get post",
+			resolverFields: resolverFields{
+				cfg:            cfg.Cfg{MaxCommentTextLength: 100},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo { return parentRepo(c) },
+				getPostErr:     fmt.Errorf("db error"),
+			},
+			args:    args{ctx: authCtx(), parentCommentID: "10", text: "Hello"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Blocked by post owner",
+			resolverFields: resolverFields{
+				cfg:            cfg.Cfg{MaxCommentTextLength: 100},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo { return parentRepo(c) },
+				getBlockRepo: func(c *gomock.Controller) repository.BlockRepo {
+					br := mocks.NewMockBlockRepo(c)
+					br.EXPECT().IsCommenterBlocked(gomock.Any(), 5, 1).Return(true, nil)
+					return br
+				},
+			},
+			args:    args{ctx: authCtx(), parentCommentID: "10", text: "Hello"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Failed to create reply",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxCommentTextLength: 100},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := parentRepo(c)
+					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).Return(0, fmt.Errorf("db error"))
+					return cr
+				},
+			},
+			args:    args{ctx: authCtx(), parentCommentID: "10", text: "Hello"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Approval required",
+			resolverFields: resolverFields{
+				cfg:            cfg.Cfg{MaxCommentTextLength: 100},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo { return parentRepo(c) },
+				post: &models.Post{
+					ID:              1,
+					Owner:           models.User{ID: 5},
+					Status:          models.PostPublished,
+					CommentsAllowed: true,
+					CommentPolicy:   models.CommentsApprovalRequired,
+				},
+				getModRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().HoldComment(gomock.Any(), gomock.Any()).Return(8, nil)
+					return mr
+				},
+			},
+			args: args{ctx: authCtx(), parentCommentID: "10", text: "Hello"},
+			want: &model.AddReplySuccess{
+				HeldComment: &model.HeldComment{
+					ID:              "8",
+					Owner:           &model.User{ID: "1", Username: "qwerty"},
+					ParentCommentID: func() *string { v := "10"; return &v }(),
+					Text:            "Hello",
+					TextFormat:      model.TextFormatPlain,
+					Reasons:         []string{approvalRequiredReason},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxCommentTextLength: 100},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := parentRepo(c)
+					cr.EXPECT().AddComment(gomock.Any(), gomock.Any()).Return(123, nil)
+					return cr
+				},
+			},
+			args: args{ctx: authCtx(), parentCommentID: "10", text: "Hello"},
+			want: &model.AddReplySuccess{
+				Comment: &model.Comment{
+					ID:              "123",
+					Owner:           &model.User{ID: "1", Username: "qwerty"},
+					Text:            "Hello",
+					TextFormat:      model.TextFormatPlain,
+					ParentCommentID: func() *string { v := "10"; return &v }(),
+					Depth:           1,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			sugar := logger.Sugar()
+			c := gomock.NewController(t)
+			post := tt.resolverFields.post
+			if post == nil {
+				post = openPost
+			}
+			pr := mocks.NewMockPostRepo(c)
+			if tt.resolverFields.getPostErr != nil {
+				pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(nil, tt.resolverFields.getPostErr)
+			} else {
+				pr.EXPECT().GetPostByID(gomock.Any(), 1).Return(post, nil).AnyTimes()
+			}
+			var blockRepo repository.BlockRepo
+			if tt.resolverFields.getBlockRepo != nil {
+				blockRepo = tt.resolverFields.getBlockRepo(c)
+			} else {
+				//nobody is blocked by default
+				br := mocks.NewMockBlockRepo(c)
+				br.EXPECT().IsCommenterBlocked(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+				blockRepo = br
+			}
+			var moderationRepo repository.ModerationRepo
+			if tt.resolverFields.getModRepo != nil {
+				moderationRepo = tt.resolverFields.getModRepo(c)
+			}
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:         sugar,
+					Cfg:            tt.resolverFields.cfg,
+					CommentRepo:    tt.resolverFields.getCommentRepo(c),
+					PostRepo:       pr,
+					BlockRepo:      blockRepo,
+					ModerationRepo: moderationRepo,
+				},
+			}
+			got, err := r.CreateReply(tt.args.ctx, tt.args.parentCommentID, tt.args.text, model.TextFormatPlain, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateReply() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if success, ok := got.(*model.AddReplySuccess); ok {
+				if success.Comment != nil {
+					success.Comment.CreatedAt = time.Time{}
+				}
+				if success.HeldComment != nil {
+					success.HeldComment.CreatedAt = time.Time{}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateReply() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+	"time"
+)
+
+// Follow is the resolver for the follow field.
+func (r *mutationResolver) Follow(ctx context.Context, userID string) (*model.User, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+	followeeID, err := strconv.Atoi(userID)
+	if err != nil {
+		r.Logger.Debugf("cant convert userID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "userID is not an int")
+	}
+	if followeeID == user.ID {
+		return nil, apperrors.New(apperrors.Validation, "cant follow yourself")
+	}
+
+	if err = r.FollowRepo.Follow(ctx, user.ID, followeeID, time.Now()); err != nil {
+		r.Logger.Debugf("cant follow user, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "user not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+
+	followee, err := r.UserRepo.GetUserByID(ctx, followeeID)
+	if err != nil {
+		r.Logger.Debugf("cant get user from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+	return newUserModel(followee), nil
+}
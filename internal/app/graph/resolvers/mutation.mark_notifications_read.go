@@ -0,0 +1,43 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// MarkNotificationsRead is the resolver for the markNotificationsRead field.
+func (r *mutationResolver) MarkNotificationsRead(ctx context.Context, ids []string) (int32, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return 0, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	//nil means all notifications, so an empty list must stay non-nil
+	var idsInt []int
+	if ids != nil {
+		idsInt = make([]int, len(ids))
+		for i, id := range ids {
+			idInt, err := strconv.Atoi(id)
+			if err != nil {
+				r.Logger.Debugf("cant convert notification id to int, err: %v", err)
+				return 0, apperrors.New(apperrors.Validation, "notification id is not an int")
+			}
+			idsInt[i] = idInt
+		}
+	}
+
+	if err := r.NotificationRepo.MarkNotificationsRead(ctx, user.ID, idsInt); err != nil {
+		r.Logger.Debugf("cant mark notifications read, err: %v", err)
+		return 0, apperrors.NewInternal(err)
+	}
+	count, err := r.NotificationRepo.CountUnreadNotifications(ctx, user.ID)
+	if err != nil {
+		r.Logger.Debugf("cant count unread notifications, err: %v", err)
+		return 0, apperrors.NewInternal(err)
+	}
+	return int32(count), nil
+}
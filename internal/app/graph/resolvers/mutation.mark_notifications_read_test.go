@@ -0,0 +1,118 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"testing"
+)
+
+func Test_mutationResolver_MarkNotificationsRead(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		ids []string
+	}
+	type resolverFields struct {
+		getNotificationRepo func(c *gomock.Controller) repository.NotificationRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           int32
+		wantErr        bool
+	}{
+		{
+			name: "Not authorized",
+			resolverFields: resolverFields{
+				getNotificationRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					return mocks.NewMockNotificationRepo(c)
+				},
+			},
+			args:    args{ctx: context.Background()},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "ID is not int",
+			resolverFields: resolverFields{
+				getNotificationRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					return mocks.NewMockNotificationRepo(c)
+				},
+			},
+			args:    args{ctx: authCtx(), ids: []string{"1", "abc"}},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getNotificationRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().MarkNotificationsRead(gomock.Any(), 1, []int{1, 2}).Return(fmt.Errorf("db error"))
+					return nr
+				},
+			},
+			args:    args{ctx: authCtx(), ids: []string{"1", "2"}},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "Mark all",
+			resolverFields: resolverFields{
+				getNotificationRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().MarkNotificationsRead(gomock.Any(), 1, nil).Return(nil)
+					nr.EXPECT().CountUnreadNotifications(gomock.Any(), 1).Return(0, nil)
+					return nr
+				},
+			},
+			args:    args{ctx: authCtx()},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "Mark some",
+			resolverFields: resolverFields{
+				getNotificationRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().MarkNotificationsRead(gomock.Any(), 1, []int{1, 2}).Return(nil)
+					nr.EXPECT().CountUnreadNotifications(gomock.Any(), 1).Return(3, nil)
+					return nr
+				},
+			},
+			args:    args{ctx: authCtx(), ids: []string{"1", "2"}},
+			want:    3,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:           logger.Sugar(),
+					NotificationRepo: tt.resolverFields.getNotificationRepo(c),
+				},
+			}
+			got, err := r.MarkNotificationsRead(tt.args.ctx, tt.args.ids)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarkNotificationsRead() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("MarkNotificationsRead() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+)
+
+// PinComment is the resolver for the pinComment field.
+func (r *mutationResolver) PinComment(ctx context.Context, commentID string) (*model.Post, error) {
+	return r.setCommentPinned(ctx, commentID, true)
+}
@@ -0,0 +1,181 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_mutationResolver_PinComment(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		commentID string
+	}
+	type resolverFields struct {
+		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+		getPostRepo    func(c *gomock.Controller) repository.PostRepo
+	}
+	authCtx := context.WithValue(context.Background(), middlewares.UserContextKey, &models.User{ID: 1, Login: "qwerty"})
+	noCommentRepo := func(c *gomock.Controller) repository.CommentRepo { return mocks.NewMockCommentRepo(c) }
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	comment := &models.Comment{ID: 7, PostID: 3, Owner: models.User{ID: 2, Login: "author"}, Text: "Hello", TextFormat: models.TextPlain}
+	getPost := func(ownerID int) func(c *gomock.Controller) repository.PostRepo {
+		return func(c *gomock.Controller) repository.PostRepo {
+			pr := mocks.NewMockPostRepo(c)
+			pr.EXPECT().GetPostByID(gomock.Any(), 3).Return(&models.Post{
+				ID:              3,
+				Title:           "title",
+				Owner:           models.User{ID: ownerID, Login: "owner"},
+				CommentsAllowed: true,
+				CommentPolicy:   models.CommentsOpen,
+			}, nil)
+			return pr
+		}
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.Post
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getCommentRepo: noCommentRepo, getPostRepo: noPostRepo},
+			args:           args{ctx: context.Background(), commentID: "7"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Comment id is not int",
+			resolverFields: resolverFields{getCommentRepo: noCommentRepo, getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx, commentID: "abc"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "Comment not found",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(nil, repository.NewErrNotFound())
+					return cr
+				},
+				getPostRepo: noPostRepo,
+			},
+			args:    args{ctx: authCtx, commentID: "7"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Comment is a reply",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(&models.Comment{ID: 7, ParentID: 4}, nil)
+					return cr
+				},
+				getPostRepo: noPostRepo,
+			},
+			args:    args{ctx: authCtx, commentID: "7"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Not a post owner",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(comment, nil)
+					return cr
+				},
+				getPostRepo: getPost(2),
+			},
+			args:    args{ctx: authCtx, commentID: "7"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Too many pinned comments",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(comment, nil)
+					cr.EXPECT().PinComment(gomock.Any(), 3, 7, gomock.Any(), 3).Return(repository.NewErrConflict())
+					return cr
+				},
+				getPostRepo: getPost(1),
+			},
+			args:    args{ctx: authCtx, commentID: "7"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(comment, nil)
+					cr.EXPECT().PinComment(gomock.Any(), 3, 7, gomock.Any(), 3).Return(fmt.Errorf("db error"))
+					return cr
+				},
+				getPostRepo: getPost(1),
+			},
+			args:    args{ctx: authCtx, commentID: "7"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(comment, nil)
+					cr.EXPECT().PinComment(gomock.Any(), 3, 7, gomock.Any(), 3).Return(nil)
+					return cr
+				},
+				getPostRepo: getPost(1),
+			},
+			args: args{ctx: authCtx, commentID: "7"},
+			want: &model.Post{
+				ID:              "3",
+				Title:           "title",
+				Owner:           &model.User{ID: "1", Username: "owner"},
+				CommentsAllowed: true,
+				CommentPolicy:   model.CommentPolicyOpen,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:      logger.Sugar(),
+					Cfg:         cfg.Cfg{MaxPinnedComments: 3},
+					CommentRepo: tt.resolverFields.getCommentRepo(c),
+					PostRepo:    tt.resolverFields.getPostRepo(c),
+				},
+			}
+			got, err := r.PinComment(tt.args.ctx, tt.args.commentID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PinComment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PinComment() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+	"time"
+)
+
+// PublishPost is the resolver for the publishPost field.
+func (r *mutationResolver) PublishPost(ctx context.Context, postID string, publishAt *time.Time) (*model.Post, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	postIDInt, err := strconv.Atoi(postID)
+	if err != nil {
+		r.Logger.Debugf("cant convert postID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "post id is not int")
+	}
+
+	//check if user is owner of this post
+	post, err := r.PostRepo.GetPostByID(ctx, postIDInt)
+	if err != nil {
+		r.Logger.Debugf("cant get post from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	if user.ID != post.Owner.ID {
+		r.Logger.Debugf("cant publish this post, user is not an owner. UserID is \"%v\", but ownerID is \"%v\"", user.ID, post.Owner.ID)
+		return nil, apperrors.New(apperrors.Forbidden, "cant modify this post")
+	}
+	if post.Status == models.PostHeld {
+		r.Logger.Debugf("cant publish post, it is held for review")
+		return nil, apperrors.New(apperrors.Conflict, "post is held for review")
+	}
+
+	now := time.Now()
+	if publishAt == nil || !publishAt.After(now) {
+		//publish now, publishing twice returns the post as is
+		if post.Status == models.PostPublished {
+			return newPostModel(post), nil
+		}
+		if err = r.PostRepo.PublishPost(ctx, postIDInt, now); err != nil {
+			r.Logger.Debugf("cant publish post, err: %v", err)
+			if errors.Is(err, repository.NewErrConflict()) {
+				//held concurrently
+				return nil, apperrors.New(apperrors.Conflict, "post is held for review")
+			}
+			return nil, apperrors.NewInternal(err)
+		}
+		post.Status = models.PostPublished
+		post.PublishAt = &now
+		return newPostModel(post), nil
+	}
+
+	//schedule
+	if post.Status == models.PostPublished {
+		return nil, apperrors.New(apperrors.Conflict, "post is already published")
+	}
+	if err = r.PostRepo.SchedulePost(ctx, postIDInt, *publishAt); err != nil {
+		r.Logger.Debugf("cant schedule post, err: %v", err)
+		if errors.Is(err, repository.NewErrConflict()) {
+			return nil, apperrors.New(apperrors.Conflict, "post is already published")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	post.Status = models.PostScheduled
+	post.PublishAt = publishAt
+	return newPostModel(post), nil
+}
@@ -0,0 +1,191 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_mutationResolver_PublishPost(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		postID    string
+		publishAt *time.Time
+	}
+	type resolverFields struct {
+		getPostRepo func(c *gomock.Controller) repository.PostRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	future := time.Date(2100, 1, 2, 3, 4, 5, 0, time.UTC)
+	past := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	newPost := func(ownerID int, status models.PostStatus) *models.Post {
+		return &models.Post{ID: 5, Title: "Title", Owner: models.User{ID: ownerID, Login: "owner"}, Status: status}
+	}
+	getPostRepo := func(post *models.Post, err error) func(c *gomock.Controller) repository.PostRepo {
+		return func(c *gomock.Controller) repository.PostRepo {
+			pr := mocks.NewMockPostRepo(c)
+			pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(post, err)
+			return pr
+		}
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		wantStatus     model.PostStatus
+		wantPublishAt  *time.Time
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: context.Background(), postID: "5"},
+			wantErr:        true,
+		},
+		{
+			name:           "postID is not int",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx(), postID: "abc"},
+			wantErr:        true,
+		},
+		{
+			name:           "Post not found",
+			resolverFields: resolverFields{getPostRepo: getPostRepo(nil, repository.NewErrNotFound())},
+			args:           args{ctx: authCtx(), postID: "5"},
+			wantErr:        true,
+		},
+		{
+			name:           "Not an owner",
+			resolverFields: resolverFields{getPostRepo: getPostRepo(newPost(2, models.PostDraft), nil)},
+			args:           args{ctx: authCtx(), postID: "5"},
+			wantErr:        true,
+		},
+		{
+			name:           "Held post",
+			resolverFields: resolverFields{getPostRepo: getPostRepo(newPost(1, models.PostHeld), nil)},
+			args:           args{ctx: authCtx(), postID: "5"},
+			wantErr:        true,
+		},
+		{
+			name: "Publish now",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(newPost(1, models.PostDraft), nil)
+					pr.EXPECT().PublishPost(gomock.Any(), 5, gomock.Any()).Return(nil)
+					return pr
+				},
+			},
+			args:       args{ctx: authCtx(), postID: "5"},
+			wantStatus: model.PostStatusPublished,
+			wantErr:    false,
+		},
+		{
+			name: "Publish at a past time publishes now",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(newPost(1, models.PostScheduled), nil)
+					pr.EXPECT().PublishPost(gomock.Any(), 5, gomock.Any()).Return(nil)
+					return pr
+				},
+			},
+			args:       args{ctx: authCtx(), postID: "5", publishAt: &past},
+			wantStatus: model.PostStatusPublished,
+			wantErr:    false,
+		},
+		{
+			name: "Publish failed",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(newPost(1, models.PostDraft), nil)
+					pr.EXPECT().PublishPost(gomock.Any(), 5, gomock.Any()).Return(fmt.Errorf("db error"))
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx(), postID: "5"},
+			wantErr: true,
+		},
+		{
+			name:           "Already published",
+			resolverFields: resolverFields{getPostRepo: getPostRepo(newPost(1, models.PostPublished), nil)},
+			args:           args{ctx: authCtx(), postID: "5"},
+			wantStatus:     model.PostStatusPublished,
+			wantErr:        false,
+		},
+		{
+			name:           "Schedule published post",
+			resolverFields: resolverFields{getPostRepo: getPostRepo(newPost(1, models.PostPublished), nil)},
+			args:           args{ctx: authCtx(), postID: "5", publishAt: &future},
+			wantErr:        true,
+		},
+		{
+			name: "Schedule conflict",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(newPost(1, models.PostDraft), nil)
+					pr.EXPECT().SchedulePost(gomock.Any(), 5, future).Return(repository.NewErrConflict())
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx(), postID: "5", publishAt: &future},
+			wantErr: true,
+		},
+		{
+			name: "Schedule",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(newPost(1, models.PostDraft), nil)
+					pr.EXPECT().SchedulePost(gomock.Any(), 5, future).Return(nil)
+					return pr
+				},
+			},
+			args:          args{ctx: authCtx(), postID: "5", publishAt: &future},
+			wantStatus:    model.PostStatusScheduled,
+			wantPublishAt: &future,
+			wantErr:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:   logger.Sugar(),
+					PostRepo: tt.resolverFields.getPostRepo(c),
+				},
+			}
+			got, err := r.PublishPost(tt.args.ctx, tt.args.postID, tt.args.publishAt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PublishPost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("PublishPost() status = %v, want %v", got.Status, tt.wantStatus)
+			}
+			if tt.wantPublishAt != nil && !reflect.DeepEqual(got.PublishAt, tt.wantPublishAt) {
+				t.Errorf("PublishPost() publishAt = %v, want %v", got.PublishAt, tt.wantPublishAt)
+			}
+		})
+	}
+}
@@ -2,10 +2,11 @@ package resolvers
 
 import (
 	"context"
-	"fmt"
 	"ozon_test_task/internal/app/graph/model"
 	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
 	"ozon_test_task/pkg/authUtils"
+	"time"
 )
 
 // Register is the resolver for the register field.
@@ -13,18 +14,18 @@ func (r *mutationResolver) Register(ctx context.Context, username string, passwo
 	//check data
 	if len(username) == 0 {
 		r.Logger.Debugf("username is empty")
-		return nil, fmt.Errorf("username cannot be empty")
+		return nil, apperrors.New(apperrors.Validation, "username cannot be empty")
 	}
 	if len(password) == 0 {
 		r.Logger.Debugf("password is empty")
-		return nil, fmt.Errorf("password cannot be empty")
+		return nil, apperrors.New(apperrors.Validation, "password cannot be empty")
 	}
 
 	//gen password salt and hash
 	salt, err := authUtils.GenPasswordSalt()
 	if err != nil {
 		r.Logger.Errorf("failed to generate salt: %v", err)
-		return nil, fmt.Errorf("internal server error")
+		return nil, apperrors.NewInternal(err)
 	}
 
 	passwordHash := authUtils.HashPassword(password, salt)
@@ -32,19 +33,21 @@ func (r *mutationResolver) Register(ctx context.Context, username string, passwo
 	//add user
 	id, err := r.UserRepo.AddUser(ctx, &models.User{
 		Login:        username,
+		RegisteredAt: time.Now(),
+	}, &models.Credentials{
 		PasswordHash: passwordHash,
 		PasswordSalt: salt,
 	})
 	if err != nil {
 		r.Logger.Errorf("failed to add user to a db: %v", err)
-		return nil, fmt.Errorf("internal server error")
+		return nil, apperrors.NewInternal(err)
 	}
 
 	//make jwt
 	jwt, err := r.JWTManager.BuildNewJWTString(id)
 	if err != nil {
 		r.Logger.Errorf("failed to build jwt string: %v", err)
-		return nil, fmt.Errorf("user created, auth error")
+		return nil, apperrors.NewInternal(err)
 	}
 
 	return &model.AuthResponse{
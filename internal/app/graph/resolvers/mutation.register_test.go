@@ -72,7 +72,7 @@ func Test_mutationResolver_Register(t *testing.T) {
 			resolverFields: resolverFields{
 				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
 					ur := mocks.NewMockUserRepo(c)
-					ur.EXPECT().AddUser(gomock.Any(), gomock.Any()).Return(0, fmt.Errorf("db error"))
+					ur.EXPECT().AddUser(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, fmt.Errorf("db error"))
 					return ur
 				},
 				getJWTManager: func(c *gomock.Controller) middlewares.JWTManager {
@@ -92,7 +92,7 @@ func Test_mutationResolver_Register(t *testing.T) {
 			resolverFields: resolverFields{
 				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
 					ur := mocks.NewMockUserRepo(c)
-					ur.EXPECT().AddUser(gomock.Any(), gomock.Any()).Return(123, nil)
+					ur.EXPECT().AddUser(gomock.Any(), gomock.Any(), gomock.Any()).Return(123, nil)
 					return ur
 				},
 				getJWTManager: func(c *gomock.Controller) middlewares.JWTManager {
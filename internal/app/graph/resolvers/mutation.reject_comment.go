@@ -0,0 +1,59 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// RejectComment is the resolver for the rejectComment field.
+func (r *mutationResolver) RejectComment(ctx context.Context, heldCommentID string) (bool, error) {
+	if _, err := r.takeHeldComment(ctx, heldCommentID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// takeHeldComment checks that the current user is a moderator or the owner of the comment post
+// and removes a held comment from the queue.
+func (r *Resolver) takeHeldComment(ctx context.Context, heldCommentID string) (*models.HeldComment, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	heldIDInt, err := strconv.Atoi(heldCommentID)
+	if err != nil {
+		r.Logger.Debugf("cant convert heldCommentID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "held comment id is not int")
+	}
+
+	if !r.isModerator(user) {
+		held, err := r.ModerationRepo.GetHeldComment(ctx, heldIDInt)
+		if err != nil {
+			r.Logger.Debugf("cant get held comment, err: %v", err)
+			if errors.Is(err, repository.NewErrNotFound()) {
+				return nil, apperrors.New(apperrors.NotFound, "held comment not found")
+			}
+			return nil, apperrors.NewInternal(err)
+		}
+		if err = r.checkHeldCommentsReviewer(ctx, user, held.RootPostID); err != nil {
+			return nil, err
+		}
+	}
+
+	held, err := r.ModerationRepo.TakeHeldComment(ctx, heldIDInt)
+	if err != nil {
+		r.Logger.Debugf("cant take held comment, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "held comment not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	return held, nil
+}
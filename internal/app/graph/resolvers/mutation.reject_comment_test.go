@@ -0,0 +1,152 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"testing"
+)
+
+func Test_mutationResolver_RejectComment(t *testing.T) {
+	type args struct {
+		ctx           context.Context
+		heldCommentID string
+	}
+	type resolverFields struct {
+		getModerationRepo func(c *gomock.Controller) repository.ModerationRepo
+		getPostRepo       func(c *gomock.Controller) repository.PostRepo
+	}
+	authCtx := func(login string) context.Context {
+		user := &models.User{ID: 1, Login: login}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noModerationRepo := func(c *gomock.Controller) repository.ModerationRepo { return mocks.NewMockModerationRepo(c) }
+	takeHeld := func(held *models.HeldComment, err error) func(c *gomock.Controller) repository.ModerationRepo {
+		return func(c *gomock.Controller) repository.ModerationRepo {
+			mr := mocks.NewMockModerationRepo(c)
+			mr.EXPECT().TakeHeldComment(gomock.Any(), 4).Return(held, err)
+			return mr
+		}
+	}
+	getHeld := func(held *models.HeldComment, err error) func(c *gomock.Controller) repository.ModerationRepo {
+		return func(c *gomock.Controller) repository.ModerationRepo {
+			mr := mocks.NewMockModerationRepo(c)
+			mr.EXPECT().GetHeldComment(gomock.Any(), 4).Return(held, err)
+			return mr
+		}
+	}
+	postOwnedBy := func(ownerID int) func(c *gomock.Controller) repository.PostRepo {
+		return func(c *gomock.Controller) repository.PostRepo {
+			pr := mocks.NewMockPostRepo(c)
+			pr.EXPECT().GetPostByID(gomock.Any(), 7).Return(&models.Post{ID: 7, Owner: models.User{ID: ownerID}}, nil)
+			return pr
+		}
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           bool
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getModerationRepo: noModerationRepo},
+			args:           args{ctx: context.Background(), heldCommentID: "4"},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name: "Not a moderator nor the post owner",
+			resolverFields: resolverFields{
+				getModerationRepo: getHeld(&models.HeldComment{ID: 4, RootPostID: 7}, nil),
+				getPostRepo:       postOwnedBy(2),
+			},
+			args:    args{ctx: authCtx("qwerty"), heldCommentID: "4"},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name:           "Held comment not found by the post owner",
+			resolverFields: resolverFields{getModerationRepo: getHeld(nil, repository.NewErrNotFound())},
+			args:           args{ctx: authCtx("qwerty"), heldCommentID: "4"},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name: "Rejected by the post owner",
+			resolverFields: resolverFields{
+				getModerationRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().GetHeldComment(gomock.Any(), 4).Return(&models.HeldComment{ID: 4, RootPostID: 7}, nil)
+					mr.EXPECT().TakeHeldComment(gomock.Any(), 4).Return(&models.HeldComment{ID: 4, RootPostID: 7}, nil)
+					return mr
+				},
+				getPostRepo: postOwnedBy(1),
+			},
+			args:    args{ctx: authCtx("qwerty"), heldCommentID: "4"},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name:           "heldCommentID is not int",
+			resolverFields: resolverFields{getModerationRepo: noModerationRepo},
+			args:           args{ctx: authCtx("moder"), heldCommentID: "abc"},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Held comment not found",
+			resolverFields: resolverFields{getModerationRepo: takeHeld(nil, repository.NewErrNotFound())},
+			args:           args{ctx: authCtx("moder"), heldCommentID: "4"},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "DB error",
+			resolverFields: resolverFields{getModerationRepo: takeHeld(nil, fmt.Errorf("db error"))},
+			args:           args{ctx: authCtx("moder"), heldCommentID: "4"},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Ok",
+			resolverFields: resolverFields{getModerationRepo: takeHeld(&models.HeldComment{ID: 4}, nil)},
+			args:           args{ctx: authCtx("moder"), heldCommentID: "4"},
+			want:           true,
+			wantErr:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			var postRepo repository.PostRepo
+			if tt.resolverFields.getPostRepo != nil {
+				postRepo = tt.resolverFields.getPostRepo(c)
+			}
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:         logger.Sugar(),
+					Cfg:            cfg.Cfg{Moderators: []string{"moder"}},
+					ModerationRepo: tt.resolverFields.getModerationRepo(c),
+					PostRepo:       postRepo,
+				},
+			}
+			got, err := r.RejectComment(tt.args.ctx, tt.args.heldCommentID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RejectComment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("RejectComment() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+)
+
+// RejectPost is the resolver for the rejectPost field.
+func (r *mutationResolver) RejectPost(ctx context.Context, postID string) (*model.Post, error) {
+	post, err := r.releaseHeldPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	//a rejected post stays a draft of its owner
+	post.PublishAt = nil
+	return newPostModel(post), nil
+}
@@ -0,0 +1,117 @@
+package resolvers
+
+import (
+	"context"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_mutationResolver_RejectPost(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		postID string
+	}
+	type resolverFields struct {
+		getPostRepo func(c *gomock.Controller) repository.PostRepo
+	}
+	authCtx := func(login string) context.Context {
+		user := &models.User{ID: 1, Login: login}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	past := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	held := func() *models.Post {
+		return &models.Post{
+			ID:                5,
+			Title:             "Title",
+			Owner:             models.User{ID: 2, Login: "owner"},
+			Status:            models.PostHeld,
+			PublishAt:         &past,
+			ModerationReasons: []string{"more than 3 links"},
+		}
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.Post
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: context.Background(), postID: "5"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Not a moderator",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx("qwerty"), postID: "5"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "Post not found",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(nil, repository.NewErrNotFound())
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx("moder"), postID: "5"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(held(), nil)
+					pr.EXPECT().ReleaseHeldPost(gomock.Any(), 5).Return(nil)
+					return pr
+				},
+			},
+			args: args{ctx: authCtx("moder"), postID: "5"},
+			want: &model.Post{
+				ID:     "5",
+				Title:  "Title",
+				Owner:  &model.User{ID: "2", Username: "owner"},
+				Status: model.PostStatusDraft,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:   logger.Sugar(),
+					Cfg:      cfg.Cfg{Moderators: []string{"moder"}},
+					PostRepo: tt.resolverFields.getPostRepo(c),
+				},
+			}
+			got, err := r.RejectPost(tt.args.ctx, tt.args.postID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RejectPost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RejectPost() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// RemoveBookmark is the resolver for the removeBookmark field.
+func (r *mutationResolver) RemoveBookmark(ctx context.Context, postID string) (*model.Post, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+	postIDInt, err := strconv.Atoi(postID)
+	if err != nil {
+		r.Logger.Debugf("cant convert postID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "postID is not an int")
+	}
+
+	post, err := r.PostRepo.GetPostByID(ctx, postIDInt)
+	if err != nil {
+		r.Logger.Debugf("cant get post from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+
+	if err = r.BookmarkRepo.RemoveBookmark(ctx, user.ID, postIDInt); err != nil {
+		r.Logger.Debugf("cant remove bookmark, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+	return newPostModel(post), nil
+}
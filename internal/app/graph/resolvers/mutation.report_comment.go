@@ -0,0 +1,38 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// ReportComment is the resolver for the reportComment field.
+func (r *mutationResolver) ReportComment(ctx context.Context, commentID string, reason model.ReportReason, details *string) (bool, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return false, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	commentIDInt, err := strconv.Atoi(commentID)
+	if err != nil {
+		r.Logger.Debugf("cant convert commentID to int, err: %v", err)
+		return false, apperrors.New(apperrors.Validation, "comment id is not int")
+	}
+
+	comment, err := r.CommentRepo.GetCommentByID(ctx, commentIDInt)
+	if err != nil {
+		r.Logger.Debugf("cant get comment from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return false, apperrors.New(apperrors.NotFound, "comment not found")
+		}
+		return false, apperrors.NewInternal(err)
+	}
+
+	return r.addReport(ctx, user, models.ReportComment, comment.ID, comment.Owner.ID, reason, details)
+}
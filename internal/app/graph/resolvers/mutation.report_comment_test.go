@@ -0,0 +1,128 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"testing"
+)
+
+func Test_mutationResolver_ReportComment(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		commentID string
+		reason    model.ReportReason
+		details   *string
+	}
+	type resolverFields struct {
+		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+		getReportRepo  func(c *gomock.Controller) repository.ReportRepo
+	}
+	authCtx := context.WithValue(context.Background(), middlewares.UserContextKey, &models.User{ID: 1, Login: "qwerty"})
+	noCommentRepo := func(c *gomock.Controller) repository.CommentRepo { return mocks.NewMockCommentRepo(c) }
+	noReportRepo := func(c *gomock.Controller) repository.ReportRepo { return mocks.NewMockReportRepo(c) }
+	getComment := func(comment *models.Comment, err error) func(c *gomock.Controller) repository.CommentRepo {
+		return func(c *gomock.Controller) repository.CommentRepo {
+			cr := mocks.NewMockCommentRepo(c)
+			cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(comment, err)
+			return cr
+		}
+	}
+	comment := &models.Comment{ID: 7, Owner: models.User{ID: 2}}
+	addReport := func(added bool, err error) func(c *gomock.Controller) repository.ReportRepo {
+		return func(c *gomock.Controller) repository.ReportRepo {
+			rr := mocks.NewMockReportRepo(c)
+			rr.EXPECT().AddReport(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, got *models.Report) (bool, error) {
+				if got.ReporterID != 1 || got.TargetType != models.ReportComment || got.TargetID != 7 || got.AuthorID != 2 || got.Reason != models.ReportHarassment {
+					t.Errorf("AddReport() got = %v", got)
+				}
+				return added, err
+			})
+			return rr
+		}
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           bool
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getCommentRepo: noCommentRepo, getReportRepo: noReportRepo},
+			args:           args{ctx: context.Background(), commentID: "7", reason: model.ReportReasonHarassment},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Comment id is not int",
+			resolverFields: resolverFields{getCommentRepo: noCommentRepo, getReportRepo: noReportRepo},
+			args:           args{ctx: authCtx, commentID: "abc", reason: model.ReportReasonHarassment},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Comment not found",
+			resolverFields: resolverFields{getCommentRepo: getComment(nil, repository.NewErrNotFound()), getReportRepo: noReportRepo},
+			args:           args{ctx: authCtx, commentID: "7", reason: model.ReportReasonHarassment},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Own comment",
+			resolverFields: resolverFields{getCommentRepo: getComment(&models.Comment{ID: 7, Owner: models.User{ID: 1}}, nil), getReportRepo: noReportRepo},
+			args:           args{ctx: authCtx, commentID: "7", reason: model.ReportReasonHarassment},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "DB error",
+			resolverFields: resolverFields{getCommentRepo: getComment(comment, nil), getReportRepo: addReport(false, fmt.Errorf("db error"))},
+			args:           args{ctx: authCtx, commentID: "7", reason: model.ReportReasonHarassment},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Already reported",
+			resolverFields: resolverFields{getCommentRepo: getComment(comment, nil), getReportRepo: addReport(false, nil)},
+			args:           args{ctx: authCtx, commentID: "7", reason: model.ReportReasonHarassment},
+			want:           false,
+			wantErr:        false,
+		},
+		{
+			name:           "Ok",
+			resolverFields: resolverFields{getCommentRepo: getComment(comment, nil), getReportRepo: addReport(true, nil)},
+			args:           args{ctx: authCtx, commentID: "7", reason: model.ReportReasonHarassment},
+			want:           true,
+			wantErr:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:      logger.Sugar(),
+					CommentRepo: tt.resolverFields.getCommentRepo(c),
+					ReportRepo:  tt.resolverFields.getReportRepo(c),
+				},
+			}
+			got, err := r.ReportComment(tt.args.ctx, tt.args.commentID, tt.args.reason, tt.args.details)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReportComment() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ReportComment() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// ReportPost is the resolver for the reportPost field.
+func (r *mutationResolver) ReportPost(ctx context.Context, postID string, reason model.ReportReason, details *string) (bool, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return false, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	postIDInt, err := strconv.Atoi(postID)
+	if err != nil {
+		r.Logger.Debugf("cant convert postID to int, err: %v", err)
+		return false, apperrors.New(apperrors.Validation, "post id is not int")
+	}
+
+	post, err := r.PostRepo.GetPostByID(ctx, postIDInt)
+	if err != nil {
+		r.Logger.Debugf("cant get post from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return false, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return false, apperrors.NewInternal(err)
+	}
+	if !r.isPostVisible(ctx, post) {
+		r.Logger.Debugf("post is not published and user is not its owner")
+		return false, apperrors.New(apperrors.NotFound, "post not found")
+	}
+
+	return r.addReport(ctx, user, models.ReportPost, post.ID, post.Owner.ID, reason, details)
+}
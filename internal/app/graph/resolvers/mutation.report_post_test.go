@@ -0,0 +1,161 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"strings"
+	"testing"
+)
+
+func Test_mutationResolver_ReportPost(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		postID  string
+		reason  model.ReportReason
+		details *string
+	}
+	type resolverFields struct {
+		getPostRepo   func(c *gomock.Controller) repository.PostRepo
+		getReportRepo func(c *gomock.Controller) repository.ReportRepo
+	}
+	authCtx := context.WithValue(context.Background(), middlewares.UserContextKey, &models.User{ID: 1, Login: "qwerty"})
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	noReportRepo := func(c *gomock.Controller) repository.ReportRepo { return mocks.NewMockReportRepo(c) }
+	getPost := func(post *models.Post, err error) func(c *gomock.Controller) repository.PostRepo {
+		return func(c *gomock.Controller) repository.PostRepo {
+			pr := mocks.NewMockPostRepo(c)
+			pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(post, err)
+			return pr
+		}
+	}
+	published := &models.Post{ID: 5, Owner: models.User{ID: 2}, Status: models.PostPublished}
+	details := "buy now"
+	report := &models.Report{
+		ReporterID: 1,
+		TargetType: models.ReportPost,
+		TargetID:   5,
+		AuthorID:   2,
+		Reason:     models.ReportSpam,
+		Details:    details,
+	}
+	addReport := func(added bool, err error) func(c *gomock.Controller) repository.ReportRepo {
+		return func(c *gomock.Controller) repository.ReportRepo {
+			rr := mocks.NewMockReportRepo(c)
+			rr.EXPECT().AddReport(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, got *models.Report) (bool, error) {
+				if got.CreatedAt.IsZero() {
+					t.Errorf("AddReport() report without CreatedAt")
+				}
+				got.CreatedAt = report.CreatedAt
+				if *got != *report {
+					t.Errorf("AddReport() got = %v, want %v", got, report)
+				}
+				return added, err
+			})
+			return rr
+		}
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           bool
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getPostRepo: noPostRepo, getReportRepo: noReportRepo},
+			args:           args{ctx: context.Background(), postID: "5", reason: model.ReportReasonSpam},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Post id is not int",
+			resolverFields: resolverFields{getPostRepo: noPostRepo, getReportRepo: noReportRepo},
+			args:           args{ctx: authCtx, postID: "abc", reason: model.ReportReasonSpam},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Post not found",
+			resolverFields: resolverFields{getPostRepo: getPost(nil, repository.NewErrNotFound()), getReportRepo: noReportRepo},
+			args:           args{ctx: authCtx, postID: "5", reason: model.ReportReasonSpam},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Draft of another user",
+			resolverFields: resolverFields{getPostRepo: getPost(&models.Post{ID: 5, Owner: models.User{ID: 2}, Status: models.PostDraft}, nil), getReportRepo: noReportRepo},
+			args:           args{ctx: authCtx, postID: "5", reason: model.ReportReasonSpam},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Own post",
+			resolverFields: resolverFields{getPostRepo: getPost(&models.Post{ID: 5, Owner: models.User{ID: 1}, Status: models.PostPublished}, nil), getReportRepo: noReportRepo},
+			args:           args{ctx: authCtx, postID: "5", reason: model.ReportReasonSpam},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Details too long",
+			resolverFields: resolverFields{getPostRepo: getPost(published, nil), getReportRepo: noReportRepo},
+			args: args{
+				ctx:     authCtx,
+				postID:  "5",
+				reason:  model.ReportReasonSpam,
+				details: func() *string { v := strings.Repeat("я", maxReportDetailsLength+1); return &v }(),
+			},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name:           "DB error",
+			resolverFields: resolverFields{getPostRepo: getPost(published, nil), getReportRepo: addReport(false, fmt.Errorf("db error"))},
+			args:           args{ctx: authCtx, postID: "5", reason: model.ReportReasonSpam, details: &details},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Already reported",
+			resolverFields: resolverFields{getPostRepo: getPost(published, nil), getReportRepo: addReport(false, nil)},
+			args:           args{ctx: authCtx, postID: "5", reason: model.ReportReasonSpam, details: &details},
+			want:           false,
+			wantErr:        false,
+		},
+		{
+			name:           "Ok",
+			resolverFields: resolverFields{getPostRepo: getPost(published, nil), getReportRepo: addReport(true, nil)},
+			args:           args{ctx: authCtx, postID: "5", reason: model.ReportReasonSpam, details: &details},
+			want:           true,
+			wantErr:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:     logger.Sugar(),
+					PostRepo:   tt.resolverFields.getPostRepo(c),
+					ReportRepo: tt.resolverFields.getReportRepo(c),
+				},
+			}
+			got, err := r.ReportPost(tt.args.ctx, tt.args.postID, tt.args.reason, tt.args.details)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReportPost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ReportPost() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
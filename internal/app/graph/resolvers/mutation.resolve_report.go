@@ -0,0 +1,59 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// ResolveReport is the resolver for the resolveReport field.
+func (r *mutationResolver) ResolveReport(ctx context.Context, reportCaseID string, action model.ReportAction) (bool, error) {
+	if _, err := r.moderatorFromContext(ctx); err != nil {
+		return false, err
+	}
+
+	caseIDInt, err := strconv.Atoi(reportCaseID)
+	if err != nil {
+		r.Logger.Debugf("cant convert reportCaseID to int, err: %v", err)
+		return false, apperrors.New(apperrors.Validation, "report case id is not int")
+	}
+
+	rc, err := r.ReportRepo.GetReportCase(ctx, caseIDInt)
+	if err != nil {
+		r.Logger.Debugf("cant get report case from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return false, apperrors.New(apperrors.NotFound, "report case not found")
+		}
+		return false, apperrors.NewInternal(err)
+	}
+
+	//actions are applied before the case is closed, so a failed one can be retried
+	if action == model.ReportActionBanAuthor {
+		if err = r.UserRepo.BanUser(ctx, rc.AuthorID); err != nil && !errors.Is(err, repository.NewErrNotFound()) {
+			r.Logger.Debugf("cant ban user, err: %v", err)
+			return false, apperrors.NewInternal(err)
+		}
+	}
+	if action == model.ReportActionHideContent || action == model.ReportActionBanAuthor {
+		if rc.TargetType == models.ReportPost {
+			err = r.PostRepo.SetPostHidden(ctx, rc.TargetID, true)
+		} else {
+			err = r.CommentRepo.SetCommentHidden(ctx, rc.TargetID, true)
+		}
+		//deleted content doesn`t need to be hidden
+		if err != nil && !errors.Is(err, repository.NewErrNotFound()) {
+			r.Logger.Debugf("cant hide reported content, err: %v", err)
+			return false, apperrors.NewInternal(err)
+		}
+	}
+
+	if err = r.ReportRepo.CloseReportCase(ctx, caseIDInt); err != nil && !errors.Is(err, repository.NewErrNotFound()) {
+		r.Logger.Debugf("cant close report case, err: %v", err)
+		return false, apperrors.NewInternal(err)
+	}
+	return true, nil
+}
@@ -0,0 +1,211 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"testing"
+)
+
+func Test_mutationResolver_ResolveReport(t *testing.T) {
+	type args struct {
+		ctx          context.Context
+		reportCaseID string
+		action       model.ReportAction
+	}
+	type resolverFields struct {
+		getReportRepo  func(c *gomock.Controller) repository.ReportRepo
+		getPostRepo    func(c *gomock.Controller) repository.PostRepo
+		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+		getUserRepo    func(c *gomock.Controller) repository.UserRepo
+	}
+	authCtx := func(login string) context.Context {
+		user := &models.User{ID: 1, Login: login}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noReportRepo := func(c *gomock.Controller) repository.ReportRepo { return mocks.NewMockReportRepo(c) }
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	noCommentRepo := func(c *gomock.Controller) repository.CommentRepo { return mocks.NewMockCommentRepo(c) }
+	noUserRepo := func(c *gomock.Controller) repository.UserRepo { return mocks.NewMockUserRepo(c) }
+	postCase := &models.ReportCase{ID: 3, TargetType: models.ReportPost, TargetID: 5, AuthorID: 2}
+	commentCase := &models.ReportCase{ID: 3, TargetType: models.ReportComment, TargetID: 7, AuthorID: 2}
+	getCase := func(rc *models.ReportCase, err error) func(c *gomock.Controller) repository.ReportRepo {
+		return func(c *gomock.Controller) repository.ReportRepo {
+			rr := mocks.NewMockReportRepo(c)
+			rr.EXPECT().GetReportCase(gomock.Any(), 3).Return(rc, err)
+			return rr
+		}
+	}
+	closeCase := func(rc *models.ReportCase, err error) func(c *gomock.Controller) repository.ReportRepo {
+		return func(c *gomock.Controller) repository.ReportRepo {
+			rr := mocks.NewMockReportRepo(c)
+			rr.EXPECT().GetReportCase(gomock.Any(), 3).Return(rc, nil)
+			rr.EXPECT().CloseReportCase(gomock.Any(), 3).Return(err)
+			return rr
+		}
+	}
+	hidePost := func(err error) func(c *gomock.Controller) repository.PostRepo {
+		return func(c *gomock.Controller) repository.PostRepo {
+			pr := mocks.NewMockPostRepo(c)
+			pr.EXPECT().SetPostHidden(gomock.Any(), 5, true).Return(err)
+			return pr
+		}
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           bool
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getReportRepo: noReportRepo, getPostRepo: noPostRepo, getCommentRepo: noCommentRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: context.Background(), reportCaseID: "3", action: model.ReportActionDismiss},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Not a moderator",
+			resolverFields: resolverFields{getReportRepo: noReportRepo, getPostRepo: noPostRepo, getCommentRepo: noCommentRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: authCtx("qwerty"), reportCaseID: "3", action: model.ReportActionDismiss},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Report case id is not int",
+			resolverFields: resolverFields{getReportRepo: noReportRepo, getPostRepo: noPostRepo, getCommentRepo: noCommentRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: authCtx("moder"), reportCaseID: "abc", action: model.ReportActionDismiss},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Report case not found",
+			resolverFields: resolverFields{getReportRepo: getCase(nil, repository.NewErrNotFound()), getPostRepo: noPostRepo, getCommentRepo: noCommentRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: authCtx("moder"), reportCaseID: "3", action: model.ReportActionDismiss},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Dismiss",
+			resolverFields: resolverFields{getReportRepo: closeCase(postCase, nil), getPostRepo: noPostRepo, getCommentRepo: noCommentRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: authCtx("moder"), reportCaseID: "3", action: model.ReportActionDismiss},
+			want:           true,
+			wantErr:        false,
+		},
+		{
+			name:           "Dismiss concurrently closed case",
+			resolverFields: resolverFields{getReportRepo: closeCase(postCase, repository.NewErrNotFound()), getPostRepo: noPostRepo, getCommentRepo: noCommentRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: authCtx("moder"), reportCaseID: "3", action: model.ReportActionDismiss},
+			want:           true,
+			wantErr:        false,
+		},
+		{
+			name:           "Close DB error",
+			resolverFields: resolverFields{getReportRepo: closeCase(postCase, fmt.Errorf("db error")), getPostRepo: noPostRepo, getCommentRepo: noCommentRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: authCtx("moder"), reportCaseID: "3", action: model.ReportActionDismiss},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name:           "Hide post",
+			resolverFields: resolverFields{getReportRepo: closeCase(postCase, nil), getPostRepo: hidePost(nil), getCommentRepo: noCommentRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: authCtx("moder"), reportCaseID: "3", action: model.ReportActionHideContent},
+			want:           true,
+			wantErr:        false,
+		},
+		{
+			name:           "Hide deleted post",
+			resolverFields: resolverFields{getReportRepo: closeCase(postCase, nil), getPostRepo: hidePost(repository.NewErrNotFound()), getCommentRepo: noCommentRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: authCtx("moder"), reportCaseID: "3", action: model.ReportActionHideContent},
+			want:           true,
+			wantErr:        false,
+		},
+		{
+			name:           "Hide DB error",
+			resolverFields: resolverFields{getReportRepo: getCase(postCase, nil), getPostRepo: hidePost(fmt.Errorf("db error")), getCommentRepo: noCommentRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: authCtx("moder"), reportCaseID: "3", action: model.ReportActionHideContent},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name: "Hide comment",
+			resolverFields: resolverFields{
+				getReportRepo: closeCase(commentCase, nil),
+				getPostRepo:   noPostRepo,
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().SetCommentHidden(gomock.Any(), 7, true).Return(nil)
+					return cr
+				},
+				getUserRepo: noUserRepo,
+			},
+			args:    args{ctx: authCtx("moder"), reportCaseID: "3", action: model.ReportActionHideContent},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "Ban author",
+			resolverFields: resolverFields{
+				getReportRepo:  closeCase(postCase, nil),
+				getPostRepo:    hidePost(nil),
+				getCommentRepo: noCommentRepo,
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().BanUser(gomock.Any(), 2).Return(nil)
+					return ur
+				},
+			},
+			args:    args{ctx: authCtx("moder"), reportCaseID: "3", action: model.ReportActionBanAuthor},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "Ban DB error",
+			resolverFields: resolverFields{
+				getReportRepo:  getCase(postCase, nil),
+				getPostRepo:    noPostRepo,
+				getCommentRepo: noCommentRepo,
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().BanUser(gomock.Any(), 2).Return(fmt.Errorf("db error"))
+					return ur
+				},
+			},
+			args:    args{ctx: authCtx("moder"), reportCaseID: "3", action: model.ReportActionBanAuthor},
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:      logger.Sugar(),
+					Cfg:         cfg.Cfg{Moderators: []string{"moder"}},
+					ReportRepo:  tt.resolverFields.getReportRepo(c),
+					PostRepo:    tt.resolverFields.getPostRepo(c),
+					CommentRepo: tt.resolverFields.getCommentRepo(c),
+					UserRepo:    tt.resolverFields.getUserRepo(c),
+				},
+			}
+			got, err := r.ResolveReport(tt.args.ctx, tt.args.reportCaseID, tt.args.action)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResolveReport() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ResolveReport() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// SetCommentHidden is the resolver for the setCommentHidden field.
+func (r *mutationResolver) SetCommentHidden(ctx context.Context, commentID string, hidden bool) (*model.Comment, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	commentIDInt, err := strconv.Atoi(commentID)
+	if err != nil {
+		r.Logger.Debugf("cant convert commentID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "comment id is not int")
+	}
+
+	comment, err := r.CommentRepo.GetCommentByID(ctx, commentIDInt)
+	if err != nil {
+		r.Logger.Debugf("cant get comment from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "comment not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+
+	//check if user is owner of the commented post
+	postID, err := r.commentPostID(ctx, comment)
+	if err != nil {
+		r.Logger.Debugf("cant get comment post id, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "comment not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	post, err := r.PostRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		r.Logger.Debugf("cant get post from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	if user.ID != post.Owner.ID {
+		r.Logger.Debugf("cant hide this comment, user is not a post owner. UserID is \"%v\", but ownerID is \"%v\"", user.ID, post.Owner.ID)
+		return nil, apperrors.New(apperrors.Forbidden, "cant modify comments of this post")
+	}
+
+	if err = r.CommentRepo.SetCommentHidden(ctx, commentIDInt, hidden); err != nil {
+		r.Logger.Debugf("cant set comment hidden, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "comment not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+
+	comment.Hidden = hidden
+	return newCommentModel(comment), nil
+}
@@ -0,0 +1,177 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_mutationResolver_SetCommentHidden(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		commentID string
+		hidden    bool
+	}
+	type resolverFields struct {
+		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+		getPostRepo    func(c *gomock.Controller) repository.PostRepo
+	}
+	authCtx := context.WithValue(context.Background(), middlewares.UserContextKey, &models.User{ID: 1, Login: "qwerty"})
+	noCommentRepo := func(c *gomock.Controller) repository.CommentRepo { return mocks.NewMockCommentRepo(c) }
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	reply := func() *models.Comment {
+		return &models.Comment{ID: 7, ParentID: 4, Owner: models.User{ID: 2, Login: "author"}, Text: "Hello", TextFormat: models.TextPlain}
+	}
+	getPost := func(ownerID int) func(c *gomock.Controller) repository.PostRepo {
+		return func(c *gomock.Controller) repository.PostRepo {
+			pr := mocks.NewMockPostRepo(c)
+			pr.EXPECT().GetPostByID(gomock.Any(), 3).Return(&models.Post{ID: 3, Owner: models.User{ID: ownerID}}, nil)
+			return pr
+		}
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.Comment
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getCommentRepo: noCommentRepo, getPostRepo: noPostRepo},
+			args:           args{ctx: context.Background(), commentID: "7", hidden: true},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Comment id is not int",
+			resolverFields: resolverFields{getCommentRepo: noCommentRepo, getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx, commentID: "abc", hidden: true},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "Comment not found",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(nil, repository.NewErrNotFound())
+					return cr
+				},
+				getPostRepo: noPostRepo,
+			},
+			args:    args{ctx: authCtx, commentID: "7", hidden: true},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Not a post owner",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(reply(), nil)
+					cr.EXPECT().GetCommentPostID(gomock.Any(), 4).Return(3, nil)
+					return cr
+				},
+				getPostRepo: getPost(2),
+			},
+			args:    args{ctx: authCtx, commentID: "7", hidden: true},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(reply(), nil)
+					cr.EXPECT().GetCommentPostID(gomock.Any(), 4).Return(3, nil)
+					cr.EXPECT().SetCommentHidden(gomock.Any(), 7, true).Return(fmt.Errorf("db error"))
+					return cr
+				},
+				getPostRepo: getPost(1),
+			},
+			args:    args{ctx: authCtx, commentID: "7", hidden: true},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Hide a reply",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(reply(), nil)
+					cr.EXPECT().GetCommentPostID(gomock.Any(), 4).Return(3, nil)
+					cr.EXPECT().SetCommentHidden(gomock.Any(), 7, true).Return(nil)
+					return cr
+				},
+				getPostRepo: getPost(1),
+			},
+			args: args{ctx: authCtx, commentID: "7", hidden: true},
+			want: &model.Comment{
+				ID:              "7",
+				Owner:           &model.User{ID: "2", Username: "author"},
+				TextFormat:      model.TextFormatPlain,
+				ParentCommentID: func() *string { v := "4"; return &v }(),
+				Hidden:          true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Restore a comment",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(&models.Comment{
+						ID:         7,
+						PostID:     3,
+						Owner:      models.User{ID: 2, Login: "author"},
+						Text:       "Hello",
+						TextFormat: models.TextPlain,
+						Hidden:     true,
+					}, nil)
+					cr.EXPECT().SetCommentHidden(gomock.Any(), 7, false).Return(nil)
+					return cr
+				},
+				getPostRepo: getPost(1),
+			},
+			args: args{ctx: authCtx, commentID: "7", hidden: false},
+			want: &model.Comment{
+				ID:         "7",
+				Owner:      &model.User{ID: "2", Username: "author"},
+				Text:       "Hello",
+				TextFormat: model.TextFormatPlain,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:      logger.Sugar(),
+					CommentRepo: tt.resolverFields.getCommentRepo(c),
+					PostRepo:    tt.resolverFields.getPostRepo(c),
+				},
+			}
+			got, err := r.SetCommentHidden(tt.args.ctx, tt.args.commentID, tt.args.hidden)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetCommentHidden() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetCommentHidden() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
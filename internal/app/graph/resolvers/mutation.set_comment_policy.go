@@ -0,0 +1,24 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// SetCommentPolicy is the resolver for the setCommentPolicy field.
+func (r *mutationResolver) SetCommentPolicy(ctx context.Context, postID string, policy model.CommentPolicy, days *int32) (*model.Post, error) {
+	daysInt := 0
+	if policy == model.CommentPolicyRegisteredOlderThanNDays {
+		if days == nil || *days <= 0 {
+			r.Logger.Debugf("days are not positive for %v policy", policy)
+			return nil, apperrors.New(apperrors.Validation, "days must be positive for %v policy", policy)
+		}
+		daysInt = int(*days)
+	} else if days != nil {
+		r.Logger.Debugf("days are set for %v policy", policy)
+		return nil, apperrors.New(apperrors.Validation, "days are allowed only for %v policy", model.CommentPolicyRegisteredOlderThanNDays)
+	}
+	return r.setCommentPolicy(ctx, postID, models.CommentPolicy(policy), daysInt)
+}
@@ -0,0 +1,170 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_mutationResolver_SetCommentPolicy(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		postID string
+		policy model.CommentPolicy
+		days   *int32
+	}
+	type resolverFields struct {
+		getPostRepo func(c *gomock.Controller) repository.PostRepo
+	}
+	authCtx := context.WithValue(context.Background(), middlewares.UserContextKey, &models.User{ID: 1, Login: "user1"})
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	days := func(v int32) *int32 { return &v }
+	createdAt := time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC)
+	ownPost := func(c *gomock.Controller) *mocks.MockPostRepo {
+		pr := mocks.NewMockPostRepo(c)
+		pr.EXPECT().GetPostByID(gomock.Any(), 10).Return(&models.Post{
+			ID:              10,
+			Title:           "title",
+			Owner:           models.User{ID: 1, Login: "user1"},
+			CommentsAllowed: true,
+			CommentPolicy:   models.CommentsOpen,
+			CreatedAt:       createdAt,
+		}, nil)
+		return pr
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.Post
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: context.Background(), postID: "10", policy: model.CommentPolicyFollowersOnly},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Days are missing",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx, postID: "10", policy: model.CommentPolicyRegisteredOlderThanNDays},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Days are not positive",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx, postID: "10", policy: model.CommentPolicyRegisteredOlderThanNDays, days: days(0)},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Days with another policy",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx, postID: "10", policy: model.CommentPolicyOpen, days: days(3)},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "Not an owner",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 10).Return(&models.Post{ID: 10, Owner: models.User{ID: 2}}, nil)
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx, postID: "10", policy: model.CommentPolicyFollowersOnly},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := ownPost(c)
+					pr.EXPECT().SetCommentPolicy(gomock.Any(), 10, models.CommentsFollowersOnly, 0, gomock.Any()).Return(fmt.Errorf("db error"))
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx, postID: "10", policy: model.CommentPolicyFollowersOnly},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Registered older than N days",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := ownPost(c)
+					pr.EXPECT().SetCommentPolicy(gomock.Any(), 10, models.CommentsRegisteredOlder, 7, gomock.Any()).Return(nil)
+					return pr
+				},
+			},
+			args: args{ctx: authCtx, postID: "10", policy: model.CommentPolicyRegisteredOlderThanNDays, days: days(7)},
+			want: &model.Post{
+				ID:                "10",
+				Title:             "title",
+				Owner:             &model.User{ID: "1", Username: "user1"},
+				CommentsAllowed:   true,
+				CommentPolicy:     model.CommentPolicyRegisteredOlderThanNDays,
+				CommentPolicyDays: 7,
+				CreatedAt:         createdAt,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Closed",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := ownPost(c)
+					pr.EXPECT().SetCommentPolicy(gomock.Any(), 10, models.CommentsClosed, 0, gomock.Any()).Return(nil)
+					return pr
+				},
+			},
+			args: args{ctx: authCtx, postID: "10", policy: model.CommentPolicyClosed},
+			want: &model.Post{
+				ID:              "10",
+				Title:           "title",
+				Owner:           &model.User{ID: "1", Username: "user1"},
+				CommentsAllowed: false,
+				CommentPolicy:   model.CommentPolicyClosed,
+				CreatedAt:       createdAt,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:   logger.Sugar(),
+					PostRepo: tt.resolverFields.getPostRepo(c),
+				},
+			}
+			got, err := r.SetCommentPolicy(tt.args.ctx, tt.args.postID, tt.args.policy, tt.args.days)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetCommentPolicy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				got.UpdatedAt = time.Time{}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetCommentPolicy() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"ozon_test_task/internal/app/models"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_mutationResolver_SetCommentsAllowed(t *testing.T) {
@@ -139,7 +140,7 @@ func Test_mutationResolver_SetCommentsAllowed(t *testing.T) {
 						Text:  "text",
 						Owner: models.User{ID: 1, Login: "user1"},
 					}, nil)
-					pr.EXPECT().SetCommentsAllowed(gomock.Any(), 10, false).Return(fmt.Errorf("db error"))
+					pr.EXPECT().SetCommentPolicy(gomock.Any(), 10, models.CommentsClosed, 0, gomock.Any()).Return(fmt.Errorf("db error"))
 					return pr
 				},
 			},
@@ -160,12 +161,13 @@ func Test_mutationResolver_SetCommentsAllowed(t *testing.T) {
 				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
 					pr := mocks.NewMockPostRepo(c)
 					pr.EXPECT().GetPostByID(gomock.Any(), 10).Return(&models.Post{
-						ID:    10,
-						Title: "title",
-						Text:  "text",
-						Owner: models.User{ID: 1, Login: "user1"},
+						ID:        10,
+						Title:     "title",
+						Text:      "text",
+						Owner:     models.User{ID: 1, Login: "user1"},
+						CreatedAt: time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
 					}, nil)
-					pr.EXPECT().SetCommentsAllowed(gomock.Any(), 10, true).Return(nil)
+					pr.EXPECT().SetCommentPolicy(gomock.Any(), 10, models.CommentsOpen, 0, gomock.Any()).Return(nil)
 					return pr
 				},
 			},
@@ -183,6 +185,8 @@ func Test_mutationResolver_SetCommentsAllowed(t *testing.T) {
 				Text:            "text",
 				Owner:           &model.User{ID: "1", Username: "user1"},
 				CommentsAllowed: true,
+				CommentPolicy:   model.CommentPolicyOpen,
+				CreatedAt:       time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
 			},
 			wantErr: false,
 		},
@@ -203,6 +207,9 @@ func Test_mutationResolver_SetCommentsAllowed(t *testing.T) {
 				t.Errorf("SetCommentsAllowed() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if !tt.wantErr {
+				got.UpdatedAt = time.Time{}
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("SetCommentsAllowed() got = %v, want %v", got, tt.want)
 			}
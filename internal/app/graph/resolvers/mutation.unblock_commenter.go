@@ -0,0 +1,41 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// UnblockCommenter is the resolver for the unblockCommenter field.
+func (r *mutationResolver) UnblockCommenter(ctx context.Context, userID string) (*model.User, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+	blockedID, err := strconv.Atoi(userID)
+	if err != nil {
+		r.Logger.Debugf("cant convert userID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "userID is not an int")
+	}
+
+	blocked, err := r.UserRepo.GetUserByID(ctx, blockedID)
+	if err != nil {
+		r.Logger.Debugf("cant get user from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "user not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+
+	if err = r.BlockRepo.UnblockCommenter(ctx, user.ID, blockedID); err != nil {
+		r.Logger.Debugf("cant unblock commenter, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+	return newUserModel(blocked), nil
+}
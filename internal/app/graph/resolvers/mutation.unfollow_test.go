@@ -0,0 +1,125 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_mutationResolver_Unfollow(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		userID string
+	}
+	type resolverFields struct {
+		getFollowRepo func(c *gomock.Controller) repository.FollowRepo
+		getUserRepo   func(c *gomock.Controller) repository.UserRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noFollowRepo := func(c *gomock.Controller) repository.FollowRepo { return mocks.NewMockFollowRepo(c) }
+	noUserRepo := func(c *gomock.Controller) repository.UserRepo { return mocks.NewMockUserRepo(c) }
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.User
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getFollowRepo: noFollowRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: context.Background(), userID: "2"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "userID is not int",
+			resolverFields: resolverFields{getFollowRepo: noFollowRepo, getUserRepo: noUserRepo},
+			args:           args{ctx: authCtx(), userID: "abc"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "User not found",
+			resolverFields: resolverFields{
+				getFollowRepo: noFollowRepo,
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByID(gomock.Any(), 2).Return(nil, repository.NewErrNotFound())
+					return ur
+				},
+			},
+			args:    args{ctx: authCtx(), userID: "2"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getFollowRepo: func(c *gomock.Controller) repository.FollowRepo {
+					fr := mocks.NewMockFollowRepo(c)
+					fr.EXPECT().Unfollow(gomock.Any(), 1, 2).Return(fmt.Errorf("db error"))
+					return fr
+				},
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByID(gomock.Any(), 2).Return(&models.User{ID: 2, Login: "followee"}, nil)
+					return ur
+				},
+			},
+			args:    args{ctx: authCtx(), userID: "2"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getFollowRepo: func(c *gomock.Controller) repository.FollowRepo {
+					fr := mocks.NewMockFollowRepo(c)
+					fr.EXPECT().Unfollow(gomock.Any(), 1, 2).Return(nil)
+					return fr
+				},
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByID(gomock.Any(), 2).Return(&models.User{ID: 2, Login: "followee"}, nil)
+					return ur
+				},
+			},
+			args:    args{ctx: authCtx(), userID: "2"},
+			want:    &model.User{ID: "2", Username: "followee"},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:     logger.Sugar(),
+					FollowRepo: tt.resolverFields.getFollowRepo(c),
+					UserRepo:   tt.resolverFields.getUserRepo(c),
+				},
+			}
+			got, err := r.Unfollow(tt.args.ctx, tt.args.userID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Unfollow() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unfollow() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
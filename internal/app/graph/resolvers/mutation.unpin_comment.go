@@ -0,0 +1,11 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+)
+
+// UnpinComment is the resolver for the unpinComment field.
+func (r *mutationResolver) UnpinComment(ctx context.Context, commentID string) (*model.Post, error) {
+	return r.setCommentPinned(ctx, commentID, false)
+}
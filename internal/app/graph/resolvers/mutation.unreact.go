@@ -0,0 +1,35 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// Unreact is the resolver for the unreact field.
+func (r *mutationResolver) Unreact(ctx context.Context, targetType model.ReactionTargetType, targetID string) (*model.ReactionPayload, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+	target, err := parseReactionTarget(targetType, targetID)
+	if err != nil {
+		r.Logger.Debugf("cant parse reaction target, err: %v", err)
+		return nil, err
+	}
+
+	reactions, err := r.ReactionRepo.Unreact(ctx, target, user.ID)
+	if err != nil {
+		r.Logger.Debugf("cant remove reaction, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, targetNotFound(targetType)
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	return newReactionPayload(targetType, targetID, reactions, ""), nil
+}
@@ -0,0 +1,117 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_mutationResolver_Unreact(t *testing.T) {
+	type args struct {
+		ctx        context.Context
+		targetType model.ReactionTargetType
+		targetID   string
+	}
+	type resolverFields struct {
+		getReactionRepo func(c *gomock.Controller) repository.ReactionRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noReactionRepo := func(c *gomock.Controller) repository.ReactionRepo { return mocks.NewMockReactionRepo(c) }
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.ReactionPayload
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getReactionRepo: noReactionRepo},
+			args:           args{ctx: context.Background(), targetType: model.ReactionTargetTypeComment, targetID: "5"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "targetID is not int",
+			resolverFields: resolverFields{getReactionRepo: noReactionRepo},
+			args:           args{ctx: authCtx(), targetType: model.ReactionTargetTypeComment, targetID: "abc"},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "Target not found",
+			resolverFields: resolverFields{
+				getReactionRepo: func(c *gomock.Controller) repository.ReactionRepo {
+					rr := mocks.NewMockReactionRepo(c)
+					rr.EXPECT().Unreact(gomock.Any(), repository.Target{Type: repository.TargetComment, ID: 5}, 1).Return(nil, repository.NewErrNotFound())
+					return rr
+				},
+			},
+			args:    args{ctx: authCtx(), targetType: model.ReactionTargetTypeComment, targetID: "5"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getReactionRepo: func(c *gomock.Controller) repository.ReactionRepo {
+					rr := mocks.NewMockReactionRepo(c)
+					rr.EXPECT().Unreact(gomock.Any(), repository.Target{Type: repository.TargetComment, ID: 5}, 1).Return(nil, fmt.Errorf("db error"))
+					return rr
+				},
+			},
+			args:    args{ctx: authCtx(), targetType: model.ReactionTargetTypeComment, targetID: "5"},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getReactionRepo: func(c *gomock.Controller) repository.ReactionRepo {
+					rr := mocks.NewMockReactionRepo(c)
+					rr.EXPECT().Unreact(gomock.Any(), repository.Target{Type: repository.TargetComment, ID: 5}, 1).Return(models.Reactions{models.ReactionLike: 1}, nil)
+					return rr
+				},
+			},
+			args: args{ctx: authCtx(), targetType: model.ReactionTargetTypeComment, targetID: "5"},
+			want: &model.ReactionPayload{
+				TargetType: model.ReactionTargetTypeComment,
+				TargetID:   "5",
+				Reactions:  []*model.ReactionCount{{Kind: model.ReactionKindLike, Count: 1}},
+				Score:      1,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:       logger.Sugar(),
+					ReactionRepo: tt.resolverFields.getReactionRepo(c),
+				},
+			}
+			got, err := r.Unreact(tt.args.ctx, tt.args.targetType, tt.args.targetID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Unreact() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unreact() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strings"
+)
+
+// UpdateProfile is the resolver for the updateProfile field.
+func (r *mutationResolver) UpdateProfile(ctx context.Context, displayName *string, bio *string, avatarURL *string) (*model.User, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	//apply changes to a copy, user in ctx is shared
+	updated := *user
+	if displayName != nil {
+		updated.DisplayName = strings.TrimSpace(*displayName)
+	}
+	if bio != nil {
+		updated.Bio = strings.TrimSpace(*bio)
+	}
+	if avatarURL != nil {
+		updated.AvatarURL = strings.TrimSpace(*avatarURL)
+	}
+	if err := validateProfile(&updated); err != nil {
+		r.Logger.Debugf("invalid profile, err: %v", err)
+		return nil, validationError(err)
+	}
+
+	if err := r.UserRepo.UpdateProfile(ctx, &updated); err != nil {
+		r.Logger.Debugf("cant update profile in db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+	return newUserModel(&updated), nil
+}
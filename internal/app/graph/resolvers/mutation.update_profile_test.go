@@ -0,0 +1,162 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_mutationResolver_UpdateProfile(t *testing.T) {
+	type args struct {
+		ctx         context.Context
+		displayName *string
+		bio         *string
+		avatarURL   *string
+	}
+	type resolverFields struct {
+		getUserRepo func(c *gomock.Controller) repository.UserRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{
+			ID:           1,
+			Login:        "qwerty",
+			DisplayName:  "Old name",
+			Bio:          "Old bio",
+			RegisteredAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+		}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.User
+		wantErr        bool
+	}{
+		{
+			name: "Not authorized",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					return mocks.NewMockUserRepo(c)
+				},
+			},
+			args:    args{ctx: context.Background(), bio: str("bio")},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Display name is too long",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					return mocks.NewMockUserRepo(c)
+				},
+			},
+			args:    args{ctx: authCtx(), displayName: str(strings.Repeat("я", maxDisplayNameLength+1))},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Display name with control characters",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					return mocks.NewMockUserRepo(c)
+				},
+			},
+			args:    args{ctx: authCtx(), displayName: str("new\nname")},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Bio is too long",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					return mocks.NewMockUserRepo(c)
+				},
+			},
+			args:    args{ctx: authCtx(), bio: str(strings.Repeat("a", maxBioLength+1))},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Avatar URL is not http",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					return mocks.NewMockUserRepo(c)
+				},
+			},
+			args:    args{ctx: authCtx(), avatarURL: str("javascript:alert(1)")},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Return(fmt.Errorf("db error"))
+					return ur
+				},
+			},
+			args:    args{ctx: authCtx(), bio: str("bio")},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().UpdateProfile(gomock.Any(), &models.User{
+						ID:           1,
+						Login:        "qwerty",
+						DisplayName:  "Old name",
+						Bio:          "",
+						AvatarURL:    "https://example.com/a.png",
+						RegisteredAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+					}).Return(nil)
+					return ur
+				},
+			},
+			args: args{ctx: authCtx(), bio: str(" "), avatarURL: str("https://example.com/a.png")},
+			want: &model.User{
+				ID:           "1",
+				Username:     "qwerty",
+				DisplayName:  str("Old name"),
+				AvatarURL:    str("https://example.com/a.png"),
+				RegisteredAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &mutationResolver{
+				Resolver: &Resolver{
+					Logger:   logger.Sugar(),
+					UserRepo: tt.resolverFields.getUserRepo(c),
+				},
+			}
+			got, err := r.UpdateProfile(tt.args.ctx, tt.args.displayName, tt.args.bio, tt.args.avatarURL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UpdateProfile() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/models"
+)
+
+// notifyAboutComment saves notifications about a new comment.
+// The repository publishes saved notifications, every replica passes them to its NotificationHub subscribers.
+// postOwnerID is an owner of a commented post and parentOwnerID is an owner of a replied comment, zero if not applicable.
+// Every user gets at most one notification about a comment and nobody is notified about their own comment.
+// Failures are only logged, because the comment is already saved.
+func (r *Resolver) notifyAboutComment(ctx context.Context, comment *models.Comment, postOwnerID, parentOwnerID int) {
+	var notifications []*models.Notification
+	notified := map[int]bool{comment.Owner.ID: true}
+	add := func(userID int, notificationType models.NotificationType) {
+		if userID == 0 || notified[userID] {
+			return
+		}
+		notified[userID] = true
+		notifications = append(notifications, &models.Notification{
+			UserID:    userID,
+			Type:      notificationType,
+			Comment:   *comment,
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+	add(parentOwnerID, models.NotificationReply)
+	add(postOwnerID, models.NotificationComment)
+	for _, user := range comment.Mentions {
+		add(user.ID, models.NotificationMention)
+	}
+	if len(notifications) == 0 {
+		return
+	}
+
+	if err := r.NotificationRepo.AddNotifications(ctx, notifications); err != nil {
+		r.Logger.Errorf("failed to add notifications: %v", err)
+	}
+}
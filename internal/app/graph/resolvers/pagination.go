@@ -0,0 +1,59 @@
+package resolvers
+
+import (
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/cursor"
+)
+
+// idOrder is a sort mode of lists ordered by item ID.
+const idOrder = "ID"
+
+// decodeAfter decodes an "after" cursor into a repository position.
+// Returns nil position if after is nil. Cursors made for another sort mode are not valid.
+func (r *Resolver) decodeAfter(after *string, order string) (*repository.Position, error) {
+	if after == nil {
+		return nil, nil
+	}
+	cur, err := r.CursorCodec.Decode(*after)
+	if err != nil {
+		return nil, err
+	}
+	if cur.Order != order {
+		return nil, cursor.NewErrInvalidCursor()
+	}
+	return &repository.Position{Key: cur.Key, ID: cur.ID}, nil
+}
+
+// encodeCursor returns an opaque cursor of an item with a given sort key and ID.
+func (r *Resolver) encodeCursor(order string, key int64, id int) string {
+	return r.CursorCodec.Encode(cursor.Cursor{Order: order, Key: key, ID: id})
+}
+
+// postSortKey returns a sort key of a post for cursors of a given order.
+func postSortKey(order repository.Order, post *models.Post) int64 {
+	switch order {
+	case repository.OrderMostCommented:
+		return int64(post.CommentsCount)
+	case repository.OrderRecentActivity:
+		return post.LastActivityAt.UnixMicro()
+	case repository.OrderTop:
+		return int64(post.Reactions.Score())
+	default:
+		return int64(post.ID)
+	}
+}
+
+// commentSortKey returns a sort key of a top-level comment for cursors of a given order.
+func commentSortKey(order repository.Order, comment *models.Comment) int64 {
+	switch order {
+	case repository.OrderMostCommented:
+		return int64(comment.DescendantsCount)
+	case repository.OrderRecentActivity:
+		return comment.LastActivityAt.UnixMicro()
+	case repository.OrderTop:
+		return int64(comment.Reactions.Score())
+	default:
+		return int64(comment.ID)
+	}
+}
@@ -0,0 +1,84 @@
+package resolvers
+
+import (
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/cursor"
+	"testing"
+)
+
+var testCursorCodec = cursor.NewCodec([]byte("test cursor secret"))
+
+// testCursor returns a cursor of an item ordered by ID, as resolvers make it with testCursorCodec.
+func testCursor(id int) string {
+	return testCursorCodec.Encode(cursor.Cursor{Order: idOrder, Key: int64(id), ID: id})
+}
+
+// testSortCursor returns a cursor of an item of a sorted list, as resolvers make it with testCursorCodec.
+func testSortCursor(order repository.Order, key int64, id int) string {
+	return testCursorCodec.Encode(cursor.Cursor{Order: string(order), Key: key, ID: id})
+}
+
+func TestResolver_decodeAfter(t *testing.T) {
+	r := &Resolver{CursorCodec: testCursorCodec}
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		after   *string
+		order   string
+		wantID  int
+		wantNil bool
+		wantErr bool
+	}{
+		{
+			name:    "nil after",
+			after:   nil,
+			order:   idOrder,
+			wantNil: true,
+		},
+		{
+			name:   "Ok",
+			after:  str(testCursor(42)),
+			order:  idOrder,
+			wantID: 42,
+		},
+		{
+			name:    "raw id is not a cursor",
+			after:   str("42"),
+			order:   idOrder,
+			wantErr: true,
+		},
+		{
+			name:    "forged cursor",
+			after:   str(cursor.NewCodec([]byte("other secret")).Encode(cursor.Cursor{Order: idOrder, Key: 1, ID: 1})),
+			order:   idOrder,
+			wantErr: true,
+		},
+		{
+			name:    "cursor of another order",
+			after:   str(testCursorCodec.Encode(cursor.Cursor{Order: "OTHER", Key: 1, ID: 1})),
+			order:   idOrder,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.decodeAfter(tt.after, tt.order)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeAfter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("decodeAfter() got = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.ID != tt.wantID {
+				t.Errorf("decodeAfter() got = %v, want ID %v", got, tt.wantID)
+			}
+		})
+	}
+}
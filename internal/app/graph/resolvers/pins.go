@@ -0,0 +1,72 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+	"time"
+)
+
+// setCommentPinned pins or unpins a top-level comment on a post of the current user and returns the post.
+func (r *Resolver) setCommentPinned(ctx context.Context, commentID string, pinned bool) (*model.Post, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	commentIDInt, err := strconv.Atoi(commentID)
+	if err != nil {
+		r.Logger.Debugf("cant convert commentID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "comment id is not int")
+	}
+
+	comment, err := r.CommentRepo.GetCommentByID(ctx, commentIDInt)
+	if err != nil {
+		r.Logger.Debugf("cant get comment from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "comment not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	if comment.ParentID != 0 {
+		r.Logger.Debugf("cant pin comment \"%v\", it is a reply", comment.ID)
+		return nil, apperrors.New(apperrors.Validation, "only top-level comments can be pinned")
+	}
+
+	//check if user is owner of the commented post
+	post, err := r.PostRepo.GetPostByID(ctx, comment.PostID)
+	if err != nil {
+		r.Logger.Debugf("cant get post from db, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "post not found")
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	if user.ID != post.Owner.ID {
+		r.Logger.Debugf("cant pin this comment, user is not a post owner. UserID is \"%v\", but ownerID is \"%v\"", user.ID, post.Owner.ID)
+		return nil, apperrors.New(apperrors.Forbidden, "cant modify comments of this post")
+	}
+
+	if pinned {
+		err = r.CommentRepo.PinComment(ctx, post.ID, comment.ID, time.Now(), r.Cfg.MaxPinnedComments)
+	} else {
+		err = r.CommentRepo.UnpinComment(ctx, post.ID, comment.ID)
+	}
+	if err != nil {
+		r.Logger.Debugf("cant set comment pinned, err: %v", err)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, apperrors.New(apperrors.NotFound, "comment not found")
+		}
+		if errors.Is(err, repository.NewErrConflict()) {
+			return nil, apperrors.New(apperrors.Conflict, "post can have at most %d pinned comments", r.Cfg.MaxPinnedComments)
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+	return newPostModel(post), nil
+}
@@ -2,19 +2,20 @@ package resolvers
 
 import (
 	"context"
-	"fmt"
 	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
 	"strconv"
 )
 
 //type postResolver struct{ *Resolver }
 
-func (p *postResolver) Comments(ctx context.Context, obj *model.Post, limit *int32, after *string) (*model.CommentConnection, error) {
+func (p *postResolver) Comments(ctx context.Context, obj *model.Post, limit *int32, after *string, orderBy model.SortOrder, excludePinned bool) (*model.CommentConnection, error) {
 	//data prepare
 	id, err := strconv.Atoi(obj.ID)
 	if err != nil {
 		p.Logger.Debugf("cant convert postID to int, err: %v", err)
-		return nil, fmt.Errorf("postID is not an int")
+		return nil, apperrors.New(apperrors.Validation, "postID is not an int")
 	}
 	limitInt := 0
 	if limit == nil {
@@ -25,48 +26,36 @@ func (p *postResolver) Comments(ctx context.Context, obj *model.Post, limit *int
 			limitInt = p.Cfg.MaxCommentsLimit
 		}
 	}
-	var afterInt int
-	if after == nil {
-		afterInt = 0
-	} else {
-		afterInt, err = strconv.Atoi(*after)
-		if err != nil {
-			p.Logger.Debugf("cant convert after to int, err: %v", err)
-			return nil, fmt.Errorf("after is not convertable to int")
-		}
+	order := repository.Order(orderBy)
+	afterPos, err := p.decodeAfter(after, string(order))
+	if err != nil {
+		p.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
 	}
 
 	//get data
-	comments, hasNextPage, err := p.CommentRepo.GetCommentsByPostID(ctx, id, limitInt, afterInt)
+	comments, hasNextPage, err := p.CommentRepo.GetCommentsByPostID(ctx, id, repository.CommentFilter{ExcludePinned: excludePinned}, order, repository.PageArgs{Limit: limitInt, After: afterPos})
 	if err != nil {
 		p.Logger.Debugf("cant get comments from db, err: %v", err)
-		return nil, fmt.Errorf("failed to get comments by post id: %w", err)
-	}
-
-	var startCursor string
-	var endCursor string
-	if len(comments) > 0 {
-		startCursor = strconv.Itoa(comments[0].ID)
-		endCursor = strconv.Itoa(comments[len(comments)-1].ID)
+		return nil, apperrors.NewInternal(err)
 	}
 
 	//prepare answer
 	edges := make([]*model.CommentEdge, len(comments))
 	for i, comment := range comments {
 		edges[i] = &model.CommentEdge{
-			Cursor: strconv.Itoa(comment.ID),
-			Node: &model.Comment{
-				ID:        strconv.Itoa(comment.ID),
-				Text:      comment.Text,
-				CreatedAt: comment.CreatedAt.String(),
-				Owner: &model.User{
-					ID:       strconv.Itoa(comment.Owner.ID),
-					Username: comment.Owner.Login,
-				},
-			},
+			Cursor: p.encodeCursor(string(order), commentSortKey(order, comment), comment.ID),
+			Node:   newCommentModel(comment),
 		}
 	}
 
+	var startCursor string
+	var endCursor string
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
 	// return the answer
 	return &model.CommentConnection{
 		Edges: edges,
@@ -17,10 +17,12 @@ import (
 
 func Test_postResolver_Comments(t *testing.T) {
 	type args struct {
-		ctx   context.Context
-		obj   *model.Post
-		limit *int32
-		after *string
+		ctx           context.Context
+		obj           *model.Post
+		limit         *int32
+		after         *string
+		orderBy       model.SortOrder
+		excludePinned bool
 	}
 	type resolverFields struct {
 		cfg            cfg.Cfg
@@ -42,8 +44,9 @@ func Test_postResolver_Comments(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx: context.Background(),
-				obj: &model.Post{ID: "abc"},
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
+				obj:     &model.Post{ID: "abc"},
 			},
 			want:    nil,
 			wantErr: true,
@@ -57,8 +60,9 @@ func Test_postResolver_Comments(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx: context.Background(),
-				obj: &model.Post{ID: "10"},
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
+				obj:     &model.Post{ID: "10"},
 				after: func() *string {
 					v := "abc"
 					return &v
@@ -67,6 +71,34 @@ func Test_postResolver_Comments(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Exclude pinned comments",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					DefaultCommentsLimit: 10,
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentsByPostID(gomock.Any(), 10, repository.CommentFilter{ExcludePinned: true}, repository.OrderOldest, repository.PageArgs{Limit: 10}).Return([]*models.Comment{}, false, nil)
+					return cr
+				},
+			},
+			args: args{
+				ctx:           context.Background(),
+				orderBy:       model.SortOrderOldest,
+				obj:           &model.Post{ID: "10"},
+				excludePinned: true,
+			},
+			want: &model.CommentConnection{
+				Edges: []*model.CommentEdge{},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { s := ""; return &s }(),
+					EndCursor:   func() *string { s := ""; return &s }(),
+					HasNextPage: false,
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "DB err",
 			resolverFields: resolverFields{
@@ -75,15 +107,16 @@ func Test_postResolver_Comments(t *testing.T) {
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
 					cr := mocks.NewMockCommentRepo(c)
-					cr.EXPECT().GetCommentsByPostID(gomock.Any(), 10, 10, 0).Return(nil, false, fmt.Errorf("db error"))
+					cr.EXPECT().GetCommentsByPostID(gomock.Any(), 10, repository.CommentFilter{}, repository.OrderOldest, repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
 					return cr
 				},
 			},
 			args: args{
-				ctx:   context.Background(),
-				obj:   &model.Post{ID: "10"},
-				limit: nil,
-				after: nil,
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
+				obj:     &model.Post{ID: "10"},
+				limit:   nil,
+				after:   nil,
 			},
 			want:    nil,
 			wantErr: true,
@@ -96,15 +129,16 @@ func Test_postResolver_Comments(t *testing.T) {
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
 					cr := mocks.NewMockCommentRepo(c)
-					cr.EXPECT().GetCommentsByPostID(gomock.Any(), 10, 10, 0).Return([]*models.Comment{}, false, nil)
+					cr.EXPECT().GetCommentsByPostID(gomock.Any(), 10, repository.CommentFilter{}, repository.OrderOldest, repository.PageArgs{Limit: 10}).Return([]*models.Comment{}, false, nil)
 					return cr
 				},
 			},
 			args: args{
-				ctx:   context.Background(),
-				obj:   &model.Post{ID: "10"},
-				limit: nil,
-				after: nil,
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
+				obj:     &model.Post{ID: "10"},
+				limit:   nil,
+				after:   nil,
 			},
 			want: &model.CommentConnection{
 				Edges: []*model.CommentEdge{},
@@ -124,7 +158,7 @@ func Test_postResolver_Comments(t *testing.T) {
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
 					cr := mocks.NewMockCommentRepo(c)
-					cr.EXPECT().GetCommentsByPostID(gomock.Any(), 10, 10, 0).Return([]*models.Comment{
+					cr.EXPECT().GetCommentsByPostID(gomock.Any(), 10, repository.CommentFilter{}, repository.OrderOldest, repository.PageArgs{Limit: 10}).Return([]*models.Comment{
 						{
 							ID:        11,
 							Text:      "comment1",
@@ -148,19 +182,20 @@ func Test_postResolver_Comments(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx:   context.Background(),
-				obj:   &model.Post{ID: "10"},
-				limit: nil,
-				after: nil,
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
+				obj:     &model.Post{ID: "10"},
+				limit:   nil,
+				after:   nil,
 			},
 			want: &model.CommentConnection{
 				Edges: []*model.CommentEdge{
 					{
-						Cursor: "11",
+						Cursor: testSortCursor(repository.OrderOldest, 11, 11),
 						Node: &model.Comment{
 							ID:        "11",
 							Text:      "comment1",
-							CreatedAt: time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC).String(),
+							CreatedAt: time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
 							Owner: &model.User{
 								ID:       "1",
 								Username: "user1",
@@ -168,11 +203,11 @@ func Test_postResolver_Comments(t *testing.T) {
 						},
 					},
 					{
-						Cursor: "12",
+						Cursor: testSortCursor(repository.OrderOldest, 12, 12),
 						Node: &model.Comment{
 							ID:        "12",
 							Text:      "comment2",
-							CreatedAt: time.Date(2020, 10, 31, 0, 0, 1, 0, time.UTC).String(),
+							CreatedAt: time.Date(2020, 10, 31, 0, 0, 1, 0, time.UTC),
 							Owner: &model.User{
 								ID:       "2",
 								Username: "user2",
@@ -181,13 +216,140 @@ func Test_postResolver_Comments(t *testing.T) {
 					},
 				},
 				PageInfo: &model.PageInfo{
-					StartCursor: func() *string { s := "11"; return &s }(),
-					EndCursor:   func() *string { s := "12"; return &s }(),
+					StartCursor: func() *string { s := testSortCursor(repository.OrderOldest, 11, 11); return &s }(),
+					EndCursor:   func() *string { s := testSortCursor(repository.OrderOldest, 12, 12); return &s }(),
 					HasNextPage: true,
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "Recent activity",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					DefaultCommentsLimit: 10,
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentsByPostID(gomock.Any(), 10, repository.CommentFilter{}, repository.OrderRecentActivity, repository.PageArgs{Limit: 10}).Return([]*models.Comment{
+						{
+							ID:               11,
+							Text:             "comment1",
+							CreatedAt:        time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+							LastActivityAt:   time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC),
+							RepliesCount:     2,
+							DescendantsCount: 3,
+							Owner: models.User{
+								ID:    1,
+								Login: "user1",
+							},
+						},
+					}, false, nil)
+					return cr
+				},
+			},
+			args: args{
+				ctx:     context.Background(),
+				orderBy: model.SortOrderRecentActivity,
+				obj:     &model.Post{ID: "10"},
+				limit:   nil,
+				after:   nil,
+			},
+			want: &model.CommentConnection{
+				Edges: []*model.CommentEdge{
+					{
+						Cursor: testSortCursor(repository.OrderRecentActivity, time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC).UnixMicro(), 11),
+						Node: &model.Comment{
+							ID:              "11",
+							Text:            "comment1",
+							ReplyCount:      2,
+							DescendantCount: 3,
+							CreatedAt:       time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+							Owner: &model.User{
+								ID:       "1",
+								Username: "user1",
+							},
+						},
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string {
+						s := testSortCursor(repository.OrderRecentActivity, time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC).UnixMicro(), 11)
+						return &s
+					}(),
+					EndCursor: func() *string {
+						s := testSortCursor(repository.OrderRecentActivity, time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC).UnixMicro(), 11)
+						return &s
+					}(),
+					HasNextPage: false,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Top",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					DefaultCommentsLimit: 10,
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentsByPostID(gomock.Any(), 10, repository.CommentFilter{}, repository.OrderTop, repository.PageArgs{Limit: 10}).Return([]*models.Comment{
+						{
+							ID:        11,
+							Text:      "comment1",
+							CreatedAt: time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+							Reactions: models.Reactions{models.ReactionDislike: 1, models.ReactionLike: 3},
+							Owner: models.User{
+								ID:    1,
+								Login: "user1",
+							},
+						},
+					}, false, nil)
+					return cr
+				},
+			},
+			args: args{
+				ctx:     context.Background(),
+				orderBy: model.SortOrderTop,
+				obj:     &model.Post{ID: "10"},
+				limit:   nil,
+				after:   nil,
+			},
+			want: &model.CommentConnection{
+				Edges: []*model.CommentEdge{
+					{
+						Cursor: testSortCursor(repository.OrderTop, 2, 11),
+						Node: &model.Comment{
+							ID:        "11",
+							Text:      "comment1",
+							CreatedAt: time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+							Reactions: []*model.ReactionCount{
+								{Kind: model.ReactionKindLike, Count: 3},
+								{Kind: model.ReactionKindDislike, Count: 1},
+							},
+							Score: 2,
+							Owner: &model.User{
+								ID:       "1",
+								Username: "user1",
+							},
+						},
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string {
+						s := testSortCursor(repository.OrderTop, 2, 11)
+						return &s
+					}(),
+					EndCursor: func() *string {
+						s := testSortCursor(repository.OrderTop, 2, 11)
+						return &s
+					}(),
+					HasNextPage: false,
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -197,11 +359,12 @@ func Test_postResolver_Comments(t *testing.T) {
 			p := &postResolver{
 				Resolver: &Resolver{
 					Logger:      sugar,
+					CursorCodec: testCursorCodec,
 					Cfg:         tt.resolverFields.cfg,
 					CommentRepo: tt.resolverFields.getCommentRepo(c),
 				},
 			}
-			got, err := p.Comments(tt.args.ctx, tt.args.obj, tt.args.limit, tt.args.after)
+			got, err := p.Comments(tt.args.ctx, tt.args.obj, tt.args.limit, tt.args.after, tt.args.orderBy, tt.args.excludePinned)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Comments() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,12 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/pkg/markdown"
+)
+
+// HTML is the resolver for the html field.
+func (p *postResolver) HTML(ctx context.Context, obj *model.Post) (string, error) {
+	return p.TextRenderer.Render(markdown.Format(obj.TextFormat), obj.Text), nil
+}
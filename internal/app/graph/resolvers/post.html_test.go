@@ -0,0 +1,51 @@
+package resolvers
+
+import (
+	"context"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/pkg/markdown"
+	"testing"
+)
+
+func Test_postResolver_HTML(t *testing.T) {
+	renderer, err := markdown.NewRenderer(16)
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		obj  *model.Post
+		want string
+	}{
+		{
+			name: "Plain",
+			obj:  &model.Post{ID: "5", Text: "**a** <b>", TextFormat: model.TextFormatPlain},
+			want: "<p>**a** &lt;b&gt;</p>\n",
+		},
+		{
+			name: "Markdown",
+			obj:  &model.Post{ID: "5", Text: "**a** <script>alert(1)</script>", TextFormat: model.TextFormatMarkdown},
+			want: "<p><strong>a</strong> alert(1)</p>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			r := &postResolver{
+				Resolver: &Resolver{
+					Logger:       logger.Sugar(),
+					TextRenderer: renderer,
+				},
+			}
+			got, err := r.HTML(context.Background(), tt.obj)
+			if err != nil {
+				t.Errorf("HTML() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("HTML() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
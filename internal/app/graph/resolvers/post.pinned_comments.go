@@ -0,0 +1,28 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// PinnedComments is the resolver for the pinnedComments field.
+func (p *postResolver) PinnedComments(ctx context.Context, obj *model.Post) ([]*model.Comment, error) {
+	postID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		p.Logger.Debugf("cant convert postID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "postID is not an int")
+	}
+
+	comments, err := p.CommentRepo.GetPinnedComments(ctx, postID)
+	if err != nil {
+		p.Logger.Debugf("cant get pinned comments from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+	result := make([]*model.Comment, len(comments))
+	for i, comment := range comments {
+		result[i] = newCommentModel(comment)
+	}
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_postResolver_PinnedComments(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		obj *model.Post
+	}
+	type resolverFields struct {
+		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           []*model.Comment
+		wantErr        bool
+	}{
+		{
+			name: "postID is not int",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+			},
+			args:    args{ctx: context.Background(), obj: &model.Post{ID: "abc"}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetPinnedComments(gomock.Any(), 3).Return(nil, fmt.Errorf("db error"))
+					return cr
+				},
+			},
+			args:    args{ctx: context.Background(), obj: &model.Post{ID: "3"}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetPinnedComments(gomock.Any(), 3).Return([]*models.Comment{
+						{ID: 8, PostID: 3, Owner: models.User{ID: 2, Login: "user2"}, Text: "second", TextFormat: models.TextPlain},
+						{ID: 5, PostID: 3, Owner: models.User{ID: 1, Login: "user1"}, Text: "first", TextFormat: models.TextPlain},
+					}, nil)
+					return cr
+				},
+			},
+			args: args{ctx: context.Background(), obj: &model.Post{ID: "3"}},
+			want: []*model.Comment{
+				{ID: "8", Owner: &model.User{ID: "2", Username: "user2"}, Text: "second", TextFormat: model.TextFormatPlain},
+				{ID: "5", Owner: &model.User{ID: "1", Username: "user1"}, Text: "first", TextFormat: model.TextFormatPlain},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			p := &postResolver{
+				Resolver: &Resolver{
+					Logger:      logger.Sugar(),
+					CommentRepo: tt.resolverFields.getCommentRepo(c),
+				},
+			}
+			got, err := p.PinnedComments(tt.args.ctx, tt.args.obj)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PinnedComments() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PinnedComments() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
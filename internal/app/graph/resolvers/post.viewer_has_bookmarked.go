@@ -0,0 +1,30 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// ViewerHasBookmarked is the resolver for the viewerHasBookmarked field.
+func (p *postResolver) ViewerHasBookmarked(ctx context.Context, obj *model.Post) (bool, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		return false, nil
+	}
+	postID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		p.Logger.Debugf("cant convert postID to int, err: %v", err)
+		return false, apperrors.New(apperrors.Validation, "postID is not an int")
+	}
+
+	bookmarked, err := p.BookmarkRepo.IsBookmarked(ctx, user.ID, postID)
+	if err != nil {
+		p.Logger.Debugf("cant check bookmark, err: %v", err)
+		return false, apperrors.NewInternal(err)
+	}
+	return bookmarked, nil
+}
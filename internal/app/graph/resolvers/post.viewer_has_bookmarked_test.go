@@ -0,0 +1,97 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"testing"
+)
+
+func Test_postResolver_ViewerHasBookmarked(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		obj *model.Post
+	}
+	type resolverFields struct {
+		getBookmarkRepo func(c *gomock.Controller) repository.BookmarkRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noBookmarkRepo := func(c *gomock.Controller) repository.BookmarkRepo { return mocks.NewMockBookmarkRepo(c) }
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           bool
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getBookmarkRepo: noBookmarkRepo},
+			args:           args{ctx: context.Background(), obj: &model.Post{ID: "5"}},
+			want:           false,
+			wantErr:        false,
+		},
+		{
+			name:           "postID is not int",
+			resolverFields: resolverFields{getBookmarkRepo: noBookmarkRepo},
+			args:           args{ctx: authCtx(), obj: &model.Post{ID: "abc"}},
+			want:           false,
+			wantErr:        true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getBookmarkRepo: func(c *gomock.Controller) repository.BookmarkRepo {
+					br := mocks.NewMockBookmarkRepo(c)
+					br.EXPECT().IsBookmarked(gomock.Any(), 1, 5).Return(false, fmt.Errorf("db error"))
+					return br
+				},
+			},
+			args:    args{ctx: authCtx(), obj: &model.Post{ID: "5"}},
+			want:    false,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getBookmarkRepo: func(c *gomock.Controller) repository.BookmarkRepo {
+					br := mocks.NewMockBookmarkRepo(c)
+					br.EXPECT().IsBookmarked(gomock.Any(), 1, 5).Return(true, nil)
+					return br
+				},
+			},
+			args:    args{ctx: authCtx(), obj: &model.Post{ID: "5"}},
+			want:    true,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &postResolver{
+				Resolver: &Resolver{
+					Logger:       logger.Sugar(),
+					BookmarkRepo: tt.resolverFields.getBookmarkRepo(c),
+				},
+			}
+			got, err := r.ViewerHasBookmarked(tt.args.ctx, tt.args.obj)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ViewerHasBookmarked() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ViewerHasBookmarked() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+)
+
+// ViewerReaction is the resolver for the viewerReaction field.
+func (p *postResolver) ViewerReaction(ctx context.Context, obj *model.Post) (*model.ReactionKind, error) {
+	return p.viewerReaction(ctx, repository.TargetPost, obj.ID)
+}
@@ -0,0 +1,111 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_postResolver_ViewerReaction(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		obj *model.Post
+	}
+	type resolverFields struct {
+		getReactionRepo func(c *gomock.Controller) repository.ReactionRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noReactionRepo := func(c *gomock.Controller) repository.ReactionRepo { return mocks.NewMockReactionRepo(c) }
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.ReactionKind
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getReactionRepo: noReactionRepo},
+			args:           args{ctx: context.Background(), obj: &model.Post{ID: "5"}},
+			want:           nil,
+			wantErr:        false,
+		},
+		{
+			name:           "postID is not int",
+			resolverFields: resolverFields{getReactionRepo: noReactionRepo},
+			args:           args{ctx: authCtx(), obj: &model.Post{ID: "abc"}},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getReactionRepo: func(c *gomock.Controller) repository.ReactionRepo {
+					rr := mocks.NewMockReactionRepo(c)
+					rr.EXPECT().GetReaction(gomock.Any(), repository.Target{Type: repository.TargetPost, ID: 5}, 1).Return(models.ReactionKind(""), fmt.Errorf("db error"))
+					return rr
+				},
+			},
+			args:    args{ctx: authCtx(), obj: &model.Post{ID: "5"}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "No reaction",
+			resolverFields: resolverFields{
+				getReactionRepo: func(c *gomock.Controller) repository.ReactionRepo {
+					rr := mocks.NewMockReactionRepo(c)
+					rr.EXPECT().GetReaction(gomock.Any(), repository.Target{Type: repository.TargetPost, ID: 5}, 1).Return(models.ReactionKind(""), nil)
+					return rr
+				},
+			},
+			args:    args{ctx: authCtx(), obj: &model.Post{ID: "5"}},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getReactionRepo: func(c *gomock.Controller) repository.ReactionRepo {
+					rr := mocks.NewMockReactionRepo(c)
+					rr.EXPECT().GetReaction(gomock.Any(), repository.Target{Type: repository.TargetPost, ID: 5}, 1).Return(models.ReactionLaugh, nil)
+					return rr
+				},
+			},
+			args:    args{ctx: authCtx(), obj: &model.Post{ID: "5"}},
+			want:    func() *model.ReactionKind { v := model.ReactionKindLaugh; return &v }(),
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &postResolver{
+				Resolver: &Resolver{
+					Logger:       logger.Sugar(),
+					ReactionRepo: tt.resolverFields.getReactionRepo(c),
+				},
+			}
+			got, err := r.ViewerReaction(tt.args.ctx, tt.args.obj)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ViewerReaction() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ViewerReaction() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package resolvers
+
+import (
+	"net/url"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/validation"
+)
+
+const (
+	// maxDisplayNameLength is a maximum length of a display name in characters.
+	maxDisplayNameLength = 64
+	// maxBioLength is a maximum length of a bio in characters.
+	maxBioLength = 500
+	// maxAvatarURLLength is a maximum length of an avatar URL in bytes.
+	maxAvatarURLLength = 2048
+)
+
+// validateProfile checks user`s profile fields, empty fields are valid.
+func validateProfile(user *models.User) error {
+	var v validation.Validator
+	v.Text("displayName", user.DisplayName, validation.Limits{MaxLength: maxDisplayNameLength})
+	v.Text("bio", user.Bio, validation.Limits{MaxLength: maxBioLength, Multiline: true})
+
+	if user.AvatarURL != "" {
+		u, err := url.Parse(user.AvatarURL)
+		switch {
+		case len(user.AvatarURL) > maxAvatarURLLength:
+			v.Add("avatarURL", "must be at most 2048 bytes long")
+		case err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+			v.Add("avatarURL", "must be an absolute http(s) URL")
+		}
+	}
+	return v.Err()
+}
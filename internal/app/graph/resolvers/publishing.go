@@ -0,0 +1,43 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"time"
+)
+
+// newPostPublishAt validates publishAt of a new post with a given status and returns its publish time, nil for drafts.
+// Published posts are published now and scheduled ones must be published in the future.
+func newPostPublishAt(status model.PostStatus, publishAt *time.Time, now time.Time) (*time.Time, error) {
+	switch status {
+	case model.PostStatusScheduled:
+		if publishAt == nil || !publishAt.After(now) {
+			return nil, apperrors.New(apperrors.Validation, "publishAt must be in the future for a scheduled post")
+		}
+		return publishAt, nil
+	case model.PostStatusPublished:
+		if publishAt != nil {
+			return nil, apperrors.New(apperrors.Validation, "publishAt is allowed only for scheduled posts")
+		}
+		return &now, nil
+	case model.PostStatusHeld:
+		return nil, apperrors.New(apperrors.Validation, "posts can't be created held")
+	default:
+		if publishAt != nil {
+			return nil, apperrors.New(apperrors.Validation, "publishAt is allowed only for scheduled posts")
+		}
+		return nil, nil
+	}
+}
+
+// isPostVisible returns true if a post is published or the current user is its owner or a moderator.
+func (r *Resolver) isPostVisible(ctx context.Context, post *models.Post) bool {
+	if post.Status == models.PostPublished {
+		return true
+	}
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	return ok && (user.ID == post.Owner.ID || r.isModerator(user))
+}
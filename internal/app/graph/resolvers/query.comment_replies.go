@@ -2,8 +2,9 @@ package resolvers
 
 import (
 	"context"
-	"fmt"
 	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
 	"strconv"
 )
 
@@ -23,39 +24,27 @@ func (r *queryResolver) CommentReplies(ctx context.Context, commentID string, li
 	commentIDInt, err := strconv.Atoi(commentID)
 	if err != nil {
 		r.Logger.Debugf("cant convert commentID to int: %v", err)
-		return nil, fmt.Errorf("commentID is not int")
+		return nil, apperrors.New(apperrors.Validation, "commentID is not int")
 	}
 
-	afterInt := 0
-	if after != nil {
-		afterInt, err = strconv.Atoi(*after)
-		if err != nil {
-			r.Logger.Debugf("cant convert after to int: %v", err)
-			return nil, fmt.Errorf("after is not int")
-		}
+	afterPos, err := r.decodeAfter(after, idOrder)
+	if err != nil {
+		r.Logger.Debugf("cant decode after cursor: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
 	}
 
 	//get replies
-	replays, hasNextPage, err := r.CommentRepo.GetReplaysByCommentID(ctx, commentIDInt, limitInt, afterInt)
+	replays, hasNextPage, err := r.CommentRepo.GetReplaysByCommentID(ctx, commentIDInt, repository.PageArgs{Limit: limitInt, After: afterPos})
 	if err != nil {
 		r.Logger.Debugf("failed to get replays from db: %v", err)
-		return nil, fmt.Errorf("interal server error")
+		return nil, apperrors.NewInternal(err)
 	}
 
 	edges := make([]*model.CommentEdge, len(replays))
 	for i, replay := range replays {
 		edges[i] = &model.CommentEdge{
-			Cursor: strconv.Itoa(replay.ID),
-			Node: &model.Comment{
-				ID: strconv.Itoa(replay.ID),
-				Owner: &model.User{
-					ID:       strconv.Itoa(replay.Owner.ID),
-					Username: replay.Owner.Login,
-				},
-				Text:      replay.Text,
-				CreatedAt: replay.CreatedAt.String(),
-				Replies:   nil,
-			},
+			Cursor: r.encodeCursor(idOrder, int64(replay.ID), replay.ID),
+			Node:   newCommentModel(replay),
 		}
 	}
 
@@ -63,8 +52,8 @@ func (r *queryResolver) CommentReplies(ctx context.Context, commentID string, li
 	endCoursor := ""
 
 	if len(edges) > 0 {
-		startCoursor = edges[0].Node.ID
-		endCoursor = edges[len(edges)-1].Node.ID
+		startCoursor = edges[0].Cursor
+		endCoursor = edges[len(edges)-1].Cursor
 	}
 
 	return &model.CommentConnection{
@@ -74,7 +74,7 @@ func Test_queryResolver_CommentReplies(t *testing.T) {
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
 					cr := mocks.NewMockCommentRepo(c)
-					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, 10, 0).Return(nil, false, fmt.Errorf("db error"))
+					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
 					return cr
 				},
 			},
@@ -96,7 +96,7 @@ func Test_queryResolver_CommentReplies(t *testing.T) {
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
 					cr := mocks.NewMockCommentRepo(c)
-					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, 5, 0).Return([]*models.Comment{}, false, nil)
+					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, repository.PageArgs{Limit: 5}).Return([]*models.Comment{}, false, nil)
 					return cr
 				},
 			},
@@ -125,7 +125,7 @@ func Test_queryResolver_CommentReplies(t *testing.T) {
 				},
 				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
 					cr := mocks.NewMockCommentRepo(c)
-					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, 10, 0).Return([]*models.Comment{
+					cr.EXPECT().GetReplaysByCommentID(gomock.Any(), 10, repository.PageArgs{Limit: 10}).Return([]*models.Comment{
 						{
 							ID: 1,
 							Owner: models.User{
@@ -157,11 +157,11 @@ func Test_queryResolver_CommentReplies(t *testing.T) {
 			want: &model.CommentConnection{
 				Edges: []*model.CommentEdge{
 					{
-						Cursor: "1",
+						Cursor: testCursor(1),
 						Node: &model.Comment{
 							ID:        "1",
 							Text:      "reply1",
-							CreatedAt: time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC).String(),
+							CreatedAt: time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC),
 							Owner: &model.User{
 								ID:       "10",
 								Username: "user10",
@@ -170,11 +170,11 @@ func Test_queryResolver_CommentReplies(t *testing.T) {
 						},
 					},
 					{
-						Cursor: "2",
+						Cursor: testCursor(2),
 						Node: &model.Comment{
 							ID:        "2",
 							Text:      "reply2",
-							CreatedAt: time.Date(2020, 12, 1, 0, 0, 1, 0, time.UTC).String(),
+							CreatedAt: time.Date(2020, 12, 1, 0, 0, 1, 0, time.UTC),
 							Owner: &model.User{
 								ID:       "11",
 								Username: "user11",
@@ -184,8 +184,8 @@ func Test_queryResolver_CommentReplies(t *testing.T) {
 					},
 				},
 				PageInfo: &model.PageInfo{
-					StartCursor: func() *string { s := "1"; return &s }(),
-					EndCursor:   func() *string { s := "2"; return &s }(),
+					StartCursor: func() *string { s := testCursor(1); return &s }(),
+					EndCursor:   func() *string { s := testCursor(2); return &s }(),
 					HasNextPage: true,
 				},
 			},
@@ -200,6 +200,7 @@ func Test_queryResolver_CommentReplies(t *testing.T) {
 			r := &queryResolver{
 				Resolver: &Resolver{
 					Logger:      sugar,
+					CursorCodec: testCursorCodec,
 					Cfg:         tt.resolverFields.cfg,
 					CommentRepo: tt.resolverFields.getCommentRepo(c),
 				},
@@ -0,0 +1,81 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// HeldComments is the resolver for the heldComments field.
+func (r *queryResolver) HeldComments(ctx context.Context, limit *int32, after *string, postID *string) (*model.HeldCommentConnection, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+	postIDInt := 0
+	if postID != nil {
+		var err error
+		postIDInt, err = strconv.Atoi(*postID)
+		if err != nil {
+			r.Logger.Debugf("cant convert postID to int, err: %v", err)
+			return nil, apperrors.New(apperrors.Validation, "post id is not int")
+		}
+	}
+	if err := r.checkHeldCommentsReviewer(ctx, user, postIDInt); err != nil {
+		return nil, err
+	}
+
+	//data prepare
+	limitInt := 0
+	if limit == nil {
+		limitInt = r.Cfg.DefaultCommentsLimit
+	} else {
+		limitInt = int(*limit)
+		if limitInt > r.Cfg.MaxCommentsLimit {
+			limitInt = r.Cfg.MaxCommentsLimit
+		}
+	}
+	order := string(repository.OrderOldest)
+	afterPos, err := r.decodeAfter(after, order)
+	if err != nil {
+		r.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
+	}
+
+	//get data
+	comments, hasNextPage, err := r.ModerationRepo.GetHeldComments(ctx, postIDInt, repository.PageArgs{Limit: limitInt, After: afterPos})
+	if err != nil {
+		r.Logger.Debugf("cant get held comments from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	//prepare answer
+	edges := make([]*model.HeldCommentEdge, len(comments))
+	for i, comment := range comments {
+		edges[i] = &model.HeldCommentEdge{
+			Cursor: r.encodeCursor(order, int64(comment.ID), comment.ID),
+			Node:   newHeldCommentModel(comment),
+		}
+	}
+
+	var startCursor string
+	var endCursor string
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
+	return &model.HeldCommentConnection{
+		Edges: edges,
+		PageInfo: &model.PageInfo{
+			StartCursor: &startCursor,
+			EndCursor:   &endCursor,
+			HasNextPage: hasNextPage,
+		},
+	}, nil
+}
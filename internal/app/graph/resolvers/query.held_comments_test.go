@@ -0,0 +1,265 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_queryResolver_HeldComments(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		limit  *int32
+		after  *string
+		postID *string
+	}
+	type resolverFields struct {
+		getModerationRepo func(c *gomock.Controller) repository.ModerationRepo
+		getPostRepo       func(c *gomock.Controller) repository.PostRepo
+	}
+	authCtx := func(login string) context.Context {
+		user := &models.User{ID: 1, Login: login}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noModerationRepo := func(c *gomock.Controller) repository.ModerationRepo { return mocks.NewMockModerationRepo(c) }
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	postRepo := func(c *gomock.Controller) repository.PostRepo {
+		pr := mocks.NewMockPostRepo(c)
+		pr.EXPECT().GetPostByID(gomock.Any(), 7).Return(&models.Post{ID: 7, Owner: models.User{ID: 1}}, nil)
+		return pr
+	}
+	postID := func(id string) *string { return &id }
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.HeldCommentConnection
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getModerationRepo: noModerationRepo, getPostRepo: noPostRepo},
+			args:           args{ctx: context.Background()},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Not a moderator",
+			resolverFields: resolverFields{getModerationRepo: noModerationRepo, getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx("qwerty")},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Post id is not int",
+			resolverFields: resolverFields{getModerationRepo: noModerationRepo, getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx("qwerty"), postID: postID("abc")},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name: "Post not found",
+			resolverFields: resolverFields{
+				getModerationRepo: noModerationRepo,
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 7).Return(nil, repository.NewErrNotFound())
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx("qwerty"), postID: postID("7")},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Not an owner of the post",
+			resolverFields: resolverFields{
+				getModerationRepo: noModerationRepo,
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 7).Return(&models.Post{ID: 7, Owner: models.User{ID: 2}}, nil)
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx("qwerty"), postID: postID("7")},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Post owner",
+			resolverFields: resolverFields{
+				getModerationRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().GetHeldComments(gomock.Any(), 7, repository.PageArgs{Limit: 10}).Return([]*models.HeldComment{
+						{
+							ID:         4,
+							RootPostID: 7,
+							Comment: models.Comment{
+								Owner:      models.User{ID: 2, Login: "owner"},
+								PostID:     7,
+								Text:       "Hello",
+								TextFormat: models.TextPlain,
+								CreatedAt:  createdAt,
+							},
+							Reasons: []string{approvalRequiredReason},
+						},
+					}, false, nil)
+					return mr
+				},
+				getPostRepo: postRepo,
+			},
+			args: args{ctx: authCtx("qwerty"), postID: postID("7")},
+			want: &model.HeldCommentConnection{
+				Edges: []*model.HeldCommentEdge{
+					{
+						Cursor: testSortCursor(repository.OrderOldest, 4, 4),
+						Node: &model.HeldComment{
+							ID:         "4",
+							Owner:      &model.User{ID: "2", Username: "owner"},
+							PostID:     func() *string { v := "7"; return &v }(),
+							Text:       "Hello",
+							TextFormat: model.TextFormatPlain,
+							CreatedAt:  createdAt,
+							Reasons:    []string{approvalRequiredReason},
+						},
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { v := testSortCursor(repository.OrderOldest, 4, 4); return &v }(),
+					EndCursor:   func() *string { v := testSortCursor(repository.OrderOldest, 4, 4); return &v }(),
+					HasNextPage: false,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:           "Cursor of another order",
+			resolverFields: resolverFields{getModerationRepo: noModerationRepo, getPostRepo: noPostRepo},
+			args: args{
+				ctx:   authCtx("moder"),
+				after: func() *string { v := testCursor(5); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getModerationRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().GetHeldComments(gomock.Any(), 0, repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
+					return mr
+				},
+				getPostRepo: noPostRepo,
+			},
+			args:    args{ctx: authCtx("moder")},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getModerationRepo: func(c *gomock.Controller) repository.ModerationRepo {
+					mr := mocks.NewMockModerationRepo(c)
+					mr.EXPECT().GetHeldComments(gomock.Any(), 0, repository.PageArgs{Limit: 20, After: &repository.Position{Key: 3, ID: 3}}).Return([]*models.HeldComment{
+						{
+							ID: 4,
+							Comment: models.Comment{
+								Owner:      models.User{ID: 2, Login: "owner"},
+								PostID:     7,
+								Text:       "Hello",
+								TextFormat: models.TextPlain,
+								CreatedAt:  createdAt,
+							},
+							Reasons: []string{"blocklisted \"hello\""},
+						},
+						{
+							ID: 5,
+							Comment: models.Comment{
+								Owner:      models.User{ID: 2, Login: "owner"},
+								ParentID:   9,
+								Text:       "Reply",
+								TextFormat: models.TextPlain,
+								CreatedAt:  createdAt,
+							},
+						},
+					}, false, nil)
+					return mr
+				},
+				getPostRepo: noPostRepo,
+			},
+			args: args{
+				ctx:   authCtx("moder"),
+				limit: func() *int32 { v := int32(50); return &v }(),
+				after: func() *string { v := testSortCursor(repository.OrderOldest, 3, 3); return &v }(),
+			},
+			want: &model.HeldCommentConnection{
+				Edges: []*model.HeldCommentEdge{
+					{
+						Cursor: testSortCursor(repository.OrderOldest, 4, 4),
+						Node: &model.HeldComment{
+							ID:         "4",
+							Owner:      &model.User{ID: "2", Username: "owner"},
+							PostID:     func() *string { v := "7"; return &v }(),
+							Text:       "Hello",
+							TextFormat: model.TextFormatPlain,
+							CreatedAt:  createdAt,
+							Reasons:    []string{"blocklisted \"hello\""},
+						},
+					},
+					{
+						Cursor: testSortCursor(repository.OrderOldest, 5, 5),
+						Node: &model.HeldComment{
+							ID:              "5",
+							Owner:           &model.User{ID: "2", Username: "owner"},
+							ParentCommentID: func() *string { v := "9"; return &v }(),
+							Text:            "Reply",
+							TextFormat:      model.TextFormatPlain,
+							CreatedAt:       createdAt,
+						},
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { v := testSortCursor(repository.OrderOldest, 4, 4); return &v }(),
+					EndCursor:   func() *string { v := testSortCursor(repository.OrderOldest, 5, 5); return &v }(),
+					HasNextPage: false,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &queryResolver{
+				Resolver: &Resolver{
+					Logger:         logger.Sugar(),
+					CursorCodec:    testCursorCodec,
+					Cfg:            cfg.Cfg{DefaultCommentsLimit: 10, MaxCommentsLimit: 20, Moderators: []string{"moder"}},
+					ModerationRepo: tt.resolverFields.getModerationRepo(c),
+					PostRepo:       tt.resolverFields.getPostRepo(c),
+				},
+			}
+			got, err := r.HeldComments(tt.args.ctx, tt.args.limit, tt.args.after, tt.args.postID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HeldComments() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HeldComments() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// HeldPosts is the resolver for the heldPosts field.
+func (r *queryResolver) HeldPosts(ctx context.Context, limit *int32, after *string) (*model.PostConnection, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+	if !r.isModerator(user) {
+		r.Logger.Debugf("user \"%v\" is not a moderator", user.Login)
+		return nil, apperrors.New(apperrors.Forbidden, "moderators only")
+	}
+
+	//prepare input data
+	limitInt := 0
+	if limit == nil {
+		limitInt = r.Cfg.DefaultPostsLimit
+	} else {
+		limitInt = int(*limit)
+		if limitInt > r.Cfg.MaxPostsLimit {
+			limitInt = r.Cfg.MaxPostsLimit
+		}
+	}
+	order := repository.OrderOldest
+	afterPos, err := r.decodeAfter(after, string(order))
+	if err != nil {
+		r.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
+	}
+
+	//get posts
+	posts, hasNextPage, err := r.PostRepo.GetPosts(ctx, repository.PostFilter{Held: true}, order, repository.PageArgs{Limit: limitInt, After: afterPos})
+	if err != nil {
+		r.Logger.Debugf("cant get held posts from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	return r.newPostConnection(order, posts, hasNextPage), nil
+}
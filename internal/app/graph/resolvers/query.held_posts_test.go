@@ -0,0 +1,136 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_queryResolver_HeldPosts(t *testing.T) {
+	type args struct {
+		ctx   context.Context
+		limit *int32
+		after *string
+	}
+	type resolverFields struct {
+		getPostRepo func(c *gomock.Controller) repository.PostRepo
+	}
+	authCtx := func(login string) context.Context {
+		user := &models.User{ID: 1, Login: login}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	filter := repository.PostFilter{Held: true}
+	held := &models.Post{
+		ID:                5,
+		Title:             "Title",
+		Owner:             models.User{ID: 2, Login: "owner"},
+		Status:            models.PostHeld,
+		ModerationReasons: []string{"more than 3 links"},
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.PostConnection
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: context.Background()},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Not a moderator",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx("qwerty")},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Cursor of another order",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args: args{
+				ctx:   authCtx("moder"),
+				after: func() *string { v := testSortCursor(repository.OrderNewest, 3, 3); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPosts(gomock.Any(), filter, repository.OrderOldest, repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx("moder")},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPosts(gomock.Any(), filter, repository.OrderOldest, repository.PageArgs{Limit: 20, After: &repository.Position{Key: 3, ID: 3}}).Return([]*models.Post{held}, true, nil)
+					return pr
+				},
+			},
+			args: args{
+				ctx:   authCtx("moder"),
+				limit: func() *int32 { v := int32(50); return &v }(),
+				after: func() *string { v := testSortCursor(repository.OrderOldest, 3, 3); return &v }(),
+			},
+			want: &model.PostConnection{
+				Edges: []*model.PostEdge{
+					{
+						Cursor: testSortCursor(repository.OrderOldest, 5, 5),
+						Node:   newPostModel(held),
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { v := testSortCursor(repository.OrderOldest, 5, 5); return &v }(),
+					EndCursor:   func() *string { v := testSortCursor(repository.OrderOldest, 5, 5); return &v }(),
+					HasNextPage: true,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &queryResolver{
+				Resolver: &Resolver{
+					Logger:      logger.Sugar(),
+					CursorCodec: testCursorCodec,
+					Cfg:         cfg.Cfg{DefaultPostsLimit: 10, MaxPostsLimit: 20, Moderators: []string{"moder"}},
+					PostRepo:    tt.resolverFields.getPostRepo(c),
+				},
+			}
+			got, err := r.HeldPosts(tt.args.ctx, tt.args.limit, tt.args.after)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HeldPosts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HeldPosts() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package resolvers
+
+import (
+	"context"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_queryResolver_Me(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		want    *model.User
+		wantErr bool
+	}{
+		{
+			name:    "Not authorized",
+			ctx:     context.Background(),
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "Ok",
+			ctx: func() context.Context {
+				user := &models.User{ID: 1, Login: "qwerty", Bio: "Hello", RegisteredAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC)}
+				return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+			}(),
+			want: &model.User{
+				ID:           "1",
+				Username:     "qwerty",
+				Bio:          func() *string { v := "Hello"; return &v }(),
+				RegisteredAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			r := &queryResolver{
+				Resolver: &Resolver{
+					Logger: logger.Sugar(),
+				},
+			}
+			got, err := r.Me(tt.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Me() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Me() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// Mentions is the resolver for the mentions field.
+func (r *queryResolver) Mentions(ctx context.Context, limit *int32, after *string) (*model.CommentConnection, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	//data prepare
+	limitInt := 0
+	if limit == nil {
+		limitInt = r.Cfg.DefaultCommentsLimit
+	} else {
+		limitInt = int(*limit)
+		if limitInt > r.Cfg.MaxCommentsLimit {
+			limitInt = r.Cfg.MaxCommentsLimit
+		}
+	}
+	order := string(repository.OrderNewest)
+	afterPos, err := r.decodeAfter(after, order)
+	if err != nil {
+		r.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
+	}
+
+	//get data
+	comments, hasNextPage, err := r.CommentRepo.GetCommentsByMentionedUserID(ctx, user.ID, repository.PageArgs{Limit: limitInt, After: afterPos})
+	if err != nil {
+		r.Logger.Debugf("cant get mentioning comments from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	//prepare answer
+	edges := make([]*model.CommentEdge, len(comments))
+	for i, comment := range comments {
+		edges[i] = &model.CommentEdge{
+			Cursor: r.encodeCursor(order, int64(comment.ID), comment.ID),
+			Node:   newCommentModel(comment),
+		}
+	}
+
+	var startCursor string
+	var endCursor string
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
+	return &model.CommentConnection{
+		Edges: edges,
+		PageInfo: &model.PageInfo{
+			StartCursor: &startCursor,
+			EndCursor:   &endCursor,
+			HasNextPage: hasNextPage,
+		},
+	}, nil
+}
@@ -0,0 +1,141 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_queryResolver_Mentions(t *testing.T) {
+	type args struct {
+		ctx   context.Context
+		limit *int32
+		after *string
+	}
+	type resolverFields struct {
+		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.CommentConnection
+		wantErr        bool
+	}{
+		{
+			name: "Not authorized",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+			},
+			args:    args{ctx: context.Background()},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Cursor of another order",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+			},
+			args: args{
+				ctx:   authCtx(),
+				after: func() *string { v := testCursor(5); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentsByMentionedUserID(gomock.Any(), 1, repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
+					return cr
+				},
+			},
+			args:    args{ctx: authCtx()},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentsByMentionedUserID(gomock.Any(), 1, repository.PageArgs{Limit: 20, After: &repository.Position{Key: 9, ID: 9}}).Return([]*models.Comment{
+						{
+							ID:        7,
+							Text:      "hi @qwerty",
+							CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+							Owner:     models.User{ID: 2, Login: "bob"},
+						},
+					}, true, nil)
+					return cr
+				},
+			},
+			args: args{
+				ctx:   authCtx(),
+				limit: func() *int32 { v := int32(50); return &v }(),
+				after: func() *string { v := testSortCursor(repository.OrderNewest, 9, 9); return &v }(),
+			},
+			want: &model.CommentConnection{
+				Edges: []*model.CommentEdge{
+					{
+						Cursor: testSortCursor(repository.OrderNewest, 7, 7),
+						Node: &model.Comment{
+							ID:        "7",
+							Text:      "hi @qwerty",
+							CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+							Owner:     &model.User{ID: "2", Username: "bob"},
+						},
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { v := testSortCursor(repository.OrderNewest, 7, 7); return &v }(),
+					EndCursor:   func() *string { v := testSortCursor(repository.OrderNewest, 7, 7); return &v }(),
+					HasNextPage: true,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &queryResolver{
+				Resolver: &Resolver{
+					Logger:      logger.Sugar(),
+					CursorCodec: testCursorCodec,
+					Cfg:         cfg.Cfg{DefaultCommentsLimit: 10, MaxCommentsLimit: 20},
+					CommentRepo: tt.resolverFields.getCommentRepo(c),
+				},
+			}
+			got, err := r.Mentions(tt.args.ctx, tt.args.limit, tt.args.after)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Mentions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Mentions() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
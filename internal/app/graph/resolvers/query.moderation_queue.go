@@ -0,0 +1,69 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// ModerationQueue is the resolver for the moderationQueue field.
+func (r *queryResolver) ModerationQueue(ctx context.Context, limit *int32, after *string) (*model.ReportCaseConnection, error) {
+	if _, err := r.moderatorFromContext(ctx); err != nil {
+		return nil, err
+	}
+
+	//data prepare
+	limitInt := 0
+	if limit == nil {
+		limitInt = r.Cfg.DefaultPostsLimit
+	} else {
+		limitInt = int(*limit)
+		if limitInt > r.Cfg.MaxPostsLimit {
+			limitInt = r.Cfg.MaxPostsLimit
+		}
+	}
+	order := string(repository.OrderMostReported)
+	afterPos, err := r.decodeAfter(after, order)
+	if err != nil {
+		r.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
+	}
+
+	//get data
+	cases, hasNextPage, err := r.ReportRepo.GetReportCases(ctx, repository.PageArgs{Limit: limitInt, After: afterPos})
+	if err != nil {
+		r.Logger.Debugf("cant get report cases from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	//prepare answer
+	edges := make([]*model.ReportCaseEdge, len(cases))
+	for i, rc := range cases {
+		node, err := r.newReportCaseModel(ctx, rc)
+		if err != nil {
+			r.Logger.Debugf("cant get reported content from db, err: %v", err)
+			return nil, apperrors.NewInternal(err)
+		}
+		edges[i] = &model.ReportCaseEdge{
+			Cursor: r.encodeCursor(order, int64(rc.ReportCount), rc.ID),
+			Node:   node,
+		}
+	}
+
+	var startCursor string
+	var endCursor string
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
+	return &model.ReportCaseConnection{
+		Edges: edges,
+		PageInfo: &model.PageInfo{
+			StartCursor: &startCursor,
+			EndCursor:   &endCursor,
+			HasNextPage: hasNextPage,
+		},
+	}, nil
+}
@@ -0,0 +1,222 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_queryResolver_ModerationQueue(t *testing.T) {
+	type args struct {
+		ctx   context.Context
+		limit *int32
+		after *string
+	}
+	type resolverFields struct {
+		getReportRepo  func(c *gomock.Controller) repository.ReportRepo
+		getPostRepo    func(c *gomock.Controller) repository.PostRepo
+		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+	}
+	authCtx := func(login string) context.Context {
+		user := &models.User{ID: 1, Login: login}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noReportRepo := func(c *gomock.Controller) repository.ReportRepo { return mocks.NewMockReportRepo(c) }
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	noCommentRepo := func(c *gomock.Controller) repository.CommentRepo { return mocks.NewMockCommentRepo(c) }
+	reportedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.ReportCaseConnection
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getReportRepo: noReportRepo, getPostRepo: noPostRepo, getCommentRepo: noCommentRepo},
+			args:           args{ctx: context.Background()},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Not a moderator",
+			resolverFields: resolverFields{getReportRepo: noReportRepo, getPostRepo: noPostRepo, getCommentRepo: noCommentRepo},
+			args:           args{ctx: authCtx("qwerty")},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Cursor of another order",
+			resolverFields: resolverFields{getReportRepo: noReportRepo, getPostRepo: noPostRepo, getCommentRepo: noCommentRepo},
+			args: args{
+				ctx:   authCtx("moder"),
+				after: func() *string { v := testCursor(5); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getReportRepo: func(c *gomock.Controller) repository.ReportRepo {
+					rr := mocks.NewMockReportRepo(c)
+					rr.EXPECT().GetReportCases(gomock.Any(), repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
+					return rr
+				},
+				getPostRepo:    noPostRepo,
+				getCommentRepo: noCommentRepo,
+			},
+			args:    args{ctx: authCtx("moder")},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Reported content DB error",
+			resolverFields: resolverFields{
+				getReportRepo: func(c *gomock.Controller) repository.ReportRepo {
+					rr := mocks.NewMockReportRepo(c)
+					rr.EXPECT().GetReportCases(gomock.Any(), repository.PageArgs{Limit: 10}).Return([]*models.ReportCase{
+						{ID: 3, TargetType: models.ReportPost, TargetID: 5, ReportCount: 1},
+					}, false, nil)
+					return rr
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(nil, fmt.Errorf("db error"))
+					return pr
+				},
+				getCommentRepo: noCommentRepo,
+			},
+			args:    args{ctx: authCtx("moder")},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getReportRepo: func(c *gomock.Controller) repository.ReportRepo {
+					rr := mocks.NewMockReportRepo(c)
+					rr.EXPECT().GetReportCases(gomock.Any(), repository.PageArgs{Limit: 20, After: &repository.Position{Key: 4, ID: 2}}).Return([]*models.ReportCase{
+						{
+							ID:             3,
+							TargetType:     models.ReportPost,
+							TargetID:       5,
+							AuthorID:       2,
+							ReportCount:    3,
+							Reasons:        map[models.ReportReason]int{models.ReportOther: 1, models.ReportSpam: 2},
+							Details:        []string{"buy now"},
+							LastReportedAt: reportedAt,
+						},
+						{
+							ID:             4,
+							TargetType:     models.ReportComment,
+							TargetID:       7,
+							AuthorID:       2,
+							ReportCount:    1,
+							Reasons:        map[models.ReportReason]int{models.ReportAbuse: 1},
+							LastReportedAt: reportedAt,
+						},
+					}, true, nil)
+					return rr
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 5).Return(&models.Post{
+						ID:     5,
+						Title:  "Cheap",
+						Text:   "Buy now",
+						Owner:  models.User{ID: 2, Login: "author"},
+						Status: models.PostPublished,
+					}, nil)
+					return pr
+				},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentByID(gomock.Any(), 7).Return(nil, repository.NewErrNotFound())
+					return cr
+				},
+			},
+			args: args{
+				ctx:   authCtx("moder"),
+				limit: func() *int32 { v := int32(50); return &v }(),
+				after: func() *string { v := testSortCursor(repository.OrderMostReported, 4, 2); return &v }(),
+			},
+			want: &model.ReportCaseConnection{
+				Edges: []*model.ReportCaseEdge{
+					{
+						Cursor: testSortCursor(repository.OrderMostReported, 3, 3),
+						Node: &model.ReportCase{
+							ID:         "3",
+							TargetType: model.ReportTargetTypePost,
+							Post: &model.Post{
+								ID:     "5",
+								Title:  "Cheap",
+								Text:   "Buy now",
+								Owner:  &model.User{ID: "2", Username: "author"},
+								Status: model.PostStatusPublished,
+							},
+							ReportCount: 3,
+							Reasons: []*model.ReportReasonCount{
+								{Reason: model.ReportReasonSpam, Count: 2},
+								{Reason: model.ReportReasonOther, Count: 1},
+							},
+							Details:        []string{"buy now"},
+							LastReportedAt: reportedAt,
+						},
+					},
+					{
+						Cursor: testSortCursor(repository.OrderMostReported, 1, 4),
+						Node: &model.ReportCase{
+							ID:             "4",
+							TargetType:     model.ReportTargetTypeComment,
+							ReportCount:    1,
+							Reasons:        []*model.ReportReasonCount{{Reason: model.ReportReasonAbuse, Count: 1}},
+							LastReportedAt: reportedAt,
+						},
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { v := testSortCursor(repository.OrderMostReported, 3, 3); return &v }(),
+					EndCursor:   func() *string { v := testSortCursor(repository.OrderMostReported, 1, 4); return &v }(),
+					HasNextPage: true,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &queryResolver{
+				Resolver: &Resolver{
+					Logger:      logger.Sugar(),
+					CursorCodec: testCursorCodec,
+					Cfg:         cfg.Cfg{DefaultPostsLimit: 10, MaxPostsLimit: 20, Moderators: []string{"moder"}},
+					ReportRepo:  tt.resolverFields.getReportRepo(c),
+					PostRepo:    tt.resolverFields.getPostRepo(c),
+					CommentRepo: tt.resolverFields.getCommentRepo(c),
+				},
+			}
+			got, err := r.ModerationQueue(tt.args.ctx, tt.args.limit, tt.args.after)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ModerationQueue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ModerationQueue() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
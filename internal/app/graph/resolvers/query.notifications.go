@@ -0,0 +1,68 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// Notifications is the resolver for the notifications field.
+func (r *queryResolver) Notifications(ctx context.Context, first *int32, after *string, unreadOnly bool) (*model.NotificationConnection, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	//data prepare
+	limitInt := 0
+	if first == nil {
+		limitInt = r.Cfg.DefaultNotificationsLimit
+	} else {
+		limitInt = int(*first)
+		if limitInt > r.Cfg.MaxNotificationsLimit {
+			limitInt = r.Cfg.MaxNotificationsLimit
+		}
+	}
+	order := string(repository.OrderNewest)
+	afterPos, err := r.decodeAfter(after, order)
+	if err != nil {
+		r.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
+	}
+
+	//get data
+	notifications, hasNextPage, err := r.NotificationRepo.GetNotifications(ctx, user.ID, unreadOnly, repository.PageArgs{Limit: limitInt, After: afterPos})
+	if err != nil {
+		r.Logger.Debugf("cant get notifications from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	//prepare answer
+	edges := make([]*model.NotificationEdge, len(notifications))
+	for i, notification := range notifications {
+		edges[i] = &model.NotificationEdge{
+			Cursor: r.encodeCursor(order, int64(notification.ID), notification.ID),
+			Node:   newNotificationModel(notification),
+		}
+	}
+
+	var startCursor string
+	var endCursor string
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
+	return &model.NotificationConnection{
+		Edges: edges,
+		PageInfo: &model.PageInfo{
+			StartCursor: &startCursor,
+			EndCursor:   &endCursor,
+			HasNextPage: hasNextPage,
+		},
+	}, nil
+}
@@ -0,0 +1,155 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_queryResolver_Notifications(t *testing.T) {
+	type args struct {
+		ctx        context.Context
+		first      *int32
+		after      *string
+		unreadOnly bool
+	}
+	type resolverFields struct {
+		getNotificationRepo func(c *gomock.Controller) repository.NotificationRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.NotificationConnection
+		wantErr        bool
+	}{
+		{
+			name: "Not authorized",
+			resolverFields: resolverFields{
+				getNotificationRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					return mocks.NewMockNotificationRepo(c)
+				},
+			},
+			args:    args{ctx: context.Background()},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Cursor of another order",
+			resolverFields: resolverFields{
+				getNotificationRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					return mocks.NewMockNotificationRepo(c)
+				},
+			},
+			args: args{
+				ctx:   authCtx(),
+				after: func() *string { v := testCursor(5); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getNotificationRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().GetNotifications(gomock.Any(), 1, false, repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
+					return nr
+				},
+			},
+			args:    args{ctx: authCtx()},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getNotificationRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().GetNotifications(gomock.Any(), 1, true, repository.PageArgs{Limit: 20, After: &repository.Position{Key: 9, ID: 9}}).Return([]*models.Notification{
+						{
+							ID:     7,
+							UserID: 1,
+							Type:   models.NotificationMention,
+							Comment: models.Comment{
+								ID:        3,
+								Text:      "hi @qwerty",
+								CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+								Owner:     models.User{ID: 2, Login: "bob"},
+							},
+							CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+						},
+					}, true, nil)
+					return nr
+				},
+			},
+			args: args{
+				ctx:        authCtx(),
+				first:      func() *int32 { v := int32(50); return &v }(),
+				after:      func() *string { v := testSortCursor(repository.OrderNewest, 9, 9); return &v }(),
+				unreadOnly: true,
+			},
+			want: &model.NotificationConnection{
+				Edges: []*model.NotificationEdge{
+					{
+						Cursor: testSortCursor(repository.OrderNewest, 7, 7),
+						Node: &model.Notification{
+							ID:    "7",
+							Type:  model.NotificationTypeMention,
+							Actor: &model.User{ID: "2", Username: "bob"},
+							Comment: &model.Comment{
+								ID:        "3",
+								Text:      "hi @qwerty",
+								CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+								Owner:     &model.User{ID: "2", Username: "bob"},
+							},
+							CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+						},
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { v := testSortCursor(repository.OrderNewest, 7, 7); return &v }(),
+					EndCursor:   func() *string { v := testSortCursor(repository.OrderNewest, 7, 7); return &v }(),
+					HasNextPage: true,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &queryResolver{
+				Resolver: &Resolver{
+					Logger:           logger.Sugar(),
+					CursorCodec:      testCursorCodec,
+					Cfg:              cfg.Cfg{DefaultNotificationsLimit: 10, MaxNotificationsLimit: 20},
+					NotificationRepo: tt.resolverFields.getNotificationRepo(c),
+				},
+			}
+			got, err := r.Notifications(tt.args.ctx, tt.args.first, tt.args.after, tt.args.unreadOnly)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Notifications() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Notifications() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
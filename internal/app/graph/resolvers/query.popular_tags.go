@@ -0,0 +1,32 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// PopularTags is the resolver for the popularTags field.
+func (r *queryResolver) PopularTags(ctx context.Context, limit *int32) ([]*model.Tag, error) {
+	limitInt := 0
+	if limit == nil {
+		limitInt = r.Cfg.DefaultTagsLimit
+	} else {
+		limitInt = int(*limit)
+		if limitInt > r.Cfg.MaxTagsLimit {
+			limitInt = r.Cfg.MaxTagsLimit
+		}
+	}
+
+	tags, err := r.TagRepo.GetPopularTags(ctx, limitInt)
+	if err != nil {
+		r.Logger.Debugf("cant get popular tags from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	result := make([]*model.Tag, len(tags))
+	for i, tag := range tags {
+		result[i] = newTagModel(tag)
+	}
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_queryResolver_PopularTags(t *testing.T) {
+	type resolverFields struct {
+		cfg        cfg.Cfg
+		getTagRepo func(c *gomock.Controller) repository.TagRepo
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		limit          *int32
+		want           []*model.Tag
+		wantErr        bool
+	}{
+		{
+			name: "Cfg value",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{DefaultTagsLimit: 10},
+				getTagRepo: func(c *gomock.Controller) repository.TagRepo {
+					tr := mocks.NewMockTagRepo(c)
+					tr.EXPECT().GetPopularTags(gomock.Any(), 10).Return(nil, nil)
+					return tr
+				},
+			},
+			limit:   nil,
+			want:    []*model.Tag{},
+			wantErr: false,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxTagsLimit: 50},
+				getTagRepo: func(c *gomock.Controller) repository.TagRepo {
+					tr := mocks.NewMockTagRepo(c)
+					tr.EXPECT().GetPopularTags(gomock.Any(), 5).Return(nil, fmt.Errorf("db error"))
+					return tr
+				},
+			},
+			limit:   func() *int32 { v := int32(5); return &v }(),
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Requested limit is bigger then cfg.max",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxTagsLimit: 2},
+				getTagRepo: func(c *gomock.Controller) repository.TagRepo {
+					tr := mocks.NewMockTagRepo(c)
+					tr.EXPECT().GetPopularTags(gomock.Any(), 2).Return([]*models.Tag{
+						{Name: "go", PostsCount: 5},
+						{Name: "redis", PostsCount: 2},
+					}, nil)
+					return tr
+				},
+			},
+			limit: func() *int32 { v := int32(20); return &v }(),
+			want: []*model.Tag{
+				{Name: "go", PostCount: 5},
+				{Name: "redis", PostCount: 2},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			sugar := logger.Sugar()
+			c := gomock.NewController(t)
+			r := &queryResolver{
+				Resolver: &Resolver{
+					Logger:  sugar,
+					Cfg:     tt.resolverFields.cfg,
+					TagRepo: tt.resolverFields.getTagRepo(c),
+				},
+			}
+			got, err := r.PopularTags(context.Background(), tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PopularTags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PopularTags() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
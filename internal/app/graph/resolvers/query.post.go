@@ -2,8 +2,8 @@ package resolvers
 
 import (
 	"context"
-	"fmt"
 	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/pkg/apperrors"
 	"strconv"
 )
 
@@ -12,7 +12,7 @@ func (r *queryResolver) Post(ctx context.Context, id string) (*model.Post, error
 	IDInt, err := strconv.Atoi(id)
 	if err != nil {
 		r.Logger.Debugf("cant convert ID to int, err: %v", err)
-		return nil, fmt.Errorf("ID is not convertable to int")
+		return nil, apperrors.New(apperrors.Validation, "ID is not convertable to int")
 	}
 
 	post, err := r.PostRepo.GetPostByID(ctx, IDInt)
@@ -20,15 +20,10 @@ func (r *queryResolver) Post(ctx context.Context, id string) (*model.Post, error
 		r.Logger.Debugf("cant get post from db, err: %v", err)
 		return nil, err
 	}
+	if !r.isPostVisible(ctx, post) {
+		r.Logger.Debugf("post is not published and user is not its owner")
+		return nil, apperrors.New(apperrors.NotFound, "post not found")
+	}
 
-	return &model.Post{
-		ID:    strconv.Itoa(post.ID),
-		Title: post.Title,
-		Text:  post.Text,
-		Owner: &model.User{
-			ID:       strconv.Itoa(post.Owner.ID),
-			Username: post.Owner.Login,
-		},
-		CommentsAllowed: post.CommentsAllowed,
-	}, nil
+	return newPostModel(post), nil
 }
@@ -8,9 +8,11 @@ import (
 	"ozon_test_task/internal/app/graph/model"
 	"ozon_test_task/internal/app/graph/repository"
 	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
 	"ozon_test_task/internal/app/models"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_queryResolver_Post(t *testing.T) {
@@ -88,6 +90,10 @@ func Test_queryResolver_Post(t *testing.T) {
 							ID:    5,
 							Login: "ownerUser",
 						},
+						CreatedAt:     time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+						UpdatedAt:     time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+						LastCommentAt: func() *time.Time { t := time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC); return &t }(),
+						Status:        models.PostPublished,
 					}, nil)
 					return pr
 				},
@@ -105,6 +111,66 @@ func Test_queryResolver_Post(t *testing.T) {
 					Username: "ownerUser",
 				},
 				CommentsAllowed: true,
+				CreatedAt:       time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+				UpdatedAt:       time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+				LastCommentAt:   func() *time.Time { t := time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC); return &t }(),
+				Status:          model.PostStatusPublished,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Draft of another user",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 10).Return(&models.Post{
+						ID:     10,
+						Title:  "Test Title",
+						Owner:  models.User{ID: 5, Login: "ownerUser"},
+						Status: models.PostDraft,
+					}, nil)
+					return pr
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 1, Login: "qwerty"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				id: "10",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Own draft",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPostByID(gomock.Any(), 10).Return(&models.Post{
+						ID:     10,
+						Title:  "Test Title",
+						Owner:  models.User{ID: 5, Login: "ownerUser"},
+						Status: models.PostDraft,
+					}, nil)
+					return pr
+				},
+			},
+			args: args{
+				ctx: func() context.Context {
+					user := &models.User{ID: 5, Login: "ownerUser"}
+					return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+				}(),
+				id: "10",
+			},
+			want: &model.Post{
+				ID:    "10",
+				Title: "Test Title",
+				Owner: &model.User{
+					ID:       "5",
+					Username: "ownerUser",
+				},
+				Status: model.PostStatusDraft,
 			},
 			wantErr: false,
 		},
@@ -2,13 +2,28 @@ package resolvers
 
 import (
 	"context"
-	"fmt"
 	"ozon_test_task/internal/app/graph/model"
-	"strconv"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
 )
 
 // Posts is the resolver for the posts field.
-func (r *queryResolver) Posts(ctx context.Context, limit *int32, after *string) (*model.PostConnection, error) {
+func (r *queryResolver) Posts(ctx context.Context, limit *int32, after *string, orderBy model.SortOrder, tag *string) (*model.PostConnection, error) {
+	filter := repository.PostFilter{}
+	if tag != nil {
+		name, err := normalizeTag(*tag)
+		if err != nil {
+			r.Logger.Debugf("cant normalize tag, err: %v", err)
+			return nil, apperrors.New(apperrors.Validation, "tag is not valid")
+		}
+		filter.Tag = name
+	}
+	return r.postConnection(ctx, filter, limit, after, orderBy)
+}
+
+// postConnection returns a page of posts matching a filter.
+func (r *Resolver) postConnection(ctx context.Context, filter repository.PostFilter, limit *int32, after *string, orderBy model.SortOrder) (*model.PostConnection, error) {
 	//prepare input data
 	limitInt := 0
 	if limit == nil {
@@ -19,33 +34,30 @@ func (r *queryResolver) Posts(ctx context.Context, limit *int32, after *string)
 			limitInt = r.Cfg.MaxPostsLimit
 		}
 	}
-	afterInt := 0
-	if after != nil {
-		var err error
-		afterInt, err = strconv.Atoi(*after)
-		if err != nil {
-			r.Logger.Debugf("cant convert after to int, err: %v", err)
-			return nil, fmt.Errorf("after is not a number")
-		}
+	order := repository.Order(orderBy)
+	afterPos, err := r.decodeAfter(after, string(order))
+	if err != nil {
+		r.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
 	}
 
 	//get posts
-	posts, hasNextPage, err := r.PostRepo.GetPosts(ctx, limitInt, afterInt)
+	posts, hasNextPage, err := r.PostRepo.GetPosts(ctx, filter, order, repository.PageArgs{Limit: limitInt, After: afterPos})
 	if err != nil {
 		r.Logger.Debugf("cant get posts from db, err: %v", err)
-		return nil, fmt.Errorf("cant get posts")
+		return nil, apperrors.NewInternal(err)
 	}
 
+	return r.newPostConnection(order, posts, hasNextPage), nil
+}
+
+// newPostConnection returns a connection of a page of posts sorted by "order".
+func (r *Resolver) newPostConnection(order repository.Order, posts []*models.Post, hasNextPage bool) *model.PostConnection {
 	edges := make([]*model.PostEdge, len(posts))
 	for i, post := range posts {
 		edges[i] = &model.PostEdge{
-			Cursor: strconv.Itoa(post.ID),
-			Node: &model.Post{
-				ID:              strconv.Itoa(post.ID),
-				Title:           post.Title,
-				Text:            post.Text,
-				CommentsAllowed: post.CommentsAllowed,
-			},
+			Cursor: r.encodeCursor(string(order), postSortKey(order, post), post.ID),
+			Node:   newPostModel(post),
 		}
 	}
 
@@ -63,5 +75,5 @@ func (r *queryResolver) Posts(ctx context.Context, limit *int32, after *string)
 			EndCursor:   &endCursor,
 			HasNextPage: hasNextPage,
 		},
-	}, nil
+	}
 }
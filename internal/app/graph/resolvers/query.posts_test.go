@@ -12,13 +12,16 @@ import (
 	"ozon_test_task/internal/app/models"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_queryResolver_Posts(t *testing.T) {
 	type args struct {
-		ctx   context.Context
-		limit *int32
-		after *string
+		ctx     context.Context
+		limit   *int32
+		after   *string
+		orderBy model.SortOrder
+		tag     *string
 	}
 	type resolverFields struct {
 		cfg         cfg.Cfg
@@ -40,8 +43,9 @@ func Test_queryResolver_Posts(t *testing.T) {
 				},
 			},
 			args: args{
-				ctx:   context.Background(),
-				limit: nil,
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
+				limit:   nil,
 				after: func() *string {
 					v := "abc"
 					return &v
@@ -58,14 +62,15 @@ func Test_queryResolver_Posts(t *testing.T) {
 				},
 				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
 					pr := mocks.NewMockPostRepo(c)
-					pr.EXPECT().GetPosts(gomock.Any(), 10, 0).Return(nil, false, fmt.Errorf("db error"))
+					pr.EXPECT().GetPosts(gomock.Any(), repository.PostFilter{}, repository.OrderOldest, repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
 					return pr
 				},
 			},
 			args: args{
-				ctx:   context.Background(),
-				limit: nil,
-				after: nil,
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
+				limit:   nil,
+				after:   nil,
 			},
 			want:    nil,
 			wantErr: true,
@@ -79,14 +84,15 @@ func Test_queryResolver_Posts(t *testing.T) {
 				},
 				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
 					pr := mocks.NewMockPostRepo(c)
-					pr.EXPECT().GetPosts(gomock.Any(), 10, 0).Return([]*models.Post{}, false, nil)
+					pr.EXPECT().GetPosts(gomock.Any(), repository.PostFilter{}, repository.OrderOldest, repository.PageArgs{Limit: 10}).Return([]*models.Post{}, false, nil)
 					return pr
 				},
 			},
 			args: args{
-				ctx:   context.Background(),
-				limit: nil,
-				after: nil,
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
+				limit:   nil,
+				after:   nil,
 			},
 			want: &model.PostConnection{
 				Edges: []*model.PostEdge{},
@@ -107,12 +113,13 @@ func Test_queryResolver_Posts(t *testing.T) {
 				},
 				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
 					pr := mocks.NewMockPostRepo(c)
-					pr.EXPECT().GetPosts(gomock.Any(), 3, 0).Return([]*models.Post{}, false, nil)
+					pr.EXPECT().GetPosts(gomock.Any(), repository.PostFilter{}, repository.OrderOldest, repository.PageArgs{Limit: 3}).Return([]*models.Post{}, false, nil)
 					return pr
 				},
 			},
 			args: args{
-				ctx: context.Background(),
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
 				limit: func() *int32 {
 					v := int32(3)
 					return &v
@@ -138,15 +145,16 @@ func Test_queryResolver_Posts(t *testing.T) {
 				},
 				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
 					pr := mocks.NewMockPostRepo(c)
-					pr.EXPECT().GetPosts(gomock.Any(), 5, 2).Return([]*models.Post{}, false, nil)
+					pr.EXPECT().GetPosts(gomock.Any(), repository.PostFilter{}, repository.OrderOldest, repository.PageArgs{Limit: 5, After: &repository.Position{Key: 2, ID: 2}}).Return([]*models.Post{}, false, nil)
 					return pr
 				},
 			},
 			args: args{
-				ctx:   context.Background(),
-				limit: nil,
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
+				limit:   nil,
 				after: func() *string {
-					v := "2"
+					v := testSortCursor(repository.OrderOldest, 2, 2)
 					return &v
 				}(),
 			},
@@ -169,57 +177,191 @@ func Test_queryResolver_Posts(t *testing.T) {
 				},
 				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
 					pr := mocks.NewMockPostRepo(c)
-					pr.EXPECT().GetPosts(gomock.Any(), 10, 0).Return([]*models.Post{
+					pr.EXPECT().GetPosts(gomock.Any(), repository.PostFilter{}, repository.OrderOldest, repository.PageArgs{Limit: 10}).Return([]*models.Post{
 						{
 							ID:              1,
 							Title:           "title1",
 							Text:            "text1",
 							CommentsAllowed: true,
+							Owner:           models.User{ID: 1, Login: "user1"},
+							CreatedAt:       time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
 						},
 						{
 							ID:              2,
 							Title:           "title2",
 							Text:            "text2",
 							CommentsAllowed: false,
+							Owner:           models.User{ID: 2, Login: "user2"},
+							CreatedAt:       time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
 						},
 					}, true, nil)
 					return pr
 				},
 			},
 			args: args{
-				ctx:   context.Background(),
-				limit: nil,
-				after: nil,
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
+				limit:   nil,
+				after:   nil,
 			},
 			want: &model.PostConnection{
 				Edges: []*model.PostEdge{
 					{
-						Cursor: "1",
+						Cursor: testSortCursor(repository.OrderOldest, 1, 1),
 						Node: &model.Post{
 							ID:              "1",
 							Title:           "title1",
 							Text:            "text1",
 							CommentsAllowed: true,
+							Owner:           &model.User{ID: "1", Username: "user1"},
+							CreatedAt:       time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+							UpdatedAt:       time.Time{},
 						},
 					},
 					{
-						Cursor: "2",
+						Cursor: testSortCursor(repository.OrderOldest, 2, 2),
 						Node: &model.Post{
 							ID:              "2",
 							Title:           "title2",
 							Text:            "text2",
 							CommentsAllowed: false,
+							Owner:           &model.User{ID: "2", Username: "user2"},
+							CreatedAt:       time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+							UpdatedAt:       time.Time{},
 						},
 					},
 				},
 				PageInfo: &model.PageInfo{
-					StartCursor: func() *string { s := "1"; return &s }(),
-					EndCursor:   func() *string { s := "2"; return &s }(),
+					StartCursor: func() *string { s := testSortCursor(repository.OrderOldest, 1, 1); return &s }(),
+					EndCursor:   func() *string { s := testSortCursor(repository.OrderOldest, 2, 2); return &s }(),
 					HasNextPage: true,
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "cursor of another order",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					return mocks.NewMockPostRepo(c)
+				},
+			},
+			args: args{
+				ctx:     context.Background(),
+				orderBy: model.SortOrderMostCommented,
+				limit:   nil,
+				after: func() *string {
+					v := testSortCursor(repository.OrderOldest, 2, 2)
+					return &v
+				}(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Most commented",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					DefaultPostsLimit: 10,
+					MaxPostsLimit:     10,
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPosts(gomock.Any(), repository.PostFilter{}, repository.OrderMostCommented, repository.PageArgs{
+						Limit: 10,
+						After: &repository.Position{Key: 7, ID: 3},
+					}).Return([]*models.Post{
+						{
+							ID:              2,
+							Title:           "title2",
+							Text:            "text2",
+							CommentsAllowed: true,
+							Owner:           models.User{ID: 2, Login: "user2"},
+							CreatedAt:       time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+							CommentsCount:   5,
+						},
+					}, false, nil)
+					return pr
+				},
+			},
+			args: args{
+				ctx:     context.Background(),
+				orderBy: model.SortOrderMostCommented,
+				limit:   nil,
+				after: func() *string {
+					v := testSortCursor(repository.OrderMostCommented, 7, 3)
+					return &v
+				}(),
+			},
+			want: &model.PostConnection{
+				Edges: []*model.PostEdge{
+					{
+						Cursor: testSortCursor(repository.OrderMostCommented, 5, 2),
+						Node: &model.Post{
+							ID:              "2",
+							Title:           "title2",
+							Text:            "text2",
+							CommentsAllowed: true,
+							CommentCount:    5,
+							Owner:           &model.User{ID: "2", Username: "user2"},
+							CreatedAt:       time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+							UpdatedAt:       time.Time{},
+						},
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { s := testSortCursor(repository.OrderMostCommented, 5, 2); return &s }(),
+					EndCursor:   func() *string { s := testSortCursor(repository.OrderMostCommented, 5, 2); return &s }(),
+					HasNextPage: false,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "tag is not valid",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					return mocks.NewMockPostRepo(c)
+				},
+			},
+			args: args{
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
+				tag:     func() *string { s := "go lang!"; return &s }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "tag provided",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{
+					DefaultPostsLimit: 10,
+					MaxPostsLimit:     10,
+				},
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPosts(gomock.Any(), repository.PostFilter{Tag: "go-lang"}, repository.OrderOldest, repository.PageArgs{Limit: 10}).Return([]*models.Post{}, false, nil)
+					return pr
+				},
+			},
+			args: args{
+				ctx:     context.Background(),
+				orderBy: model.SortOrderOldest,
+				tag:     func() *string { s := "#Go Lang"; return &s }(),
+			},
+			want: &model.PostConnection{
+				Edges: []*model.PostEdge{},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { s := ""; return &s }(),
+					EndCursor:   func() *string { s := ""; return &s }(),
+					HasNextPage: false,
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -228,12 +370,13 @@ func Test_queryResolver_Posts(t *testing.T) {
 			c := gomock.NewController(t)
 			r := &queryResolver{
 				Resolver: &Resolver{
-					Logger:   sugar,
-					Cfg:      tt.resolverFields.cfg,
-					PostRepo: tt.resolverFields.getPostRepo(c),
+					Logger:      sugar,
+					CursorCodec: testCursorCodec,
+					Cfg:         tt.resolverFields.cfg,
+					PostRepo:    tt.resolverFields.getPostRepo(c),
 				},
 			}
-			got, err := r.Posts(tt.args.ctx, tt.args.limit, tt.args.after)
+			got, err := r.Posts(tt.args.ctx, tt.args.limit, tt.args.after, tt.args.orderBy, tt.args.tag)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Posts() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,86 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
+	"ozon_test_task/pkg/search"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// relevanceOrder is a sort mode of search results.
+const relevanceOrder = "RELEVANCE"
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, query string, typeArg []model.SearchType, first *int32, after *string, authorID *string, from *time.Time, to *time.Time) (*model.SearchConnection, error) {
+	//prepare input data
+	if strings.TrimSpace(query) == "" {
+		return nil, apperrors.New(apperrors.Validation, "query is empty")
+	}
+	limitInt := 0
+	if first == nil {
+		limitInt = r.Cfg.DefaultPostsLimit
+	} else {
+		limitInt = int(*first)
+		if limitInt > r.Cfg.MaxPostsLimit {
+			limitInt = r.Cfg.MaxPostsLimit
+		}
+	}
+	afterPos, err := r.decodeAfter(after, relevanceOrder)
+	if err != nil {
+		r.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
+	}
+
+	searchQuery := repository.SearchQuery{Text: query, From: from, To: to}
+	for _, t := range typeArg {
+		searchQuery.Types = append(searchQuery.Types, repository.SearchType(t))
+	}
+	if authorID != nil {
+		searchQuery.AuthorID, err = strconv.Atoi(*authorID)
+		if err != nil {
+			r.Logger.Debugf("cant convert authorID to int, err: %v", err)
+			return nil, apperrors.New(apperrors.Validation, "authorID is not valid")
+		}
+	}
+
+	//search
+	hits, hasNextPage, err := r.SearchRepo.Search(ctx, searchQuery, repository.PageArgs{Limit: limitInt, After: afterPos})
+	if err != nil {
+		r.Logger.Debugf("cant search in db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	edges := make([]*model.SearchEdge, len(hits))
+	for i, hit := range hits {
+		edges[i] = &model.SearchEdge{
+			Cursor:  r.encodeCursor(relevanceOrder, hit.Rank, hit.SortID()),
+			Snippet: hit.Snippet,
+			Rank:    float64(hit.Rank) / search.RankScale,
+		}
+		if hit.Post != nil {
+			edges[i].Node = newPostModel(hit.Post)
+		} else {
+			edges[i].Node = newCommentModel(hit.Comment)
+		}
+	}
+
+	startCursor := ""
+	endCursor := ""
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
+	return &model.SearchConnection{
+		Edges: edges,
+		PageInfo: &model.PageInfo{
+			StartCursor: &startCursor,
+			EndCursor:   &endCursor,
+			HasNextPage: hasNextPage,
+		},
+	}, nil
+}
@@ -0,0 +1,207 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_queryResolver_Search(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		query    string
+		typeArg  []model.SearchType
+		first    *int32
+		after    *string
+		authorID *string
+		from     *time.Time
+		to       *time.Time
+	}
+	type resolverFields struct {
+		cfg           cfg.Cfg
+		getSearchRepo func(c *gomock.Controller) repository.SearchRepo
+	}
+	from := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.SearchConnection
+		wantErr        bool
+	}{
+		{
+			name: "Empty query",
+			resolverFields: resolverFields{
+				getSearchRepo: func(c *gomock.Controller) repository.SearchRepo {
+					return mocks.NewMockSearchRepo(c)
+				},
+			},
+			args: args{
+				ctx:   context.Background(),
+				query: "  ",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Cursor of another order",
+			resolverFields: resolverFields{
+				getSearchRepo: func(c *gomock.Controller) repository.SearchRepo {
+					return mocks.NewMockSearchRepo(c)
+				},
+			},
+			args: args{
+				ctx:   context.Background(),
+				query: "hello",
+				after: func() *string { v := testCursor(5); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "authorID is not int",
+			resolverFields: resolverFields{
+				getSearchRepo: func(c *gomock.Controller) repository.SearchRepo {
+					return mocks.NewMockSearchRepo(c)
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				query:    "hello",
+				authorID: func() *string { v := "abc"; return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB err",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{DefaultPostsLimit: 10},
+				getSearchRepo: func(c *gomock.Controller) repository.SearchRepo {
+					sr := mocks.NewMockSearchRepo(c)
+					sr.EXPECT().Search(gomock.Any(), repository.SearchQuery{Text: "hello"}, repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
+					return sr
+				},
+			},
+			args: args{
+				ctx:   context.Background(),
+				query: "hello",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxPostsLimit: 2},
+				getSearchRepo: func(c *gomock.Controller) repository.SearchRepo {
+					sr := mocks.NewMockSearchRepo(c)
+					sr.EXPECT().Search(gomock.Any(), repository.SearchQuery{
+						Text:     "hello",
+						Types:    []repository.SearchType{repository.SearchTypePost, repository.SearchTypeComment},
+						AuthorID: 1,
+						From:     &from,
+					}, repository.PageArgs{Limit: 2, After: &repository.Position{Key: 3000000, ID: 9}}).Return([]*models.SearchHit{
+						{
+							Post: &models.Post{
+								ID:        5,
+								Title:     "Hello",
+								Text:      "world",
+								Owner:     models.User{ID: 1, Login: "qwerty"},
+								CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+							},
+							Rank:    2500000,
+							Snippet: "<mark>Hello</mark>",
+						},
+						{
+							Comment: &models.Comment{
+								ID:        7,
+								Owner:     models.User{ID: 1, Login: "qwerty"},
+								PostID:    5,
+								Text:      "hello there",
+								CreatedAt: time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+							},
+							Rank:    500000,
+							Snippet: "<mark>hello</mark> there",
+						},
+					}, true, nil)
+					return sr
+				},
+			},
+			args: args{
+				ctx:      context.Background(),
+				query:    "hello",
+				typeArg:  []model.SearchType{model.SearchTypePost, model.SearchTypeComment},
+				first:    func() *int32 { v := int32(20); return &v }(),
+				after:    func() *string { v := testSortCursor(relevanceOrder, 3000000, 9); return &v }(),
+				authorID: func() *string { v := "1"; return &v }(),
+				from:     &from,
+			},
+			want: &model.SearchConnection{
+				Edges: []*model.SearchEdge{
+					{
+						Cursor: testSortCursor(relevanceOrder, 2500000, 11),
+						Node: &model.Post{
+							ID:        "5",
+							Title:     "Hello",
+							Text:      "world",
+							Owner:     &model.User{ID: "1", Username: "qwerty"},
+							CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+						},
+						Snippet: "<mark>Hello</mark>",
+						Rank:    2.5,
+					},
+					{
+						Cursor: testSortCursor(relevanceOrder, 500000, 14),
+						Node: &model.Comment{
+							ID:        "7",
+							Owner:     &model.User{ID: "1", Username: "qwerty"},
+							Text:      "hello there",
+							CreatedAt: time.Date(2020, 10, 31, 0, 0, 0, 0, time.UTC),
+						},
+						Snippet: "<mark>hello</mark> there",
+						Rank:    0.5,
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { v := testSortCursor(relevanceOrder, 2500000, 11); return &v }(),
+					EndCursor:   func() *string { v := testSortCursor(relevanceOrder, 500000, 14); return &v }(),
+					HasNextPage: true,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			sugar := logger.Sugar()
+			c := gomock.NewController(t)
+			r := &queryResolver{
+				Resolver: &Resolver{
+					Logger:      sugar,
+					CursorCodec: testCursorCodec,
+					Cfg:         tt.resolverFields.cfg,
+					SearchRepo:  tt.resolverFields.getSearchRepo(c),
+				},
+			}
+			got, err := r.Search(tt.args.ctx, tt.args.query, tt.args.typeArg, tt.args.first, tt.args.after, tt.args.authorID, tt.args.from, tt.args.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Search() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
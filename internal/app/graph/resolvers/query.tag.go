@@ -0,0 +1,28 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// Tag is the resolver for the tag field.
+func (r *queryResolver) Tag(ctx context.Context, name string) (*model.Tag, error) {
+	normalized, err := normalizeTag(name)
+	if err != nil {
+		r.Logger.Debugf("cant normalize tag, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "tag is not valid")
+	}
+
+	tag, err := r.TagRepo.GetTag(ctx, normalized)
+	if err != nil {
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, nil
+		}
+		r.Logger.Debugf("cant get tag from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+	return newTagModel(tag), nil
+}
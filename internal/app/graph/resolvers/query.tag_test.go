@@ -0,0 +1,99 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_queryResolver_Tag(t *testing.T) {
+	type resolverFields struct {
+		getTagRepo func(c *gomock.Controller) repository.TagRepo
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		tagName        string
+		want           *model.Tag
+		wantErr        bool
+	}{
+		{
+			name: "Tag is not valid",
+			resolverFields: resolverFields{
+				getTagRepo: func(c *gomock.Controller) repository.TagRepo {
+					return mocks.NewMockTagRepo(c)
+				},
+			},
+			tagName: "go?",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Tag not found",
+			resolverFields: resolverFields{
+				getTagRepo: func(c *gomock.Controller) repository.TagRepo {
+					tr := mocks.NewMockTagRepo(c)
+					tr.EXPECT().GetTag(gomock.Any(), "go").Return(nil, repository.NewErrNotFound())
+					return tr
+				},
+			},
+			tagName: "go",
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getTagRepo: func(c *gomock.Controller) repository.TagRepo {
+					tr := mocks.NewMockTagRepo(c)
+					tr.EXPECT().GetTag(gomock.Any(), "go").Return(nil, fmt.Errorf("db error"))
+					return tr
+				},
+			},
+			tagName: "go",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getTagRepo: func(c *gomock.Controller) repository.TagRepo {
+					tr := mocks.NewMockTagRepo(c)
+					tr.EXPECT().GetTag(gomock.Any(), "go").Return(&models.Tag{Name: "go", PostsCount: 3}, nil)
+					return tr
+				},
+			},
+			tagName: "#Go",
+			want:    &model.Tag{Name: "go", PostCount: 3},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			sugar := logger.Sugar()
+			c := gomock.NewController(t)
+			r := &queryResolver{
+				Resolver: &Resolver{
+					Logger:  sugar,
+					TagRepo: tt.resolverFields.getTagRepo(c),
+				},
+			}
+			got, err := r.Tag(context.Background(), tt.tagName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Tag() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tag() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
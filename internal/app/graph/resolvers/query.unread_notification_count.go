@@ -0,0 +1,24 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// UnreadNotificationCount is the resolver for the unreadNotificationCount field.
+func (r *queryResolver) UnreadNotificationCount(ctx context.Context) (int32, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return 0, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	count, err := r.NotificationRepo.CountUnreadNotifications(ctx, user.ID)
+	if err != nil {
+		r.Logger.Debugf("cant count unread notifications, err: %v", err)
+		return 0, apperrors.NewInternal(err)
+	}
+	return int32(count), nil
+}
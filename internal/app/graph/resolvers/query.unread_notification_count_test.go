@@ -0,0 +1,88 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"testing"
+)
+
+func Test_queryResolver_UnreadNotificationCount(t *testing.T) {
+	type resolverFields struct {
+		getNotificationRepo func(c *gomock.Controller) repository.NotificationRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		ctx            context.Context
+		want           int32
+		wantErr        bool
+	}{
+		{
+			name: "Not authorized",
+			resolverFields: resolverFields{
+				getNotificationRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					return mocks.NewMockNotificationRepo(c)
+				},
+			},
+			ctx:     context.Background(),
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getNotificationRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().CountUnreadNotifications(gomock.Any(), 1).Return(0, fmt.Errorf("db error"))
+					return nr
+				},
+			},
+			ctx:     authCtx(),
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getNotificationRepo: func(c *gomock.Controller) repository.NotificationRepo {
+					nr := mocks.NewMockNotificationRepo(c)
+					nr.EXPECT().CountUnreadNotifications(gomock.Any(), 1).Return(4, nil)
+					return nr
+				},
+			},
+			ctx:     authCtx(),
+			want:    4,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &queryResolver{
+				Resolver: &Resolver{
+					Logger:           logger.Sugar(),
+					NotificationRepo: tt.resolverFields.getNotificationRepo(c),
+				},
+			}
+			got, err := r.UnreadNotificationCount(tt.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnreadNotificationCount() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("UnreadNotificationCount() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
+	userID, err := strconv.Atoi(id)
+	if err != nil {
+		r.Logger.Debugf("cant convert id to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "id is not an int")
+	}
+
+	user, err := r.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, nil
+		}
+		r.Logger.Debugf("cant get user from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+	return newUserModel(user), nil
+}
@@ -0,0 +1,22 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// UserByUsername is the resolver for the userByUsername field.
+func (r *queryResolver) UserByUsername(ctx context.Context, username string) (*model.User, error) {
+	user, err := r.UserRepo.GetUserByLogin(ctx, username)
+	if err != nil {
+		if errors.Is(err, repository.NewErrNotFound()) {
+			return nil, nil
+		}
+		r.Logger.Debugf("cant get user from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+	return newUserModel(user), nil
+}
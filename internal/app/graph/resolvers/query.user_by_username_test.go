@@ -0,0 +1,87 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_queryResolver_UserByUsername(t *testing.T) {
+	type resolverFields struct {
+		getUserRepo func(c *gomock.Controller) repository.UserRepo
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		username       string
+		want           *model.User
+		wantErr        bool
+	}{
+		{
+			name: "User not found",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByLogin(gomock.Any(), "qwerty").Return(nil, repository.NewErrNotFound())
+					return ur
+				},
+			},
+			username: "qwerty",
+			want:     nil,
+			wantErr:  false,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByLogin(gomock.Any(), "qwerty").Return(nil, fmt.Errorf("db error"))
+					return ur
+				},
+			},
+			username: "qwerty",
+			want:     nil,
+			wantErr:  true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByLogin(gomock.Any(), "qwerty").Return(&models.User{ID: 10, Login: "qwerty"}, nil)
+					return ur
+				},
+			},
+			username: "qwerty",
+			want:     &model.User{ID: "10", Username: "qwerty"},
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &queryResolver{
+				Resolver: &Resolver{
+					Logger:   logger.Sugar(),
+					UserRepo: tt.resolverFields.getUserRepo(c),
+				},
+			}
+			got, err := r.UserByUsername(context.Background(), tt.username)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UserByUsername() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UserByUsername() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
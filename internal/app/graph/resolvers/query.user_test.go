@@ -0,0 +1,98 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_queryResolver_User(t *testing.T) {
+	type resolverFields struct {
+		getUserRepo func(c *gomock.Controller) repository.UserRepo
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		id             string
+		want           *model.User
+		wantErr        bool
+	}{
+		{
+			name: "ID is not convertable to int",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					return mocks.NewMockUserRepo(c)
+				},
+			},
+			id:      "abc",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "User not found",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByID(gomock.Any(), 10).Return(nil, repository.NewErrNotFound())
+					return ur
+				},
+			},
+			id:      "10",
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByID(gomock.Any(), 10).Return(nil, fmt.Errorf("db error"))
+					return ur
+				},
+			},
+			id:      "10",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getUserRepo: func(c *gomock.Controller) repository.UserRepo {
+					ur := mocks.NewMockUserRepo(c)
+					ur.EXPECT().GetUserByID(gomock.Any(), 10).Return(&models.User{ID: 10, Login: "qwerty"}, nil)
+					return ur
+				},
+			},
+			id:      "10",
+			want:    &model.User{ID: "10", Username: "qwerty"},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &queryResolver{
+				Resolver: &Resolver{
+					Logger:   logger.Sugar(),
+					UserRepo: tt.resolverFields.getUserRepo(c),
+				},
+			}
+			got, err := r.User(context.Background(), tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("User() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("User() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
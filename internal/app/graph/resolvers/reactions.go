@@ -0,0 +1,65 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+	"strings"
+)
+
+// parseReactionTarget converts react and unreact arguments into a repository target.
+func parseReactionTarget(targetType model.ReactionTargetType, targetID string) (repository.Target, error) {
+	id, err := strconv.Atoi(targetID)
+	if err != nil {
+		return repository.Target{}, apperrors.New(apperrors.Validation, "targetID is not an int")
+	}
+	return repository.Target{Type: repository.TargetType(targetType), ID: id}, nil
+}
+
+// targetNotFound returns an error for a post or a comment which doesn`t exist.
+func targetNotFound(targetType model.ReactionTargetType) error {
+	return apperrors.New(apperrors.NotFound, "%s not found", strings.ToLower(string(targetType)))
+}
+
+// newReactionPayload returns updated reactions of a target with a reaction of the current user, empty kind means no reaction.
+func newReactionPayload(targetType model.ReactionTargetType, targetID string, reactions models.Reactions, kind models.ReactionKind) *model.ReactionPayload {
+	payload := &model.ReactionPayload{
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reactions:  newReactionsModel(reactions),
+		Score:      int32(reactions.Score()),
+	}
+	if kind != "" {
+		viewerReaction := model.ReactionKind(kind)
+		payload.ViewerReaction = &viewerReaction
+	}
+	return payload
+}
+
+// viewerReaction returns a reaction of the current user to a target, nil if not authorized or the user didn`t react.
+func (r *Resolver) viewerReaction(ctx context.Context, targetType repository.TargetType, targetID string) (*model.ReactionKind, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		return nil, nil
+	}
+	id, err := strconv.Atoi(targetID)
+	if err != nil {
+		r.Logger.Debugf("cant convert targetID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "targetID is not an int")
+	}
+
+	kind, err := r.ReactionRepo.GetReaction(ctx, repository.Target{Type: targetType, ID: id}, user.ID)
+	if err != nil {
+		r.Logger.Debugf("cant get reaction from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+	if kind == "" {
+		return nil, nil
+	}
+	viewerReaction := model.ReactionKind(kind)
+	return &viewerReaction, nil
+}
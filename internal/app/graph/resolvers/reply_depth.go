@@ -0,0 +1,30 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// replyParent returns a comment which a reply to parent is attached to, limited by Cfg.MaxReplyDepth.
+// Replies beyond the limit are attached to the deepest allowed ancestor of parent if Cfg.ReparentDeepReplies is set, otherwise rejected.
+func (r *Resolver) replyParent(ctx context.Context, parent *models.Comment) (*models.Comment, error) {
+	maxDepth := r.Cfg.MaxReplyDepth
+	if maxDepth == 0 || parent.Depth < maxDepth {
+		return parent, nil
+	}
+	if !r.Cfg.ReparentDeepReplies {
+		r.Logger.Debugf("reply depth limit reached, parent depth is \"%v\", max depth is \"%v\"", parent.Depth, maxDepth)
+		return nil, apperrors.New(apperrors.Validation, "replies can be nested at most %d levels deep", maxDepth)
+	}
+
+	for parent.Depth >= maxDepth {
+		ancestor, err := r.CommentRepo.GetCommentByID(ctx, parent.ParentID)
+		if err != nil {
+			r.Logger.Debugf("cant get ancestor comment from db, err: %v", err)
+			return nil, apperrors.NewInternal(err)
+		}
+		parent = ancestor
+	}
+	return parent, nil
+}
@@ -0,0 +1,99 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// maxReportDetailsLength is a maximum length of report details in characters.
+const maxReportDetailsLength = 1000
+
+// addReport saves a report of the current user about content of another user.
+// Returns false if the user has already reported the content.
+func (r *Resolver) addReport(ctx context.Context, user *models.User, targetType models.ReportTarget, targetID, authorID int, reason model.ReportReason, details *string) (bool, error) {
+	if user.ID == authorID {
+		r.Logger.Debugf("user \"%v\" reports own content", user.ID)
+		return false, apperrors.New(apperrors.Forbidden, "cant report your own content")
+	}
+	report := &models.Report{
+		ReporterID: user.ID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		AuthorID:   authorID,
+		Reason:     models.ReportReason(reason),
+		CreatedAt:  time.Now(),
+	}
+	if details != nil {
+		if utf8.RuneCountInString(*details) > maxReportDetailsLength {
+			r.Logger.Debugf("report details too long, len is \"%v\"", utf8.RuneCountInString(*details))
+			return false, apperrors.New(apperrors.Validation, "details too long, max length: %d", maxReportDetailsLength)
+		}
+		report.Details = *details
+	}
+
+	added, err := r.ReportRepo.AddReport(ctx, report)
+	if err != nil {
+		r.Logger.Debugf("cant add report to db, err: %v", err)
+		return false, apperrors.NewInternal(err)
+	}
+	return added, nil
+}
+
+// moderatorFromContext returns the current user if it is a moderator.
+func (r *Resolver) moderatorFromContext(ctx context.Context) (*models.User, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+	if !r.isModerator(user) {
+		r.Logger.Debugf("user \"%v\" is not a moderator", user.Login)
+		return nil, apperrors.New(apperrors.Forbidden, "moderators only")
+	}
+	return user, nil
+}
+
+// newReportCaseModel converts a report case into a GraphQL model with the reported content.
+// Content is fetched by its ID, deleted content is left null.
+func (r *Resolver) newReportCaseModel(ctx context.Context, rc *models.ReportCase) (*model.ReportCase, error) {
+	m := &model.ReportCase{
+		ID:             strconv.Itoa(rc.ID),
+		TargetType:     model.ReportTargetType(rc.TargetType),
+		ReportCount:    int32(rc.ReportCount),
+		Details:        rc.Details,
+		LastReportedAt: rc.LastReportedAt,
+	}
+	for _, reason := range models.ReportReasons {
+		if count := rc.Reasons[reason]; count > 0 {
+			m.Reasons = append(m.Reasons, &model.ReportReasonCount{Reason: model.ReportReason(reason), Count: int32(count)})
+		}
+	}
+
+	switch rc.TargetType {
+	case models.ReportPost:
+		post, err := r.PostRepo.GetPostByID(ctx, rc.TargetID)
+		if err != nil && !errors.Is(err, repository.NewErrNotFound()) {
+			return nil, err
+		}
+		if post != nil {
+			m.Post = newPostModel(post)
+		}
+	case models.ReportComment:
+		comment, err := r.CommentRepo.GetCommentByID(ctx, rc.TargetID)
+		if err != nil && !errors.Is(err, repository.NewErrNotFound()) {
+			return nil, err
+		}
+		if comment != nil {
+			m.Comment = newCommentModel(comment)
+		}
+	}
+	return m, nil
+}
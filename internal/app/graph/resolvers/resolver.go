@@ -6,6 +6,11 @@ import (
 	"ozon_test_task/internal/app/graph/model"
 	"ozon_test_task/internal/app/graph/repository"
 	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/cursor"
+	"ozon_test_task/pkg/markdown"
+	"ozon_test_task/pkg/moderation"
+	"ozon_test_task/pkg/pubsub"
 )
 
 // This file will not be regenerated automatically.
@@ -13,11 +18,29 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	UserRepo     repository.UserRepo
-	PostRepo     repository.PostRepo
-	CommentRepo  repository.CommentRepo
-	Cfg          cfg.Cfg
-	JWTManager   middlewares.JWTManager
-	CommentAdded chan *model.Comment
-	Logger       *zap.SugaredLogger
+	UserRepo         repository.UserRepo
+	PostRepo         repository.PostRepo
+	CommentRepo      repository.CommentRepo
+	FollowRepo       repository.FollowRepo
+	BookmarkRepo     repository.BookmarkRepo
+	TagRepo          repository.TagRepo
+	SearchRepo       repository.SearchRepo
+	ReactionRepo     repository.ReactionRepo
+	NotificationRepo repository.NotificationRepo
+	ModerationRepo   repository.ModerationRepo
+	ReportRepo       repository.ReportRepo
+	BlockRepo        repository.BlockRepo
+	IdempotencyRepo  repository.IdempotencyRepo
+	Cfg              cfg.Cfg
+	JWTManager       middlewares.JWTManager
+	CursorCodec      *cursor.Codec
+	CommentAdded     chan *model.Comment
+	// NotificationHub delivers new notifications to subscribers by recipient ID.
+	// It is fed by the storage with notifications added by all replicas.
+	NotificationHub *pubsub.Hub[int, *models.Notification]
+	// TextRenderer renders post and comment texts into sanitized HTML.
+	TextRenderer *markdown.Renderer
+	// Moderator checks new posts and comments, nil disables moderation.
+	Moderator *moderation.Pipeline
+	Logger    *zap.SugaredLogger
 }
@@ -0,0 +1,19 @@
+package resolvers
+
+import (
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// newValidationErrorResult converts a Validation error into the ValidationError result,
+// fields are taken from the "fields" extension set by validationError.
+func newValidationErrorResult(err *apperrors.Error) *model.ValidationError {
+	result := &model.ValidationError{Message: err.Message, Fields: []*model.FieldError{}}
+	fields, _ := err.Extensions["fields"].([]map[string]any)
+	for _, f := range fields {
+		path, _ := f["path"].(string)
+		message, _ := f["message"].(string)
+		result.Fields = append(result.Fields, &model.FieldError{Path: path, Message: message})
+	}
+	return result
+}
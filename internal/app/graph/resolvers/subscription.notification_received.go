@@ -0,0 +1,32 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+)
+
+// NotificationReceived is the resolver for the notificationReceived field.
+func (r *subscriptionResolver) NotificationReceived(ctx context.Context) (<-chan *model.Notification, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		r.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+
+	notifications := r.NotificationHub.Subscribe(ctx, user.ID)
+	ch := make(chan *model.Notification)
+	go func() {
+		defer close(ch)
+		for notification := range notifications {
+			select {
+			case ch <- newNotificationModel(notification):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
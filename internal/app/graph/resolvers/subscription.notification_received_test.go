@@ -0,0 +1,62 @@
+package resolvers
+
+import (
+	"context"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/pubsub"
+	"testing"
+	"time"
+)
+
+func Test_subscriptionResolver_NotificationReceived(t *testing.T) {
+	t.Run("Not authorized", func(t *testing.T) {
+		r := &subscriptionResolver{
+			Resolver: &Resolver{
+				Logger:          zaptest.NewLogger(t).Sugar(),
+				NotificationHub: pubsub.NewHub[int, *models.Notification](1),
+			},
+		}
+		if _, err := r.NotificationReceived(context.Background()); err == nil {
+			t.Errorf("NotificationReceived() error = nil, wantErr true")
+		}
+	})
+
+	t.Run("Ok", func(t *testing.T) {
+		hub := pubsub.NewHub[int, *models.Notification](1)
+		r := &subscriptionResolver{
+			Resolver: &Resolver{
+				Logger:          zaptest.NewLogger(t).Sugar(),
+				NotificationHub: hub,
+			},
+		}
+		user := &models.User{ID: 1, Login: "qwerty"}
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), middlewares.UserContextKey, user))
+		ch, err := r.NotificationReceived(ctx)
+		if err != nil {
+			t.Fatalf("NotificationReceived() error = %v", err)
+		}
+
+		hub.Publish(2, &models.Notification{ID: 6, UserID: 2, Type: models.NotificationReply})
+		hub.Publish(1, &models.Notification{ID: 7, UserID: 1, Type: models.NotificationReply})
+		select {
+		case got := <-ch:
+			if got.ID != "7" {
+				t.Errorf("NotificationReceived() got notification %v, want 7", got.ID)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("NotificationReceived() got no notification")
+		}
+
+		cancel()
+		select {
+		case _, ok := <-ch:
+			if ok {
+				t.Errorf("NotificationReceived() channel is not closed after unsubscribe")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("NotificationReceived() channel is not closed after unsubscribe")
+		}
+	})
+}
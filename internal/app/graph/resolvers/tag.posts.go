@@ -0,0 +1,12 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+)
+
+// Posts is the resolver for the posts field.
+func (t *tagResolver) Posts(ctx context.Context, obj *model.Tag, limit *int32, after *string, orderBy model.SortOrder) (*model.PostConnection, error) {
+	return t.postConnection(ctx, repository.PostFilter{Tag: obj.Name}, limit, after, orderBy)
+}
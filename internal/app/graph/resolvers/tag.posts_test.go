@@ -0,0 +1,107 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_tagResolver_Posts(t *testing.T) {
+	type resolverFields struct {
+		getPostRepo func(c *gomock.Controller) repository.PostRepo
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		orderBy        model.SortOrder
+		want           *model.PostConnection
+		wantErr        bool
+	}{
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPosts(gomock.Any(), repository.PostFilter{Tag: "go"}, repository.OrderOldest, repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
+					return pr
+				},
+			},
+			orderBy: model.SortOrderOldest,
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPosts(gomock.Any(), repository.PostFilter{Tag: "go"}, repository.OrderNewest, repository.PageArgs{Limit: 10}).Return([]*models.Post{
+						{
+							ID:        3,
+							Title:     "title",
+							Text:      "text",
+							Owner:     models.User{ID: 1, Login: "user1"},
+							CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+							Tags:      []string{"go"},
+						},
+					}, true, nil)
+					return pr
+				},
+			},
+			orderBy: model.SortOrderNewest,
+			want: &model.PostConnection{
+				Edges: []*model.PostEdge{
+					{
+						Cursor: testSortCursor(repository.OrderNewest, 3, 3),
+						Node: &model.Post{
+							ID:        "3",
+							Title:     "title",
+							Text:      "text",
+							Owner:     &model.User{ID: "1", Username: "user1"},
+							CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+							Tags:      []string{"go"},
+						},
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { v := testSortCursor(repository.OrderNewest, 3, 3); return &v }(),
+					EndCursor:   func() *string { v := testSortCursor(repository.OrderNewest, 3, 3); return &v }(),
+					HasNextPage: true,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			sugar := logger.Sugar()
+			c := gomock.NewController(t)
+			r := &tagResolver{
+				Resolver: &Resolver{
+					Logger:      sugar,
+					CursorCodec: testCursorCodec,
+					Cfg:         cfg.Cfg{DefaultPostsLimit: 10},
+					PostRepo:    tt.resolverFields.getPostRepo(c),
+				},
+			}
+			got, err := r.Posts(context.Background(), &model.Tag{Name: "go"}, nil, nil, tt.orderBy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Posts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Posts() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package resolvers
+
+import (
+	"ozon_test_task/pkg/apperrors"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxTagLength is a maximum length of a tag name in runes.
+const maxTagLength = 32
+
+// normalizeTag returns a normalized tag name: lower-cased, without leading "#" and with spaces replaced by "-".
+// Only letters, digits, "-" and "_" are allowed.
+func normalizeTag(tag string) (string, error) {
+	name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	name = strings.Join(strings.Fields(name), "-")
+	if name == "" {
+		return "", apperrors.New(apperrors.Validation, "tag is empty")
+	}
+	if utf8.RuneCountInString(name) > maxTagLength {
+		return "", apperrors.New(apperrors.Validation, "tag %q is longer than %d characters", tag, maxTagLength)
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return "", apperrors.New(apperrors.Validation, "tag %q contains invalid character %q", tag, r)
+		}
+	}
+	return name, nil
+}
+
+// normalizeTags normalizes tags, removes duplicates and sorts them.
+// Returns an error if any tag is invalid or there are more than maxTags unique tags.
+func normalizeTags(tags []string, maxTags int) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		name, err := normalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if len(names) > maxTags {
+		return nil, apperrors.New(apperrors.Validation, "post can have at most %d tags", maxTags)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,35 @@
+package resolvers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_normalizeTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "Empty", tags: nil, want: nil},
+		{name: "Normalized and sorted", tags: []string{" #Go ", "Graph  QL", "тест_1"}, want: []string{"go", "graph-ql", "тест_1"}},
+		{name: "Duplicates", tags: []string{"go", "GO", "#go"}, want: []string{"go"}},
+		{name: "Too many", tags: []string{"a", "b", "c", "d"}, wantErr: true},
+		{name: "Empty tag", tags: []string{"#"}, wantErr: true},
+		{name: "Invalid character", tags: []string{"go,lang"}, wantErr: true},
+		{name: "Too long", tags: []string{"abcdefghijklmnopqrstuvwxyz1234567"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTags(tt.tags, 3)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("normalizeTags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeTags() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
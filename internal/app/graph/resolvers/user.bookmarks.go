@@ -0,0 +1,75 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// bookmarkOrder is a sort mode of bookmarks, recently bookmarked first.
+const bookmarkOrder = "BOOKMARKED_AT"
+
+// Bookmarks is the resolver for the bookmarks field.
+func (u *userResolver) Bookmarks(ctx context.Context, obj *model.User, limit *int32, after *string) (*model.PostConnection, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		u.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+	if strconv.Itoa(user.ID) != obj.ID {
+		u.Logger.Debugf("cant get bookmarks of another user, userID is \"%v\", but requested \"%v\"", user.ID, obj.ID)
+		return nil, apperrors.New(apperrors.Forbidden, "bookmarks are private")
+	}
+
+	//data prepare
+	limitInt := 0
+	if limit == nil {
+		limitInt = u.Cfg.DefaultPostsLimit
+	} else {
+		limitInt = int(*limit)
+		if limitInt > u.Cfg.MaxPostsLimit {
+			limitInt = u.Cfg.MaxPostsLimit
+		}
+	}
+	afterPos, err := u.decodeAfter(after, bookmarkOrder)
+	if err != nil {
+		u.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
+	}
+
+	//get data
+	bookmarks, hasNextPage, err := u.BookmarkRepo.GetBookmarks(ctx, user.ID, repository.PageArgs{Limit: limitInt, After: afterPos})
+	if err != nil {
+		u.Logger.Debugf("cant get bookmarks from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	//prepare answer
+	edges := make([]*model.PostEdge, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		edges[i] = &model.PostEdge{
+			Cursor: u.encodeCursor(bookmarkOrder, bookmark.CreatedAt.UnixMicro(), bookmark.Post.ID),
+			Node:   newPostModel(&bookmark.Post),
+		}
+	}
+
+	var startCursor string
+	var endCursor string
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
+	return &model.PostConnection{
+		Edges: edges,
+		PageInfo: &model.PageInfo{
+			StartCursor: &startCursor,
+			EndCursor:   &endCursor,
+			HasNextPage: hasNextPage,
+		},
+	}, nil
+}
@@ -0,0 +1,140 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/cursor"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_userResolver_Bookmarks(t *testing.T) {
+	type args struct {
+		ctx   context.Context
+		obj   *model.User
+		limit *int32
+		after *string
+	}
+	type resolverFields struct {
+		getBookmarkRepo func(c *gomock.Controller) repository.BookmarkRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noBookmarkRepo := func(c *gomock.Controller) repository.BookmarkRepo { return mocks.NewMockBookmarkRepo(c) }
+	bookmarkedAt := time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC)
+	bookmarkCursor := func(key int64, id int) string {
+		return testCursorCodec.Encode(cursor.Cursor{Order: bookmarkOrder, Key: key, ID: id})
+	}
+	post := models.Post{ID: 5, Title: "Title", Owner: models.User{ID: 2, Login: "bob"}}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.PostConnection
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getBookmarkRepo: noBookmarkRepo},
+			args:           args{ctx: context.Background(), obj: &model.User{ID: "1"}},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Another user",
+			resolverFields: resolverFields{getBookmarkRepo: noBookmarkRepo},
+			args:           args{ctx: authCtx(), obj: &model.User{ID: "2"}},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Cursor of another order",
+			resolverFields: resolverFields{getBookmarkRepo: noBookmarkRepo},
+			args: args{
+				ctx:   authCtx(),
+				obj:   &model.User{ID: "1"},
+				after: func() *string { v := testCursor(5); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getBookmarkRepo: func(c *gomock.Controller) repository.BookmarkRepo {
+					br := mocks.NewMockBookmarkRepo(c)
+					br.EXPECT().GetBookmarks(gomock.Any(), 1, repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
+					return br
+				},
+			},
+			args:    args{ctx: authCtx(), obj: &model.User{ID: "1"}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getBookmarkRepo: func(c *gomock.Controller) repository.BookmarkRepo {
+					br := mocks.NewMockBookmarkRepo(c)
+					br.EXPECT().GetBookmarks(gomock.Any(), 1, repository.PageArgs{Limit: 20, After: &repository.Position{Key: 100, ID: 9}}).Return([]*models.Bookmark{
+						{Post: post, CreatedAt: bookmarkedAt},
+					}, true, nil)
+					return br
+				},
+			},
+			args: args{
+				ctx:   authCtx(),
+				obj:   &model.User{ID: "1"},
+				limit: func() *int32 { v := int32(50); return &v }(),
+				after: func() *string { v := bookmarkCursor(100, 9); return &v }(),
+			},
+			want: &model.PostConnection{
+				Edges: []*model.PostEdge{
+					{
+						Cursor: bookmarkCursor(bookmarkedAt.UnixMicro(), 5),
+						Node:   newPostModel(&post),
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { v := bookmarkCursor(bookmarkedAt.UnixMicro(), 5); return &v }(),
+					EndCursor:   func() *string { v := bookmarkCursor(bookmarkedAt.UnixMicro(), 5); return &v }(),
+					HasNextPage: true,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &userResolver{
+				Resolver: &Resolver{
+					Logger:       logger.Sugar(),
+					CursorCodec:  testCursorCodec,
+					Cfg:          cfg.Cfg{DefaultPostsLimit: 10, MaxPostsLimit: 20},
+					BookmarkRepo: tt.resolverFields.getBookmarkRepo(c),
+				},
+			}
+			got, err := r.Bookmarks(tt.args.ctx, tt.args.obj, tt.args.limit, tt.args.after)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Bookmarks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bookmarks() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
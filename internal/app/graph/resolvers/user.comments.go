@@ -0,0 +1,66 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// Comments is the resolver for the comments field.
+func (u *userResolver) Comments(ctx context.Context, obj *model.User, limit *int32, after *string) (*model.CommentConnection, error) {
+	//data prepare
+	id, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		u.Logger.Debugf("cant convert userID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "userID is not an int")
+	}
+	limitInt := 0
+	if limit == nil {
+		limitInt = u.Cfg.DefaultCommentsLimit
+	} else {
+		limitInt = int(*limit)
+		if limitInt > u.Cfg.MaxCommentsLimit {
+			limitInt = u.Cfg.MaxCommentsLimit
+		}
+	}
+	order := string(repository.OrderNewest)
+	afterPos, err := u.decodeAfter(after, order)
+	if err != nil {
+		u.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
+	}
+
+	//get data
+	comments, hasNextPage, err := u.CommentRepo.GetCommentsByOwnerID(ctx, id, repository.PageArgs{Limit: limitInt, After: afterPos})
+	if err != nil {
+		u.Logger.Debugf("cant get user comments from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	//prepare answer
+	edges := make([]*model.CommentEdge, len(comments))
+	for i, comment := range comments {
+		edges[i] = &model.CommentEdge{
+			Cursor: u.encodeCursor(order, int64(comment.ID), comment.ID),
+			Node:   newCommentModel(comment),
+		}
+	}
+
+	var startCursor string
+	var endCursor string
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
+	return &model.CommentConnection{
+		Edges: edges,
+		PageInfo: &model.PageInfo{
+			StartCursor: &startCursor,
+			EndCursor:   &endCursor,
+			HasNextPage: hasNextPage,
+		},
+	}, nil
+}
@@ -0,0 +1,140 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_userResolver_Comments(t *testing.T) {
+	type args struct {
+		obj   *model.User
+		limit *int32
+		after *string
+	}
+	type resolverFields struct {
+		cfg            cfg.Cfg
+		getCommentRepo func(c *gomock.Controller) repository.CommentRepo
+	}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.CommentConnection
+		wantErr        bool
+	}{
+		{
+			name: "userID is not int",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+			},
+			args:    args{obj: &model.User{ID: "abc"}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Cursor of another order",
+			resolverFields: resolverFields{
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					return mocks.NewMockCommentRepo(c)
+				},
+			},
+			args: args{
+				obj:   &model.User{ID: "1"},
+				after: func() *string { v := testCursor(5); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{DefaultCommentsLimit: 10},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentsByOwnerID(gomock.Any(), 1, repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
+					return cr
+				},
+			},
+			args:    args{obj: &model.User{ID: "1"}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				cfg: cfg.Cfg{MaxCommentsLimit: 5},
+				getCommentRepo: func(c *gomock.Controller) repository.CommentRepo {
+					cr := mocks.NewMockCommentRepo(c)
+					cr.EXPECT().GetCommentsByOwnerID(gomock.Any(), 1, repository.PageArgs{Limit: 5, After: &repository.Position{Key: 9, ID: 9}}).Return([]*models.Comment{
+						{
+							ID:        7,
+							Owner:     models.User{ID: 1, Login: "qwerty"},
+							PostID:    5,
+							Text:      "Hello",
+							CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+						},
+					}, true, nil)
+					return cr
+				},
+			},
+			args: args{
+				obj:   &model.User{ID: "1"},
+				limit: func() *int32 { v := int32(20); return &v }(),
+				after: func() *string { v := testSortCursor(repository.OrderNewest, 9, 9); return &v }(),
+			},
+			want: &model.CommentConnection{
+				Edges: []*model.CommentEdge{
+					{
+						Cursor: testSortCursor(repository.OrderNewest, 7, 7),
+						Node: &model.Comment{
+							ID:        "7",
+							Owner:     &model.User{ID: "1", Username: "qwerty"},
+							Text:      "Hello",
+							CreatedAt: time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC),
+						},
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { v := testSortCursor(repository.OrderNewest, 7, 7); return &v }(),
+					EndCursor:   func() *string { v := testSortCursor(repository.OrderNewest, 7, 7); return &v }(),
+					HasNextPage: true,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &userResolver{
+				Resolver: &Resolver{
+					Logger:      logger.Sugar(),
+					CursorCodec: testCursorCodec,
+					Cfg:         tt.resolverFields.cfg,
+					CommentRepo: tt.resolverFields.getCommentRepo(c),
+				},
+			}
+			got, err := r.Comments(context.Background(), tt.args.obj, tt.args.limit, tt.args.after)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Comments() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Comments() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
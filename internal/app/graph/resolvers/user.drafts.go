@@ -0,0 +1,51 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// Drafts is the resolver for the drafts field.
+func (u *userResolver) Drafts(ctx context.Context, obj *model.User, limit *int32, after *string) (*model.PostConnection, error) {
+	user, ok := ctx.Value(middlewares.UserContextKey).(*models.User)
+	if !ok {
+		u.Logger.Debugf("cant get user from ctx")
+		return nil, apperrors.New(apperrors.Unauthenticated, "Not authorized")
+	}
+	if strconv.Itoa(user.ID) != obj.ID {
+		u.Logger.Debugf("cant get drafts of another user, userID is \"%v\", but requested \"%v\"", user.ID, obj.ID)
+		return nil, apperrors.New(apperrors.Forbidden, "drafts are private")
+	}
+
+	//prepare input data
+	limitInt := 0
+	if limit == nil {
+		limitInt = u.Cfg.DefaultPostsLimit
+	} else {
+		limitInt = int(*limit)
+		if limitInt > u.Cfg.MaxPostsLimit {
+			limitInt = u.Cfg.MaxPostsLimit
+		}
+	}
+	order := repository.OrderNewest
+	afterPos, err := u.decodeAfter(after, string(order))
+	if err != nil {
+		u.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
+	}
+
+	//get posts
+	filter := repository.PostFilter{OwnerID: user.ID, Unpublished: true}
+	posts, hasNextPage, err := u.PostRepo.GetPosts(ctx, filter, order, repository.PageArgs{Limit: limitInt, After: afterPos})
+	if err != nil {
+		u.Logger.Debugf("cant get drafts from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	return u.newPostConnection(order, posts, hasNextPage), nil
+}
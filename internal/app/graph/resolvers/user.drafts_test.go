@@ -0,0 +1,133 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/middlewares"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+)
+
+func Test_userResolver_Drafts(t *testing.T) {
+	type args struct {
+		ctx   context.Context
+		obj   *model.User
+		limit *int32
+		after *string
+	}
+	type resolverFields struct {
+		getPostRepo func(c *gomock.Controller) repository.PostRepo
+	}
+	authCtx := func() context.Context {
+		user := &models.User{ID: 1, Login: "qwerty"}
+		return context.WithValue(context.Background(), middlewares.UserContextKey, user)
+	}
+	noPostRepo := func(c *gomock.Controller) repository.PostRepo { return mocks.NewMockPostRepo(c) }
+	filter := repository.PostFilter{OwnerID: 1, Unpublished: true}
+	draft := &models.Post{ID: 5, Title: "Title", Owner: models.User{ID: 1, Login: "qwerty"}, Status: models.PostDraft}
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.PostConnection
+		wantErr        bool
+	}{
+		{
+			name:           "Not authorized",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: context.Background(), obj: &model.User{ID: "1"}},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Another user",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args:           args{ctx: authCtx(), obj: &model.User{ID: "2"}},
+			want:           nil,
+			wantErr:        true,
+		},
+		{
+			name:           "Cursor of another order",
+			resolverFields: resolverFields{getPostRepo: noPostRepo},
+			args: args{
+				ctx:   authCtx(),
+				obj:   &model.User{ID: "1"},
+				after: func() *string { v := testSortCursor(repository.OrderTop, 3, 5); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPosts(gomock.Any(), filter, repository.OrderNewest, repository.PageArgs{Limit: 10}).Return(nil, false, fmt.Errorf("db error"))
+					return pr
+				},
+			},
+			args:    args{ctx: authCtx(), obj: &model.User{ID: "1"}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getPostRepo: func(c *gomock.Controller) repository.PostRepo {
+					pr := mocks.NewMockPostRepo(c)
+					pr.EXPECT().GetPosts(gomock.Any(), filter, repository.OrderNewest, repository.PageArgs{Limit: 20, After: &repository.Position{Key: 9, ID: 9}}).Return([]*models.Post{draft}, true, nil)
+					return pr
+				},
+			},
+			args: args{
+				ctx:   authCtx(),
+				obj:   &model.User{ID: "1"},
+				limit: func() *int32 { v := int32(50); return &v }(),
+				after: func() *string { v := testSortCursor(repository.OrderNewest, 9, 9); return &v }(),
+			},
+			want: &model.PostConnection{
+				Edges: []*model.PostEdge{
+					{
+						Cursor: testSortCursor(repository.OrderNewest, 5, 5),
+						Node:   newPostModel(draft),
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { v := testSortCursor(repository.OrderNewest, 5, 5); return &v }(),
+					EndCursor:   func() *string { v := testSortCursor(repository.OrderNewest, 5, 5); return &v }(),
+					HasNextPage: true,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &userResolver{
+				Resolver: &Resolver{
+					Logger:      logger.Sugar(),
+					CursorCodec: testCursorCodec,
+					Cfg:         cfg.Cfg{DefaultPostsLimit: 10, MaxPostsLimit: 20},
+					PostRepo:    tt.resolverFields.getPostRepo(c),
+				},
+			}
+			got, err := r.Drafts(tt.args.ctx, tt.args.obj, tt.args.limit, tt.args.after)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Drafts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Drafts() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
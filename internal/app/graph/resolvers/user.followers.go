@@ -0,0 +1,78 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// followOrder is a sort mode of followers and following lists, recently followed first.
+const followOrder = "FOLLOWED_AT"
+
+// getFollowsFunc is FollowRepo.GetFollowers or FollowRepo.GetFollowing.
+type getFollowsFunc func(ctx context.Context, userID int, page repository.PageArgs) ([]*models.Follow, bool, error)
+
+// Followers is the resolver for the followers field.
+func (u *userResolver) Followers(ctx context.Context, obj *model.User, limit *int32, after *string) (*model.UserConnection, error) {
+	return u.followConnection(ctx, obj, limit, after, u.FollowRepo.GetFollowers)
+}
+
+// followConnection returns a page of user`s followers or followees got by getFollows.
+func (u *userResolver) followConnection(ctx context.Context, obj *model.User, limit *int32, after *string, getFollows getFollowsFunc) (*model.UserConnection, error) {
+	//data prepare
+	id, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		u.Logger.Debugf("cant convert userID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "userID is not an int")
+	}
+	limitInt := 0
+	if limit == nil {
+		limitInt = u.Cfg.DefaultUsersLimit
+	} else {
+		limitInt = int(*limit)
+		if limitInt > u.Cfg.MaxUsersLimit {
+			limitInt = u.Cfg.MaxUsersLimit
+		}
+	}
+	afterPos, err := u.decodeAfter(after, followOrder)
+	if err != nil {
+		u.Logger.Debugf("cant decode after cursor, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "after is not a valid cursor")
+	}
+
+	//get data
+	follows, hasNextPage, err := getFollows(ctx, id, repository.PageArgs{Limit: limitInt, After: afterPos})
+	if err != nil {
+		u.Logger.Debugf("cant get follows from db, err: %v", err)
+		return nil, apperrors.NewInternal(err)
+	}
+
+	//prepare answer
+	edges := make([]*model.UserEdge, len(follows))
+	for i, follow := range follows {
+		edges[i] = &model.UserEdge{
+			Cursor:     u.encodeCursor(followOrder, follow.CreatedAt.UnixMicro(), follow.User.ID),
+			Node:       newUserModel(&follow.User),
+			FollowedAt: follow.CreatedAt,
+		}
+	}
+
+	var startCursor string
+	var endCursor string
+	if len(edges) > 0 {
+		startCursor = edges[0].Cursor
+		endCursor = edges[len(edges)-1].Cursor
+	}
+
+	return &model.UserConnection{
+		Edges: edges,
+		PageInfo: &model.PageInfo{
+			StartCursor: &startCursor,
+			EndCursor:   &endCursor,
+			HasNextPage: hasNextPage,
+		},
+	}, nil
+}
@@ -0,0 +1,128 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap/zaptest"
+	"ozon_test_task/cfg"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/graph/repository/mocks"
+	"ozon_test_task/internal/app/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_userResolver_Followers(t *testing.T) {
+	type args struct {
+		obj   *model.User
+		limit *int32
+		after *string
+	}
+	type resolverFields struct {
+		getFollowRepo func(c *gomock.Controller) repository.FollowRepo
+	}
+	followedAt := time.Date(2020, 10, 30, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		resolverFields resolverFields
+		args           args
+		want           *model.UserConnection
+		wantErr        bool
+	}{
+		{
+			name: "userID is not int",
+			resolverFields: resolverFields{
+				getFollowRepo: func(c *gomock.Controller) repository.FollowRepo {
+					return mocks.NewMockFollowRepo(c)
+				},
+			},
+			args:    args{obj: &model.User{ID: "abc"}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Cursor of another order",
+			resolverFields: resolverFields{
+				getFollowRepo: func(c *gomock.Controller) repository.FollowRepo {
+					return mocks.NewMockFollowRepo(c)
+				},
+			},
+			args: args{
+				obj:   &model.User{ID: "1"},
+				after: func() *string { v := testCursor(5); return &v }(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "DB error",
+			resolverFields: resolverFields{
+				getFollowRepo: func(c *gomock.Controller) repository.FollowRepo {
+					fr := mocks.NewMockFollowRepo(c)
+					fr.EXPECT().GetFollowers(gomock.Any(), 1, repository.PageArgs{Limit: 20}).Return(nil, false, fmt.Errorf("db error"))
+					return fr
+				},
+			},
+			args:    args{obj: &model.User{ID: "1"}},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Ok",
+			resolverFields: resolverFields{
+				getFollowRepo: func(c *gomock.Controller) repository.FollowRepo {
+					fr := mocks.NewMockFollowRepo(c)
+					fr.EXPECT().GetFollowers(gomock.Any(), 1, repository.PageArgs{Limit: 50, After: &repository.Position{Key: 100, ID: 3}}).Return([]*models.Follow{
+						{User: models.User{ID: 2, Login: "user2"}, CreatedAt: followedAt},
+					}, false, nil)
+					return fr
+				},
+			},
+			args: args{
+				obj:   &model.User{ID: "1"},
+				limit: func() *int32 { v := int32(500); return &v }(),
+				after: func() *string { v := testSortCursor(followOrder, 100, 3); return &v }(),
+			},
+			want: &model.UserConnection{
+				Edges: []*model.UserEdge{
+					{
+						Cursor:     testSortCursor(followOrder, followedAt.UnixMicro(), 2),
+						Node:       &model.User{ID: "2", Username: "user2"},
+						FollowedAt: followedAt,
+					},
+				},
+				PageInfo: &model.PageInfo{
+					StartCursor: func() *string { v := testSortCursor(followOrder, followedAt.UnixMicro(), 2); return &v }(),
+					EndCursor:   func() *string { v := testSortCursor(followOrder, followedAt.UnixMicro(), 2); return &v }(),
+					HasNextPage: false,
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := zaptest.NewLogger(t)
+			c := gomock.NewController(t)
+			r := &userResolver{
+				Resolver: &Resolver{
+					Logger:      logger.Sugar(),
+					CursorCodec: testCursorCodec,
+					Cfg:         cfg.Cfg{DefaultUsersLimit: 20, MaxUsersLimit: 50},
+					FollowRepo:  tt.resolverFields.getFollowRepo(c),
+				},
+			}
+			got, err := r.Followers(context.Background(), tt.args.obj, tt.args.limit, tt.args.after)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Followers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Followers() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+)
+
+// Following is the resolver for the following field.
+func (u *userResolver) Following(ctx context.Context, obj *model.User, limit *int32, after *string) (*model.UserConnection, error) {
+	return u.followConnection(ctx, obj, limit, after, u.FollowRepo.GetFollowing)
+}
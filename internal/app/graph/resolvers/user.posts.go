@@ -0,0 +1,19 @@
+package resolvers
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/model"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+)
+
+// Posts is the resolver for the posts field.
+func (u *userResolver) Posts(ctx context.Context, obj *model.User, limit *int32, after *string, orderBy model.SortOrder) (*model.PostConnection, error) {
+	id, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		u.Logger.Debugf("cant convert userID to int, err: %v", err)
+		return nil, apperrors.New(apperrors.Validation, "userID is not an int")
+	}
+	return u.postConnection(ctx, repository.PostFilter{OwnerID: id}, limit, after, orderBy)
+}
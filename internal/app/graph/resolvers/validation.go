@@ -0,0 +1,43 @@
+package resolvers
+
+import (
+	"errors"
+	"ozon_test_task/pkg/apperrors"
+	"ozon_test_task/pkg/validation"
+)
+
+// maxPostTitleRunes is a width of the posts.title column in Postgres.
+const maxPostTitleRunes = 255
+
+// validatePost checks a title and a text of a new post.
+func (r *Resolver) validatePost(title, text string) error {
+	var v validation.Validator
+	v.Text("title", title, validation.Limits{MinLength: 1, MaxLength: r.Cfg.MaxPostTitleLength, MaxRunes: maxPostTitleRunes})
+	v.Text("text", text, validation.Limits{MinLength: 1, MaxLength: r.Cfg.MaxPostTextLength, Multiline: true})
+	return v.Err()
+}
+
+// validateCommentText checks a text of a new comment or reply.
+func (r *Resolver) validateCommentText(text string) error {
+	var v validation.Validator
+	v.Text("text", text, validation.Limits{MinLength: 1, MaxLength: r.Cfg.MaxCommentTextLength, Multiline: true})
+	return v.Err()
+}
+
+// validationError converts validation errors into a Validation error listing invalid fields in the "fields" extension:
+// [{"path": "title", "message": "must not be empty"}]. Other errors are returned as is.
+func validationError(err error) error {
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		return err
+	}
+	fields := make([]map[string]any, len(errs))
+	for i, e := range errs {
+		fields[i] = map[string]any{"path": e.Path, "message": e.Message}
+	}
+	return &apperrors.Error{
+		Code:       apperrors.Validation,
+		Message:    "invalid input: " + errs.Error(),
+		Extensions: map[string]any{"fields": fields},
+	}
+}
@@ -0,0 +1,43 @@
+package resolvers
+
+import (
+	"fmt"
+	"ozon_test_task/cfg"
+	"ozon_test_task/pkg/apperrors"
+	"reflect"
+	"testing"
+)
+
+func Test_validationError(t *testing.T) {
+	r := &Resolver{Cfg: cfg.Cfg{MaxPostTitleLength: 5, MaxPostTextLength: 10}}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "Not a validation error",
+			err:  fmt.Errorf("db error"),
+			want: fmt.Errorf("db error"),
+		},
+		{
+			name: "Fields are listed in extensions",
+			err:  r.validatePost("", "Привет, мир!"),
+			want: &apperrors.Error{
+				Code:    apperrors.Validation,
+				Message: "invalid input: title: must not be empty; text: must be at most 10 characters long",
+				Extensions: map[string]any{"fields": []map[string]any{
+					{"path": "title", "message": "must not be empty"},
+					{"path": "text", "message": "must be at most 10 characters long"},
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validationError(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validationError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package scalars
+
+import (
+	"io"
+	"ozon_test_task/pkg/apperrors"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// dateTimeLayout is RFC 3339 with optional fractional seconds.
+const dateTimeLayout = time.RFC3339Nano
+
+// MarshalDateTime marshals time.Time as a DateTime scalar: RFC 3339 string in UTC.
+func MarshalDateTime(t time.Time) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(t.UTC().Format(dateTimeLayout)))
+	})
+}
+
+// UnmarshalDateTime parses a DateTime scalar. Only RFC 3339 strings with a time zone are accepted.
+func UnmarshalDateTime(v any) (time.Time, error) {
+	str, ok := v.(string)
+	if !ok {
+		return time.Time{}, apperrors.New(apperrors.Validation, "DateTime must be a string")
+	}
+	t, err := time.Parse(dateTimeLayout, str)
+	if err != nil {
+		return time.Time{}, apperrors.New(apperrors.Validation, "DateTime must be in RFC 3339 format, e.g. \"2006-01-02T15:04:05Z\"")
+	}
+	return t.UTC(), nil
+}
@@ -0,0 +1,96 @@
+package scalars
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshalDateTime(t *testing.T) {
+	//withMonotonic returns a time with a given wall clock reading and the monotonic one of time.Now()
+	withMonotonic := func(wall time.Time) time.Time {
+		now := time.Now()
+		got := now.Add(wall.Sub(now))
+		if !strings.Contains(got.String(), " m=") {
+			t.Fatalf("time %v has no monotonic clock reading", got)
+		}
+		return got
+	}
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{
+			name: "UTC",
+			t:    time.Date(2020, 10, 31, 12, 30, 0, 0, time.UTC),
+			want: `"2020-10-31T12:30:00Z"`,
+		},
+		{
+			name: "converted to UTC",
+			t:    time.Date(2020, 10, 31, 15, 30, 0, 0, time.FixedZone("MSK", 3*60*60)),
+			want: `"2020-10-31T12:30:00Z"`,
+		},
+		{
+			name: "fractional seconds, with monotonic clock",
+			t:    withMonotonic(time.Date(2020, 10, 31, 12, 30, 0, 123000000, time.UTC)),
+			want: `"2020-10-31T12:30:00.123Z"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			MarshalDateTime(tt.t).MarshalGQL(buf)
+			if buf.String() != tt.want {
+				t.Errorf("MarshalDateTime() got = %v, want %v", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalDateTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       any
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "Ok",
+			v:    "2020-10-31T12:30:00Z",
+			want: time.Date(2020, 10, 31, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "offset",
+			v:    "2020-10-31T15:30:00.5+03:00",
+			want: time.Date(2020, 10, 31, 12, 30, 0, 500000000, time.UTC),
+		},
+		{
+			name:    "no time zone",
+			v:       "2020-10-31T12:30:00",
+			wantErr: true,
+		},
+		{
+			name:    "Go format",
+			v:       "2020-10-31 12:30:00 +0000 UTC",
+			wantErr: true,
+		},
+		{
+			name:    "not a string",
+			v:       1604147400,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalDateTime(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalDateTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("UnmarshalDateTime() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"context"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"go.uber.org/zap"
 	"net/http"
 	"ozon_test_task/internal/app/graph/repository"
@@ -23,7 +24,7 @@ type JWTManager interface {
 // GetAuthMiddleware - returns authentication middleware func.
 // It will just try to get user using JWT, but it won`t deny access for unauthorised users.
 // After successful authentication it will add "*models.User" to a context using UserContextKey as a key.
-// If user is unauthorised - nothing would be in context.
+// If user is unauthorised or banned - nothing would be in context.
 func GetAuthMiddleware(manager JWTManager, userRepo repository.UserRepo, logger *zap.SugaredLogger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -46,6 +47,11 @@ func GetAuthMiddleware(manager JWTManager, userRepo repository.UserRepo, logger
 				next.ServeHTTP(w, r)
 				return
 			}
+			if user.Banned {
+				logger.Debugf("user \"%v\" is banned", user.Login)
+				next.ServeHTTP(w, r)
+				return
+			}
 
 			logger.Debugf("success auth!")
 			ctx := context.WithValue(r.Context(), UserContextKey, user)
@@ -53,3 +59,34 @@ func GetAuthMiddleware(manager JWTManager, userRepo repository.UserRepo, logger
 		})
 	}
 }
+
+// GetWebsocketInitFunc - returns websocket init func authenticating subscriptions.
+// Browsers can`t set headers for websockets, so JWT is taken from "Authorization" field of connection init payload.
+// Like auth middleware it won`t deny the connection, resolvers decide if a user is required.
+func GetWebsocketInitFunc(manager JWTManager, userRepo repository.UserRepo, logger *zap.SugaredLogger) transport.WebsocketInitFunc {
+	return func(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		authToken := initPayload.Authorization()
+		if authToken == "" {
+			logger.Debugf("Auth init payload is empty")
+			return ctx, nil, nil
+		}
+
+		userID, err := manager.GetUserID(authToken)
+		if err != nil {
+			logger.Debugf("cant get userID from jwt, err: %v", err)
+			return ctx, nil, nil
+		}
+		user, err := userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			logger.Debugf("cant get user from db, err: %v", err)
+			return ctx, nil, nil
+		}
+		if user.Banned {
+			logger.Debugf("user \"%v\" is banned", user.Login)
+			return ctx, nil, nil
+		}
+
+		logger.Debugf("success websocket auth!")
+		return context.WithValue(ctx, UserContextKey, user), nil, nil
+	}
+}
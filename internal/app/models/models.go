@@ -7,21 +7,281 @@ type Post struct {
 	Owner           User
 	Title           string
 	Text            string
+	TextFormat      TextFormat
 	CommentsAllowed bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	LastCommentAt   *time.Time //nil if post has no comments.
+	// CommentsCount is an amount of all post`s comments including replies.
+	CommentsCount int
+	// LastActivityAt is a time of the post creation or its latest comment.
+	LastActivityAt time.Time
+	// Tags are normalized tag names sorted alphabetically.
+	Tags []string
+	// Reactions are amounts of readers` reactions of each kind.
+	Reactions Reactions
+	// Status is a publication status, only published posts are listed and visible to other users.
+	Status PostStatus
+	// PublishAt is a time the post was published or is scheduled to be published, nil for drafts.
+	// For held posts it is a time requested by the owner.
+	PublishAt *time.Time
+	// ModerationReasons are reasons the post was held for review by, empty unless the post is held.
+	ModerationReasons []string
+	// Hidden is true if a moderator hid the post, it stays in lists without its title and text.
+	Hidden bool
+	// CommentPolicy is a rule of who can comment the post, CommentsAllowed is false only for CommentsClosed.
+	CommentPolicy CommentPolicy
+	// CommentPolicyDays is a minimal age of commenters` accounts in days for CommentsRegisteredOlder policy, zero otherwise.
+	CommentPolicyDays int
 }
 
+// CommentPolicy is a rule of who can comment a post. Restrictions other than CommentsClosed don`t apply to the post owner.
+type CommentPolicy string
+
+const (
+	CommentsOpen   CommentPolicy = "OPEN"
+	CommentsClosed CommentPolicy = "CLOSED"
+	// CommentsFollowersOnly allows comments only from followers of the post owner.
+	CommentsFollowersOnly CommentPolicy = "FOLLOWERS_ONLY"
+	// CommentsRegisteredOlder allows comments only from users registered more than Post.CommentPolicyDays days ago.
+	CommentsRegisteredOlder CommentPolicy = "REGISTERED_OLDER_THAN_N_DAYS"
+	// CommentsApprovalRequired holds comments for review by moderators.
+	CommentsApprovalRequired CommentPolicy = "APPROVAL_REQUIRED"
+)
+
+// PostStatus is a publication status of a post.
+type PostStatus string
+
+const (
+	PostDraft     PostStatus = "DRAFT"
+	PostScheduled PostStatus = "SCHEDULED"
+	PostPublished PostStatus = "PUBLISHED"
+	// PostHeld is a post held for review by moderation, it is visible only to its owner and moderators.
+	PostHeld PostStatus = "HELD"
+)
+
+// TextFormat is a markup format of a post or comment text.
+type TextFormat string
+
+const (
+	TextPlain    TextFormat = "PLAIN"
+	TextMarkdown TextFormat = "MARKDOWN"
+)
+
 type Comment struct {
-	ID        int
-	Owner     User
-	PostID    int //zero if comment is a sub-comment
-	ParentID  int //zero if comment doesnt have parent.
-	Text      string
-	CreatedAt time.Time
+	ID         int
+	Owner      User
+	PostID     int //zero if comment is a sub-comment
+	ParentID   int //zero if comment doesnt have parent.
+	Text       string
+	TextFormat TextFormat
+	CreatedAt  time.Time
+	// Depth is zero for top-level comments and parent`s depth + 1 for replies, set by CommentRepo.AddComment.
+	Depth int
+	// RepliesCount is an amount of direct replies.
+	RepliesCount int
+	// DescendantsCount is an amount of all replies in the comment`s subtree.
+	DescendantsCount int
+	// LastActivityAt is a time of the comment creation or its latest reply in the subtree.
+	LastActivityAt time.Time
+	// Reactions are amounts of readers` reactions of each kind.
+	Reactions Reactions
+	// Mentions are users mentioned in the text in order of appearance, saved by CommentRepo.AddComment.
+	// Repositories don`t load them with a comment, use CommentRepo.GetMentions.
+	Mentions []User
+	// Hidden is true if the comment was hidden, it stays in the tree without its text.
+	Hidden bool
+}
+
+// HeldComment is a comment or a reply held for review by moderation.
+// It is saved as a regular comment only when a moderator approves it, so it doesn`t appear in comments lists and counters.
+type HeldComment struct {
+	ID int
+	// Comment is the held comment, its ID and counters are not set.
+	Comment Comment
+	// RootPostID is the post of the comment thread, equal to Comment.PostID for top-level comments.
+	RootPostID int
+	// Reasons are reasons the comment was held for review by.
+	Reasons []string
 }
 
 type User struct {
-	ID           int
-	Login        string
+	ID    int
+	Login string
+	// DisplayName, Bio and AvatarURL are optional profile fields, empty if not set.
+	DisplayName  string
+	Bio          string
+	AvatarURL    string
+	RegisteredAt time.Time
+	// Banned is true if a moderator banned the user, banned users can`t sign in.
+	Banned bool
+}
+
+// Credentials are user`s password hash and salt.
+// They are kept apart from User, so they can`t be exposed with a profile by mistake.
+type Credentials struct {
 	PasswordHash string
 	PasswordSalt string
 }
+
+// Follow is a user in a list of followers or followed users.
+type Follow struct {
+	User User
+	// CreatedAt is a time the user was followed or started following.
+	CreatedAt time.Time
+}
+
+// Bookmark is a post saved by a user to read later.
+type Bookmark struct {
+	Post Post
+	// CreatedAt is a time the post was bookmarked.
+	CreatedAt time.Time
+}
+
+// ReactionKind is a kind of reader`s reaction to a post or a comment.
+type ReactionKind string
+
+const (
+	ReactionLike    ReactionKind = "LIKE"
+	ReactionLove    ReactionKind = "LOVE"
+	ReactionLaugh   ReactionKind = "LAUGH"
+	ReactionDislike ReactionKind = "DISLIKE"
+)
+
+// ReactionKinds are all reaction kinds in display order.
+var ReactionKinds = []ReactionKind{ReactionLike, ReactionLove, ReactionLaugh, ReactionDislike}
+
+// Weight returns a contribution of a reaction to a score: -1 for a dislike, 1 for other kinds
+// and 0 for an empty kind (no reaction).
+func (k ReactionKind) Weight() int {
+	switch k {
+	case "":
+		return 0
+	case ReactionDislike:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// Reactions are amounts of reactions of each kind, absent kinds have zero amount.
+type Reactions map[ReactionKind]int
+
+// Score returns a sum of reactions weights.
+func (r Reactions) Score() int {
+	score := 0
+	for kind, count := range r {
+		score += kind.Weight() * count
+	}
+	return score
+}
+
+// NotificationType is a reason of a notification.
+type NotificationType string
+
+const (
+	// NotificationComment is a new comment on user`s post.
+	NotificationComment NotificationType = "COMMENT"
+	// NotificationReply is a new reply to user`s comment.
+	NotificationReply NotificationType = "REPLY"
+	// NotificationMention is a new comment mentioning the user.
+	NotificationMention NotificationType = "MENTION"
+)
+
+// Notification tells a user about a comment related to them.
+type Notification struct {
+	ID int
+	// UserID is an ID of a recipient.
+	UserID int
+	Type   NotificationType
+	// Comment is a comment the notification is about, its owner is an actor.
+	Comment   Comment
+	CreatedAt time.Time
+	Read      bool
+}
+
+// Tag is a topic of posts.
+type Tag struct {
+	Name string
+	// PostsCount is an amount of posts with the tag.
+	PostsCount int
+}
+
+// SearchHit is a post or a comment found by a full-text search.
+type SearchHit struct {
+	Post    *Post    //nil if hit is a comment.
+	Comment *Comment //nil if hit is a post.
+	// Rank is a relevance of the hit, bigger is better.
+	Rank int64
+	// Snippet is an HTML-escaped fragment of the text with matched words wrapped in <mark> tags.
+	Snippet string
+}
+
+// SortID returns an ID unique among posts and comments, used as a tiebreaker for hits with equal ranks.
+func (h *SearchHit) SortID() int {
+	if h.Post != nil {
+		return h.Post.ID*2 + 1
+	}
+	return h.Comment.ID * 2
+}
+
+// ReportTarget is a type of reported content.
+type ReportTarget string
+
+const (
+	ReportPost    ReportTarget = "POST"
+	ReportComment ReportTarget = "COMMENT"
+)
+
+// ReportReason is a reason a reader reported content for.
+type ReportReason string
+
+const (
+	ReportSpam       ReportReason = "SPAM"
+	ReportAbuse      ReportReason = "ABUSE"
+	ReportHarassment ReportReason = "HARASSMENT"
+	ReportOffTopic   ReportReason = "OFF_TOPIC"
+	ReportOther      ReportReason = "OTHER"
+)
+
+// ReportReasons are all report reasons in display order.
+var ReportReasons = []ReportReason{ReportSpam, ReportAbuse, ReportHarassment, ReportOffTopic, ReportOther}
+
+// Report is a reader`s complaint about a post or a comment.
+type Report struct {
+	ReporterID int
+	TargetType ReportTarget
+	TargetID   int
+	// AuthorID is an owner of the reported content.
+	AuthorID int
+	Reason   ReportReason
+	// Details is an optional explanation, empty if not given.
+	Details   string
+	CreatedAt time.Time
+}
+
+// ReportCase collects all reports about a post or a comment until a moderator resolves them.
+type ReportCase struct {
+	ID         int
+	TargetType ReportTarget
+	TargetID   int
+	AuthorID   int
+	// ReportCount is an amount of reports, every reader reports the content at most once.
+	ReportCount int
+	// Reasons are amounts of reports for each reason, absent reasons have zero amount.
+	Reasons map[ReportReason]int
+	// Details are non-empty explanations of reports, oldest first.
+	Details        []string
+	LastReportedAt time.Time
+}
+
+// IdempotencyRecord is a result of a create mutation stored by a user`s clientMutationId to be returned on replays.
+type IdempotencyRecord struct {
+	UserID int
+	Key    string
+	// Fingerprint identifies a mutation and its arguments, a replay must have the same fingerprint.
+	Fingerprint string
+	// Result is a JSON encoded response, nil while the first request is in progress.
+	Result    []byte
+	ExpiresAt time.Time
+}
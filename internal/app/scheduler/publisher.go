@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+// DuePostsPublisher publishes scheduled posts which are due, e.g. repository.PostRepo.
+// It must publish every post once, even if several app replicas call it concurrently.
+type DuePostsPublisher interface {
+	PublishDuePosts(ctx context.Context, now time.Time) ([]int, error)
+}
+
+// RunPostPublisher publishes due scheduled posts every interval until ctx is done.
+// Failures are logged and retried on the next tick.
+func RunPostPublisher(ctx context.Context, publisher DuePostsPublisher, interval time.Duration, logger *zap.SugaredLogger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		publishDuePosts(ctx, publisher, logger)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDuePosts runs a single publishing round.
+func publishDuePosts(ctx context.Context, publisher DuePostsPublisher, logger *zap.SugaredLogger) {
+	ids, err := publisher.PublishDuePosts(ctx, time.Now())
+	if err != nil {
+		logger.Errorf("failed to publish scheduled posts: %v", err)
+	}
+	if len(ids) > 0 {
+		logger.Infof("published scheduled posts: %v", ids)
+	}
+}
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap/zaptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakePublisher publishes posts from due whose publish time is not after now.
+type fakePublisher struct {
+	mu    sync.Mutex
+	due   map[int]time.Time
+	calls int
+	err   error
+}
+
+func (p *fakePublisher) PublishDuePosts(ctx context.Context, now time.Time) ([]int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	var ids []int
+	for id, publishAt := range p.due {
+		if !publishAt.After(now) {
+			ids = append(ids, id)
+			delete(p.due, id)
+		}
+	}
+	return ids, nil
+}
+
+func (p *fakePublisher) state() (calls, left int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls, len(p.due)
+}
+
+func TestRunPostPublisher(t *testing.T) {
+	publisher := &fakePublisher{due: map[int]time.Time{
+		1: time.Now().Add(-time.Minute),
+		2: time.Now().Add(30 * time.Millisecond),
+		3: time.Now().Add(time.Hour),
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		RunPostPublisher(ctx, publisher, 10*time.Millisecond, zaptest.NewLogger(t).Sugar())
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, left := publisher.state(); left == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("due posts were not published")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("RunPostPublisher() didn`t stop after ctx is done")
+	}
+	if _, left := publisher.state(); left != 1 {
+		t.Errorf("RunPostPublisher() published a post before its publish time")
+	}
+}
+
+func TestRunPostPublisher_Error(t *testing.T) {
+	publisher := &fakePublisher{err: fmt.Errorf("db error")}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	RunPostPublisher(ctx, publisher, 10*time.Millisecond, zaptest.NewLogger(t).Sugar())
+	if calls, _ := publisher.state(); calls < 2 {
+		t.Errorf("RunPostPublisher() made %d calls, want retries after an error", calls)
+	}
+}
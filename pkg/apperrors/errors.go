@@ -0,0 +1,58 @@
+package apperrors
+
+import "fmt"
+
+// Code is a machine-readable class of an error, returned to clients in extensions.code.
+type Code string
+
+const (
+	// Unauthenticated means the request has no valid user token.
+	Unauthenticated Code = "UNAUTHENTICATED"
+	// Forbidden means the user is not allowed to do the action.
+	Forbidden Code = "FORBIDDEN"
+	// NotFound means a requested entity doesn`t exist.
+	NotFound Code = "NOT_FOUND"
+	// Validation means the input is invalid, the request shouldn`t be retried as is.
+	Validation Code = "VALIDATION"
+	// Conflict means the action conflicts with a current state of an entity.
+	Conflict Code = "CONFLICT"
+	// RateLimited means the user sends too many requests.
+	RateLimited Code = "RATE_LIMITED"
+	// Internal means an unexpected failure, its cause is logged and never shown to clients.
+	Internal Code = "INTERNAL"
+)
+
+// internalMessage is a message of all Internal errors.
+const internalMessage = "internal server error"
+
+// Error is an error with a code.
+type Error struct {
+	Code Code
+	// Message is shown to clients.
+	Message string
+	// Extensions are returned to clients in error extensions along with the code.
+	Extensions map[string]any
+	// Cause is an underlying error, it is logged but never shown to clients.
+	Cause error
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// New returns an error with a message formatted by fmt.Sprintf.
+func New(code Code, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewInternal returns an Internal error hiding its cause (which may be nil) behind a generic message.
+func NewInternal(cause error) *Error {
+	return &Error{Code: Internal, Message: internalMessage, Cause: cause}
+}
@@ -0,0 +1,58 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	cause := errors.New("connection refused")
+	tests := []struct {
+		name     string
+		err      *Error
+		wantCode Code
+		wantMsg  string
+		wantErr  string
+	}{
+		{
+			name:     "formatted message",
+			err:      New(Validation, "post can have at most %d tags", 5),
+			wantCode: Validation,
+			wantMsg:  "post can have at most 5 tags",
+			wantErr:  "post can have at most 5 tags",
+		},
+		{
+			name:     "internal error hides its cause",
+			err:      NewInternal(fmt.Errorf("failed to get post: %w", cause)),
+			wantCode: Internal,
+			wantMsg:  "internal server error",
+			wantErr:  "internal server error: failed to get post: connection refused",
+		},
+		{
+			name:     "internal error without a cause",
+			err:      NewInternal(nil),
+			wantCode: Internal,
+			wantMsg:  "internal server error",
+			wantErr:  "internal server error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Code != tt.wantCode {
+				t.Errorf("Code = %v, want %v", tt.err.Code, tt.wantCode)
+			}
+			if tt.err.Message != tt.wantMsg {
+				t.Errorf("Message = %q, want %q", tt.err.Message, tt.wantMsg)
+			}
+			if tt.err.Error() != tt.wantErr {
+				t.Errorf("Error() = %q, want %q", tt.err.Error(), tt.wantErr)
+			}
+		})
+	}
+
+	var appErr *Error
+	if err := fmt.Errorf("resolver: %w", NewInternal(cause)); !errors.As(err, &appErr) || !errors.Is(err, cause) {
+		t.Errorf("wrapped error = %v, want to unwrap into Error and its cause", err)
+	}
+}
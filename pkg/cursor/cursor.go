@@ -0,0 +1,60 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// macLen is the amount of HMAC bytes appended to a payload. Truncated to keep cursors short.
+const macLen = 16
+
+// Cursor is a decoded pagination position.
+// Key is a primary sort key (depends on Order), ID is a tiebreaker for items with equal keys.
+type Cursor struct {
+	Order string `json:"o"`
+	Key   int64  `json:"k"`
+	ID    int    `json:"i"`
+}
+
+// Codec encodes cursors into opaque signed tokens and decodes them back.
+type Codec struct {
+	secret []byte
+}
+
+// NewCodec returns a new Codec which signs cursors with a given secret.
+func NewCodec(secret []byte) *Codec {
+	return &Codec{secret: secret}
+}
+
+// Encode returns an opaque token for a given cursor.
+func (c *Codec) Encode(cur Cursor) string {
+	payload, _ := json.Marshal(cur) // can`t fail: Cursor contains only strings and numbers.
+	return base64.RawURLEncoding.EncodeToString(append(payload, c.sign(payload)...))
+}
+
+// Decode parses a token made by Encode.
+// Returns NewErrInvalidCursor if token is malformed or its signature doesn`t match.
+func (c *Codec) Decode(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) <= macLen {
+		return Cursor{}, NewErrInvalidCursor()
+	}
+	payload, mac := raw[:len(raw)-macLen], raw[len(raw)-macLen:]
+	if !hmac.Equal(mac, c.sign(payload)) {
+		return Cursor{}, NewErrInvalidCursor()
+	}
+
+	var cur Cursor
+	if err := json.Unmarshal(payload, &cur); err != nil {
+		return Cursor{}, NewErrInvalidCursor()
+	}
+	return cur, nil
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	h := hmac.New(sha256.New, c.secret)
+	h.Write(payload)
+	return h.Sum(nil)[:macLen]
+}
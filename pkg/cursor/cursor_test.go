@@ -0,0 +1,93 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestCodec_Decode(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	cur := Cursor{Order: "NEWEST", Key: 1700000000000000, ID: 42}
+	token := codec.Encode(cur)
+
+	//tampered flips a bit of the last byte of a decoded token, which is a part of the MAC
+	tampered := func(token string) string {
+		raw, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw[len(raw)-1] ^= 1
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	//truncated drops the last byte of a decoded token
+	truncated := func(token string) string {
+		raw, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(raw[:len(raw)-1])
+	}
+
+	tests := []struct {
+		name    string
+		codec   *Codec
+		token   string
+		want    Cursor
+		wantErr bool
+	}{
+		{
+			name:  "Round trip",
+			codec: codec,
+			token: token,
+			want:  cur,
+		},
+		{
+			name:    "Tampered MAC",
+			codec:   codec,
+			token:   tampered(token),
+			wantErr: true,
+		},
+		{
+			name:    "Truncated token",
+			codec:   codec,
+			token:   truncated(token),
+			wantErr: true,
+		},
+		{
+			name:    "Token shorter than MAC",
+			codec:   codec,
+			token:   base64.RawURLEncoding.EncodeToString(make([]byte, macLen)),
+			wantErr: true,
+		},
+		{
+			name:    "Bad base64",
+			codec:   codec,
+			token:   "not base64!",
+			wantErr: true,
+		},
+		{
+			name:    "Wrong secret",
+			codec:   NewCodec([]byte("other secret")),
+			token:   token,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.codec.Decode(tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, NewErrInvalidCursor()) {
+					t.Errorf("Decode() error = %v, want invalid cursor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Decode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package cursor
+
+import "errors"
+
+var errInvalidCursor = errors.New("cursor is not valid")
+
+func NewErrInvalidCursor() error {
+	return errInvalidCursor
+}
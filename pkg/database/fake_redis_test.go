@@ -0,0 +1,540 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// fakeRedis is an in-memory server speaking RESP with a small subset of Redis commands used by RepoRedis tests.
+type fakeRedis struct {
+	mu      sync.Mutex
+	strings map[string]string
+	hashes  map[string]map[string]string
+	zsets   map[string]map[string]float64
+	streams map[string][]fakeStreamEntry
+	expires map[string]time.Time
+	lastSeq int
+	// subscribers are reply channels of connections subscribed to a channel.
+	subscribers map[string][]chan string
+}
+
+// fakeStreamEntry is an entry of a stream, IDs are "1-<seq>" with seq growing across all streams.
+type fakeStreamEntry struct {
+	seq    int
+	fields []string
+}
+
+// newFakeRedisClient returns a client of a new fakeRedis, the client is closed with the test.
+func newFakeRedisClient(t *testing.T) (*redis.Client, *fakeRedis) {
+	t.Helper()
+	f := &fakeRedis{
+		strings:     map[string]string{},
+		hashes:      map[string]map[string]string{},
+		zsets:       map[string]map[string]float64{},
+		streams:     map[string][]fakeStreamEntry{},
+		expires:     map[string]time.Time{},
+		subscribers: map[string][]chan string{},
+	}
+	client := redis.NewClient(&redis.Options{
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			server, conn := net.Pipe()
+			go f.serve(server)
+			return conn, nil
+		},
+	})
+	t.Cleanup(func() { client.Close() })
+	return client, f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	//replies are written concurrently, because pipelines send all commands before reading replies
+	replies := make(chan string, 1024)
+	defer close(replies)
+	go func() {
+		for reply := range replies {
+			if _, err := io.WriteString(conn, reply); err != nil {
+				conn.Close()
+			}
+		}
+	}()
+
+	defer f.unsubscribe(replies)
+
+	//queued are commands of an open MULTI transaction, nil if there is none
+	var queued [][]string
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(args[0])
+		if queued != nil && cmd != "EXEC" {
+			queued = append(queued, args)
+			replies <- "+QUEUED\r\n"
+			continue
+		}
+		f.mu.Lock()
+		var reply string
+		switch cmd {
+		case "MULTI":
+			queued = [][]string{}
+			reply = okReply
+		case "EXEC":
+			reply = fmt.Sprintf("*%d\r\n", len(queued))
+			for _, args := range queued {
+				reply += f.exec(args)
+			}
+			queued = nil
+		case "SUBSCRIBE":
+			for i, channel := range args[1:] {
+				f.subscribers[channel] = append(f.subscribers[channel], replies)
+				reply += "*3\r\n" + bulk("subscribe") + bulk(channel) + integer(i+1)
+			}
+		default:
+			reply = f.exec(args)
+		}
+		f.mu.Unlock()
+		if reply == nilArrayReply && strings.EqualFold(args[0], "XREAD") {
+			//blocking reads are not supported, a short pause keeps readers from spinning
+			time.Sleep(10 * time.Millisecond)
+		}
+		replies <- reply
+	}
+}
+
+// unsubscribe removes a reply channel of a closed connection from subscribers.
+func (f *fakeRedis) unsubscribe(replies chan string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for channel, subs := range f.subscribers {
+		f.subscribers[channel] = slices.DeleteFunc(subs, func(ch chan string) bool { return ch == replies })
+	}
+}
+
+// readCommand reads a RESP array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func bulk(s string) string { return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s) }
+
+func array(items []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(items))
+	for _, item := range items {
+		b.WriteString(bulk(item))
+	}
+	return b.String()
+}
+
+func integer(n int) string { return fmt.Sprintf(":%d\r\n", n) }
+
+func (e fakeStreamEntry) reply() string {
+	return "*2\r\n" + bulk(fmt.Sprintf("1-%d", e.seq)) + array(e.fields)
+}
+
+// streamSeq parses a sequence of a stream entry ID, "0" is before all entries.
+func streamSeq(id string) int {
+	_, seq, _ := strings.Cut(id, "-")
+	n, _ := strconv.Atoi(seq)
+	return n
+}
+
+const (
+	nilReply      = "$-1\r\n"
+	nilArrayReply = "*-1\r\n"
+	okReply       = "+OK\r\n"
+)
+
+// expire removes a key if its TTL is over.
+func (f *fakeRedis) expire(key string) {
+	if at, ok := f.expires[key]; ok && !time.Now().Before(at) {
+		f.del(key)
+	}
+}
+
+func (f *fakeRedis) del(key string) bool {
+	_, s := f.strings[key]
+	_, h := f.hashes[key]
+	_, z := f.zsets[key]
+	_, x := f.streams[key]
+	delete(f.strings, key)
+	delete(f.hashes, key)
+	delete(f.zsets, key)
+	delete(f.streams, key)
+	delete(f.expires, key)
+	return s || h || z || x
+}
+
+func (f *fakeRedis) keys() []string {
+	var keys []string
+	for k := range f.strings {
+		keys = append(keys, k)
+	}
+	for k := range f.hashes {
+		keys = append(keys, k)
+	}
+	for k := range f.zsets {
+		keys = append(keys, k)
+	}
+	for k := range f.streams {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedMembers returns members of a sorted set ordered by score, then by member.
+func (f *fakeRedis) sortedMembers(key string, desc bool) []string {
+	z := f.zsets[key]
+	members := make([]string, 0, len(z))
+	for member := range z {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		less := z[members[i]] < z[members[j]] || z[members[i]] == z[members[j]] && members[i] < members[j]
+		if desc {
+			return !less
+		}
+		return less
+	})
+	return members
+}
+
+// parseScoreBound parses an inclusive score bound of ZRANGEBYSCORE.
+func parseScoreBound(bound string) float64 {
+	switch bound {
+	case "-inf":
+		return math.Inf(-1)
+	case "+inf":
+		return math.Inf(1)
+	}
+	score, _ := strconv.ParseFloat(bound, 64)
+	return score
+}
+
+func formatScore(score float64) string { return strconv.FormatFloat(score, 'f', -1, 64) }
+
+func (f *fakeRedis) exec(args []string) string {
+	cmd := strings.ToUpper(args[0])
+	for _, key := range args[1:min(len(args), 2)] {
+		f.expire(key)
+	}
+	switch cmd {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		v, ok := f.strings[args[1]]
+		if !ok {
+			return nilReply
+		}
+		return bulk(v)
+	case "INCR":
+		n, err := strconv.Atoi(f.strings[args[1]])
+		if err != nil && f.strings[args[1]] != "" {
+			return "-ERR value is not an integer or out of range\r\n"
+		}
+		f.strings[args[1]] = strconv.Itoa(n + 1)
+		return integer(n + 1)
+	case "SET":
+		key, value := args[1], args[2]
+		var nx, xx, keepTTL bool
+		var ttl time.Duration
+		for i := 3; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "NX":
+				nx = true
+			case "XX":
+				xx = true
+			case "KEEPTTL":
+				keepTTL = true
+			case "PX", "EX":
+				n, _ := strconv.Atoi(args[i+1])
+				ttl = time.Duration(n) * time.Millisecond
+				if strings.ToUpper(args[i]) == "EX" {
+					ttl = time.Duration(n) * time.Second
+				}
+				i++
+			}
+		}
+		_, exists := f.strings[key]
+		if nx && exists || xx && !exists {
+			return nilReply
+		}
+		f.strings[key] = value
+		switch {
+		case ttl > 0:
+			f.expires[key] = time.Now().Add(ttl)
+		case !keepTTL:
+			delete(f.expires, key)
+		}
+		return okReply
+	case "PEXPIRE":
+		if _, ok := f.strings[args[1]]; !ok {
+			return integer(0)
+		}
+		n, _ := strconv.Atoi(args[2])
+		f.expires[args[1]] = time.Now().Add(time.Duration(n) * time.Millisecond)
+		return integer(1)
+	case "PTTL":
+		at, ok := f.expires[args[1]]
+		if !ok {
+			return integer(-1)
+		}
+		return integer(int(time.Until(at).Milliseconds()))
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			f.expire(key)
+			if f.del(key) {
+				n++
+			}
+		}
+		return integer(n)
+	case "EXISTS":
+		n := 0
+		for _, key := range args[1:] {
+			f.expire(key)
+			if slices.Contains(f.keys(), key) {
+				n++
+			}
+		}
+		return integer(n)
+	case "HSET":
+		h := f.hashes[args[1]]
+		if h == nil {
+			h = map[string]string{}
+			f.hashes[args[1]] = h
+		}
+		n := 0
+		for i := 2; i+1 < len(args); i += 2 {
+			if _, ok := h[args[i]]; !ok {
+				n++
+			}
+			h[args[i]] = args[i+1]
+		}
+		return integer(n)
+	case "HGET":
+		v, ok := f.hashes[args[1]][args[2]]
+		if !ok {
+			return nilReply
+		}
+		return bulk(v)
+	case "HMGET":
+		var b strings.Builder
+		fmt.Fprintf(&b, "*%d\r\n", len(args)-2)
+		for _, field := range args[2:] {
+			if v, ok := f.hashes[args[1]][field]; ok {
+				b.WriteString(bulk(v))
+			} else {
+				b.WriteString(nilReply)
+			}
+		}
+		return b.String()
+	case "HGETALL":
+		var items []string
+		h := f.hashes[args[1]]
+		fields := make([]string, 0, len(h))
+		for field := range h {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			items = append(items, field, h[field])
+		}
+		return array(items)
+	case "ZADD":
+		z := f.zsets[args[1]]
+		if z == nil {
+			z = map[string]float64{}
+			f.zsets[args[1]] = z
+		}
+		i, nx := 2, false
+		if strings.ToUpper(args[i]) == "NX" {
+			nx, i = true, i+1
+		}
+		n := 0
+		for ; i+1 < len(args); i += 2 {
+			score, _ := strconv.ParseFloat(args[i], 64)
+			if _, ok := z[args[i+1]]; ok {
+				if !nx {
+					z[args[i+1]] = score
+				}
+				continue
+			}
+			z[args[i+1]] = score
+			n++
+		}
+		return integer(n)
+	case "ZSCORE":
+		score, ok := f.zsets[args[1]][args[2]]
+		if !ok {
+			return nilReply
+		}
+		return bulk(formatScore(score))
+	case "ZCARD":
+		return integer(len(f.zsets[args[1]]))
+	case "ZRANGE":
+		members := f.sortedMembers(args[1], false)
+		start, _ := strconv.Atoi(args[2])
+		stop, _ := strconv.Atoi(args[3])
+		if start < 0 {
+			start += len(members)
+		}
+		if stop < 0 {
+			stop += len(members)
+		}
+		start, stop = max(start, 0), min(stop, len(members)-1)
+		if start > stop {
+			return array(nil)
+		}
+		return array(members[start : stop+1])
+	case "ZRANGEBYSCORE", "ZREVRANGEBYSCORE":
+		desc := cmd == "ZREVRANGEBYSCORE"
+		lo, hi := args[2], args[3]
+		if desc {
+			lo, hi = hi, lo
+		}
+		minScore, maxScore := parseScoreBound(lo), parseScoreBound(hi)
+		withScores, offset, count := false, 0, -1
+		for i := 4; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "WITHSCORES":
+				withScores = true
+			case "LIMIT":
+				offset, _ = strconv.Atoi(args[i+1])
+				count, _ = strconv.Atoi(args[i+2])
+				i += 2
+			}
+		}
+		z := f.zsets[args[1]]
+		var items []string
+		for _, member := range f.sortedMembers(args[1], desc) {
+			if z[member] < minScore || z[member] > maxScore {
+				continue
+			}
+			if offset > 0 {
+				offset--
+				continue
+			}
+			if count == 0 {
+				break
+			}
+			count--
+			items = append(items, member)
+			if withScores {
+				items = append(items, formatScore(z[member]))
+			}
+		}
+		return array(items)
+	case "XADD":
+		i := 2
+	options:
+		for ; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "MAXLEN", "MINID", "LIMIT":
+				if args[i+1] == "~" || args[i+1] == "=" {
+					i++
+				}
+				i++
+				continue
+			case "NOMKSTREAM":
+				continue
+			}
+			break options
+		}
+		f.lastSeq++
+		f.streams[args[1]] = append(f.streams[args[1]], fakeStreamEntry{seq: f.lastSeq, fields: args[i+1:]})
+		return bulk(fmt.Sprintf("1-%d", f.lastSeq))
+	case "XREVRANGE":
+		entries := f.streams[args[1]]
+		count := len(entries)
+		if len(args) > 5 && strings.ToUpper(args[4]) == "COUNT" {
+			count, _ = strconv.Atoi(args[5])
+		}
+		var b strings.Builder
+		n := 0
+		for i := len(entries) - 1; i >= 0 && n < count; i-- {
+			b.WriteString(entries[i].reply())
+			n++
+		}
+		return fmt.Sprintf("*%d\r\n", n) + b.String()
+	case "XREAD":
+		i := slices.IndexFunc(args, func(arg string) bool { return strings.EqualFold(arg, "STREAMS") })
+		key, after := args[i+1], streamSeq(args[i+2])
+		var b strings.Builder
+		n := 0
+		for _, entry := range f.streams[key] {
+			if entry.seq > after {
+				b.WriteString(entry.reply())
+				n++
+			}
+		}
+		if n == 0 {
+			return nilArrayReply
+		}
+		return "*1\r\n*2\r\n" + bulk(key) + fmt.Sprintf("*%d\r\n", n) + b.String()
+	case "PUBLISH":
+		subs := f.subscribers[args[1]]
+		for _, ch := range subs {
+			ch <- "*3\r\n" + bulk("message") + bulk(args[1]) + bulk(args[2])
+		}
+		return integer(len(subs))
+	case "SCAN":
+		pattern := "*"
+		for i := 2; i+1 < len(args); i += 2 {
+			if strings.ToUpper(args[i]) == "MATCH" {
+				pattern = args[i+1]
+			}
+		}
+		var matched []string
+		for _, key := range f.keys() {
+			if ok, _ := path.Match(pattern, key); ok {
+				matched = append(matched, key)
+			}
+		}
+		return "*2\r\n" + bulk("0") + array(matched)
+	}
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", cmd)
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+)
+
+// fakeSQLConnector opens connections answering every query with err, or with no rows if err is nil.
+type fakeSQLConnector struct {
+	err error
+}
+
+// newFakeSQLDB returns a database of a fakeSQLConnector, the database is closed with the test.
+func newFakeSQLDB(t *testing.T, err error) *sql.DB {
+	t.Helper()
+	db := sql.OpenDB(fakeSQLConnector{err: err})
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func (c fakeSQLConnector) Connect(context.Context) (driver.Conn, error) { return fakeSQLConn(c), nil }
+func (c fakeSQLConnector) Driver() driver.Driver                        { return nil }
+
+type fakeSQLConn fakeSQLConnector
+
+func (c fakeSQLConn) Prepare(string) (driver.Stmt, error) { return fakeSQLStmt(c), nil }
+func (c fakeSQLConn) Close() error                        { return nil }
+func (c fakeSQLConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeSQLStmt fakeSQLConn
+
+func (s fakeSQLStmt) Close() error  { return nil }
+func (s fakeSQLStmt) NumInput() int { return -1 }
+
+func (s fakeSQLStmt) Exec([]driver.Value) (driver.Result, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (s fakeSQLStmt) Query([]driver.Value) (driver.Rows, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return fakeSQLRows{}, nil
+}
+
+type fakeSQLRows struct{}
+
+func (fakeSQLRows) Columns() []string         { return nil }
+func (fakeSQLRows) Close() error              { return nil }
+func (fakeSQLRows) Next([]driver.Value) error { return io.EOF }
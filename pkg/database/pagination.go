@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// afterID returns an ID of the last seen item of a page ordered by ID, zero for the first page.
+func afterID(page repository.PageArgs) int {
+	if page.After == nil {
+		return 0
+	}
+	return page.After.ID
+}
+
+// pgKeyset builds WHERE and ORDER BY parts of keyset pagination queries.
+type pgKeyset struct {
+	key    string // sort key column.
+	id     string // tiebreaker column.
+	desc   bool
+	isTime bool // key column is a timestamp, Position.Key is a unix time in microseconds.
+}
+
+// newPGKeyset returns a keyset for a given order.
+// Items are sorted by "alias.id" unless a key column for the order is set in keys.
+func newPGKeyset(order repository.Order, alias string, keys map[repository.Order]string) pgKeyset {
+	ks := pgKeyset{
+		key:    alias + ".id",
+		id:     alias + ".id",
+		desc:   order.IsDesc(),
+		isTime: order == repository.OrderRecentActivity,
+	}
+	if key, ok := keys[order]; ok {
+		ks.key = key
+	}
+	return ks
+}
+
+// where returns a condition selecting items after a given position. Its arguments are appended to args.
+func (k pgKeyset) where(after *repository.Position, args *[]any) string {
+	if after == nil {
+		return "TRUE"
+	}
+	op := ">"
+	if k.desc {
+		op = "<"
+	}
+	if k.key == k.id {
+		*args = append(*args, after.ID)
+		return fmt.Sprintf("%s %s $%d", k.id, op, len(*args))
+	}
+
+	var key any = after.Key
+	if k.isTime {
+		key = time.UnixMicro(after.Key)
+	}
+	*args = append(*args, key, after.ID)
+	return fmt.Sprintf("(%s, %s) %s ($%d, $%d)", k.key, k.id, op, len(*args)-1, len(*args))
+}
+
+// orderBy returns an ORDER BY expression.
+func (k pgKeyset) orderBy() string {
+	dir := ""
+	if k.desc {
+		dir = " DESC"
+	}
+	if k.key == k.id {
+		return k.id + dir
+	}
+	return k.key + dir + ", " + k.id + dir
+}
+
+// zMember returns a sorted set member for an ID.
+// IDs are zero-padded, so members with equal scores are ordered by ID.
+func zMember(id int) string {
+	return fmt.Sprintf("%012d", id)
+}
+
+// zPage returns IDs of a page of a sorted set, where score is a sort key and member is an ID.
+// Members must be made by zMember if scores are not unique.
+func (r *RepoRedis) zPage(ctx context.Context, key string, desc bool, page repository.PageArgs) (ids []int, hasNextPage bool, err error) {
+	return r.zPageExcept(ctx, key, desc, page, nil)
+}
+
+// zPageExcept is zPage which skips IDs in except.
+func (r *RepoRedis) zPageExcept(ctx context.Context, key string, desc bool, page repository.PageArgs, except map[int]bool) (ids []int, hasNextPage bool, err error) {
+	want := page.Limit + 1
+	min, max := "-inf", "+inf"
+	if page.After != nil {
+		bound := strconv.FormatInt(page.After.Key, 10)
+		if desc {
+			max = bound
+		} else {
+			min = bound
+		}
+	}
+
+	for offset := int64(0); len(ids) < want; {
+		by := &redis.ZRangeBy{Min: min, Max: max, Offset: offset, Count: int64(want)}
+		var zs []redis.Z
+		if desc {
+			zs, err = r.client.ZRevRangeByScoreWithScores(ctx, key, by).Result()
+		} else {
+			zs, err = r.client.ZRangeByScoreWithScores(ctx, key, by).Result()
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get ids from sorted set: %w", err)
+		}
+
+		for _, z := range zs {
+			member, _ := z.Member.(string)
+			id, err := strconv.Atoi(member)
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid id in sorted set: %w", err)
+			}
+			//skip items with the same key before "after" one (including itself)
+			if page.After != nil && int64(z.Score) == page.After.Key &&
+				(desc && id >= page.After.ID || !desc && id <= page.After.ID) {
+				continue
+			}
+			if except[id] {
+				continue
+			}
+			ids = append(ids, id)
+		}
+		if len(zs) < want {
+			break
+		}
+		offset += int64(len(zs))
+	}
+
+	if len(ids) > page.Limit {
+		hasNextPage = true
+		ids = ids[:page.Limit]
+	}
+	return ids, hasNextPage, nil
+}
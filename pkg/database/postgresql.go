@@ -3,13 +3,18 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"ozon_test_task/internal/app/graph/repository"
 	"ozon_test_task/internal/app/models"
+	"time"
+
+	"github.com/lib/pq"
 )
 
-// RepoPG is a PostgreSQL repository that implements PostRepo, CommentRepo, and UserRepo interfaces.
+// RepoPG is a PostgreSQL repository that implements PostRepo, CommentRepo, UserRepo, FollowRepo, BookmarkRepo, TagRepo, SearchRepo,
+// ReactionRepo, NotificationRepo, ModerationRepo, ReportRepo, BlockRepo and IdempotencyRepo interfaces.
 type RepoPG struct {
 	DB *sql.DB
 }
@@ -61,151 +66,322 @@ func (r *RepoPG) InitDB() error {
 		return fmt.Errorf("failed to create comments table: %w", err)
 	}
 
+	if err := r.migrate(ctx); err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+
 	return nil
 }
 
-// AddPost adds a new post to the database and returns its generated ID.
+// AddPost adds a new post with its tags to the database and returns its generated ID.
 func (r *RepoPG) AddPost(ctx context.Context, post *models.Post) (int, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	var id int
 	query := `
-		INSERT INTO posts (owner_id, title, text, commentsallowed)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO posts (owner_id, title, text, commentsallowed, created_at, updated_at, last_activity_at, status, publish_at, text_format,
+		                   moderation_reasons, comment_policy, comment_policy_days)
+		VALUES ($1, $2, $3, $4, $5, $6, $5, $7, $8, $9, $10, $11, $12)
 		RETURNING id`
-	err := r.DB.QueryRowContext(ctx, query, post.Owner.ID, post.Title, post.Text, post.CommentsAllowed).Scan(&id)
+	err = tx.QueryRowContext(ctx, query, post.Owner.ID, post.Title, post.Text, post.CommentsAllowed,
+		post.CreatedAt, post.UpdatedAt, post.Status, post.PublishAt, post.TextFormat, pq.Array(post.ModerationReasons),
+		post.CommentPolicy, post.CommentPolicyDays).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("failed to add post: %w", err)
 	}
+
+	if len(post.Tags) > 0 {
+		query = `INSERT INTO post_tags (post_id, tag) SELECT $1, unnest($2::TEXT[]) ON CONFLICT DO NOTHING`
+		if _, err = tx.ExecContext(ctx, query, id, pq.Array(post.Tags)); err != nil {
+			return 0, fmt.Errorf("failed to add post tags: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return id, nil
 }
 
-// SetCommentsAllowed updates the commentsallowed flag for a given post.
-func (r *RepoPG) SetCommentsAllowed(ctx context.Context, postID int, commentsAllowed bool) error {
-	query := `UPDATE posts SET commentsallowed = $1 WHERE id = $2`
-	result, err := r.DB.ExecContext(ctx, query, commentsAllowed, postID)
+// SetCommentPolicy updates the comment policy and the commentsallowed flag for a given post.
+func (r *RepoPG) SetCommentPolicy(ctx context.Context, postID int, policy models.CommentPolicy, days int, updatedAt time.Time) error {
+	query := `UPDATE posts SET comment_policy = $2, comment_policy_days = $3, commentsallowed = $4, updated_at = $5 WHERE id = $1`
+	return r.execOne(ctx, "failed to update comment policy", query, postID, policy, days, policy != models.CommentsClosed, updatedAt)
+}
+
+// pgUserColumns are public profile columns of a user, "u" is users alias. Credentials are never selected with them.
+const pgUserColumns = `u.id, u.login, u.display_name, u.bio, u.avatar_url, u.registered_at, u.banned`
+
+// scanUser scans a row selected with pgUserColumns.
+func scanUser(row interface{ Scan(dest ...any) error }) (*models.User, error) {
+	var u models.User
+	if err := row.Scan(&u.ID, &u.Login, &u.DisplayName, &u.Bio, &u.AvatarURL, &u.RegisteredAt, &u.Banned); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// pgPostColumns are columns selected by scanPost, "p" is posts and "u" is users (owners) alias.
+const pgPostColumns = `p.id, p.title, p.text, p.text_format, p.commentsallowed, p.created_at, p.updated_at, p.last_comment_at,
+		       p.comments_count, p.last_activity_at, p.reactions, p.status, p.publish_at, p.moderation_reasons, p.hidden,
+		       p.comment_policy, p.comment_policy_days,
+		       ARRAY(SELECT t.tag FROM post_tags t WHERE t.post_id = p.id ORDER BY t.tag),
+		       ` + pgUserColumns
+
+// scanPost scans a row selected with pgPostColumns.
+func scanPost(row interface{ Scan(dest ...any) error }) (*models.Post, error) {
+	var p models.Post
+	var tags, moderationReasons pq.StringArray
+	var reactions []byte
+	err := row.Scan(&p.ID, &p.Title, &p.Text, &p.TextFormat, &p.CommentsAllowed, &p.CreatedAt, &p.UpdatedAt, &p.LastCommentAt,
+		&p.CommentsCount, &p.LastActivityAt, &reactions, &p.Status, &p.PublishAt, &moderationReasons, &p.Hidden,
+		&p.CommentPolicy, &p.CommentPolicyDays,
+		&tags,
+		&p.Owner.ID, &p.Owner.Login, &p.Owner.DisplayName, &p.Owner.Bio, &p.Owner.AvatarURL, &p.Owner.RegisteredAt, &p.Owner.Banned)
 	if err != nil {
-		return fmt.Errorf("failed to update comments allowed: %w", err)
+		return nil, err
 	}
-	rows, err := result.RowsAffected()
+	if len(tags) > 0 {
+		p.Tags = tags
+	}
+	if len(moderationReasons) > 0 {
+		p.ModerationReasons = moderationReasons
+	}
+	if err := json.Unmarshal(reactions, &p.Reactions); err != nil {
+		return nil, fmt.Errorf("invalid reactions: %w", err)
+	}
+	return &p, nil
+}
+
+// pgCommentColumns are columns selected by scanComment, "c" is comments and "u" is users (owners) alias.
+const pgCommentColumns = `c.id, c.post_id, c.parent_id, c.text, c.text_format, c.created_at, c.depth,
+		       c.replies_count, c.descendants_count, c.last_activity_at, c.reactions, c.hidden,
+		       ` + pgUserColumns
+
+// scanComment scans a row selected with pgCommentColumns.
+func scanComment(row interface{ Scan(dest ...any) error }) (*models.Comment, error) {
+	var c models.Comment
+	var reactions []byte
+	err := row.Scan(&c.ID, &c.PostID, &c.ParentID, &c.Text, &c.TextFormat, &c.CreatedAt, &c.Depth,
+		&c.RepliesCount, &c.DescendantsCount, &c.LastActivityAt, &reactions, &c.Hidden,
+		&c.Owner.ID, &c.Owner.Login, &c.Owner.DisplayName, &c.Owner.Bio, &c.Owner.AvatarURL, &c.Owner.RegisteredAt, &c.Owner.Banned)
 	if err != nil {
-		return fmt.Errorf("failed to get affected rows: %w", err)
+		return nil, err
 	}
-	if rows == 0 {
-		return sql.ErrNoRows
+	if err := json.Unmarshal(reactions, &c.Reactions); err != nil {
+		return nil, fmt.Errorf("invalid reactions: %w", err)
 	}
-	return nil
+	return &c, nil
 }
 
 // GetPostByID returns a post by its ID.
+// returns repository.NewErrNotFound if not found.
 func (r *RepoPG) GetPostByID(ctx context.Context, postID int) (*models.Post, error) {
 	query := `
-		SELECT p.id, p.title, p.text, p.commentsallowed,
-		       u.id, u.login
+		SELECT ` + pgPostColumns + `
 		FROM posts p
 		JOIN users u ON p.owner_id = u.id
 		WHERE p.id = $1`
 	row := r.DB.QueryRowContext(ctx, query, postID)
 
-	var p models.Post
-	var u models.User
-	if err := row.Scan(&p.ID, &p.Title, &p.Text, &p.CommentsAllowed, &u.ID, &u.Login); err != nil {
+	p, err := scanPost(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.NewErrNotFound()
+		}
 		return nil, fmt.Errorf("failed to get post by ID: %w", err)
 	}
-	p.Owner = u
-	return &p, nil
+	return p, nil
 }
 
-// GetPosts retrieves a list of posts with pagination.
-func (r *RepoPG) GetPosts(ctx context.Context, limit int, after int) (posts []*models.Post, hasNextPage bool, err error) {
-	limitPlusOne := limit + 1
+// GetPosts retrieves a filtered list of posts with pagination.
+func (r *RepoPG) GetPosts(ctx context.Context, filter repository.PostFilter, order repository.Order, page repository.PageArgs) (posts []*models.Post, hasNextPage bool, err error) {
+	keyset := newPGKeyset(order, "p", map[repository.Order]string{
+		repository.OrderMostCommented:  "p.comments_count",
+		repository.OrderRecentActivity: "p.last_activity_at",
+		repository.OrderTop:            "p.score",
+	})
+	args := []any{page.Limit + 1}
+	conditions := keyset.where(page.After, &args)
+	switch {
+	case filter.Held:
+		conditions += " AND p.status = 'HELD'"
+	case filter.Unpublished:
+		conditions += " AND p.status <> 'PUBLISHED'"
+	default:
+		conditions += " AND p.status = 'PUBLISHED'"
+	}
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		conditions += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM post_tags t WHERE t.post_id = p.id AND t.tag = $%d)", len(args))
+	}
+	if filter.OwnerID != 0 {
+		args = append(args, filter.OwnerID)
+		conditions += fmt.Sprintf(" AND p.owner_id = $%d", len(args))
+	}
 	query := `
-		SELECT p.id, p.title, p.text, p.commentsallowed,
-		       u.id, u.login
+		SELECT ` + pgPostColumns + `
 		FROM posts p
 		JOIN users u ON p.owner_id = u.id
-		WHERE p.id > $1
-		ORDER BY p.id
-		LIMIT $2`
-	rows, err := r.DB.QueryContext(ctx, query, after, limitPlusOne)
+		WHERE ` + conditions + `
+		ORDER BY ` + keyset.orderBy() + `
+		LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to get posts: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var p models.Post
-		var u models.User
-		if err := rows.Scan(&p.ID, &p.Title, &p.Text, &p.CommentsAllowed, &u.ID, &u.Login); err != nil {
+		p, err := scanPost(rows)
+		if err != nil {
 			return nil, false, fmt.Errorf("failed to scan post: %w", err)
 		}
-		p.Owner = u
-		posts = append(posts, &p)
+		posts = append(posts, p)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, false, fmt.Errorf("rows error: %w", err)
 	}
-	if len(posts) > limit {
+	if len(posts) > page.Limit {
 		hasNextPage = true
-		posts = posts[:limit]
+		posts = posts[:page.Limit]
 	}
 	return posts, hasNextPage, nil
 }
 
 // AddComment adds a new comment to the database and returns its ID.
+// Comments counters and activity times of the post and comment`s ancestors are updated in the same transaction.
 func (r *RepoPG) AddComment(ctx context.Context, comment *models.Comment) (int, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	var id int
 	query := `
-		INSERT INTO comments (owner_id, post_id, parent_id, text, created_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO comments (owner_id, post_id, parent_id, text, text_format, created_at, last_activity_at, depth)
+		VALUES ($1, $2, $3, $4, $5, $6, $6, COALESCE((SELECT depth + 1 FROM comments WHERE id = $3), 0))
 		RETURNING id`
-	err := r.DB.QueryRowContext(ctx, query, comment.Owner.ID, comment.PostID, comment.ParentID, comment.Text, comment.CreatedAt).Scan(&id)
+	err = tx.QueryRowContext(ctx, query, comment.Owner.ID, comment.PostID, comment.ParentID, comment.Text, comment.TextFormat, comment.CreatedAt).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("failed to add comment: %w", err)
 	}
+
+	if len(comment.Mentions) > 0 {
+		userIDs := make([]int64, len(comment.Mentions))
+		for i, user := range comment.Mentions {
+			userIDs[i] = int64(user.ID)
+		}
+		query = `
+		INSERT INTO comment_mentions (comment_id, user_id, position)
+		SELECT $1, m.user_id, m.position FROM unnest($2::INTEGER[]) WITH ORDINALITY AS m(user_id, position)
+		ON CONFLICT DO NOTHING`
+		if _, err = tx.ExecContext(ctx, query, id, pq.Array(userIDs)); err != nil {
+			return 0, fmt.Errorf("failed to add comment mentions: %w", err)
+		}
+	}
+
+	postID := comment.PostID
+	if comment.ParentID != 0 {
+		//update ancestors and find a post of the top-level one
+		query = `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, post_id FROM comments WHERE id = $1
+			UNION ALL
+			SELECT c.id, c.parent_id, c.post_id FROM comments c JOIN ancestors a ON c.id = a.parent_id
+		), updated AS (
+			UPDATE comments SET replies_count = replies_count + CASE WHEN id = $1 THEN 1 ELSE 0 END,
+			                    descendants_count = descendants_count + 1,
+			                    last_activity_at = GREATEST(last_activity_at, $2)
+			WHERE id IN (SELECT id FROM ancestors)
+		)
+		SELECT post_id FROM ancestors WHERE parent_id = 0`
+		err = tx.QueryRowContext(ctx, query, comment.ParentID, comment.CreatedAt).Scan(&postID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, repository.NewErrNotFound()
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to update comment ancestors: %w", err)
+		}
+	}
+
+	query = `
+		UPDATE posts SET comments_count = comments_count + 1,
+		                 last_comment_at = GREATEST(last_comment_at, $2),
+		                 last_activity_at = GREATEST(last_activity_at, $2)
+		WHERE id = $1`
+	result, err := tx.ExecContext(ctx, query, postID, comment.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update post counters: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return 0, repository.NewErrNotFound()
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit comment: %w", err)
+	}
 	return id, nil
 }
 
 // GetCommentsByPostID returns top-level comments (without a parent or their sub-comments) for a given post.
-func (r *RepoPG) GetCommentsByPostID(ctx context.Context, postID int, limit int, after int) (comments []*models.Comment, hasNextPage bool, err error) {
-	limitPlusOne := limit + 1
+func (r *RepoPG) GetCommentsByPostID(ctx context.Context, postID int, filter repository.CommentFilter, order repository.Order, page repository.PageArgs) (comments []*models.Comment, hasNextPage bool, err error) {
+	keyset := newPGKeyset(order, "c", map[repository.Order]string{
+		repository.OrderMostCommented:  "c.descendants_count",
+		repository.OrderRecentActivity: "c.last_activity_at",
+		repository.OrderTop:            "c.score",
+	})
+	args := []any{page.Limit + 1, postID}
+	pinned := "TRUE"
+	if filter.ExcludePinned {
+		pinned = "NOT EXISTS (SELECT 1 FROM pinned_comments pc WHERE pc.comment_id = c.id)"
+	}
 	query := `
-		SELECT c.id, c.post_id, c.parent_id, c.text, c.created_at,
-		       u.id, u.login
+		SELECT ` + pgCommentColumns + `
 		FROM comments c
 		JOIN users u ON c.owner_id = u.id
-		WHERE c.post_id = $1 AND c.parent_id = 0 AND c.id > $2
-		ORDER BY c.id
-		LIMIT $3`
-	rows, err := r.DB.QueryContext(ctx, query, postID, after, limitPlusOne)
+		WHERE c.post_id = $2 AND c.parent_id = 0 AND ` + pinned + ` AND ` + keyset.where(page.After, &args) + `
+		ORDER BY ` + keyset.orderBy() + `
+		LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to get comments by post ID: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var c models.Comment
-		var u models.User
-		if err := rows.Scan(&c.ID, &c.PostID, &c.ParentID, &c.Text, &c.CreatedAt, &u.ID, &u.Login); err != nil {
+		c, err := scanComment(rows)
+		if err != nil {
 			return nil, false, fmt.Errorf("failed to scan comment: %w", err)
 		}
-		c.Owner = u
-		comments = append(comments, &c)
+		comments = append(comments, c)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, false, fmt.Errorf("rows error: %w", err)
 	}
-	if len(comments) > limit {
+	if len(comments) > page.Limit {
 		hasNextPage = true
-		comments = comments[:limit]
+		comments = comments[:page.Limit]
 	}
 	return comments, hasNextPage, nil
 }
 
 // GetReplaysByCommentID gets replies for a given comment.
-func (r *RepoPG) GetReplaysByCommentID(ctx context.Context, commentID int, limit int, after int) (replies []*models.Comment, hasNextPage bool, err error) {
+func (r *RepoPG) GetReplaysByCommentID(ctx context.Context, commentID int, page repository.PageArgs) (replies []*models.Comment, hasNextPage bool, err error) {
+	limit := page.Limit
 	limitPlusOne := limit + 1
+	after := afterID(page)
 	query := `
-		SELECT c.id, c.post_id, c.parent_id, c.text, c.created_at,
-		       u.id, u.login
+		SELECT ` + pgCommentColumns + `
 		FROM comments c
 		JOIN users u ON c.owner_id = u.id
 		WHERE c.parent_id = $1 AND c.id > $2
@@ -218,13 +394,11 @@ func (r *RepoPG) GetReplaysByCommentID(ctx context.Context, commentID int, limit
 	defer rows.Close()
 
 	for rows.Next() {
-		var c models.Comment
-		var u models.User
-		if err := rows.Scan(&c.ID, &c.PostID, &c.ParentID, &c.Text, &c.CreatedAt, &u.ID, &u.Login); err != nil {
+		c, err := scanComment(rows)
+		if err != nil {
 			return nil, false, fmt.Errorf("failed to scan reply: %w", err)
 		}
-		c.Owner = u
-		replies = append(replies, &c)
+		replies = append(replies, c)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, false, fmt.Errorf("rows error: %w", err)
@@ -236,14 +410,111 @@ func (r *RepoPG) GetReplaysByCommentID(ctx context.Context, commentID int, limit
 	return replies, hasNextPage, nil
 }
 
-// AddUser adds a new user to the database and returns the user's ID.
-func (r *RepoPG) AddUser(ctx context.Context, user *models.User) (int, error) {
+// GetCommentsByOwnerID gets comments and replies of a given user, newest first.
+func (r *RepoPG) GetCommentsByOwnerID(ctx context.Context, ownerID int, page repository.PageArgs) (comments []*models.Comment, hasNextPage bool, err error) {
+	keyset := newPGKeyset(repository.OrderNewest, "c", nil)
+	args := []any{page.Limit + 1, ownerID}
+	query := `
+		SELECT ` + pgCommentColumns + `
+		FROM comments c
+		JOIN users u ON c.owner_id = u.id
+		WHERE c.owner_id = $2 AND ` + keyset.where(page.After, &args) + `
+		ORDER BY ` + keyset.orderBy() + `
+		LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get comments by owner ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+	if len(comments) > page.Limit {
+		hasNextPage = true
+		comments = comments[:page.Limit]
+	}
+	return comments, hasNextPage, nil
+}
+
+// GetMentions returns users mentioned in a comment in order of appearance.
+func (r *RepoPG) GetMentions(ctx context.Context, commentID int) ([]*models.User, error) {
+	query := `
+		SELECT ` + pgUserColumns + `
+		FROM comment_mentions m
+		JOIN users u ON m.user_id = u.id
+		WHERE m.comment_id = $1
+		ORDER BY m.position`
+	rows, err := r.DB.QueryContext(ctx, query, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mentions: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan mentioned user: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return users, nil
+}
+
+// GetCommentsByMentionedUserID gets comments and replies mentioning a given user, newest first.
+func (r *RepoPG) GetCommentsByMentionedUserID(ctx context.Context, userID int, page repository.PageArgs) (comments []*models.Comment, hasNextPage bool, err error) {
+	keyset := newPGKeyset(repository.OrderNewest, "c", nil)
+	args := []any{page.Limit + 1, userID}
+	query := `
+		SELECT ` + pgCommentColumns + `
+		FROM comment_mentions m
+		JOIN comments c ON m.comment_id = c.id
+		JOIN users u ON c.owner_id = u.id
+		WHERE m.user_id = $2 AND ` + keyset.where(page.After, &args) + `
+		ORDER BY ` + keyset.orderBy() + `
+		LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get comments by mentioned user ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+	if len(comments) > page.Limit {
+		hasNextPage = true
+		comments = comments[:page.Limit]
+	}
+	return comments, hasNextPage, nil
+}
+
+// AddUser adds a new user with its credentials to the database and returns the user's ID.
+func (r *RepoPG) AddUser(ctx context.Context, user *models.User, cred *models.Credentials) (int, error) {
 	userID := 0
 	query := `
-		INSERT INTO users (login, password_hash, password_salt)
-		VALUES ($1, $2, $3)
+		INSERT INTO users (login, password_hash, password_salt, registered_at)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id`
-	err := r.DB.QueryRowContext(ctx, query, user.Login, user.PasswordHash, user.PasswordSalt).Scan(&userID)
+	err := r.DB.QueryRowContext(ctx, query, user.Login, cred.PasswordHash, cred.PasswordSalt, user.RegisteredAt).Scan(&userID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to add user: %w", err)
 	}
@@ -252,30 +523,61 @@ func (r *RepoPG) AddUser(ctx context.Context, user *models.User) (int, error) {
 
 // GetUserByID returns a user from the database by its ID without password hash and salt.
 func (r *RepoPG) GetUserByID(ctx context.Context, userID int) (*models.User, error) {
-	query := `SELECT id, login FROM users WHERE id = $1`
-	row := r.DB.QueryRowContext(ctx, query, userID)
-
-	var u models.User
-	if err := row.Scan(&u.ID, &u.Login); err != nil {
+	query := `SELECT ` + pgUserColumns + ` FROM users u WHERE u.id = $1`
+	u, err := scanUser(r.DB.QueryRowContext(ctx, query, userID))
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, repository.NewErrNotFound()
 		}
 		return nil, fmt.Errorf("failed to get user by ID: %w", err)
 	}
-	return &u, nil
+	return u, nil
+}
+
+// GetUserByLogin returns a user from the database by its login without password hash and salt.
+func (r *RepoPG) GetUserByLogin(ctx context.Context, login string) (*models.User, error) {
+	query := `SELECT ` + pgUserColumns + ` FROM users u WHERE u.login = $1`
+	u, err := scanUser(r.DB.QueryRowContext(ctx, query, login))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.NewErrNotFound()
+		}
+		return nil, fmt.Errorf("failed to get user by login: %w", err)
+	}
+	return u, nil
 }
 
-// GetUserByIDWithCred returns a user by its login with credentials (password_hash and password_salt).
-func (r *RepoPG) GetUserByLoginWithCred(ctx context.Context, login string) (*models.User, error) {
-	query := `SELECT id, login, password_hash, password_salt FROM users WHERE login = $1`
+// GetUserByLoginWithCred returns a user by its login with its credentials (password_hash and password_salt).
+func (r *RepoPG) GetUserByLoginWithCred(ctx context.Context, login string) (*models.User, *models.Credentials, error) {
+	query := `SELECT ` + pgUserColumns + `, u.password_hash, u.password_salt FROM users u WHERE u.login = $1`
 	row := r.DB.QueryRowContext(ctx, query, login)
 
 	var u models.User
-	if err := row.Scan(&u.ID, &u.Login, &u.PasswordHash, &u.PasswordSalt); err != nil {
+	var cred models.Credentials
+	err := row.Scan(&u.ID, &u.Login, &u.DisplayName, &u.Bio, &u.AvatarURL, &u.RegisteredAt, &u.Banned,
+		&cred.PasswordHash, &cred.PasswordSalt)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, repository.NewErrNotFound()
+			return nil, nil, repository.NewErrNotFound()
 		}
-		return nil, fmt.Errorf("failed to get user by ID: %w", err)
+		return nil, nil, fmt.Errorf("failed to get user by login: %w", err)
 	}
-	return &u, nil
+	return &u, &cred, nil
+}
+
+// UpdateProfile updates user`s display name, bio and avatar URL.
+func (r *RepoPG) UpdateProfile(ctx context.Context, user *models.User) error {
+	query := `UPDATE users SET display_name = $2, bio = $3, avatar_url = $4 WHERE id = $1`
+	result, err := r.DB.ExecContext(ctx, query, user.ID, user.DisplayName, user.Bio, user.AvatarURL)
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return repository.NewErrNotFound()
+	}
+	return nil
 }
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// BlockCommenter blocks a user from commenting posts of an owner, blocking twice is not an error.
+func (r *RepoPG) BlockCommenter(ctx context.Context, ownerID, userID int, createdAt time.Time) error {
+	query := `
+		INSERT INTO comment_blocks (owner_id, user_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`
+	_, err := r.DB.ExecContext(ctx, query, ownerID, userID, createdAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation {
+			return repository.NewErrNotFound()
+		}
+		return fmt.Errorf("failed to block commenter: %w", err)
+	}
+	return nil
+}
+
+// UnblockCommenter removes a block.
+func (r *RepoPG) UnblockCommenter(ctx context.Context, ownerID, userID int) error {
+	query := `DELETE FROM comment_blocks WHERE owner_id = $1 AND user_id = $2`
+	if _, err := r.DB.ExecContext(ctx, query, ownerID, userID); err != nil {
+		return fmt.Errorf("failed to unblock commenter: %w", err)
+	}
+	return nil
+}
+
+// IsCommenterBlocked returns true if an owner blocked a user from commenting their posts.
+func (r *RepoPG) IsCommenterBlocked(ctx context.Context, ownerID, userID int) (bool, error) {
+	var blocked bool
+	query := `SELECT EXISTS (SELECT 1 FROM comment_blocks WHERE owner_id = $1 AND user_id = $2)`
+	if err := r.DB.QueryRowContext(ctx, query, ownerID, userID).Scan(&blocked); err != nil {
+		return false, fmt.Errorf("failed to check commenter block: %w", err)
+	}
+	return blocked, nil
+}
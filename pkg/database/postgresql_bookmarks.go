@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// AddBookmark adds a bookmark, bookmarking twice is not an error.
+func (r *RepoPG) AddBookmark(ctx context.Context, userID, postID int, createdAt time.Time) error {
+	query := `
+		INSERT INTO bookmarks (user_id, post_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`
+	_, err := r.DB.ExecContext(ctx, query, userID, postID, createdAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation {
+			return repository.NewErrNotFound()
+		}
+		return fmt.Errorf("failed to add bookmark: %w", err)
+	}
+	return nil
+}
+
+// RemoveBookmark removes a bookmark.
+func (r *RepoPG) RemoveBookmark(ctx context.Context, userID, postID int) error {
+	query := `DELETE FROM bookmarks WHERE user_id = $1 AND post_id = $2`
+	if _, err := r.DB.ExecContext(ctx, query, userID, postID); err != nil {
+		return fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+	return nil
+}
+
+// GetBookmarks returns bookmarked posts of a user, recently bookmarked first.
+func (r *RepoPG) GetBookmarks(ctx context.Context, userID int, page repository.PageArgs) (bookmarks []*models.Bookmark, hasNextPage bool, err error) {
+	//recent activity order is a descending order by a timestamp key
+	keyset := newPGKeyset(repository.OrderRecentActivity, "p", map[repository.Order]string{
+		repository.OrderRecentActivity: "b.created_at",
+	})
+	args := []any{page.Limit + 1, userID}
+	query := `
+		SELECT ` + pgPostColumns + `, b.created_at
+		FROM bookmarks b
+		JOIN posts p ON p.id = b.post_id
+		JOIN users u ON p.owner_id = u.id
+		WHERE b.user_id = $2 AND ` + keyset.where(page.After, &args) + `
+		ORDER BY ` + keyset.orderBy() + `
+		LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		b := &models.Bookmark{}
+		p, err := scanPost(rowWithExtra{row: rows, extra: []any{&b.CreatedAt}})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan bookmark: %w", err)
+		}
+		b.Post = *p
+		bookmarks = append(bookmarks, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+	if len(bookmarks) > page.Limit {
+		hasNextPage = true
+		bookmarks = bookmarks[:page.Limit]
+	}
+	return bookmarks, hasNextPage, nil
+}
+
+// IsBookmarked returns true if a user bookmarked a post.
+func (r *RepoPG) IsBookmarked(ctx context.Context, userID, postID int) (bool, error) {
+	var bookmarked bool
+	query := `SELECT EXISTS (SELECT 1 FROM bookmarks WHERE user_id = $1 AND post_id = $2)`
+	if err := r.DB.QueryRowContext(ctx, query, userID, postID).Scan(&bookmarked); err != nil {
+		return false, fmt.Errorf("failed to check bookmark: %w", err)
+	}
+	return bookmarked, nil
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// pgForeignKeyViolation is a PostgreSQL error code of a foreign key violation.
+const pgForeignKeyViolation = "23503"
+
+// Follow adds a follow, following twice is not an error.
+func (r *RepoPG) Follow(ctx context.Context, followerID, followeeID int, createdAt time.Time) error {
+	query := `
+		INSERT INTO follows (follower_id, followee_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`
+	_, err := r.DB.ExecContext(ctx, query, followerID, followeeID, createdAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgForeignKeyViolation {
+			return repository.NewErrNotFound()
+		}
+		return fmt.Errorf("failed to follow: %w", err)
+	}
+	return nil
+}
+
+// Unfollow removes a follow.
+func (r *RepoPG) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	query := `DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`
+	if _, err := r.DB.ExecContext(ctx, query, followerID, followeeID); err != nil {
+		return fmt.Errorf("failed to unfollow: %w", err)
+	}
+	return nil
+}
+
+// IsFollowing returns true if a follower follows a followee.
+func (r *RepoPG) IsFollowing(ctx context.Context, followerID, followeeID int) (bool, error) {
+	var following bool
+	query := `SELECT EXISTS (SELECT 1 FROM follows WHERE follower_id = $1 AND followee_id = $2)`
+	if err := r.DB.QueryRowContext(ctx, query, followerID, followeeID).Scan(&following); err != nil {
+		return false, fmt.Errorf("failed to check follow: %w", err)
+	}
+	return following, nil
+}
+
+// GetFollowers returns followers of a user, recently followed first.
+func (r *RepoPG) GetFollowers(ctx context.Context, userID int, page repository.PageArgs) (followers []*models.Follow, hasNextPage bool, err error) {
+	return r.getFollows(ctx, "followee_id", "follower_id", userID, page)
+}
+
+// GetFollowing returns users followed by a user, recently followed first.
+func (r *RepoPG) GetFollowing(ctx context.Context, userID int, page repository.PageArgs) (following []*models.Follow, hasNextPage bool, err error) {
+	return r.getFollows(ctx, "follower_id", "followee_id", userID, page)
+}
+
+// getFollows returns users from "userColumn" of follows with "byColumn" equal to userID, recently followed first.
+func (r *RepoPG) getFollows(ctx context.Context, byColumn, userColumn string, userID int, page repository.PageArgs) (follows []*models.Follow, hasNextPage bool, err error) {
+	//recent activity order is a descending order by a timestamp key
+	keyset := newPGKeyset(repository.OrderRecentActivity, "u", map[repository.Order]string{
+		repository.OrderRecentActivity: "f.created_at",
+	})
+	args := []any{page.Limit + 1, userID}
+	query := `
+		SELECT ` + pgUserColumns + `, f.created_at
+		FROM follows f
+		JOIN users u ON u.id = f.` + userColumn + `
+		WHERE f.` + byColumn + ` = $2 AND ` + keyset.where(page.After, &args) + `
+		ORDER BY ` + keyset.orderBy() + `
+		LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get follows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var f models.Follow
+		u := &f.User
+		err := rows.Scan(&u.ID, &u.Login, &u.DisplayName, &u.Bio, &u.AvatarURL, &u.RegisteredAt, &u.Banned, &f.CreatedAt)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan follow: %w", err)
+		}
+		follows = append(follows, &f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+	if len(follows) > page.Limit {
+		hasNextPage = true
+		follows = follows[:page.Limit]
+	}
+	return follows, hasNextPage, nil
+}
+
+// GetFeed returns posts of users followed by a user, newest first.
+// Feed is built on read using the posts owner index.
+func (r *RepoPG) GetFeed(ctx context.Context, userID int, page repository.PageArgs) (posts []*models.Post, hasNextPage bool, err error) {
+	keyset := newPGKeyset(repository.OrderNewest, "p", nil)
+	args := []any{page.Limit + 1, userID}
+	query := `
+		SELECT ` + pgPostColumns + `
+		FROM posts p
+		JOIN users u ON p.owner_id = u.id
+		WHERE p.owner_id IN (SELECT f.followee_id FROM follows f WHERE f.follower_id = $2)
+		  AND p.status = 'PUBLISHED' AND ` + keyset.where(page.After, &args) + `
+		ORDER BY ` + keyset.orderBy() + `
+		LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get feed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p, err := scanPost(rows)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan post: %w", err)
+		}
+		posts = append(posts, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+	if len(posts) > page.Limit {
+		hasNextPage = true
+		posts = posts[:page.Limit]
+	}
+	return posts, hasNextPage, nil
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"time"
+)
+
+// ReserveIdempotencyKey saves a record without a result unless the user has an unexpired record with its key,
+// expired records of the user are deleted first.
+func (r *RepoPG) ReserveIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord, now time.Time) (*models.IdempotencyRecord, error) {
+	query := `DELETE FROM idempotency_keys WHERE user_id = $1 AND expires_at <= $2`
+	if _, err := r.DB.ExecContext(ctx, query, record.UserID, now); err != nil {
+		return nil, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	query = `
+		INSERT INTO idempotency_keys (user_id, key, fingerprint, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING`
+	res, err := r.DB.ExecContext(ctx, query, record.UserID, record.Key, record.Fingerprint, record.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	reserved, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if reserved == 1 {
+		return nil, nil
+	}
+
+	stored := &models.IdempotencyRecord{UserID: record.UserID, Key: record.Key}
+	query = `SELECT fingerprint, result, expires_at FROM idempotency_keys WHERE user_id = $1 AND key = $2`
+	err = r.DB.QueryRowContext(ctx, query, record.UserID, record.Key).Scan(&stored.Fingerprint, &stored.Result, &stored.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.NewErrConflict()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return stored, nil
+}
+
+// SaveIdempotencyResult saves a result of a reserved record and its new expiration time.
+func (r *RepoPG) SaveIdempotencyResult(ctx context.Context, record *models.IdempotencyRecord) error {
+	query := `UPDATE idempotency_keys SET result = $3, expires_at = $4 WHERE user_id = $1 AND key = $2`
+	if _, err := r.DB.ExecContext(ctx, query, record.UserID, record.Key, record.Result, record.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to save idempotency result: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey removes a user`s record.
+func (r *RepoPG) ReleaseIdempotencyKey(ctx context.Context, userID int, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2`
+	if _, err := r.DB.ExecContext(ctx, query, userID, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
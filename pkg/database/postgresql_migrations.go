@@ -0,0 +1,349 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// pgMigrationsLockID is an advisory lock key which serializes migrations of several app replicas.
+const pgMigrationsLockID = 7531
+
+// pgMigrations are schema changes applied after the base tables creation, each one only once and in order.
+// Applied migrations must never be edited, add a new one instead.
+var pgMigrations = []string{
+	// 1: comments counters and activity times used for sorting.
+	`
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS comments_count INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+	ALTER TABLE comments ADD COLUMN IF NOT EXISTS descendants_count INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE comments ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+	WITH RECURSIVE paths AS (
+		SELECT c.id AS ancestor_id, c.id AS descendant_id, c.created_at FROM comments c
+		UNION ALL
+		SELECT p.ancestor_id, c.id, c.created_at FROM paths p JOIN comments c ON c.parent_id = p.descendant_id
+	)
+	UPDATE comments SET descendants_count = s.cnt - 1, last_activity_at = s.last
+	FROM (SELECT ancestor_id, COUNT(*) AS cnt, MAX(created_at) AS last FROM paths GROUP BY ancestor_id) s
+	WHERE comments.id = s.ancestor_id;
+
+	UPDATE posts SET comments_count = s.cnt, last_activity_at = s.last
+	FROM (
+		SELECT post_id, SUM(descendants_count + 1) AS cnt, MAX(last_activity_at) AS last
+		FROM comments WHERE parent_id = 0 GROUP BY post_id
+	) s
+	WHERE posts.id = s.post_id;
+
+	CREATE INDEX IF NOT EXISTS posts_comments_count_idx ON posts (comments_count, id);
+	CREATE INDEX IF NOT EXISTS posts_last_activity_at_idx ON posts (last_activity_at, id);
+	CREATE INDEX IF NOT EXISTS comments_post_id_idx ON comments (post_id, parent_id, id);
+	CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id, id);
+	`,
+	// 2: posts timestamps. Creation time of existing posts is unknown, so the earliest comment time is used.
+	`
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ;
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS last_comment_at TIMESTAMPTZ;
+
+	UPDATE posts SET created_at = s.first, last_comment_at = s.last
+	FROM (
+		SELECT post_id, MIN(created_at) AS first, MAX(last_activity_at) AS last
+		FROM comments WHERE parent_id = 0 GROUP BY post_id
+	) s
+	WHERE posts.id = s.post_id;
+
+	UPDATE posts SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+	UPDATE posts SET updated_at = created_at,
+	                 last_activity_at = COALESCE(last_comment_at, created_at);
+
+	ALTER TABLE posts ALTER COLUMN created_at SET NOT NULL;
+	ALTER TABLE posts ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+	ALTER TABLE posts ALTER COLUMN updated_at SET NOT NULL;
+	ALTER TABLE posts ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP;
+	`,
+	// 3: direct replies counter.
+	`
+	ALTER TABLE comments ADD COLUMN IF NOT EXISTS replies_count INTEGER NOT NULL DEFAULT 0;
+
+	UPDATE comments SET replies_count = s.cnt
+	FROM (SELECT parent_id, COUNT(*) AS cnt FROM comments WHERE parent_id <> 0 GROUP BY parent_id) s
+	WHERE comments.id = s.parent_id;
+	`,
+	// 4: full-text search. "simple" configuration is used, because texts are in different languages.
+	`
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', text), 'B')) STORED;
+	ALTER TABLE comments ADD COLUMN IF NOT EXISTS search_vector tsvector
+		GENERATED ALWAYS AS (to_tsvector('simple', text)) STORED;
+
+	CREATE INDEX IF NOT EXISTS posts_search_vector_idx ON posts USING GIN (search_vector);
+	CREATE INDEX IF NOT EXISTS comments_search_vector_idx ON comments USING GIN (search_vector);
+	`,
+	// 5: post tags.
+	`
+	CREATE TABLE IF NOT EXISTS post_tags (
+		post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+		tag VARCHAR(64) NOT NULL,
+		PRIMARY KEY (post_id, tag)
+	);
+
+	CREATE INDEX IF NOT EXISTS post_tags_tag_idx ON post_tags (tag, post_id);
+	`,
+	// 6: user`s posts and comments.
+	`
+	CREATE INDEX IF NOT EXISTS posts_owner_id_idx ON posts (owner_id, id);
+	CREATE INDEX IF NOT EXISTS comments_owner_id_idx ON comments (owner_id, id);
+	`,
+	// 7: user profiles. Registration time of existing users is unknown, their first post or comment time is used.
+	`
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS display_name VARCHAR(255) NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS bio TEXT NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url TEXT NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS registered_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+	UPDATE users SET registered_at = s.first
+	FROM (
+		SELECT owner_id, MIN(created_at) AS first
+		FROM (SELECT owner_id, created_at FROM posts UNION ALL SELECT owner_id, created_at FROM comments) c
+		GROUP BY owner_id
+	) s
+	WHERE users.id = s.owner_id AND s.first < users.registered_at;
+	`,
+	// 8: follows.
+	`
+	CREATE TABLE IF NOT EXISTS follows (
+		follower_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		followee_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (follower_id, followee_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS follows_follower_idx ON follows (follower_id, created_at, followee_id);
+	CREATE INDEX IF NOT EXISTS follows_followee_idx ON follows (followee_id, created_at, follower_id);
+	`,
+	// 9: reactions to posts and comments, one per user, with aggregated counters
+	`
+	CREATE TABLE IF NOT EXISTS post_reactions (
+		post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		kind VARCHAR(16) NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (post_id, user_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS comment_reactions (
+		comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		kind VARCHAR(16) NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (comment_id, user_id)
+	);
+
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS reactions JSONB NOT NULL DEFAULT '{}';
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS score INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE comments ADD COLUMN IF NOT EXISTS reactions JSONB NOT NULL DEFAULT '{}';
+	ALTER TABLE comments ADD COLUMN IF NOT EXISTS score INTEGER NOT NULL DEFAULT 0;
+
+	CREATE INDEX IF NOT EXISTS posts_score_idx ON posts (score, id);
+	CREATE INDEX IF NOT EXISTS comments_post_score_idx ON comments (post_id, score, id) WHERE parent_id = 0;
+	`,
+	// 10: users mentioned in comments
+	`
+	CREATE TABLE IF NOT EXISTS comment_mentions (
+		comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		position INTEGER NOT NULL,
+		PRIMARY KEY (comment_id, user_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS comment_mentions_user_idx ON comment_mentions (user_id, comment_id);
+	`,
+	// 11: notifications about comments, replies and mentions
+	`
+	CREATE TABLE IF NOT EXISTS notifications (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		type VARCHAR(16) NOT NULL,
+		comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		read BOOLEAN NOT NULL DEFAULT FALSE
+	);
+
+	CREATE INDEX IF NOT EXISTS notifications_user_idx ON notifications (user_id, id);
+	CREATE INDEX IF NOT EXISTS notifications_unread_idx ON notifications (user_id, id) WHERE NOT read;
+	`,
+	// 12: bookmarks
+	`
+	CREATE TABLE IF NOT EXISTS bookmarks (
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (user_id, post_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS bookmarks_user_idx ON bookmarks (user_id, created_at, post_id);
+	`,
+	// 13: drafts and scheduled posts, existing posts are published at creation
+	`
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'PUBLISHED';
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+	UPDATE posts SET publish_at = created_at WHERE publish_at IS NULL AND status = 'PUBLISHED';
+
+	CREATE INDEX IF NOT EXISTS posts_scheduled_idx ON posts (publish_at) WHERE status = 'SCHEDULED';
+	CREATE INDEX IF NOT EXISTS posts_unpublished_idx ON posts (owner_id, id) WHERE status <> 'PUBLISHED';
+	`,
+	// 14: text formats of posts and comments
+	`
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS text_format VARCHAR(16) NOT NULL DEFAULT 'PLAIN';
+	ALTER TABLE comments ADD COLUMN IF NOT EXISTS text_format VARCHAR(16) NOT NULL DEFAULT 'PLAIN';
+	`,
+	// 15: content held for review by moderation, held comments are saved to comments when approved
+	`
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS moderation_reasons TEXT[] NOT NULL DEFAULT '{}';
+	CREATE INDEX IF NOT EXISTS posts_held_idx ON posts (id) WHERE status = 'HELD';
+
+	CREATE TABLE IF NOT EXISTS held_comments (
+		id SERIAL PRIMARY KEY,
+		owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		post_id INTEGER NOT NULL DEFAULT 0,
+		parent_id INTEGER NOT NULL DEFAULT 0,
+		text TEXT NOT NULL,
+		text_format VARCHAR(16) NOT NULL DEFAULT 'PLAIN',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		reasons TEXT[] NOT NULL DEFAULT '{}'
+	);
+	`,
+	// 16: reports, hidden content and banned users
+	`
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS hidden BOOLEAN NOT NULL DEFAULT FALSE;
+	ALTER TABLE comments ADD COLUMN IF NOT EXISTS hidden BOOLEAN NOT NULL DEFAULT FALSE;
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS banned BOOLEAN NOT NULL DEFAULT FALSE;
+	CREATE TABLE IF NOT EXISTS report_cases (
+		id SERIAL PRIMARY KEY,
+		target_type VARCHAR(16) NOT NULL,
+		target_id INTEGER NOT NULL,
+		author_id INTEGER NOT NULL,
+		report_count INTEGER NOT NULL DEFAULT 0,
+		last_reported_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (target_type, target_id)
+	);
+	CREATE INDEX IF NOT EXISTS report_cases_count_idx ON report_cases (report_count DESC, id DESC);
+	CREATE TABLE IF NOT EXISTS reports (
+		case_id INTEGER NOT NULL REFERENCES report_cases(id) ON DELETE CASCADE,
+		reporter_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		reason VARCHAR(16) NOT NULL,
+		details TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (case_id, reporter_id)
+	);
+	`,
+	// 17: comment policies of posts and commenters blocked by post owners
+	`
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS comment_policy VARCHAR(32) NOT NULL DEFAULT 'OPEN';
+	ALTER TABLE posts ADD COLUMN IF NOT EXISTS comment_policy_days INTEGER NOT NULL DEFAULT 0;
+	UPDATE posts SET comment_policy = 'CLOSED' WHERE NOT commentsallowed;
+
+	CREATE TABLE IF NOT EXISTS comment_blocks (
+		owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (owner_id, user_id)
+	);
+	`,
+	// 18: comments pinned by post owners
+	`
+	CREATE TABLE IF NOT EXISTS pinned_comments (
+		post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+		comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+		pinned_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (post_id, comment_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_pinned_comments_comment_id ON pinned_comments(comment_id);
+	`,
+	// 19: depths of comments in their trees
+	`
+	ALTER TABLE comments ADD COLUMN IF NOT EXISTS depth INTEGER NOT NULL DEFAULT 0;
+	WITH RECURSIVE tree AS (
+		SELECT id, 0 AS depth FROM comments WHERE parent_id = 0
+		UNION ALL
+		SELECT c.id, t.depth + 1 FROM comments c JOIN tree t ON c.parent_id = t.id
+	)
+	UPDATE comments SET depth = tree.depth FROM tree WHERE comments.id = tree.id AND tree.depth > 0;
+	`,
+	// 20: results of create mutations by clientMutationId of their users
+	`
+	CREATE TABLE IF NOT EXISTS idempotency_keys (
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		key VARCHAR(255) NOT NULL,
+		fingerprint TEXT NOT NULL,
+		result BYTEA,
+		expires_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (user_id, key)
+	);
+	`,
+	// 21: posts of threads of held comments, so post owners can review comments of their posts
+	`
+	ALTER TABLE held_comments ADD COLUMN IF NOT EXISTS root_post_id INTEGER NOT NULL DEFAULT 0;
+	UPDATE held_comments SET root_post_id = post_id WHERE post_id <> 0;
+	WITH RECURSIVE ancestors AS (
+		SELECT h.id AS held_id, c.parent_id, c.post_id FROM held_comments h JOIN comments c ON c.id = h.parent_id WHERE h.post_id = 0
+		UNION ALL
+		SELECT a.held_id, c.parent_id, c.post_id FROM comments c JOIN ancestors a ON c.id = a.parent_id
+	)
+	UPDATE held_comments h SET root_post_id = a.post_id FROM ancestors a WHERE a.held_id = h.id AND a.parent_id = 0;
+	CREATE INDEX IF NOT EXISTS held_comments_root_post_id_idx ON held_comments (root_post_id, id);
+	`,
+	// 22: index of pinned comments named like the others
+	`
+	ALTER INDEX IF EXISTS idx_pinned_comments_comment_id RENAME TO pinned_comments_comment_id_idx;
+	`,
+}
+
+// migrate applies pgMigrations which were not applied yet.
+func (r *RepoPG) migrate(ctx context.Context) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);`
+	if _, err := r.DB.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	for i, migration := range pgMigrations {
+		if err := r.applyMigration(ctx, i+1, migration); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration applies a single migration in a transaction if it was not applied yet.
+func (r *RepoPG) applyMigration(ctx context.Context, version int, migration string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, pgMigrationsLockID); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+
+	var applied bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, version).Scan(&applied)
+	if err != nil {
+		return fmt.Errorf("failed to check migration: %w", err)
+	}
+	if applied {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, migration); err != nil {
+		return fmt.Errorf("failed to execute migration: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+		return fmt.Errorf("failed to save migration version: %w", err)
+	}
+	return tx.Commit()
+}
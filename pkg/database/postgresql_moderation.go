@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+
+	"github.com/lib/pq"
+)
+
+// pgHeldCommentColumns are columns selected by scanHeldComment, "h" is held_comments and "u" is users (owners) alias.
+const pgHeldCommentColumns = `h.id, h.root_post_id, h.post_id, h.parent_id, h.text, h.text_format, h.created_at, h.reasons,
+		       ` + pgUserColumns
+
+// scanHeldComment scans a row selected with pgHeldCommentColumns.
+func scanHeldComment(row interface{ Scan(dest ...any) error }) (*models.HeldComment, error) {
+	var h models.HeldComment
+	var reasons pq.StringArray
+	c := &h.Comment
+	err := row.Scan(&h.ID, &h.RootPostID, &c.PostID, &c.ParentID, &c.Text, &c.TextFormat, &c.CreatedAt, &reasons,
+		&c.Owner.ID, &c.Owner.Login, &c.Owner.DisplayName, &c.Owner.Bio, &c.Owner.AvatarURL, &c.Owner.RegisteredAt, &c.Owner.Banned)
+	if err != nil {
+		return nil, err
+	}
+	if len(reasons) > 0 {
+		h.Reasons = reasons
+	}
+	return &h, nil
+}
+
+// HoldComment saves a comment held for review.
+func (r *RepoPG) HoldComment(ctx context.Context, held *models.HeldComment) (int, error) {
+	var id int
+	c := held.Comment
+	query := `
+		INSERT INTO held_comments (owner_id, root_post_id, post_id, parent_id, text, text_format, created_at, reasons)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`
+	err := r.DB.QueryRowContext(ctx, query, c.Owner.ID, held.RootPostID, c.PostID, c.ParentID, c.Text, c.TextFormat, c.CreatedAt,
+		pq.Array(held.Reasons)).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to hold comment: %w", err)
+	}
+	return id, nil
+}
+
+// GetHeldComments returns held comments, oldest first.
+func (r *RepoPG) GetHeldComments(ctx context.Context, rootPostID int, page repository.PageArgs) (comments []*models.HeldComment, hasNextPage bool, err error) {
+	keyset := newPGKeyset(repository.OrderOldest, "h", nil)
+	args := []any{page.Limit + 1, rootPostID}
+	query := `
+		SELECT ` + pgHeldCommentColumns + `
+		FROM held_comments h
+		JOIN users u ON h.owner_id = u.id
+		WHERE ($2 = 0 OR h.root_post_id = $2) AND ` + keyset.where(page.After, &args) + `
+		ORDER BY ` + keyset.orderBy() + `
+		LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get held comments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		h, err := scanHeldComment(rows)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan held comment: %w", err)
+		}
+		comments = append(comments, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+	if len(comments) > page.Limit {
+		hasNextPage = true
+		comments = comments[:page.Limit]
+	}
+	return comments, hasNextPage, nil
+}
+
+// GetHeldComment returns a held comment.
+func (r *RepoPG) GetHeldComment(ctx context.Context, heldID int) (*models.HeldComment, error) {
+	query := `
+		SELECT ` + pgHeldCommentColumns + `
+		FROM held_comments h
+		JOIN users u ON h.owner_id = u.id
+		WHERE h.id = $1`
+	h, err := scanHeldComment(r.DB.QueryRowContext(ctx, query, heldID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.NewErrNotFound()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held comment: %w", err)
+	}
+	return h, nil
+}
+
+// TakeHeldComment removes a held comment and returns it.
+func (r *RepoPG) TakeHeldComment(ctx context.Context, heldID int) (*models.HeldComment, error) {
+	query := `
+		DELETE FROM held_comments h
+		USING users u
+		WHERE h.id = $1 AND u.id = h.owner_id
+		RETURNING ` + pgHeldCommentColumns
+	h, err := scanHeldComment(r.DB.QueryRowContext(ctx, query, heldID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.NewErrNotFound()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to take held comment: %w", err)
+	}
+	return h, nil
+}
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// pgNotificationsChannel is a channel IDs of new notifications are sent to by NOTIFY.
+// Every process listens to it to deliver notifications added by all replicas to its subscribers.
+const pgNotificationsChannel = "notifications"
+
+// pgListenerPingInterval is how often an idle notifications listener checks its connection.
+const pgListenerPingInterval = 90 * time.Second
+
+// rowWithExtra is a row scanning extra columns after the ones scanned by a scan function (e.g. scanComment).
+type rowWithExtra struct {
+	row   interface{ Scan(dest ...any) error }
+	extra []any
+}
+
+// Scan scans a row into dest and then into extra destinations.
+func (r rowWithExtra) Scan(dest ...any) error {
+	return r.row.Scan(append(dest, r.extra...)...)
+}
+
+// AddNotifications saves notifications in a transaction, sets their IDs and sends them to pgNotificationsChannel.
+// Listeners get them when the transaction is committed.
+func (r *RepoPG) AddNotifications(ctx context.Context, notifications []*models.Notification) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO notifications (user_id, type, comment_id, created_at, read)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+	for _, n := range notifications {
+		err = tx.QueryRowContext(ctx, query, n.UserID, n.Type, n.Comment.ID, n.CreatedAt, n.Read).Scan(&n.ID)
+		if err != nil {
+			return fmt.Errorf("failed to add notification: %w", err)
+		}
+		if _, err = tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, pgNotificationsChannel, strconv.Itoa(n.ID)); err != nil {
+			return fmt.Errorf("failed to send notification: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit notifications: %w", err)
+	}
+	return nil
+}
+
+// GetNotifications returns notifications of a user with their comments, newest first.
+func (r *RepoPG) GetNotifications(ctx context.Context, userID int, unreadOnly bool, page repository.PageArgs) (notifications []*models.Notification, hasNextPage bool, err error) {
+	keyset := newPGKeyset(repository.OrderNewest, "n", nil)
+	args := []any{page.Limit + 1, userID, unreadOnly}
+	query := `
+		SELECT ` + pgCommentColumns + `, n.id, n.type, n.created_at, n.read
+		FROM notifications n
+		JOIN comments c ON n.comment_id = c.id
+		JOIN users u ON c.owner_id = u.id
+		WHERE n.user_id = $2 AND (NOT $3 OR NOT n.read) AND ` + keyset.where(page.After, &args) + `
+		ORDER BY ` + keyset.orderBy() + `
+		LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		n := &models.Notification{UserID: userID}
+		c, err := scanComment(rowWithExtra{row: rows, extra: []any{&n.ID, &n.Type, &n.CreatedAt, &n.Read}})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		n.Comment = *c
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+	if len(notifications) > page.Limit {
+		hasNextPage = true
+		notifications = notifications[:page.Limit]
+	}
+	return notifications, hasNextPage, nil
+}
+
+// getNotificationByID returns a notification with its comment.
+func (r *RepoPG) getNotificationByID(ctx context.Context, notificationID int) (*models.Notification, error) {
+	query := `
+		SELECT ` + pgCommentColumns + `, n.id, n.user_id, n.type, n.created_at, n.read
+		FROM notifications n
+		JOIN comments c ON n.comment_id = c.id
+		JOIN users u ON c.owner_id = u.id
+		WHERE n.id = $1`
+	n := &models.Notification{}
+	c, err := scanComment(rowWithExtra{
+		row:   r.DB.QueryRowContext(ctx, query, notificationID),
+		extra: []any{&n.ID, &n.UserID, &n.Type, &n.CreatedAt, &n.Read},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.NewErrNotFound()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+	n.Comment = *c
+	return n, nil
+}
+
+// WatchNotifications passes notifications added by any process to f until ctx is done.
+// It listens on a separate connection opened with connString, because database/sql pools connections.
+// Notifications sent while the connection is lost are not passed, they are still returned by GetNotifications.
+// Errors are passed to onError and do not stop watching.
+func (r *RepoPG) WatchNotifications(ctx context.Context, connString string, f func(*models.Notification), onError func(error)) {
+	listener := pq.NewListener(connString, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			onError(fmt.Errorf("notifications listener failed: %w", err))
+		}
+	})
+	defer listener.Close()
+	if err := listener.Listen(pgNotificationsChannel); err != nil {
+		onError(fmt.Errorf("failed to listen to notifications: %w", err))
+	}
+
+	for {
+		var event *pq.Notification
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pgListenerPingInterval):
+			if err := listener.Ping(); err != nil {
+				onError(fmt.Errorf("failed to ping notifications listener: %w", err))
+			}
+			continue
+		case event = <-listener.Notify:
+		}
+		if event == nil {
+			//the connection was lost and reestablished
+			continue
+		}
+		id, err := strconv.Atoi(event.Extra)
+		if err != nil {
+			onError(fmt.Errorf("invalid notification id: %w", err))
+			continue
+		}
+		n, err := r.getNotificationByID(ctx, id)
+		if err != nil {
+			onError(fmt.Errorf("failed to get notification by id: %w", err))
+			continue
+		}
+		f(n)
+	}
+}
+
+// MarkNotificationsRead marks notifications of a user read, all of them if ids is nil.
+func (r *RepoPG) MarkNotificationsRead(ctx context.Context, userID int, ids []int) error {
+	var idsArray any
+	if ids != nil {
+		ids64 := make([]int64, len(ids))
+		for i, id := range ids {
+			ids64[i] = int64(id)
+		}
+		idsArray = pq.Array(ids64)
+	}
+	query := `
+		UPDATE notifications SET read = TRUE
+		WHERE user_id = $1 AND NOT read AND ($2::INTEGER[] IS NULL OR id = ANY($2))`
+	if _, err := r.DB.ExecContext(ctx, query, userID, idsArray); err != nil {
+		return fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return nil
+}
+
+// CountUnreadNotifications returns an amount of unread notifications of a user.
+func (r *RepoPG) CountUnreadNotifications(ctx context.Context, userID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND NOT read`
+	if err := r.DB.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
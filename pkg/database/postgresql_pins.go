@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"time"
+)
+
+// PinComment pins a top-level comment of a post, pinning a pinned comment is not an error.
+// The post row is locked, so concurrent pins can`t exceed maxPinned.
+func (r *RepoPG) PinComment(ctx context.Context, postID, commentID int, pinnedAt time.Time, maxPinned int) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM posts WHERE id = $1 FOR UPDATE`, postID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.NewErrNotFound()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock post: %w", err)
+	}
+
+	var isTopLevel, pinned bool
+	var count int
+	query := `
+		SELECT
+			EXISTS (SELECT 1 FROM comments WHERE id = $2 AND post_id = $1 AND parent_id = 0),
+			EXISTS (SELECT 1 FROM pinned_comments WHERE post_id = $1 AND comment_id = $2),
+			(SELECT COUNT(*) FROM pinned_comments WHERE post_id = $1)`
+	if err := tx.QueryRowContext(ctx, query, postID, commentID).Scan(&isTopLevel, &pinned, &count); err != nil {
+		return fmt.Errorf("failed to check pinned comments: %w", err)
+	}
+	if !isTopLevel {
+		return repository.NewErrNotFound()
+	}
+	if pinned {
+		return nil
+	}
+	if count >= maxPinned {
+		return repository.NewErrConflict()
+	}
+
+	query = `INSERT INTO pinned_comments (post_id, comment_id, pinned_at) VALUES ($1, $2, $3)`
+	if _, err := tx.ExecContext(ctx, query, postID, commentID, pinnedAt); err != nil {
+		return fmt.Errorf("failed to pin comment: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit pin: %w", err)
+	}
+	return nil
+}
+
+// UnpinComment unpins a comment of a post.
+func (r *RepoPG) UnpinComment(ctx context.Context, postID, commentID int) error {
+	query := `DELETE FROM pinned_comments WHERE post_id = $1 AND comment_id = $2`
+	if _, err := r.DB.ExecContext(ctx, query, postID, commentID); err != nil {
+		return fmt.Errorf("failed to unpin comment: %w", err)
+	}
+	return nil
+}
+
+// GetPinnedComments returns pinned comments of a post, earliest pinned first.
+func (r *RepoPG) GetPinnedComments(ctx context.Context, postID int) ([]*models.Comment, error) {
+	query := `
+		SELECT ` + pgCommentColumns + `
+		FROM pinned_comments pc
+		JOIN comments c ON pc.comment_id = c.id
+		JOIN users u ON c.owner_id = u.id
+		WHERE pc.post_id = $1
+		ORDER BY pc.pinned_at, c.id`
+	rows, err := r.DB.QueryContext(ctx, query, postID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pinned comments: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*models.Comment
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return comments, nil
+}
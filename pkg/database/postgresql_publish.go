@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"time"
+)
+
+// PublishPost publishes a draft or a scheduled post.
+func (r *RepoPG) PublishPost(ctx context.Context, postID int, publishedAt time.Time) error {
+	query := `
+		UPDATE posts SET status = 'PUBLISHED', publish_at = $2, last_activity_at = GREATEST(last_activity_at, $2)
+		WHERE id = $1 AND status NOT IN ('PUBLISHED', 'HELD')`
+	result, err := r.DB.ExecContext(ctx, query, postID, publishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to publish post: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		//already published, held or doesn`t exist
+		var status models.PostStatus
+		err = r.DB.QueryRowContext(ctx, `SELECT status FROM posts WHERE id = $1`, postID).Scan(&status)
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.NewErrNotFound()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get post status: %w", err)
+		}
+		if status == models.PostHeld {
+			return repository.NewErrConflict()
+		}
+	}
+	return nil
+}
+
+// SchedulePost sets a publish time of a draft or a scheduled post.
+func (r *RepoPG) SchedulePost(ctx context.Context, postID int, publishAt time.Time) error {
+	query := `UPDATE posts SET status = 'SCHEDULED', publish_at = $2 WHERE id = $1 AND status NOT IN ('PUBLISHED', 'HELD')`
+	result, err := r.DB.ExecContext(ctx, query, postID, publishAt)
+	if err != nil {
+		return fmt.Errorf("failed to schedule post: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		//already published, held or doesn`t exist
+		if err := r.checkPostExists(ctx, postID); err != nil {
+			return err
+		}
+		return repository.NewErrConflict()
+	}
+	return nil
+}
+
+// PublishDuePosts publishes scheduled posts which are due.
+// Concurrent updates of the same row wait for each other and recheck the status, so a post is published by one of them.
+func (r *RepoPG) PublishDuePosts(ctx context.Context, now time.Time) ([]int, error) {
+	query := `
+		UPDATE posts SET status = 'PUBLISHED', last_activity_at = GREATEST(last_activity_at, publish_at)
+		WHERE status = 'SCHEDULED' AND publish_at <= $1
+		RETURNING id`
+	rows, err := r.DB.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to publish due posts: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan post id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return ids, nil
+}
+
+// ReleaseHeldPost makes a held post a draft.
+func (r *RepoPG) ReleaseHeldPost(ctx context.Context, postID int) error {
+	query := `
+		UPDATE posts SET status = 'DRAFT', publish_at = NULL, moderation_reasons = '{}'
+		WHERE id = $1 AND status = 'HELD'`
+	result, err := r.DB.ExecContext(ctx, query, postID)
+	if err != nil {
+		return fmt.Errorf("failed to release held post: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		//not held or doesn`t exist
+		if err := r.checkPostExists(ctx, postID); err != nil {
+			return err
+		}
+		return repository.NewErrConflict()
+	}
+	return nil
+}
+
+// checkPostExists returns repository.NewErrNotFound if a post doesn`t exist.
+func (r *RepoPG) checkPostExists(ctx context.Context, postID int) error {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM posts WHERE id = $1)`
+	if err := r.DB.QueryRowContext(ctx, query, postID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check post: %w", err)
+	}
+	if !exists {
+		return repository.NewErrNotFound()
+	}
+	return nil
+}
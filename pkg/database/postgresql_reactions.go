@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+)
+
+// pgReactionTables returns a table of targets, a table of their reactions and its target ID column.
+func pgReactionTables(targetType repository.TargetType) (targets, reactions, idColumn string, err error) {
+	switch targetType {
+	case repository.TargetPost:
+		return "posts", "post_reactions", "post_id", nil
+	case repository.TargetComment:
+		return "comments", "comment_reactions", "comment_id", nil
+	default:
+		return "", "", "", fmt.Errorf("unknown target type %q", targetType)
+	}
+}
+
+// React sets user`s reaction to a post or a comment replacing a previous one.
+func (r *RepoPG) React(ctx context.Context, target repository.Target, userID int, kind models.ReactionKind) (models.Reactions, error) {
+	return r.setReaction(ctx, target, userID, kind)
+}
+
+// Unreact removes user`s reaction to a post or a comment.
+func (r *RepoPG) Unreact(ctx context.Context, target repository.Target, userID int) (models.Reactions, error) {
+	return r.setReaction(ctx, target, userID, "")
+}
+
+// setReaction replaces user`s reaction to a target with a given kind, empty kind removes the reaction.
+// Target`s counters and score are updated in the same transaction, target row is locked to serialize concurrent reactions.
+func (r *RepoPG) setReaction(ctx context.Context, target repository.Target, userID int, kind models.ReactionKind) (models.Reactions, error) {
+	targets, reactionsTable, idColumn, err := pgReactionTables(target.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var raw []byte
+	query := `SELECT reactions FROM ` + targets + ` WHERE id = $1 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, target.ID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.NewErrNotFound()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock reaction target: %w", err)
+	}
+	var reactions models.Reactions
+	if err := json.Unmarshal(raw, &reactions); err != nil {
+		return nil, fmt.Errorf("invalid reactions: %w", err)
+	}
+
+	var old models.ReactionKind
+	query = `SELECT kind FROM ` + reactionsTable + ` WHERE ` + idColumn + ` = $1 AND user_id = $2`
+	err = tx.QueryRowContext(ctx, query, target.ID, userID).Scan(&old)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get reaction: %w", err)
+	}
+	if old == kind {
+		return reactions, nil
+	}
+
+	if kind == "" {
+		query = `DELETE FROM ` + reactionsTable + ` WHERE ` + idColumn + ` = $1 AND user_id = $2`
+		_, err = tx.ExecContext(ctx, query, target.ID, userID)
+	} else {
+		query = `
+		INSERT INTO ` + reactionsTable + ` (` + idColumn + `, user_id, kind)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (` + idColumn + `, user_id) DO UPDATE SET kind = EXCLUDED.kind, created_at = CURRENT_TIMESTAMP`
+		_, err = tx.ExecContext(ctx, query, target.ID, userID, kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save reaction: %w", err)
+	}
+
+	if reactions == nil {
+		reactions = models.Reactions{}
+	}
+	if old != "" {
+		reactions[old]--
+		if reactions[old] <= 0 {
+			delete(reactions, old)
+		}
+	}
+	if kind != "" {
+		reactions[kind]++
+	}
+	raw, err = json.Marshal(reactions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal reactions: %w", err)
+	}
+	query = `UPDATE ` + targets + ` SET reactions = $2, score = $3 WHERE id = $1`
+	if _, err = tx.ExecContext(ctx, query, target.ID, raw, reactions.Score()); err != nil {
+		return nil, fmt.Errorf("failed to update reaction counters: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit reaction: %w", err)
+	}
+	return reactions, nil
+}
+
+// GetReaction returns a kind of user`s reaction to a post or a comment, empty if the user didn`t react.
+func (r *RepoPG) GetReaction(ctx context.Context, target repository.Target, userID int) (models.ReactionKind, error) {
+	_, reactionsTable, idColumn, err := pgReactionTables(target.Type)
+	if err != nil {
+		return "", err
+	}
+	var kind models.ReactionKind
+	query := `SELECT kind FROM ` + reactionsTable + ` WHERE ` + idColumn + ` = $1 AND user_id = $2`
+	err = r.DB.QueryRowContext(ctx, query, target.ID, userID).Scan(&kind)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get reaction: %w", err)
+	}
+	return kind, nil
+}
@@ -0,0 +1,169 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+
+	"github.com/lib/pq"
+)
+
+// pgReportCaseColumns are columns selected by scanReportCase, "rc" is report_cases alias.
+const pgReportCaseColumns = `rc.id, rc.target_type, rc.target_id, rc.author_id, rc.report_count, rc.last_reported_at,
+		       ARRAY(SELECT r.reason FROM reports r WHERE r.case_id = rc.id),
+		       ARRAY(SELECT r.details FROM reports r WHERE r.case_id = rc.id AND r.details <> '' ORDER BY r.created_at, r.reporter_id)`
+
+// scanReportCase scans a row selected with pgReportCaseColumns.
+func scanReportCase(row interface{ Scan(dest ...any) error }) (*models.ReportCase, error) {
+	var rc models.ReportCase
+	var reasons, details pq.StringArray
+	err := row.Scan(&rc.ID, &rc.TargetType, &rc.TargetID, &rc.AuthorID, &rc.ReportCount, &rc.LastReportedAt, &reasons, &details)
+	if err != nil {
+		return nil, err
+	}
+	rc.Reasons = make(map[models.ReportReason]int)
+	for _, reason := range reasons {
+		rc.Reasons[models.ReportReason(reason)]++
+	}
+	if len(details) > 0 {
+		rc.Details = details
+	}
+	return &rc, nil
+}
+
+// AddReport adds a report to a case of the reported content, opening the case if there is no open one.
+func (r *RepoPG) AddReport(ctx context.Context, report *models.Report) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	//no-op update locks the case, so reports of the same content are counted one by one
+	var caseID int
+	query := `
+		INSERT INTO report_cases (target_type, target_id, author_id, last_reported_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (target_type, target_id) DO UPDATE SET target_type = EXCLUDED.target_type
+		RETURNING id`
+	err = tx.QueryRowContext(ctx, query, report.TargetType, report.TargetID, report.AuthorID, report.CreatedAt).Scan(&caseID)
+	if err != nil {
+		return false, fmt.Errorf("failed to open report case: %w", err)
+	}
+
+	query = `
+		INSERT INTO reports (case_id, reporter_id, reason, details, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING`
+	result, err := tx.ExecContext(ctx, query, caseID, report.ReporterID, report.Reason, report.Details, report.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to add report: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		//already reported, a case opened by this call is rolled back
+		return false, nil
+	}
+
+	query = `
+		UPDATE report_cases SET report_count = report_count + 1, last_reported_at = GREATEST(last_reported_at, $2)
+		WHERE id = $1`
+	if _, err = tx.ExecContext(ctx, query, caseID, report.CreatedAt); err != nil {
+		return false, fmt.Errorf("failed to update report case: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetReportCases returns open report cases, most reported first.
+func (r *RepoPG) GetReportCases(ctx context.Context, page repository.PageArgs) (cases []*models.ReportCase, hasNextPage bool, err error) {
+	keyset := newPGKeyset(repository.OrderMostReported, "rc", map[repository.Order]string{
+		repository.OrderMostReported: "rc.report_count",
+	})
+	args := []any{page.Limit + 1}
+	query := `
+		SELECT ` + pgReportCaseColumns + `
+		FROM report_cases rc
+		WHERE ` + keyset.where(page.After, &args) + `
+		ORDER BY ` + keyset.orderBy() + `
+		LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get report cases: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rc, err := scanReportCase(rows)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan report case: %w", err)
+		}
+		cases = append(cases, rc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+	if len(cases) > page.Limit {
+		hasNextPage = true
+		cases = cases[:page.Limit]
+	}
+	return cases, hasNextPage, nil
+}
+
+// GetReportCase returns an open report case by its ID.
+func (r *RepoPG) GetReportCase(ctx context.Context, caseID int) (*models.ReportCase, error) {
+	query := `SELECT ` + pgReportCaseColumns + ` FROM report_cases rc WHERE rc.id = $1`
+	rc, err := scanReportCase(r.DB.QueryRowContext(ctx, query, caseID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.NewErrNotFound()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report case: %w", err)
+	}
+	return rc, nil
+}
+
+// CloseReportCase deletes a report case, its reports are deleted by cascade.
+func (r *RepoPG) CloseReportCase(ctx context.Context, caseID int) error {
+	return r.execOne(ctx, "failed to close report case", `DELETE FROM report_cases WHERE id = $1`, caseID)
+}
+
+// SetPostHidden hides or restores a post.
+func (r *RepoPG) SetPostHidden(ctx context.Context, postID int, hidden bool) error {
+	return r.execOne(ctx, "failed to set post hidden", `UPDATE posts SET hidden = $2 WHERE id = $1`, postID, hidden)
+}
+
+// SetCommentHidden hides or restores a comment.
+func (r *RepoPG) SetCommentHidden(ctx context.Context, commentID int, hidden bool) error {
+	return r.execOne(ctx, "failed to set comment hidden", `UPDATE comments SET hidden = $2 WHERE id = $1`, commentID, hidden)
+}
+
+// BanUser bans a user.
+func (r *RepoPG) BanUser(ctx context.Context, userID int) error {
+	return r.execOne(ctx, "failed to ban user", `UPDATE users SET banned = TRUE WHERE id = $1`, userID)
+}
+
+// execOne executes a query changing a single row, returns repository.NewErrNotFound if no row was changed.
+func (r *RepoPG) execOne(ctx context.Context, errMsg string, query string, args ...any) error {
+	result, err := r.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", errMsg, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return repository.NewErrNotFound()
+	}
+	return nil
+}
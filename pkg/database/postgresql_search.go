@@ -0,0 +1,159 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/search"
+	"slices"
+	"strings"
+)
+
+// pgHeadlineOptions are ts_headline options of search snippets.
+const pgHeadlineOptions = `StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=10`
+
+// Search finds posts and comments using tsvector columns, hits are ranked by ts_rank.
+// Comments are found only while the post of their thread is published and not hidden.
+func (r *RepoPG) Search(ctx context.Context, query repository.SearchQuery, page repository.PageArgs) (hits []*models.SearchHit, hasNextPage bool, err error) {
+	args := []any{query.Text, page.Limit + 1, search.RankScale}
+	filters := func(alias string) string {
+		var sb strings.Builder
+		if query.AuthorID != 0 {
+			args = append(args, query.AuthorID)
+			fmt.Fprintf(&sb, " AND %s.owner_id = $%d", alias, len(args))
+		}
+		if query.From != nil {
+			args = append(args, *query.From)
+			fmt.Fprintf(&sb, " AND %s.created_at >= $%d", alias, len(args))
+		}
+		if query.To != nil {
+			args = append(args, *query.To)
+			fmt.Fprintf(&sb, " AND %s.created_at <= $%d", alias, len(args))
+		}
+		return sb.String()
+	}
+
+	var branches []string
+	if len(query.Types) == 0 || slices.Contains(query.Types, repository.SearchTypePost) {
+		branches = append(branches, `
+			SELECT 'POST' AS type, p.id, p.id * 2 + 1 AS sort_id,
+			       (ts_rank(p.search_vector, q) * $3)::BIGINT AS rank,
+			       p.title || E'\n' || p.text AS body
+			FROM posts p, websearch_to_tsquery('simple', $1) q
+			WHERE p.search_vector @@ q AND p.status = 'PUBLISHED' AND NOT p.hidden`+filters("p"))
+	}
+	if len(query.Types) == 0 || slices.Contains(query.Types, repository.SearchTypeComment) {
+		branches = append(branches, `
+			SELECT 'COMMENT' AS type, c.id, c.id * 2 AS sort_id,
+			       (ts_rank(c.search_vector, q) * $3)::BIGINT AS rank,
+			       c.text AS body
+			FROM comments c, websearch_to_tsquery('simple', $1) q
+			WHERE c.search_vector @@ q AND NOT c.hidden AND EXISTS (
+				WITH RECURSIVE ancestors AS (
+					SELECT a.parent_id, a.post_id FROM comments a WHERE a.id = c.id
+					UNION ALL
+					SELECT a.parent_id, a.post_id FROM comments a JOIN ancestors an ON a.id = an.parent_id
+				)
+				SELECT 1 FROM ancestors an JOIN posts p ON p.id = an.post_id
+				WHERE an.parent_id = 0 AND p.status = 'PUBLISHED' AND NOT p.hidden
+			)`+filters("c"))
+	}
+
+	after := "TRUE"
+	if page.After != nil {
+		args = append(args, page.After.Key, page.After.ID)
+		after = fmt.Sprintf("(h.rank, h.sort_id) < ($%d, $%d)", len(args)-1, len(args))
+	}
+
+	//body is escaped before ts_headline, so snippets are safe HTML with only <mark> tags
+	sqlQuery := `
+		SELECT h.type, h.id, h.rank,
+		       ts_headline('simple', replace(replace(replace(h.body, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+		                   websearch_to_tsquery('simple', $1), '` + pgHeadlineOptions + `')
+		FROM (` + strings.Join(branches, " UNION ALL ") + `) h
+		WHERE ` + after + `
+		ORDER BY h.rank DESC, h.sort_id DESC
+		LIMIT $2`
+	rows, err := r.DB.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	type found struct {
+		hitType string
+		id      int
+		hit     *models.SearchHit
+	}
+	var founds []found
+	for rows.Next() {
+		f := found{hit: &models.SearchHit{}}
+		if err := rows.Scan(&f.hitType, &f.id, &f.hit.Rank, &f.hit.Snippet); err != nil {
+			return nil, false, fmt.Errorf("failed to scan search hit: %w", err)
+		}
+		founds = append(founds, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("rows error: %w", err)
+	}
+	if len(founds) > page.Limit {
+		hasNextPage = true
+		founds = founds[:page.Limit]
+	}
+
+	//load found items
+	for _, f := range founds {
+		if f.hitType == string(repository.SearchTypePost) {
+			f.hit.Post, err = r.GetPostByID(ctx, f.id)
+		} else {
+			f.hit.Comment, err = r.GetCommentByID(ctx, f.id)
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get search hit: %w", err)
+		}
+		hits = append(hits, f.hit)
+	}
+	return hits, hasNextPage, nil
+}
+
+// GetCommentByID returns a comment by its ID.
+// returns repository.NewErrNotFound if not found.
+func (r *RepoPG) GetCommentByID(ctx context.Context, commentID int) (*models.Comment, error) {
+	query := `
+		SELECT ` + pgCommentColumns + `
+		FROM comments c
+		JOIN users u ON c.owner_id = u.id
+		WHERE c.id = $1`
+	c, err := scanComment(r.DB.QueryRowContext(ctx, query, commentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.NewErrNotFound()
+		}
+		return nil, fmt.Errorf("failed to get comment by ID: %w", err)
+	}
+	return c, nil
+}
+
+// GetCommentPostID returns an ID of a post of a comment, for replies it is a post of their top-level ancestor.
+// returns repository.NewErrNotFound if not found.
+func (r *RepoPG) GetCommentPostID(ctx context.Context, commentID int) (int, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, post_id FROM comments WHERE id = $1
+			UNION ALL
+			SELECT c.id, c.parent_id, c.post_id FROM comments c JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT post_id FROM ancestors WHERE parent_id = 0`
+	var postID int
+	err := r.DB.QueryRowContext(ctx, query, commentID).Scan(&postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, repository.NewErrNotFound()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get comment post: %w", err)
+	}
+	return postID, nil
+}
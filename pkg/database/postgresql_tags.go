@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+)
+
+// GetTag returns a tag with amount of its published posts.
+func (r *RepoPG) GetTag(ctx context.Context, name string) (*models.Tag, error) {
+	tag := &models.Tag{Name: name}
+	query := `
+		SELECT COUNT(*)
+		FROM post_tags t
+		JOIN posts p ON p.id = t.post_id
+		WHERE t.tag = $1 AND p.status = 'PUBLISHED'`
+	if err := r.DB.QueryRowContext(ctx, query, name).Scan(&tag.PostsCount); err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	if tag.PostsCount == 0 {
+		return nil, repository.NewErrNotFound()
+	}
+	return tag, nil
+}
+
+// GetPopularTags returns tags with the most published posts.
+func (r *RepoPG) GetPopularTags(ctx context.Context, limit int) (tags []*models.Tag, err error) {
+	query := `
+		SELECT t.tag, COUNT(*) AS cnt
+		FROM post_tags t
+		JOIN posts p ON p.id = t.post_id
+		WHERE p.status = 'PUBLISHED'
+		GROUP BY t.tag
+		ORDER BY cnt DESC, t.tag
+		LIMIT $1`
+	rows, err := r.DB.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get popular tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.Name, &tag.PostsCount); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, &tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return tags, nil
+}
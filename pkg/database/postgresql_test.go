@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"testing"
+)
+
+func TestRepoPG_GetPostByID(t *testing.T) {
+	tests := []struct {
+		name         string
+		dbErr        error
+		wantNotFound bool
+	}{
+		{
+			name:         "Not found",
+			dbErr:        nil,
+			wantNotFound: true,
+		},
+		{
+			name:         "Database error",
+			dbErr:        fmt.Errorf("connection refused"),
+			wantNotFound: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepoPG(newFakeSQLDB(t, tt.dbErr))
+			post, err := r.GetPostByID(context.Background(), 1)
+			if err == nil || post != nil {
+				t.Fatalf("GetPostByID() = %v, %v, want an error", post, err)
+			}
+			if got := errors.Is(err, repository.NewErrNotFound()); got != tt.wantNotFound {
+				t.Errorf("GetPostByID() error = %v, wantNotFound %v", err, tt.wantNotFound)
+			}
+		})
+	}
+}
@@ -6,20 +6,28 @@ import (
 	"fmt"
 	"ozon_test_task/internal/app/graph/repository"
 	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/search"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
-// RepoRedis is a Redis repository that implements PostRepo, CommentRepo, and UserRepo.
+// RepoRedis is a Redis repository that implements PostRepo, CommentRepo, UserRepo, FollowRepo, BookmarkRepo, TagRepo, SearchRepo,
+// ReactionRepo, NotificationRepo, ModerationRepo, ReportRepo, BlockRepo and IdempotencyRepo.
 type RepoRedis struct {
 	client *redis.Client
+	// index is an in-process full-text index, it is filled by BuildSearchIndex and kept in sync with writes of all processes
+	// by WatchSearchChanges.
+	index *search.Index
+	// searchChangesID is an ID of the last search changes stream entry applied by BuildSearchIndex.
+	searchChangesID string
 }
 
 // NewRepoRedis returns a new RepoRedis.
 func NewRepoRedis(client *redis.Client) *RepoRedis {
-	return &RepoRedis{client: client}
+	return &RepoRedis{client: client, index: search.NewIndex()}
 }
 
 // AddPost adds a new post and returns its ID.
@@ -32,34 +40,99 @@ func (r *RepoRedis) AddPost(ctx context.Context, post *models.Post) (int, error)
 	postID := int(id64)
 	key := fmt.Sprintf("post:%d", postID)
 
-	//h set
-	err = r.client.HSet(ctx, key, map[string]interface{}{
-		"owner_id":        post.Owner.ID,
-		"title":           post.Title,
-		"text":            post.Text,
-		"commentsallowed": post.CommentsAllowed,
-	}).Err()
+	fields := map[string]interface{}{
+		"owner_id":         post.Owner.ID,
+		"title":            post.Title,
+		"text":             post.Text,
+		"text_format":      string(post.TextFormat),
+		"commentsallowed":  post.CommentsAllowed,
+		"created_at":       post.CreatedAt.UnixMicro(),
+		"updated_at":       post.UpdatedAt.UnixMicro(),
+		"comments_count":   0,
+		"last_activity_at": post.CreatedAt.UnixMicro(),
+		"tags":             strings.Join(post.Tags, ","),
+		"status":           string(post.Status),
+		"comment_policy":   string(post.CommentPolicy),
+		"policy_days":      post.CommentPolicyDays,
+	}
+	if post.PublishAt != nil {
+		fields["publish_at"] = post.PublishAt.UnixMicro()
+	}
+	if len(post.ModerationReasons) > 0 {
+		fields["moderation_reasons"] = joinReasons(post.ModerationReasons)
+	}
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, fields)
+
+		//only published posts are listed
+		switch post.Status {
+		case models.PostPublished:
+			addPublishedPost(ctx, pipe, postID, post.Owner.ID, post.Tags, post.CreatedAt, 0)
+		case models.PostHeld:
+			pipe.ZAdd(ctx, heldPostsKey, &redis.Z{Score: float64(postID), Member: postID})
+			pipe.ZAdd(ctx, unpublishedPostsKey(post.Owner.ID), &redis.Z{Score: float64(postID), Member: postID})
+		case models.PostScheduled:
+			pipe.ZAdd(ctx, scheduledPostsKey, &redis.Z{Score: float64(post.PublishAt.UnixMicro()), Member: zMember(postID)})
+			fallthrough
+		default:
+			pipe.ZAdd(ctx, unpublishedPostsKey(post.Owner.ID), &redis.Z{Score: float64(postID), Member: postID})
+		}
+		return nil
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to add post: %w", err)
 	}
 
-	//add
-	if err := r.client.ZAdd(ctx, "posts", &redis.Z{Score: float64(postID), Member: postID}).Err(); err != nil {
-		return 0, fmt.Errorf("failed to add post to sorted set: %w", err)
+	post.ID = postID
+	if post.Status != models.PostPublished {
+		return postID, nil
+	}
+	r.index.Add(postDoc(post))
+	if err = r.notifySearchChange(ctx, search.DocPost, postID); err != nil {
+		return 0, err
+	}
+	if err = r.fanOutPost(ctx, post); err != nil {
+		return 0, err
 	}
 	return postID, nil
 }
 
-// SetCommentsAllowed updates the "commentsallowed" field for a given post.
-func (r *RepoRedis) SetCommentsAllowed(ctx context.Context, postID int, commentsAllowed bool) error {
+// SetCommentPolicy updates the "comment_policy", "policy_days" and "commentsallowed" fields for a given post.
+func (r *RepoRedis) SetCommentPolicy(ctx context.Context, postID int, policy models.CommentPolicy, days int, updatedAt time.Time) error {
 	key := fmt.Sprintf("post:%d", postID)
-	if err := r.client.HSet(ctx, key, "commentsallowed", commentsAllowed).Err(); err != nil {
-		return fmt.Errorf("failed to update comments allowed: %w", err)
+	exists, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check post: %w", err)
+	}
+	if exists == 0 {
+		return repository.NewErrNotFound()
+	}
+	err = r.client.HSet(ctx, key, map[string]interface{}{
+		"comment_policy":  string(policy),
+		"policy_days":     days,
+		"commentsallowed": policy != models.CommentsClosed,
+		"updated_at":      updatedAt.UnixMicro(),
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to update comment policy: %w", err)
 	}
 	return nil
 }
 
+// commentPolicy parses a "comment_policy" field of a post hash, posts saved without it have an open or a closed policy.
+func commentPolicy(m map[string]string, commentsAllowed bool) models.CommentPolicy {
+	switch {
+	case m["comment_policy"] != "":
+		return models.CommentPolicy(m["comment_policy"])
+	case commentsAllowed:
+		return models.CommentsOpen
+	default:
+		return models.CommentsClosed
+	}
+}
+
 // GetPostByID returns a post by its ID.
+// returns repository.NewErrNotFound if not found.
 func (r *RepoRedis) GetPostByID(ctx context.Context, postID int) (*models.Post, error) {
 	//get post data
 	key := fmt.Sprintf("post:%d", postID)
@@ -68,7 +141,7 @@ func (r *RepoRedis) GetPostByID(ctx context.Context, postID int) (*models.Post,
 		return nil, fmt.Errorf("failed to get post: %w", err)
 	}
 	if len(m) == 0 {
-		return nil, fmt.Errorf("post not found")
+		return nil, repository.NewErrNotFound()
 	}
 	ownerID, err := strconv.Atoi(m["owner_id"])
 	if err != nil {
@@ -78,12 +151,60 @@ func (r *RepoRedis) GetPostByID(ctx context.Context, postID int) (*models.Post,
 	if err != nil {
 		return nil, fmt.Errorf("invalid commentsallowed: %w", err)
 	}
+	commentsCount, err := optionalInt(m, "comments_count")
+	if err != nil {
+		return nil, err
+	}
+	lastActivityAt, err := optionalTime(m, "last_activity_at")
+	if err != nil {
+		return nil, err
+	}
+	createdAt, err := optionalTime(m, "created_at")
+	if err != nil {
+		return nil, err
+	}
+	updatedAt, err := optionalTime(m, "updated_at")
+	if err != nil {
+		return nil, err
+	}
+	reactions, err := parseReactions(m)
+	if err != nil {
+		return nil, err
+	}
+	publishAt, err := postPublishAt(m)
+	if err != nil {
+		return nil, err
+	}
+	policyDays, err := optionalInt(m, "policy_days")
+	if err != nil {
+		return nil, err
+	}
 	post := &models.Post{
-		ID:              postID,
-		Owner:           models.User{ID: ownerID},
-		Title:           m["title"],
-		Text:            m["text"],
-		CommentsAllowed: commentsAllowed,
+		ID:                postID,
+		Owner:             models.User{ID: ownerID},
+		Title:             m["title"],
+		Text:              m["text"],
+		TextFormat:        textFormat(m),
+		CommentsAllowed:   commentsAllowed,
+		CreatedAt:         createdAt,
+		UpdatedAt:         updatedAt,
+		CommentsCount:     int(commentsCount),
+		LastActivityAt:    lastActivityAt,
+		Tags:              splitTags(m["tags"]),
+		Reactions:         reactions,
+		Status:            postStatus(m),
+		PublishAt:         publishAt,
+		ModerationReasons: splitReasons(m["moderation_reasons"]),
+		Hidden:            m["hidden"] == "1",
+		CommentPolicy:     commentPolicy(m, commentsAllowed),
+		CommentPolicyDays: int(policyDays),
+	}
+	if _, ok := m["last_comment_at"]; ok {
+		lastCommentAt, err := optionalTime(m, "last_comment_at")
+		if err != nil {
+			return nil, err
+		}
+		post.LastCommentAt = &lastCommentAt
 	}
 
 	//get owner data
@@ -96,28 +217,41 @@ func (r *RepoRedis) GetPostByID(ctx context.Context, postID int) (*models.Post,
 }
 
 // GetPosts returns a list of posts.
-func (r *RepoRedis) GetPosts(ctx context.Context, limit int, after int) (posts []*models.Post, hasNextPage bool, err error) {
-	min := fmt.Sprintf("(%d", after)
-	max := "+inf"
-	zRangeArgs := &redis.ZRangeBy{
-		Min:   min,
-		Max:   max,
-		Count: int64(limit + 1),
-	}
-	idStrs, err := r.client.ZRangeByScore(ctx, "posts", zRangeArgs).Result()
+func (r *RepoRedis) GetPosts(ctx context.Context, filter repository.PostFilter, order repository.Order, page repository.PageArgs) (posts []*models.Post, hasNextPage bool, err error) {
+	//each filter has its own sorted sets, so they cannot be combined
+	setKey := "posts"
+	switch {
+	case filter.Tag != "" && filter.OwnerID != 0:
+		return nil, false, fmt.Errorf("filtering by tag and owner at once is not supported")
+	case filter.Held:
+		if filter.OwnerID != 0 || filter.Tag != "" || (order != repository.OrderNewest && order != repository.OrderOldest) {
+			return nil, false, fmt.Errorf("held posts can't be filtered and can be sorted only by id")
+		}
+		setKey = heldPostsKey
+	case filter.Unpublished:
+		if filter.OwnerID == 0 || filter.Tag != "" || (order != repository.OrderNewest && order != repository.OrderOldest) {
+			return nil, false, fmt.Errorf("unpublished posts can be filtered only by owner and sorted only by id")
+		}
+		setKey = unpublishedPostsKey(filter.OwnerID)
+	case filter.Tag != "":
+		setKey = tagPostsKey(filter.Tag)
+	case filter.OwnerID != 0:
+		setKey = userPostsKey(filter.OwnerID)
+	}
+	switch order {
+	case repository.OrderMostCommented:
+		setKey += ":by_comments"
+	case repository.OrderRecentActivity:
+		setKey += ":by_activity"
+	case repository.OrderTop:
+		setKey += ":by_score"
+	}
+	ids, hasNextPage, err := r.zPage(ctx, setKey, order.IsDesc(), page)
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to get posts from sorted set: %w", err)
+		return nil, false, fmt.Errorf("failed to get post ids: %w", err)
 	}
 
-	if len(idStrs) > limit {
-		hasNextPage = true
-		idStrs = idStrs[:limit]
-	}
-	for _, idStr := range idStrs {
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			return nil, false, fmt.Errorf("invalid post id: %w", err)
-		}
+	for _, id := range ids {
 		post, err := r.GetPostByID(ctx, id)
 		if err != nil {
 			return nil, false, fmt.Errorf("failed to get post by id: %w", err)
@@ -128,65 +262,165 @@ func (r *RepoRedis) GetPosts(ctx context.Context, limit int, after int) (posts [
 }
 
 // AddComment adds a new comment to Redis and returns its ID.
+// Also updates comments counters and activity times of the post and comment`s ancestors.
 func (r *RepoRedis) AddComment(ctx context.Context, comment *models.Comment) (int, error) {
+	//find a post and ancestors of the comment
+	postID := comment.PostID
+	var ancestors []int
+	if comment.ParentID != 0 {
+		var err error
+		ancestors, postID, err = r.commentAncestors(ctx, comment.ParentID)
+		if err != nil {
+			return 0, err
+		}
+	}
+	postFields, err := r.client.HMGet(ctx, fmt.Sprintf("post:%d", postID), "owner_id", "tags").Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check post: %w", err)
+	}
+	if postFields[0] == nil {
+		return 0, repository.NewErrNotFound()
+	}
+	postOwnerID, err := strconv.Atoi(postFields[0].(string))
+	if err != nil {
+		return 0, fmt.Errorf("invalid owner_id: %w", err)
+	}
+	postTags, _ := postFields[1].(string)
+
 	id64, err := r.client.Incr(ctx, "counter:comment").Result()
 	if err != nil {
 		return 0, fmt.Errorf("failed to generate comment id: %w", err)
 	}
 	commentID := int(id64)
 	key := fmt.Sprintf("comment:%d", commentID)
+	activity := float64(comment.CreatedAt.UnixMicro())
 
-	err = r.client.HSet(ctx, key, map[string]interface{}{
-		"owner_id":   comment.Owner.ID,
-		"post_id":    comment.PostID,
-		"parent_id":  comment.ParentID,
-		"text":       comment.Text,
-		"created_at": comment.CreatedAt.Unix(),
-	}).Err()
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, map[string]interface{}{
+			"owner_id":          comment.Owner.ID,
+			"post_id":           comment.PostID,
+			"parent_id":         comment.ParentID,
+			"text":              comment.Text,
+			"text_format":       string(comment.TextFormat),
+			"created_at":        comment.CreatedAt.Unix(),
+			"depth":             len(ancestors),
+			"replies_count":     0,
+			"descendants_count": 0,
+			"last_activity_at":  comment.CreatedAt.UnixMicro(),
+		})
+		pipe.ZAdd(ctx, fmt.Sprintf("user:%d:comments", comment.Owner.ID), &redis.Z{Score: float64(commentID), Member: commentID})
+		for _, user := range comment.Mentions {
+			pipe.RPush(ctx, commentMentionsKey(commentID), user.ID)
+			pipe.ZAdd(ctx, userMentionsKey(user.ID), &redis.Z{Score: float64(commentID), Member: commentID})
+		}
+
+		if comment.ParentID == 0 {
+			// Top-level comment for a post
+			setKey := fmt.Sprintf("post:%d:comments", comment.PostID)
+			pipe.ZAdd(ctx, setKey, &redis.Z{Score: float64(commentID), Member: commentID})
+			pipe.ZAdd(ctx, setKey+":by_descendants", &redis.Z{Score: 0, Member: zMember(commentID)})
+			pipe.ZAdd(ctx, setKey+":by_activity", &redis.Z{Score: activity, Member: zMember(commentID)})
+			pipe.ZAdd(ctx, setKey+":by_score", &redis.Z{Score: 0, Member: zMember(commentID)})
+		} else {
+			// Reply
+			setKey := fmt.Sprintf("comment:%d:replies", comment.ParentID)
+			pipe.ZAdd(ctx, setKey, &redis.Z{Score: float64(commentID), Member: commentID})
+			pipe.HIncrBy(ctx, fmt.Sprintf("comment:%d", comment.ParentID), "replies_count", 1)
+
+			for _, ancestorID := range ancestors {
+				ancestorKey := fmt.Sprintf("comment:%d", ancestorID)
+				pipe.HIncrBy(ctx, ancestorKey, "descendants_count", 1)
+				pipe.HSet(ctx, ancestorKey, "last_activity_at", comment.CreatedAt.UnixMicro())
+			}
+			rootID := ancestors[len(ancestors)-1]
+			setKey = fmt.Sprintf("post:%d:comments", postID)
+			pipe.ZIncrBy(ctx, setKey+":by_descendants", 1, zMember(rootID))
+			pipe.ZAdd(ctx, setKey+":by_activity", &redis.Z{Score: activity, Member: zMember(rootID)})
+		}
+
+		postKey := fmt.Sprintf("post:%d", postID)
+		pipe.HIncrBy(ctx, postKey, "comments_count", 1)
+		pipe.HSet(ctx, postKey, map[string]interface{}{
+			"last_comment_at":  comment.CreatedAt.UnixMicro(),
+			"last_activity_at": comment.CreatedAt.UnixMicro(),
+		})
+		for _, setKey := range postSetKeys(postOwnerID, splitTags(postTags)) {
+			pipe.ZIncrBy(ctx, setKey+":by_comments", 1, zMember(postID))
+			pipe.ZAdd(ctx, setKey+":by_activity", &redis.Z{Score: activity, Member: zMember(postID)})
+		}
+		return nil
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to add comment: %w", err)
 	}
 
-	if comment.ParentID == 0 {
-		// Top-level comment for a post
-		setKey := fmt.Sprintf("post:%d:comments", comment.PostID)
-		if err := r.client.ZAdd(ctx, setKey, &redis.Z{Score: float64(commentID), Member: commentID}).Err(); err != nil {
-			return 0, fmt.Errorf("failed to add comment to post sorted set: %w", err)
+	comment.ID = commentID
+	r.index.Add(commentDoc(comment))
+	if err = r.notifySearchChange(ctx, search.DocComment, commentID); err != nil {
+		return 0, err
+	}
+	return commentID, nil
+}
+
+// commentAncestors returns IDs of a comment and its ancestors from the comment to the top-level one, and a post ID of the top-level one.
+// returns repository.NewErrNotFound if the comment or one of its ancestors doesn`t exist.
+func (r *RepoRedis) commentAncestors(ctx context.Context, commentID int) (ancestors []int, postID int, err error) {
+	for id := commentID; id != 0; {
+		fields, err := r.client.HMGet(ctx, fmt.Sprintf("comment:%d", id), "parent_id", "post_id").Result()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get parent comment: %w", err)
 		}
-	} else {
-		// Reply
-		setKey := fmt.Sprintf("comment:%d:replies", comment.ParentID)
-		if err := r.client.ZAdd(ctx, setKey, &redis.Z{Score: float64(commentID), Member: commentID}).Err(); err != nil {
-			return 0, fmt.Errorf("failed to add reply to comment sorted set: %w", err)
+		if fields[0] == nil {
+			return nil, 0, repository.NewErrNotFound()
+		}
+		ancestors = append(ancestors, id)
+		parentID, err := strconv.Atoi(fields[0].(string))
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid parent_id: %w", err)
 		}
+		if parentID == 0 {
+			postID, err = strconv.Atoi(fields[1].(string))
+			if err != nil {
+				return nil, 0, fmt.Errorf("invalid post_id: %w", err)
+			}
+		}
+		id = parentID
 	}
-	return commentID, nil
+	return ancestors, postID, nil
+}
+
+// GetCommentPostID returns an ID of a post of a comment, for replies it is a post of their top-level ancestor.
+func (r *RepoRedis) GetCommentPostID(ctx context.Context, commentID int) (int, error) {
+	_, postID, err := r.commentAncestors(ctx, commentID)
+	if err != nil {
+		return 0, err
+	}
+	return postID, nil
 }
 
 // GetCommentsByPostID retrieves top-level comments (without replays) for a post.
-func (r *RepoRedis) GetCommentsByPostID(ctx context.Context, postID int, limit int, after int) (comments []*models.Comment, hasNextPage bool, err error) {
+func (r *RepoRedis) GetCommentsByPostID(ctx context.Context, postID int, filter repository.CommentFilter, order repository.Order, page repository.PageArgs) (comments []*models.Comment, hasNextPage bool, err error) {
 	setKey := fmt.Sprintf("post:%d:comments", postID)
-	min := fmt.Sprintf("(%d", after)
-	max := "+inf"
-	zRangeArgs := &redis.ZRangeBy{
-		Min:   min,
-		Max:   max,
-		Count: int64(limit + 1),
+	switch order {
+	case repository.OrderMostCommented:
+		setKey += ":by_descendants"
+	case repository.OrderRecentActivity:
+		setKey += ":by_activity"
+	case repository.OrderTop:
+		setKey += ":by_score"
+	}
+	var except map[int]bool
+	if filter.ExcludePinned {
+		if except, err = r.pinnedCommentIDs(ctx, postID); err != nil {
+			return nil, false, err
+		}
 	}
-	idStrs, err := r.client.ZRangeByScore(ctx, setKey, zRangeArgs).Result()
+	ids, hasNextPage, err := r.zPageExcept(ctx, setKey, order.IsDesc(), page, except)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to get comment ids: %w", err)
 	}
-	if len(idStrs) > limit {
-		hasNextPage = true
-		idStrs = idStrs[:limit]
-	}
-	for _, idStr := range idStrs {
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			return nil, false, fmt.Errorf("invalid comment id: %w", err)
-		}
-		comment, err := r.getCommentByID(ctx, id)
+	for _, id := range ids {
+		comment, err := r.GetCommentByID(ctx, id)
 		if err != nil {
 			return nil, false, fmt.Errorf("failed to get comment by id: %w", err)
 		}
@@ -196,29 +430,14 @@ func (r *RepoRedis) GetCommentsByPostID(ctx context.Context, postID int, limit i
 }
 
 // GetReplaysByCommentID returns replies for a given comment.
-func (r *RepoRedis) GetReplaysByCommentID(ctx context.Context, commentID int, limit int, after int) (replies []*models.Comment, hasNextPage bool, err error) {
+func (r *RepoRedis) GetReplaysByCommentID(ctx context.Context, commentID int, page repository.PageArgs) (replies []*models.Comment, hasNextPage bool, err error) {
 	setKey := fmt.Sprintf("comment:%d:replies", commentID)
-	min := fmt.Sprintf("(%d", after)
-	max := "+inf"
-	zRangeArgs := &redis.ZRangeBy{
-		Min:   min,
-		Max:   max,
-		Count: int64(limit + 1),
-	}
-	idStrs, err := r.client.ZRangeByScore(ctx, setKey, zRangeArgs).Result()
+	ids, hasNextPage, err := r.zPage(ctx, setKey, false, page)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to get reply ids: %w", err)
 	}
-	if len(idStrs) > limit {
-		hasNextPage = true
-		idStrs = idStrs[:limit]
-	}
-	for _, idStr := range idStrs {
-		id, err := strconv.Atoi(idStr)
-		if err != nil {
-			return nil, false, fmt.Errorf("invalid reply id: %w", err)
-		}
-		reply, err := r.getCommentByID(ctx, id)
+	for _, id := range ids {
+		reply, err := r.GetCommentByID(ctx, id)
 		if err != nil {
 			return nil, false, fmt.Errorf("failed to get reply by id: %w", err)
 		}
@@ -227,8 +446,25 @@ func (r *RepoRedis) GetReplaysByCommentID(ctx context.Context, commentID int, li
 	return replies, hasNextPage, nil
 }
 
-// getCommentByID returns a comment by its ID.
-func (r *RepoRedis) getCommentByID(ctx context.Context, commentID int) (*models.Comment, error) {
+// GetCommentsByOwnerID returns comments and replies of a given user, newest first.
+func (r *RepoRedis) GetCommentsByOwnerID(ctx context.Context, ownerID int, page repository.PageArgs) (comments []*models.Comment, hasNextPage bool, err error) {
+	setKey := fmt.Sprintf("user:%d:comments", ownerID)
+	ids, hasNextPage, err := r.zPage(ctx, setKey, true, page)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get comment ids: %w", err)
+	}
+	for _, id := range ids {
+		comment, err := r.GetCommentByID(ctx, id)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get comment by id: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+	return comments, hasNextPage, nil
+}
+
+// GetCommentByID returns a comment or a reply by its ID.
+func (r *RepoRedis) GetCommentByID(ctx context.Context, commentID int) (*models.Comment, error) {
 	//get comment data
 	key := fmt.Sprintf("comment:%d", commentID)
 	m, err := r.client.HGetAll(ctx, key).Result()
@@ -236,7 +472,7 @@ func (r *RepoRedis) getCommentByID(ctx context.Context, commentID int) (*models.
 		return nil, fmt.Errorf("failed to get comment: %w", err)
 	}
 	if len(m) == 0 {
-		return nil, fmt.Errorf("comment not found")
+		return nil, repository.NewErrNotFound()
 	}
 	ownerID, err := strconv.Atoi(m["owner_id"])
 	if err != nil {
@@ -254,13 +490,40 @@ func (r *RepoRedis) getCommentByID(ctx context.Context, commentID int) (*models.
 	if err != nil {
 		return nil, fmt.Errorf("invalid created_at: %w", err)
 	}
+	repliesCount, err := optionalInt(m, "replies_count")
+	if err != nil {
+		return nil, err
+	}
+	descendantsCount, err := optionalInt(m, "descendants_count")
+	if err != nil {
+		return nil, err
+	}
+	lastActivityAt, err := optionalTime(m, "last_activity_at")
+	if err != nil {
+		return nil, err
+	}
+	depth, err := r.commentDepth(ctx, m, parentID)
+	if err != nil {
+		return nil, err
+	}
+	reactions, err := parseReactions(m)
+	if err != nil {
+		return nil, err
+	}
 	comment := &models.Comment{
-		ID:        commentID,
-		Owner:     models.User{ID: ownerID},
-		PostID:    postID,
-		ParentID:  parentID,
-		Text:      m["text"],
-		CreatedAt: time.Unix(createdAtUnix, 0),
+		ID:               commentID,
+		Owner:            models.User{ID: ownerID},
+		PostID:           postID,
+		ParentID:         parentID,
+		Text:             m["text"],
+		TextFormat:       textFormat(m),
+		CreatedAt:        time.Unix(createdAtUnix, 0),
+		Depth:            depth,
+		RepliesCount:     int(repliesCount),
+		DescendantsCount: int(descendantsCount),
+		LastActivityAt:   lastActivityAt,
+		Reactions:        reactions,
+		Hidden:           m["hidden"] == "1",
 	}
 
 	//get owner data
@@ -272,8 +535,61 @@ func (r *RepoRedis) getCommentByID(ctx context.Context, commentID int) (*models.
 	return comment, nil
 }
 
-// AddUser adds a new user to Redis and returns it`s ID.
-func (r *RepoRedis) AddUser(ctx context.Context, user *models.User) (int, error) {
+// commentDepth returns a depth of a comment, comments saved by an older app version don`t have it and get it by their ancestors.
+func (r *RepoRedis) commentDepth(ctx context.Context, m map[string]string, parentID int) (int, error) {
+	if val, ok := m["depth"]; ok {
+		depth, err := strconv.Atoi(val)
+		if err != nil {
+			return 0, fmt.Errorf("invalid depth: %w", err)
+		}
+		return depth, nil
+	}
+	if parentID == 0 {
+		return 0, nil
+	}
+	ancestors, _, err := r.commentAncestors(ctx, parentID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get comment depth: %w", err)
+	}
+	return len(ancestors), nil
+}
+
+// optionalTime parses a time hash field saved as a unix time in microseconds.
+// Returns zero time if the field is absent (e.g. saved by an older app version).
+func optionalTime(m map[string]string, field string) (time.Time, error) {
+	if _, ok := m[field]; !ok {
+		return time.Time{}, nil
+	}
+	micro, err := optionalInt(m, field)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMicro(micro), nil
+}
+
+// optionalInt parses an integer hash field. Returns zero if the field is absent (e.g. saved by an older app version).
+func optionalInt(m map[string]string, field string) (int64, error) {
+	val, ok := m[field]
+	if !ok {
+		return 0, nil
+	}
+	i, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", field, err)
+	}
+	return i, nil
+}
+
+// textFormat parses a "text_format" field of a post or comment hash, texts saved before formats were added are plain.
+func textFormat(m map[string]string) models.TextFormat {
+	if m["text_format"] == "" {
+		return models.TextPlain
+	}
+	return models.TextFormat(m["text_format"])
+}
+
+// AddUser adds a new user with its credentials to Redis and returns it`s ID.
+func (r *RepoRedis) AddUser(ctx context.Context, user *models.User, cred *models.Credentials) (int, error) {
 	id64, err := r.client.Incr(ctx, "counter:user").Result()
 	if err != nil {
 		return 0, fmt.Errorf("failed to generate user id: %w", err)
@@ -283,9 +599,13 @@ func (r *RepoRedis) AddUser(ctx context.Context, user *models.User) (int, error)
 	//save user
 	userKey := fmt.Sprintf("user:%d", userID)
 	err = r.client.HSet(ctx, userKey, map[string]interface{}{
-		"login":        user.Login,
-		"passwordhash": user.PasswordHash,
-		"passwordsalt": user.PasswordSalt,
+		"login":         user.Login,
+		"passwordhash":  cred.PasswordHash,
+		"passwordsalt":  cred.PasswordSalt,
+		"display_name":  user.DisplayName,
+		"bio":           user.Bio,
+		"avatar_url":    user.AvatarURL,
+		"registered_at": user.RegisteredAt.UnixMicro(),
 	}).Err()
 	if err != nil {
 		return 0, fmt.Errorf("failed to add user: %w", err)
@@ -311,16 +631,28 @@ func (r *RepoRedis) GetUserByID(ctx context.Context, userID int) (*models.User,
 	if len(m) == 0 {
 		return nil, repository.NewErrNotFound()
 	}
-	user := &models.User{
-		ID:    userID,
-		Login: m["login"],
+	return parseUser(userID, m)
+}
+
+// parseUser returns a user profile from a user hash, credentials are skipped.
+func parseUser(userID int, m map[string]string) (*models.User, error) {
+	registeredAt, err := optionalTime(m, "registered_at")
+	if err != nil {
+		return nil, err
 	}
-	return user, nil
+	return &models.User{
+		ID:           userID,
+		Login:        m["login"],
+		DisplayName:  m["display_name"],
+		Bio:          m["bio"],
+		AvatarURL:    m["avatar_url"],
+		RegisteredAt: registeredAt,
+		Banned:       m["banned"] == "1",
+	}, nil
 }
 
-// GetUserByLoginWithCred returns a user by its login with credentials (password_hash and password_salt).
-func (r *RepoRedis) GetUserByLoginWithCred(ctx context.Context, login string) (*models.User, error) {
-	// get userID by login
+// GetUserByLogin returns a user by its login without password hash and salt.
+func (r *RepoRedis) GetUserByLogin(ctx context.Context, login string) (*models.User, error) {
 	loginKey := fmt.Sprintf("login:%s", login)
 	userID, err := r.client.Get(ctx, loginKey).Int()
 	if errors.Is(err, redis.Nil) {
@@ -328,24 +660,60 @@ func (r *RepoRedis) GetUserByLoginWithCred(ctx context.Context, login string) (*
 	} else if err != nil {
 		return nil, fmt.Errorf("failed to get user ID: %w", err)
 	}
+	return r.GetUserByID(ctx, userID)
+}
+
+// GetUserByLoginWithCred returns a user by its login with its credentials (password_hash and password_salt).
+func (r *RepoRedis) GetUserByLoginWithCred(ctx context.Context, login string) (*models.User, *models.Credentials, error) {
+	// get userID by login
+	loginKey := fmt.Sprintf("login:%s", login)
+	userID, err := r.client.Get(ctx, loginKey).Int()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil, repository.NewErrNotFound()
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user ID: %w", err)
+	}
 
 	// get user by ID
 	userKey := fmt.Sprintf("user:%d", userID)
 	m, err := r.client.HGetAll(ctx, userKey).Result()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
 	}
 	if len(m) == 0 {
-		return nil, repository.NewErrNotFound()
+		return nil, nil, repository.NewErrNotFound()
 	}
 
 	//return answer
-	user := &models.User{
-		ID:           userID,
-		Login:        m["login"],
+	user, err := parseUser(userID, m)
+	if err != nil {
+		return nil, nil, err
+	}
+	cred := &models.Credentials{
 		PasswordHash: m["passwordhash"],
 		PasswordSalt: m["passwordsalt"],
 	}
 
-	return user, nil
+	return user, cred, nil
+}
+
+// UpdateProfile updates user`s display name, bio and avatar URL.
+func (r *RepoRedis) UpdateProfile(ctx context.Context, user *models.User) error {
+	key := fmt.Sprintf("user:%d", user.ID)
+	exists, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check user: %w", err)
+	}
+	if exists == 0 {
+		return repository.NewErrNotFound()
+	}
+	err = r.client.HSet(ctx, key, map[string]interface{}{
+		"display_name": user.DisplayName,
+		"bio":          user.Bio,
+		"avatar_url":   user.AvatarURL,
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// commentBlocksKey returns a key of a sorted set with users blocked by an owner from commenting, scored by block time.
+func commentBlocksKey(ownerID int) string {
+	return fmt.Sprintf("user:%d:comment_blocks", ownerID)
+}
+
+// BlockCommenter blocks a user from commenting posts of an owner, blocking twice keeps the first block time.
+func (r *RepoRedis) BlockCommenter(ctx context.Context, ownerID, userID int, createdAt time.Time) error {
+	exists, err := r.client.Exists(ctx, fmt.Sprintf("user:%d", userID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check user: %w", err)
+	}
+	if exists == 0 {
+		return repository.NewErrNotFound()
+	}
+	err = r.client.ZAddNX(ctx, commentBlocksKey(ownerID), &redis.Z{Score: float64(createdAt.UnixMicro()), Member: zMember(userID)}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to block commenter: %w", err)
+	}
+	return nil
+}
+
+// UnblockCommenter removes a block.
+func (r *RepoRedis) UnblockCommenter(ctx context.Context, ownerID, userID int) error {
+	if err := r.client.ZRem(ctx, commentBlocksKey(ownerID), zMember(userID)).Err(); err != nil {
+		return fmt.Errorf("failed to unblock commenter: %w", err)
+	}
+	return nil
+}
+
+// IsCommenterBlocked returns true if an owner blocked a user from commenting their posts.
+func (r *RepoRedis) IsCommenterBlocked(ctx context.Context, ownerID, userID int) (bool, error) {
+	_, err := r.client.ZScore(ctx, commentBlocksKey(ownerID), zMember(userID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check commenter block: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// bookmarksKey returns a key of a sorted set with IDs of posts bookmarked by a user, scored by bookmark time.
+func bookmarksKey(userID int) string {
+	return fmt.Sprintf("user:%d:bookmarks", userID)
+}
+
+// AddBookmark adds a bookmark, bookmarking twice is not an error.
+func (r *RepoRedis) AddBookmark(ctx context.Context, userID, postID int, createdAt time.Time) error {
+	exists, err := r.client.Exists(ctx, fmt.Sprintf("post:%d", postID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check post: %w", err)
+	}
+	if exists == 0 {
+		return repository.NewErrNotFound()
+	}
+
+	//NX keeps the first bookmark time if already bookmarked
+	z := &redis.Z{Score: float64(createdAt.UnixMicro()), Member: zMember(postID)}
+	if err := r.client.ZAddNX(ctx, bookmarksKey(userID), z).Err(); err != nil {
+		return fmt.Errorf("failed to add bookmark: %w", err)
+	}
+	return nil
+}
+
+// RemoveBookmark removes a bookmark.
+func (r *RepoRedis) RemoveBookmark(ctx context.Context, userID, postID int) error {
+	if err := r.client.ZRem(ctx, bookmarksKey(userID), zMember(postID)).Err(); err != nil {
+		return fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+	return nil
+}
+
+// GetBookmarks returns bookmarked posts of a user, recently bookmarked first.
+func (r *RepoRedis) GetBookmarks(ctx context.Context, userID int, page repository.PageArgs) (bookmarks []*models.Bookmark, hasNextPage bool, err error) {
+	key := bookmarksKey(userID)
+	ids, hasNextPage, err := r.zPage(ctx, key, true, page)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get bookmarked post ids: %w", err)
+	}
+	for _, id := range ids {
+		score, err := r.client.ZScore(ctx, key, zMember(id)).Result()
+		if errors.Is(err, redis.Nil) {
+			//removed while reading
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get bookmark time: %w", err)
+		}
+		post, err := r.GetPostByID(ctx, id)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get post by id: %w", err)
+		}
+		bookmarks = append(bookmarks, &models.Bookmark{Post: *post, CreatedAt: time.UnixMicro(int64(score))})
+	}
+	return bookmarks, hasNextPage, nil
+}
+
+// IsBookmarked returns true if a user bookmarked a post.
+func (r *RepoRedis) IsBookmarked(ctx context.Context, userID, postID int) (bool, error) {
+	_, err := r.client.ZScore(ctx, bookmarksKey(userID), zMember(postID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check bookmark: %w", err)
+	}
+	return true, nil
+}
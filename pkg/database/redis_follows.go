@@ -0,0 +1,168 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// followersKey returns a key of a sorted set with followers of a user, scored by follow time.
+func followersKey(userID int) string {
+	return fmt.Sprintf("user:%d:followers", userID)
+}
+
+// followingKey returns a key of a sorted set with users followed by a user, scored by follow time.
+func followingKey(userID int) string {
+	return fmt.Sprintf("user:%d:following", userID)
+}
+
+// feedKey returns a key of a sorted set with IDs of posts of users followed by a user.
+func feedKey(userID int) string {
+	return fmt.Sprintf("user:%d:feed", userID)
+}
+
+// Follow adds a follow and copies followee`s posts to follower`s feed.
+func (r *RepoRedis) Follow(ctx context.Context, followerID, followeeID int, createdAt time.Time) error {
+	exists, err := r.client.Exists(ctx, fmt.Sprintf("user:%d", followeeID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check user: %w", err)
+	}
+	if exists == 0 {
+		return repository.NewErrNotFound()
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		score := float64(createdAt.UnixMicro())
+		//NX keeps the first follow time if already following
+		pipe.ZAddNX(ctx, followersKey(followeeID), &redis.Z{Score: score, Member: zMember(followerID)})
+		pipe.ZAddNX(ctx, followingKey(followerID), &redis.Z{Score: score, Member: zMember(followeeID)})
+		pipe.ZUnionStore(ctx, feedKey(followerID), &redis.ZStore{
+			Keys:      []string{feedKey(followerID), userPostsKey(followeeID)},
+			Aggregate: "MAX",
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to follow: %w", err)
+	}
+	return nil
+}
+
+// IsFollowing returns true if a follower follows a followee.
+func (r *RepoRedis) IsFollowing(ctx context.Context, followerID, followeeID int) (bool, error) {
+	_, err := r.client.ZScore(ctx, followingKey(followerID), zMember(followeeID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check follow: %w", err)
+	}
+	return true, nil
+}
+
+// Unfollow removes a follow and followee`s posts from follower`s feed.
+func (r *RepoRedis) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	postIDs, err := r.client.ZRange(ctx, userPostsKey(followeeID), 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get followee posts: %w", err)
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, followersKey(followeeID), zMember(followerID))
+		pipe.ZRem(ctx, followingKey(followerID), zMember(followeeID))
+		if len(postIDs) > 0 {
+			members := make([]interface{}, len(postIDs))
+			for i, id := range postIDs {
+				members[i] = id
+			}
+			pipe.ZRem(ctx, feedKey(followerID), members...)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unfollow: %w", err)
+	}
+	return nil
+}
+
+// GetFollowers returns followers of a user, recently followed first.
+func (r *RepoRedis) GetFollowers(ctx context.Context, userID int, page repository.PageArgs) (followers []*models.Follow, hasNextPage bool, err error) {
+	return r.getFollows(ctx, followersKey(userID), page)
+}
+
+// GetFollowing returns users followed by a user, recently followed first.
+func (r *RepoRedis) GetFollowing(ctx context.Context, userID int, page repository.PageArgs) (following []*models.Follow, hasNextPage bool, err error) {
+	return r.getFollows(ctx, followingKey(userID), page)
+}
+
+// getFollows returns users of a followers or following sorted set, recently followed first.
+func (r *RepoRedis) getFollows(ctx context.Context, key string, page repository.PageArgs) (follows []*models.Follow, hasNextPage bool, err error) {
+	ids, hasNextPage, err := r.zPage(ctx, key, true, page)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get user ids: %w", err)
+	}
+	for _, id := range ids {
+		score, err := r.client.ZScore(ctx, key, zMember(id)).Result()
+		if errors.Is(err, redis.Nil) {
+			//unfollowed while reading
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get follow time: %w", err)
+		}
+		user, err := r.GetUserByID(ctx, id)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get user by id: %w", err)
+		}
+		follows = append(follows, &models.Follow{User: *user, CreatedAt: time.UnixMicro(int64(score))})
+	}
+	return follows, hasNextPage, nil
+}
+
+// GetFeed returns posts from a user`s feed, newest first.
+func (r *RepoRedis) GetFeed(ctx context.Context, userID int, page repository.PageArgs) (posts []*models.Post, hasNextPage bool, err error) {
+	ids, hasNextPage, err := r.zPage(ctx, feedKey(userID), true, page)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get feed post ids: %w", err)
+	}
+	for _, id := range ids {
+		post, err := r.GetPostByID(ctx, id)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get post by id: %w", err)
+		}
+		posts = append(posts, post)
+	}
+	return posts, hasNextPage, nil
+}
+
+// fanOutPost adds a new post to feeds of owner`s followers.
+// It runs after the post is added to owner`s posts, so users following the owner concurrently get it from Follow.
+func (r *RepoRedis) fanOutPost(ctx context.Context, post *models.Post) error {
+	followers, err := r.client.ZRange(ctx, followersKey(post.Owner.ID), 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get followers: %w", err)
+	}
+	if len(followers) == 0 {
+		return nil
+	}
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, member := range followers {
+			followerID, err := strconv.Atoi(member)
+			if err != nil {
+				return fmt.Errorf("invalid follower id: %w", err)
+			}
+			pipe.ZAdd(ctx, feedKey(followerID), &redis.Z{Score: float64(post.ID), Member: post.ID})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add post to feeds: %w", err)
+	}
+	return nil
+}
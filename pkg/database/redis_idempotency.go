@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// idempotencyKey returns a key of a JSON encoded idempotency record of a user, it expires with the record.
+func idempotencyKey(userID int, key string) string {
+	return fmt.Sprintf("user:%d:idempotency:%s", userID, key)
+}
+
+// redisIdempotencyRecord is a stored form of models.IdempotencyRecord.
+type redisIdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Result      []byte `json:"result,omitempty"`
+}
+
+// ReserveIdempotencyKey saves a record without a result unless the user has a record with its key,
+// the record expires at its expiration time.
+func (r *RepoRedis) ReserveIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord, now time.Time) (*models.IdempotencyRecord, error) {
+	ttl := record.ExpiresAt.Sub(now)
+	if ttl <= 0 {
+		return nil, fmt.Errorf("idempotency record is expired")
+	}
+	data, err := json.Marshal(redisIdempotencyRecord{Fingerprint: record.Fingerprint})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode idempotency record: %w", err)
+	}
+	key := idempotencyKey(record.UserID, record.Key)
+	reserved, err := r.client.SetNX(ctx, key, data, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	data, err = r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, repository.NewErrConflict()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	var stored redisIdempotencyRecord
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode idempotency record: %w", err)
+	}
+	return &models.IdempotencyRecord{
+		UserID:      record.UserID,
+		Key:         record.Key,
+		Fingerprint: stored.Fingerprint,
+		Result:      stored.Result,
+	}, nil
+}
+
+// SaveIdempotencyResult saves a result of a reserved record and makes it expire at its new expiration time,
+// an expired reservation is not saved.
+func (r *RepoRedis) SaveIdempotencyResult(ctx context.Context, record *models.IdempotencyRecord) error {
+	ttl := time.Until(record.ExpiresAt)
+	if ttl <= 0 {
+		return fmt.Errorf("idempotency record is expired")
+	}
+	data, err := json.Marshal(redisIdempotencyRecord{Fingerprint: record.Fingerprint, Result: record.Result})
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotency record: %w", err)
+	}
+	err = r.client.SetArgs(ctx, idempotencyKey(record.UserID, record.Key), data, redis.SetArgs{Mode: "XX", TTL: ttl}).Err()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to save idempotency result: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey removes a user`s record.
+func (r *RepoRedis) ReleaseIdempotencyKey(ctx context.Context, userID int, key string) error {
+	if err := r.client.Del(ctx, idempotencyKey(userID, key)).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
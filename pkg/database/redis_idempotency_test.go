@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"ozon_test_task/internal/app/models"
+	"testing"
+	"time"
+)
+
+func TestRepoRedis_SaveIdempotencyResult(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedisClient(t)
+	r := NewRepoRedis(client)
+
+	now := time.Now()
+	record := &models.IdempotencyRecord{UserID: 1, Key: "k1", Fingerprint: "f", ExpiresAt: now.Add(time.Minute)}
+	stored, err := r.ReserveIdempotencyKey(ctx, record, now)
+	if err != nil || stored != nil {
+		t.Fatalf("ReserveIdempotencyKey() = %v, %v, want nil, nil", stored, err)
+	}
+	if ttl := client.PTTL(ctx, idempotencyKey(1, "k1")).Val(); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("reservation TTL = %v, want the lease", ttl)
+	}
+
+	record.Result = []byte(`{"id":1}`)
+	record.ExpiresAt = now.Add(24 * time.Hour)
+	if err = r.SaveIdempotencyResult(ctx, record); err != nil {
+		t.Fatalf("SaveIdempotencyResult() error = %v", err)
+	}
+	if ttl := client.PTTL(ctx, idempotencyKey(1, "k1")).Val(); ttl <= time.Hour {
+		t.Errorf("result TTL = %v, want it extended to the record expiration", ttl)
+	}
+
+	stored, err = r.ReserveIdempotencyKey(ctx, record, now)
+	if err != nil || stored == nil || string(stored.Result) != string(record.Result) {
+		t.Errorf("ReserveIdempotencyKey() of a saved record = %+v, %v", stored, err)
+	}
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"strconv"
+)
+
+// commentMentionsKey returns a key of a list with IDs of users mentioned in a comment in order of appearance.
+func commentMentionsKey(commentID int) string {
+	return fmt.Sprintf("comment:%d:mentions", commentID)
+}
+
+// userMentionsKey returns a key of a sorted set with IDs of comments mentioning a user.
+func userMentionsKey(userID int) string {
+	return fmt.Sprintf("user:%d:mentions", userID)
+}
+
+// GetMentions returns users mentioned in a comment in order of appearance.
+func (r *RepoRedis) GetMentions(ctx context.Context, commentID int) ([]*models.User, error) {
+	ids, err := r.client.LRange(ctx, commentMentionsKey(commentID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mentions: %w", err)
+	}
+	users := make([]*models.User, 0, len(ids))
+	for _, id := range ids {
+		userID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mentioned user id: %w", err)
+		}
+		user, err := r.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get mentioned user: %w", err)
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// GetCommentsByMentionedUserID returns comments and replies mentioning a given user, newest first.
+func (r *RepoRedis) GetCommentsByMentionedUserID(ctx context.Context, userID int, page repository.PageArgs) (comments []*models.Comment, hasNextPage bool, err error) {
+	ids, hasNextPage, err := r.zPage(ctx, userMentionsKey(userID), true, page)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get comment ids: %w", err)
+	}
+	for _, id := range ids {
+		comment, err := r.GetCommentByID(ctx, id)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get comment by id: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+	return comments, hasNextPage, nil
+}
@@ -0,0 +1,526 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisMigrationsKey is a key of a number of applied redisMigrations.
+const redisMigrationsKey = "migrations:version"
+
+// redisMigrationsLockKey is a key of a lock which serializes migrations of several app replicas.
+const redisMigrationsLockKey = "migrations:lock"
+
+// redisMigrationsLockTTL limits how long a crashed replica keeps migrations locked.
+const redisMigrationsLockTTL = 10 * time.Minute
+
+// redisMigrations backfill data saved by older app versions, each one is applied only once and in order.
+// Applied migrations must never be edited, add a new one instead.
+var redisMigrations = []func(r *RepoRedis, ctx context.Context) error{
+	// 1: comments counters and activity times used for sorting.
+	(*RepoRedis).migrateSortOrders,
+	// 2: posts timestamps. Creation time of existing posts is unknown, so the earliest comment time is used.
+	(*RepoRedis).migratePostTimes,
+	// 3: reaction scores used for sorting.
+	(*RepoRedis).migrateScores,
+	// 4: lists of posts and comments of users.
+	(*RepoRedis).migrateUserLists,
+	// 5: direct replies counter.
+	(*RepoRedis).migrateRepliesCount,
+	// 6: users registration time. It is unknown for existing users, so the earliest post or comment time is used.
+	(*RepoRedis).migrateRegisteredAt,
+	// 7: posts of threads of held comments.
+	(*RepoRedis).migrateHeldCommentPosts,
+}
+
+// Migrate applies redisMigrations which were not applied yet. Should be called on startup before BuildSearchIndex.
+func (r *RepoRedis) Migrate(ctx context.Context) error {
+	for {
+		locked, err := r.client.SetNX(ctx, redisMigrationsLockKey, 1, redisMigrationsLockTTL).Result()
+		if err != nil {
+			return fmt.Errorf("failed to lock migrations: %w", err)
+		}
+		if locked {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	defer r.client.Del(ctx, redisMigrationsLockKey)
+
+	version, err := r.client.Get(ctx, redisMigrationsKey).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to get migrations version: %w", err)
+	}
+	for ; version < len(redisMigrations); version++ {
+		if err = redisMigrations[version](r, ctx); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", version+1, err)
+		}
+		if err = r.client.Set(ctx, redisMigrationsKey, version+1, 0).Err(); err != nil {
+			return fmt.Errorf("failed to save migration version: %w", err)
+		}
+	}
+	return nil
+}
+
+// migrationComment is a comment loaded by migrations with totals of its subtree.
+type migrationComment struct {
+	postID    int
+	parentID  int
+	createdAt int64
+	// replies is an amount of direct replies.
+	replies int
+	// descendants is an amount of replies in the subtree.
+	descendants int
+	// lastActivity is the latest creation time in the subtree, Unix microseconds.
+	lastActivity int64
+}
+
+// migrationPost is a summary of top-level comment trees of a post.
+type migrationPost struct {
+	comments      int
+	firstComment  int64
+	lastActivity  int64
+	topCommentIDs []int
+}
+
+// loadMigrationComments loads all comments and summarizes their subtrees and posts.
+func (r *RepoRedis) loadMigrationComments(ctx context.Context) (map[int]*migrationComment, map[int]*migrationPost, error) {
+	lastID, err := r.client.Get(ctx, "counter:comment").Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, nil, fmt.Errorf("failed to get comments counter: %w", err)
+	}
+	comments := make(map[int]*migrationComment, lastID)
+	err = r.forEachComment(ctx, func(id int, m map[string]string) error {
+		postID, err := strconv.Atoi(m["post_id"])
+		if err != nil {
+			return fmt.Errorf("invalid post_id: %w", err)
+		}
+		parentID, err := strconv.Atoi(m["parent_id"])
+		if err != nil {
+			return fmt.Errorf("invalid parent_id: %w", err)
+		}
+		createdAt, err := strconv.ParseInt(m["created_at"], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid created_at: %w", err)
+		}
+		//creation times of comments are in seconds, saved activity times keep microseconds of the latest reply
+		lastActivity, err := optionalInt(m, "last_activity_at")
+		if err != nil {
+			return err
+		}
+		comments[id] = &migrationComment{
+			postID:       postID,
+			parentID:     parentID,
+			createdAt:    createdAt * 1e6,
+			lastActivity: max(lastActivity, createdAt*1e6),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	//replies always have greater ids than their parents, so subtrees are summed up from the last comment
+	posts := map[int]*migrationPost{}
+	for id := lastID; id >= 1; id-- {
+		c, ok := comments[id]
+		if !ok {
+			continue
+		}
+		if parent, ok := comments[c.parentID]; ok {
+			parent.replies++
+			parent.descendants += c.descendants + 1
+			parent.lastActivity = max(parent.lastActivity, c.lastActivity)
+			continue
+		}
+		if c.parentID != 0 {
+			continue
+		}
+		p := posts[c.postID]
+		if p == nil {
+			p = &migrationPost{firstComment: c.createdAt}
+			posts[c.postID] = p
+		}
+		p.comments += c.descendants + 1
+		p.firstComment = min(p.firstComment, c.createdAt)
+		p.lastActivity = max(p.lastActivity, c.lastActivity)
+		p.topCommentIDs = append(p.topCommentIDs, id)
+	}
+	return comments, posts, nil
+}
+
+// forEachHash calls f with every stored hash of a kind ("post", "comment", "user" or "held_comment"), which ids are up to "counter:<kind>".
+func (r *RepoRedis) forEachHash(ctx context.Context, kind string, f func(id int, m map[string]string) error) error {
+	lastID, err := r.client.Get(ctx, "counter:"+kind).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to get %s counter: %w", kind, err)
+	}
+	for id := 1; id <= lastID; id++ {
+		m, err := r.client.HGetAll(ctx, fmt.Sprintf("%s:%d", kind, id)).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get %s: %w", kind, err)
+		}
+		if len(m) == 0 {
+			continue
+		}
+		if err = f(id, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachPost calls f with every stored post hash.
+func (r *RepoRedis) forEachPost(ctx context.Context, f func(postID int, m map[string]string) error) error {
+	return r.forEachHash(ctx, "post", f)
+}
+
+// forEachComment calls f with every stored comment hash.
+func (r *RepoRedis) forEachComment(ctx context.Context, f func(commentID int, m map[string]string) error) error {
+	return r.forEachHash(ctx, "comment", f)
+}
+
+// isListedPost reports whether a post is in the list of published posts, only listed posts are in sorted sets.
+func (r *RepoRedis) isListedPost(ctx context.Context, postID int) (bool, error) {
+	err := r.client.ZScore(ctx, "posts", strconv.Itoa(postID)).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check post listing: %w", err)
+	}
+	return true, nil
+}
+
+// migrateSortOrders recounts descendants, comments and activity times of all comments and posts from comment trees,
+// and fills "by_descendants", "by_activity" and "by_comments" sorted sets with them.
+func (r *RepoRedis) migrateSortOrders(ctx context.Context) error {
+	comments, posts, err := r.loadMigrationComments(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for id, c := range comments {
+			pipe.HSet(ctx, fmt.Sprintf("comment:%d", id), map[string]interface{}{
+				"descendants_count": c.descendants,
+				"last_activity_at":  c.lastActivity,
+			})
+		}
+		for postID, p := range posts {
+			setKey := fmt.Sprintf("post:%d:comments", postID)
+			for _, id := range p.topCommentIDs {
+				c := comments[id]
+				pipe.ZAdd(ctx, setKey+":by_descendants", &redis.Z{Score: float64(c.descendants), Member: zMember(id)})
+				pipe.ZAdd(ctx, setKey+":by_activity", &redis.Z{Score: float64(c.lastActivity), Member: zMember(id)})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save comments sort orders: %w", err)
+	}
+
+	now := time.Now().UnixMicro()
+	return r.forEachPost(ctx, func(postID int, m map[string]string) error {
+		p := posts[postID]
+		if p == nil {
+			p = &migrationPost{}
+		}
+		lastActivity, err := optionalInt(m, "last_activity_at")
+		if err != nil {
+			return err
+		}
+		createdAt, err := optionalInt(m, "created_at")
+		if err != nil {
+			return err
+		}
+		lastActivity = max(lastActivity, createdAt, p.lastActivity)
+		if lastActivity == 0 {
+			lastActivity = now
+		}
+		ownerID, err := strconv.Atoi(m["owner_id"])
+		if err != nil {
+			return fmt.Errorf("invalid owner_id: %w", err)
+		}
+		listed, err := r.isListedPost(ctx, postID)
+		if err != nil {
+			return err
+		}
+
+		_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, fmt.Sprintf("post:%d", postID), map[string]interface{}{
+				"comments_count":   p.comments,
+				"last_activity_at": lastActivity,
+			})
+			if !listed {
+				return nil
+			}
+			for _, setKey := range postSetKeys(ownerID, splitTags(m["tags"])) {
+				pipe.ZAdd(ctx, setKey+":by_comments", &redis.Z{Score: float64(p.comments), Member: zMember(postID)})
+				pipe.ZAdd(ctx, setKey+":by_activity", &redis.Z{Score: float64(lastActivity), Member: zMember(postID)})
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save post sort orders: %w", err)
+		}
+		return nil
+	})
+}
+
+// migratePostTimes fills "created_at", "updated_at" and "last_comment_at" of posts saved without them,
+// and sets their activity time to the last comment or creation time like for new posts.
+func (r *RepoRedis) migratePostTimes(ctx context.Context) error {
+	_, posts, err := r.loadMigrationComments(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMicro()
+	return r.forEachPost(ctx, func(postID int, m map[string]string) error {
+		if _, ok := m["created_at"]; ok {
+			return nil
+		}
+		createdAt := now
+		p := posts[postID]
+		if p != nil {
+			createdAt = p.firstComment
+		}
+		fields := map[string]interface{}{"created_at": createdAt}
+		if _, ok := m["updated_at"]; !ok {
+			fields["updated_at"] = createdAt
+		}
+		lastActivity, err := optionalInt(m, "last_comment_at")
+		if err != nil {
+			return err
+		}
+		if _, ok := m["last_comment_at"]; !ok && p != nil {
+			lastActivity = p.lastActivity
+			fields["last_comment_at"] = lastActivity
+		}
+		if lastActivity == 0 {
+			lastActivity = createdAt
+		}
+		fields["last_activity_at"] = lastActivity
+
+		ownerID, err := strconv.Atoi(m["owner_id"])
+		if err != nil {
+			return fmt.Errorf("invalid owner_id: %w", err)
+		}
+		listed, err := r.isListedPost(ctx, postID)
+		if err != nil {
+			return err
+		}
+		_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, fmt.Sprintf("post:%d", postID), fields)
+			if !listed {
+				return nil
+			}
+			for _, setKey := range postSetKeys(ownerID, splitTags(m["tags"])) {
+				pipe.ZAdd(ctx, setKey+":by_activity", &redis.Z{Score: float64(lastActivity), Member: zMember(postID)})
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save post times: %w", err)
+		}
+		return nil
+	})
+}
+
+// migrateScores fills "by_score" sorted sets of listed posts and top-level comments with scores of their reactions.
+func (r *RepoRedis) migrateScores(ctx context.Context) error {
+	err := r.forEachPost(ctx, func(postID int, m map[string]string) error {
+		listed, err := r.isListedPost(ctx, postID)
+		if err != nil || !listed {
+			return err
+		}
+		ownerID, err := strconv.Atoi(m["owner_id"])
+		if err != nil {
+			return fmt.Errorf("invalid owner_id: %w", err)
+		}
+		reactions, err := parseReactions(m)
+		if err != nil {
+			return err
+		}
+		_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, setKey := range postSetKeys(ownerID, splitTags(m["tags"])) {
+				pipe.ZAdd(ctx, setKey+":by_score", &redis.Z{Score: float64(reactions.Score()), Member: zMember(postID)})
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save post score: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.forEachComment(ctx, func(commentID int, m map[string]string) error {
+		if m["parent_id"] != "0" {
+			return nil
+		}
+		reactions, err := parseReactions(m)
+		if err != nil {
+			return err
+		}
+		setKey := fmt.Sprintf("post:%s:comments:by_score", m["post_id"])
+		if err = r.client.ZAdd(ctx, setKey, &redis.Z{Score: float64(reactions.Score()), Member: zMember(commentID)}).Err(); err != nil {
+			return fmt.Errorf("failed to save comment score: %w", err)
+		}
+		return nil
+	})
+}
+
+// migrateUserLists adds listed posts and all comments to sorted sets of their owners scored by id.
+func (r *RepoRedis) migrateUserLists(ctx context.Context) error {
+	err := r.forEachPost(ctx, func(postID int, m map[string]string) error {
+		listed, err := r.isListedPost(ctx, postID)
+		if err != nil || !listed {
+			return err
+		}
+		ownerID, err := strconv.Atoi(m["owner_id"])
+		if err != nil {
+			return fmt.Errorf("invalid owner_id: %w", err)
+		}
+		if err = r.client.ZAdd(ctx, userPostsKey(ownerID), &redis.Z{Score: float64(postID), Member: postID}).Err(); err != nil {
+			return fmt.Errorf("failed to add post to user posts: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.forEachComment(ctx, func(commentID int, m map[string]string) error {
+		ownerID, err := strconv.Atoi(m["owner_id"])
+		if err != nil {
+			return fmt.Errorf("invalid owner_id: %w", err)
+		}
+		err = r.client.ZAdd(ctx, fmt.Sprintf("user:%d:comments", ownerID), &redis.Z{Score: float64(commentID), Member: commentID}).Err()
+		if err != nil {
+			return fmt.Errorf("failed to add comment to user comments: %w", err)
+		}
+		return nil
+	})
+}
+
+// migrateRepliesCount recounts direct replies of all comments.
+func (r *RepoRedis) migrateRepliesCount(ctx context.Context) error {
+	comments, _, err := r.loadMigrationComments(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for id, c := range comments {
+			pipe.HSet(ctx, fmt.Sprintf("comment:%d", id), "replies_count", c.replies)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save replies count: %w", err)
+	}
+	return nil
+}
+
+// migrateRegisteredAt fills "registered_at" of users saved without it with the time of their first post or comment,
+// or the current time if they have none.
+func (r *RepoRedis) migrateRegisteredAt(ctx context.Context) error {
+	now := time.Now().UnixMicro()
+	first := map[int]int64{}
+	addTime := func(ownerField string, createdAt int64) error {
+		ownerID, err := strconv.Atoi(ownerField)
+		if err != nil {
+			return fmt.Errorf("invalid owner_id: %w", err)
+		}
+		if t, ok := first[ownerID]; !ok || createdAt < t {
+			first[ownerID] = createdAt
+		}
+		return nil
+	}
+	err := r.forEachPost(ctx, func(postID int, m map[string]string) error {
+		if _, ok := m["created_at"]; !ok {
+			return nil
+		}
+		createdAt, err := optionalInt(m, "created_at")
+		if err != nil {
+			return err
+		}
+		return addTime(m["owner_id"], createdAt)
+	})
+	if err != nil {
+		return err
+	}
+	err = r.forEachComment(ctx, func(commentID int, m map[string]string) error {
+		//creation times of comments are in seconds
+		createdAt, err := strconv.ParseInt(m["created_at"], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid created_at: %w", err)
+		}
+		return addTime(m["owner_id"], createdAt*1e6)
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.forEachHash(ctx, "user", func(userID int, m map[string]string) error {
+		if _, ok := m["registered_at"]; ok {
+			return nil
+		}
+		registeredAt := now
+		if t, ok := first[userID]; ok {
+			registeredAt = min(t, now)
+		}
+		if err := r.client.HSet(ctx, fmt.Sprintf("user:%d", userID), "registered_at", registeredAt).Err(); err != nil {
+			return fmt.Errorf("failed to save user registration time: %w", err)
+		}
+		return nil
+	})
+}
+
+// migrateHeldCommentPosts fills "root_post_id" of held comments saved without it and adds them to held comments of the post.
+// Replies to deleted comments get zero, they are left to moderators.
+func (r *RepoRedis) migrateHeldCommentPosts(ctx context.Context) error {
+	return r.forEachHash(ctx, "held_comment", func(heldID int, m map[string]string) error {
+		if _, ok := m["root_post_id"]; ok {
+			return nil
+		}
+		rootPostID, err := strconv.Atoi(m["post_id"])
+		if err != nil {
+			return fmt.Errorf("invalid post_id: %w", err)
+		}
+		if rootPostID == 0 {
+			parentID, err := strconv.Atoi(m["parent_id"])
+			if err != nil {
+				return fmt.Errorf("invalid parent_id: %w", err)
+			}
+			rootPostID, err = r.GetCommentPostID(ctx, parentID)
+			if err != nil && !errors.Is(err, repository.NewErrNotFound()) {
+				return err
+			}
+		}
+
+		_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, heldCommentKey(heldID), "root_post_id", rootPostID)
+			pipe.ZAdd(ctx, postHeldCommentsKey(rootPostID), &redis.Z{Score: float64(heldID), Member: heldID})
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save held comment post: %w", err)
+		}
+		return nil
+	})
+}
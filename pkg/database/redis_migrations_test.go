@@ -0,0 +1,351 @@
+package database
+
+import (
+	"context"
+	"ozon_test_task/internal/app/graph/repository"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// setFakeHashes saves hashes by keys.
+func setFakeHashes(t *testing.T, client *redis.Client, hashes map[string]map[string]any) {
+	t.Helper()
+	for key, fields := range hashes {
+		if err := client.HSet(context.Background(), key, fields).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRepoRedis_Migrate_sortOrders(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newFakeRedisClient(t)
+	r := NewRepoRedis(client)
+
+	//data of an app version without counters and sorted sets, the last comment is saved by a newer version with a wrong counter
+	setFakeHashes(t, client, map[string]map[string]any{
+		"post:1":    {"owner_id": 2, "title": "Title", "text": "Text", "commentsallowed": "true", "tags": "go"},
+		"post:2":    {"owner_id": 2, "title": "Draft", "text": "Text", "commentsallowed": "true", "status": "DRAFT"},
+		"comment:1": {"owner_id": 3, "post_id": 1, "parent_id": 0, "text": "a", "created_at": 100},
+		"comment:2": {"owner_id": 3, "post_id": 1, "parent_id": 1, "text": "b", "created_at": 200},
+		"comment:3": {"owner_id": 3, "post_id": 1, "parent_id": 0, "text": "c", "created_at": 150, "last_activity_at": 150000001, "replies_count": 2},
+	})
+	for key, value := range map[string]int{"counter:post": 2, "counter:comment": 3} {
+		if err := client.Set(ctx, key, value, 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.ZAdd(ctx, "posts", &redis.Z{Score: 1, Member: 1}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	wantFields := []struct {
+		key, field, want string
+	}{
+		{"comment:1", "descendants_count", "1"},
+		{"comment:1", "last_activity_at", "200000000"},
+		{"comment:1", "replies_count", "1"},
+		{"comment:2", "descendants_count", "0"},
+		{"comment:2", "replies_count", "0"},
+		{"comment:3", "last_activity_at", "150000001"},
+		{"comment:3", "replies_count", "0"},
+		{"post:1", "comments_count", "3"},
+		{"post:1", "last_activity_at", "200000000"},
+		{"post:2", "comments_count", "0"},
+	}
+	for _, w := range wantFields {
+		if got := fake.hashes[w.key][w.field]; got != w.want {
+			t.Errorf("%s %s = %q, want %q", w.key, w.field, got, w.want)
+		}
+	}
+
+	wantScores := []struct {
+		key    string
+		member string
+		want   float64
+	}{
+		{"posts:by_comments", zMember(1), 3},
+		{"posts:by_activity", zMember(1), 200000000},
+		{userPostsKey(2) + ":by_comments", zMember(1), 3},
+		{tagPostsKey("go") + ":by_activity", zMember(1), 200000000},
+		{"post:1:comments:by_descendants", zMember(1), 1},
+		{"post:1:comments:by_descendants", zMember(3), 0},
+		{"post:1:comments:by_activity", zMember(1), 200000000},
+		{"post:1:comments:by_activity", zMember(3), 150000001},
+	}
+	for _, w := range wantScores {
+		got, ok := fake.zsets[w.key][w.member]
+		if !ok || got != w.want {
+			t.Errorf("%s %s score = %v, %v, want %v", w.key, w.member, got, ok, w.want)
+		}
+	}
+	if _, ok := fake.zsets["posts:by_comments"][zMember(2)]; ok {
+		t.Errorf("unlisted post is added to posts:by_comments")
+	}
+
+	if got := fake.strings[redisMigrationsKey]; got != strconv.Itoa(len(redisMigrations)) {
+		t.Errorf("migrations version = %q, want %d", got, len(redisMigrations))
+	}
+	if _, ok := fake.strings[redisMigrationsLockKey]; ok {
+		t.Errorf("migrations lock is not released")
+	}
+}
+
+func TestRepoRedis_Migrate_postTimes(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newFakeRedisClient(t)
+	r := NewRepoRedis(client)
+
+	//the first two posts are saved by an app version without timestamps, the second one is updated by a newer version
+	setFakeHashes(t, client, map[string]map[string]any{
+		"post:1":    {"owner_id": 2, "title": "Title", "text": "Text", "commentsallowed": "true"},
+		"post:2":    {"owner_id": 2, "title": "Title", "text": "Text", "commentsallowed": "true", "updated_at": 500000000},
+		"post:3":    {"owner_id": 2, "title": "Title", "text": "Text", "commentsallowed": "true", "created_at": 300000000, "updated_at": 300000000},
+		"comment:1": {"owner_id": 3, "post_id": 1, "parent_id": 0, "text": "a", "created_at": 100},
+		"comment:2": {"owner_id": 3, "post_id": 1, "parent_id": 0, "text": "b", "created_at": 200},
+	})
+	for key, value := range map[string]int{"counter:post": 3, "counter:comment": 2} {
+		if err := client.Set(ctx, key, value, 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.ZAdd(ctx, "posts", &redis.Z{Score: 1, Member: 1}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	wantFields := []struct {
+		key, field, want string
+	}{
+		{"post:1", "created_at", "100000000"},
+		{"post:1", "updated_at", "100000000"},
+		{"post:1", "last_comment_at", "200000000"},
+		{"post:1", "last_activity_at", "200000000"},
+		{"post:2", "updated_at", "500000000"},
+		{"post:3", "created_at", "300000000"},
+		{"post:3", "last_activity_at", "300000000"},
+	}
+	for _, w := range wantFields {
+		if got := fake.hashes[w.key][w.field]; got != w.want {
+			t.Errorf("%s %s = %q, want %q", w.key, w.field, got, w.want)
+		}
+	}
+	post2 := fake.hashes["post:2"]
+	if post2["created_at"] == "" || post2["last_activity_at"] != post2["created_at"] {
+		t.Errorf("post:2 created_at = %q, last_activity_at = %q, want equal times", post2["created_at"], post2["last_activity_at"])
+	}
+	if _, ok := post2["last_comment_at"]; ok {
+		t.Errorf("post:2 without comments has last_comment_at")
+	}
+	if got := fake.zsets["posts:by_activity"][zMember(1)]; got != 200000000 {
+		t.Errorf("posts:by_activity score = %v, want 200000000", got)
+	}
+}
+
+func TestRepoRedis_Migrate_scores(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedisClient(t)
+	r := NewRepoRedis(client)
+
+	//data of an app version without "by_score" sorted sets
+	setFakeHashes(t, client, map[string]map[string]any{
+		"user:2":    {"login": "owner"},
+		"post:1":    {"owner_id": 2, "title": "Title", "text": "Text", "commentsallowed": "true", "reactions:LIKE": 1},
+		"comment:1": {"owner_id": 2, "post_id": 1, "parent_id": 0, "text": "a", "created_at": 100},
+		"comment:2": {"owner_id": 2, "post_id": 1, "parent_id": 0, "text": "b", "created_at": 200, "reactions:LIKE": 2},
+		"comment:3": {"owner_id": 2, "post_id": 1, "parent_id": 1, "text": "c", "created_at": 300, "reactions:LIKE": 5},
+	})
+	for key, value := range map[string]int{"counter:post": 1, "counter:comment": 3} {
+		if err := client.Set(ctx, key, value, 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.ZAdd(ctx, "posts", &redis.Z{Score: 1, Member: 1}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	comments, _, err := r.GetCommentsByPostID(ctx, 1, repository.CommentFilter{}, repository.OrderTop, repository.PageArgs{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetCommentsByPostID() error = %v", err)
+	}
+	var ids []int
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{2, 1}) {
+		t.Errorf("GetCommentsByPostID() TOP ids = %v, want [2 1]", ids)
+	}
+
+	for _, filter := range []repository.PostFilter{{}, {OwnerID: 2}} {
+		posts, _, err := r.GetPosts(ctx, filter, repository.OrderTop, repository.PageArgs{Limit: 10})
+		if err != nil {
+			t.Fatalf("GetPosts() error = %v", err)
+		}
+		if len(posts) != 1 || posts[0].ID != 1 {
+			t.Errorf("GetPosts(%+v) TOP = %v, want post 1", filter, posts)
+		}
+	}
+}
+
+func TestRepoRedis_Migrate_userLists(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedisClient(t)
+	r := NewRepoRedis(client)
+
+	//data of an app version without lists of users posts and comments, the second post is not published
+	setFakeHashes(t, client, map[string]map[string]any{
+		"user:2":    {"login": "owner"},
+		"user:3":    {"login": "commenter"},
+		"post:1":    {"owner_id": 2, "title": "Title", "text": "Text", "commentsallowed": "true"},
+		"post:2":    {"owner_id": 2, "title": "Draft", "text": "Text", "commentsallowed": "true", "status": "DRAFT"},
+		"comment:1": {"owner_id": 3, "post_id": 1, "parent_id": 0, "text": "a", "created_at": 100},
+		"comment:2": {"owner_id": 3, "post_id": 1, "parent_id": 1, "text": "b", "created_at": 200},
+	})
+	for key, value := range map[string]int{"counter:post": 2, "counter:comment": 2} {
+		if err := client.Set(ctx, key, value, 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.ZAdd(ctx, "posts", &redis.Z{Score: 1, Member: 1}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	posts, _, err := r.GetPosts(ctx, repository.PostFilter{OwnerID: 2}, repository.OrderNewest, repository.PageArgs{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetPosts() error = %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != 1 {
+		t.Errorf("GetPosts() of the owner = %v, want post 1", posts)
+	}
+
+	comments, _, err := r.GetCommentsByOwnerID(ctx, 3, repository.PageArgs{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetCommentsByOwnerID() error = %v", err)
+	}
+	var ids []int
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{2, 1}) {
+		t.Errorf("GetCommentsByOwnerID() ids = %v, want [2 1]", ids)
+	}
+}
+
+func TestRepoRedis_Migrate_registeredAt(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newFakeRedisClient(t)
+	r := NewRepoRedis(client)
+
+	//the first two users are saved by an app version without registration time
+	setFakeHashes(t, client, map[string]map[string]any{
+		"user:1":    {"login": "author"},
+		"user:2":    {"login": "reader"},
+		"user:3":    {"login": "new", "registered_at": 900000000},
+		"post:1":    {"owner_id": 1, "title": "Title", "text": "Text", "commentsallowed": "true", "created_at": 300000000},
+		"post:2":    {"owner_id": 3, "title": "Title", "text": "Text", "commentsallowed": "true", "created_at": 950000000},
+		"comment:1": {"owner_id": 1, "post_id": 1, "parent_id": 0, "text": "a", "created_at": 200},
+	})
+	for key, value := range map[string]int{"counter:user": 3, "counter:post": 2, "counter:comment": 1} {
+		if err := client.Set(ctx, key, value, 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := r.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	if got := fake.hashes["user:1"]["registered_at"]; got != "200000000" {
+		t.Errorf("user:1 registered_at = %q, want the first comment time", got)
+	}
+	if got := fake.hashes["user:3"]["registered_at"]; got != "900000000" {
+		t.Errorf("user:3 registered_at = %q, want it unchanged", got)
+	}
+	user, err := r.GetUserByID(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetUserByID() error = %v", err)
+	}
+	if time.Since(user.RegisteredAt) > time.Minute {
+		t.Errorf("user without posts and comments registered at %v, want the migration time", user.RegisteredAt)
+	}
+}
+
+func TestRepoRedis_Migrate_heldCommentPosts(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedisClient(t)
+	r := NewRepoRedis(client)
+
+	//held comments saved by an app version without posts of their threads, the second one is a reply
+	setFakeHashes(t, client, map[string]map[string]any{
+		"user:3":         {"login": "commenter"},
+		"comment:1":      {"owner_id": 3, "post_id": 1, "parent_id": 0, "text": "a", "created_at": 100},
+		"comment:2":      {"owner_id": 3, "post_id": 0, "parent_id": 1, "text": "b", "created_at": 200},
+		"held_comment:1": {"owner_id": 3, "post_id": 1, "parent_id": 0, "text": "c", "created_at": 300000000},
+		"held_comment:2": {"owner_id": 3, "post_id": 0, "parent_id": 2, "text": "d", "created_at": 400000000},
+		"held_comment:3": {"owner_id": 3, "post_id": 2, "parent_id": 0, "text": "e", "created_at": 500000000},
+	})
+	for key, value := range map[string]int{"counter:comment": 2, "counter:held_comment": 3} {
+		if err := client.Set(ctx, key, value, 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := r.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	held, _, err := r.GetHeldComments(ctx, 1, repository.PageArgs{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetHeldComments() error = %v", err)
+	}
+	var ids []int
+	for _, h := range held {
+		if h.RootPostID != 1 {
+			t.Errorf("held comment %d RootPostID = %d, want 1", h.ID, h.RootPostID)
+		}
+		ids = append(ids, h.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("GetHeldComments() of post 1 ids = %v, want [1 2]", ids)
+	}
+}
+
+func TestRepoRedis_Migrate_applied(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newFakeRedisClient(t)
+	r := NewRepoRedis(client)
+
+	//applied migrations must not touch data, so a broken comment does not fail them
+	setFakeHashes(t, client, map[string]map[string]any{"comment:1": {"created_at": "broken"}})
+	for key, value := range map[string]int{"counter:comment": 1, redisMigrationsKey: len(redisMigrations)} {
+		if err := client.Set(ctx, key, value, 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := r.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if _, ok := fake.hashes["comment:1"]["descendants_count"]; ok {
+		t.Errorf("applied migration is applied again")
+	}
+}
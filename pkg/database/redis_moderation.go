@@ -0,0 +1,225 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// heldPostsKey is a key of a sorted set with IDs of posts held for review.
+const heldPostsKey = "posts:held"
+
+// heldCommentsKey is a key of a sorted set with IDs of comments held for review.
+const heldCommentsKey = "comments:held"
+
+// postHeldCommentsKey returns a key of a sorted set with IDs of held comments of a post thread.
+func postHeldCommentsKey(postID int) string {
+	return fmt.Sprintf("post:%d:comments:held", postID)
+}
+
+// heldCommentKey returns a key of a held comment hash.
+func heldCommentKey(heldID int) string {
+	return fmt.Sprintf("held_comment:%d", heldID)
+}
+
+// joinReasons joins moderation reasons into a hash field, a reason is a single line.
+func joinReasons(reasons []string) string {
+	return strings.Join(reasons, "\n")
+}
+
+// splitReasons parses a hash field with moderation reasons.
+func splitReasons(reasons string) []string {
+	if reasons == "" {
+		return nil
+	}
+	return strings.Split(reasons, "\n")
+}
+
+// ReleaseHeldPost makes a held post a draft.
+func (r *RepoRedis) ReleaseHeldPost(ctx context.Context, postID int) error {
+	key := fmt.Sprintf("post:%d", postID)
+	txf := func(tx *redis.Tx) error {
+		m, err := tx.HMGet(ctx, key, "owner_id", "status").Result()
+		if err != nil {
+			return fmt.Errorf("failed to get post: %w", err)
+		}
+		if m[0] == nil {
+			return repository.NewErrNotFound()
+		}
+		status, _ := m[1].(string)
+		if models.PostStatus(status) != models.PostHeld {
+			return repository.NewErrConflict()
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, "status", string(models.PostDraft))
+			pipe.HDel(ctx, key, "publish_at", "moderation_reasons")
+			pipe.ZRem(ctx, heldPostsKey, postID)
+			return nil
+		})
+		return err
+	}
+	var err error
+	for i := 0; i < maxPublishRetries; i++ {
+		err = r.client.Watch(ctx, txf, key)
+		if !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+	if errors.Is(err, repository.NewErrNotFound()) || errors.Is(err, repository.NewErrConflict()) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to release held post: %w", err)
+	}
+	return nil
+}
+
+// HoldComment saves a comment held for review.
+func (r *RepoRedis) HoldComment(ctx context.Context, held *models.HeldComment) (int, error) {
+	id64, err := r.client.Incr(ctx, "counter:held_comment").Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate held comment id: %w", err)
+	}
+	heldID := int(id64)
+	c := held.Comment
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, heldCommentKey(heldID), map[string]interface{}{
+			"owner_id":     c.Owner.ID,
+			"root_post_id": held.RootPostID,
+			"post_id":      c.PostID,
+			"parent_id":    c.ParentID,
+			"text":         c.Text,
+			"text_format":  string(c.TextFormat),
+			"created_at":   c.CreatedAt.UnixMicro(),
+			"reasons":      joinReasons(held.Reasons),
+		})
+		pipe.ZAdd(ctx, heldCommentsKey, &redis.Z{Score: float64(heldID), Member: heldID})
+		pipe.ZAdd(ctx, postHeldCommentsKey(held.RootPostID), &redis.Z{Score: float64(heldID), Member: heldID})
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to hold comment: %w", err)
+	}
+	return heldID, nil
+}
+
+// GetHeldComments returns held comments, oldest first.
+func (r *RepoRedis) GetHeldComments(ctx context.Context, rootPostID int, page repository.PageArgs) (comments []*models.HeldComment, hasNextPage bool, err error) {
+	key := heldCommentsKey
+	if rootPostID != 0 {
+		key = postHeldCommentsKey(rootPostID)
+	}
+	ids, hasNextPage, err := r.zPage(ctx, key, false, page)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get held comment ids: %w", err)
+	}
+
+	for _, id := range ids {
+		m, err := r.client.HGetAll(ctx, heldCommentKey(id)).Result()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get held comment: %w", err)
+		}
+		if len(m) == 0 {
+			//taken after ids were selected
+			continue
+		}
+		h, err := r.parseHeldComment(ctx, id, m)
+		if err != nil {
+			return nil, false, err
+		}
+		comments = append(comments, h)
+	}
+	return comments, hasNextPage, nil
+}
+
+// GetHeldComment returns a held comment.
+func (r *RepoRedis) GetHeldComment(ctx context.Context, heldID int) (*models.HeldComment, error) {
+	m, err := r.client.HGetAll(ctx, heldCommentKey(heldID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held comment: %w", err)
+	}
+	if len(m) == 0 {
+		return nil, repository.NewErrNotFound()
+	}
+	return r.parseHeldComment(ctx, heldID, m)
+}
+
+// TakeHeldComment removes a held comment and returns it.
+// The hash is read and deleted in one transaction, so concurrent calls return it once.
+func (r *RepoRedis) TakeHeldComment(ctx context.Context, heldID int) (*models.HeldComment, error) {
+	//the post of a held comment never changes, so it is read before the transaction
+	rootPostID, err := r.client.HGet(ctx, heldCommentKey(heldID), "root_post_id").Int()
+	if errors.Is(err, redis.Nil) {
+		return nil, repository.NewErrNotFound()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held comment post: %w", err)
+	}
+
+	var get *redis.StringStringMapCmd
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.HGetAll(ctx, heldCommentKey(heldID))
+		pipe.Del(ctx, heldCommentKey(heldID))
+		pipe.ZRem(ctx, heldCommentsKey, heldID)
+		pipe.ZRem(ctx, postHeldCommentsKey(rootPostID), heldID)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to take held comment: %w", err)
+	}
+	m := get.Val()
+	if len(m) == 0 {
+		return nil, repository.NewErrNotFound()
+	}
+	return r.parseHeldComment(ctx, heldID, m)
+}
+
+// parseHeldComment parses a held comment hash and gets its owner.
+func (r *RepoRedis) parseHeldComment(ctx context.Context, heldID int, m map[string]string) (*models.HeldComment, error) {
+	ownerID, err := strconv.Atoi(m["owner_id"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid owner_id: %w", err)
+	}
+	rootPostID, err := strconv.Atoi(m["root_post_id"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid root_post_id: %w", err)
+	}
+	postID, err := strconv.Atoi(m["post_id"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid post_id: %w", err)
+	}
+	parentID, err := strconv.Atoi(m["parent_id"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid parent_id: %w", err)
+	}
+	createdAt, err := strconv.ParseInt(m["created_at"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid created_at: %w", err)
+	}
+	owner, err := r.GetUserByID(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held comment owner: %w", err)
+	}
+	return &models.HeldComment{
+		ID: heldID,
+		Comment: models.Comment{
+			Owner:      *owner,
+			PostID:     postID,
+			ParentID:   parentID,
+			Text:       m["text"],
+			TextFormat: textFormat(m),
+			CreatedAt:  time.UnixMicro(createdAt),
+		},
+		RootPostID: rootPostID,
+		Reasons:    splitReasons(m["reasons"]),
+	}, nil
+}
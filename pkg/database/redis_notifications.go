@@ -0,0 +1,210 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// notificationsKey returns a key of a sorted set with IDs of user`s notifications.
+func notificationsKey(userID int) string {
+	return fmt.Sprintf("user:%d:notifications", userID)
+}
+
+// unreadNotificationsKey returns a key of a sorted set with IDs of user`s unread notifications.
+func unreadNotificationsKey(userID int) string {
+	return fmt.Sprintf("user:%d:notifications:unread", userID)
+}
+
+// notificationsChannel is a channel IDs of new notifications are published to.
+// Every process subscribes to it to deliver notifications added by all replicas to its subscribers.
+const notificationsChannel = "notifications"
+
+// AddNotifications saves notifications, sets their IDs and publishes them to notificationsChannel.
+func (r *RepoRedis) AddNotifications(ctx context.Context, notifications []*models.Notification) error {
+	for _, n := range notifications {
+		id64, err := r.client.Incr(ctx, "counter:notification").Result()
+		if err != nil {
+			return fmt.Errorf("failed to generate notification id: %w", err)
+		}
+		n.ID = int(id64)
+	}
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, n := range notifications {
+			pipe.HSet(ctx, fmt.Sprintf("notification:%d", n.ID), map[string]interface{}{
+				"user_id":    n.UserID,
+				"type":       string(n.Type),
+				"comment_id": n.Comment.ID,
+				"created_at": n.CreatedAt.UnixMicro(),
+				"read":       n.Read,
+			})
+			z := &redis.Z{Score: float64(n.ID), Member: n.ID}
+			pipe.ZAdd(ctx, notificationsKey(n.UserID), z)
+			if !n.Read {
+				pipe.ZAdd(ctx, unreadNotificationsKey(n.UserID), z)
+			}
+			pipe.Publish(ctx, notificationsChannel, n.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add notifications: %w", err)
+	}
+	return nil
+}
+
+// WatchNotifications passes notifications added by any process to f until ctx is done.
+// Notifications published while the connection is lost are not passed, they are still returned by GetNotifications.
+// Errors are passed to onError and do not stop watching.
+func (r *RepoRedis) WatchNotifications(ctx context.Context, f func(*models.Notification), onError func(error)) {
+	sub := r.client.Subscribe(ctx, notificationsChannel)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		var msg *redis.Message
+		select {
+		case <-ctx.Done():
+			return
+		case msg = <-ch:
+		}
+		if msg == nil {
+			//the subscription is closed
+			return
+		}
+		id, err := strconv.Atoi(msg.Payload)
+		if err != nil {
+			onError(fmt.Errorf("invalid notification id: %w", err))
+			continue
+		}
+		n, err := r.getNotificationByID(ctx, id)
+		if err != nil {
+			onError(fmt.Errorf("failed to get notification by id: %w", err))
+			continue
+		}
+		f(n)
+	}
+}
+
+// GetNotifications returns notifications of a user with their comments, newest first.
+func (r *RepoRedis) GetNotifications(ctx context.Context, userID int, unreadOnly bool, page repository.PageArgs) (notifications []*models.Notification, hasNextPage bool, err error) {
+	setKey := notificationsKey(userID)
+	if unreadOnly {
+		setKey = unreadNotificationsKey(userID)
+	}
+	ids, hasNextPage, err := r.zPage(ctx, setKey, true, page)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get notification ids: %w", err)
+	}
+
+	for _, id := range ids {
+		n, err := r.getNotificationByID(ctx, id)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get notification by id: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, hasNextPage, nil
+}
+
+// getNotificationByID returns a notification with its comment.
+func (r *RepoRedis) getNotificationByID(ctx context.Context, notificationID int) (*models.Notification, error) {
+	m, err := r.client.HGetAll(ctx, fmt.Sprintf("notification:%d", notificationID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+	if len(m) == 0 {
+		return nil, repository.NewErrNotFound()
+	}
+	userID, err := strconv.Atoi(m["user_id"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+	commentID, err := strconv.Atoi(m["comment_id"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid comment_id: %w", err)
+	}
+	createdAt, err := strconv.ParseInt(m["created_at"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid created_at: %w", err)
+	}
+	read, err := strconv.ParseBool(m["read"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid read: %w", err)
+	}
+
+	comment, err := r.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification comment: %w", err)
+	}
+	return &models.Notification{
+		ID:        notificationID,
+		UserID:    userID,
+		Type:      models.NotificationType(m["type"]),
+		Comment:   *comment,
+		CreatedAt: time.UnixMicro(createdAt),
+		Read:      read,
+	}, nil
+}
+
+// MarkNotificationsRead marks notifications of a user read, all of them if ids is nil.
+func (r *RepoRedis) MarkNotificationsRead(ctx context.Context, userID int, ids []int) error {
+	unreadKey := unreadNotificationsKey(userID)
+	var unread []int
+	if ids == nil {
+		members, err := r.client.ZRange(ctx, unreadKey, 0, -1).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get unread notifications: %w", err)
+		}
+		for _, member := range members {
+			id, err := strconv.Atoi(member)
+			if err != nil {
+				return fmt.Errorf("invalid notification id: %w", err)
+			}
+			unread = append(unread, id)
+		}
+	} else {
+		//only ids from user`s own unread set are marked
+		for _, id := range ids {
+			_, err := r.client.ZScore(ctx, unreadKey, strconv.Itoa(id)).Result()
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to check notification: %w", err)
+			}
+			unread = append(unread, id)
+		}
+	}
+	if len(unread) == 0 {
+		return nil
+	}
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range unread {
+			pipe.HSet(ctx, fmt.Sprintf("notification:%d", id), "read", true)
+			pipe.ZRem(ctx, unreadKey, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return nil
+}
+
+// CountUnreadNotifications returns an amount of unread notifications of a user.
+func (r *RepoRedis) CountUnreadNotifications(ctx context.Context, userID int) (int, error) {
+	count, err := r.client.ZCard(ctx, unreadNotificationsKey(userID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return int(count), nil
+}
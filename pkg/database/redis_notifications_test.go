@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"ozon_test_task/internal/app/models"
+	"testing"
+	"time"
+)
+
+func TestRepoRedis_WatchNotifications(t *testing.T) {
+	ctx := context.Background()
+	client, fake := newFakeRedisClient(t)
+	writer, reader := NewRepoRedis(client), NewRepoRedis(client)
+
+	setFakeHashes(t, client, map[string]map[string]any{
+		"user:2":    {"login": "commenter"},
+		"comment:1": {"owner_id": 2, "post_id": 1, "parent_id": 0, "text": "Hello", "created_at": 100},
+	})
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	received := make(chan *models.Notification, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		reader.WatchNotifications(watchCtx, func(n *models.Notification) { received <- n },
+			func(err error) { t.Errorf("WatchNotifications() error = %v", err) })
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	//notifications published before the subscription are not received
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		fake.mu.Lock()
+		subscribed := len(fake.subscribers[notificationsChannel]) > 0
+		fake.mu.Unlock()
+		if subscribed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("WatchNotifications() did not subscribe")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	notification := &models.Notification{
+		UserID:    3,
+		Type:      models.NotificationComment,
+		Comment:   models.Comment{ID: 1},
+		CreatedAt: time.UnixMicro(100000000),
+	}
+	if err := writer.AddNotifications(ctx, []*models.Notification{notification}); err != nil {
+		t.Fatalf("AddNotifications() error = %v", err)
+	}
+
+	select {
+	case got := <-received:
+		if got.ID != notification.ID || got.UserID != 3 || got.Comment.Text != "Hello" || got.Comment.Owner.Login != "commenter" {
+			t.Errorf("received notification %+v, want notification %d to user 3 about comment 1", got, notification.ID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("notification is not received")
+	}
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// pinnedCommentsKey returns a key of a sorted set with pinned comments of a post, scored by pin time.
+func pinnedCommentsKey(postID int) string {
+	return fmt.Sprintf("post:%d:pinned", postID)
+}
+
+// PinComment pins a top-level comment of a post, pinning a pinned comment keeps the first pin time.
+func (r *RepoRedis) PinComment(ctx context.Context, postID, commentID int, pinnedAt time.Time, maxPinned int) error {
+	m, err := r.client.HMGet(ctx, fmt.Sprintf("comment:%d", commentID), "post_id", "parent_id").Result()
+	if err != nil {
+		return fmt.Errorf("failed to get comment: %w", err)
+	}
+	commentPostID, _ := m[0].(string)
+	parentID, _ := m[1].(string)
+	if commentPostID != strconv.Itoa(postID) || parentID != "0" {
+		return repository.NewErrNotFound()
+	}
+
+	key := pinnedCommentsKey(postID)
+	member := zMember(commentID)
+	txf := func(tx *redis.Tx) error {
+		_, err := tx.ZScore(ctx, key, member).Result()
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			return fmt.Errorf("failed to check pinned comment: %w", err)
+		}
+		count, err := tx.ZCard(ctx, key).Result()
+		if err != nil {
+			return fmt.Errorf("failed to count pinned comments: %w", err)
+		}
+		if count >= int64(maxPinned) {
+			return repository.NewErrConflict()
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.ZAdd(ctx, key, &redis.Z{Score: float64(pinnedAt.UnixMicro()), Member: member})
+			return nil
+		})
+		return err
+	}
+	for i := 0; i < maxPublishRetries; i++ {
+		err = r.client.Watch(ctx, txf, key)
+		if !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+	if errors.Is(err, repository.NewErrConflict()) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to pin comment: %w", err)
+	}
+	return nil
+}
+
+// UnpinComment unpins a comment of a post.
+func (r *RepoRedis) UnpinComment(ctx context.Context, postID, commentID int) error {
+	if err := r.client.ZRem(ctx, pinnedCommentsKey(postID), zMember(commentID)).Err(); err != nil {
+		return fmt.Errorf("failed to unpin comment: %w", err)
+	}
+	return nil
+}
+
+// GetPinnedComments returns pinned comments of a post, earliest pinned first.
+func (r *RepoRedis) GetPinnedComments(ctx context.Context, postID int) ([]*models.Comment, error) {
+	members, err := r.client.ZRange(ctx, pinnedCommentsKey(postID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pinned comment ids: %w", err)
+	}
+	var comments []*models.Comment
+	for _, member := range members {
+		id, err := strconv.Atoi(member)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id in sorted set: %w", err)
+		}
+		comment, err := r.GetCommentByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comment by id: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+// pinnedCommentIDs returns a set of IDs of pinned comments of a post.
+func (r *RepoRedis) pinnedCommentIDs(ctx context.Context, postID int) (map[int]bool, error) {
+	members, err := r.client.ZRange(ctx, pinnedCommentsKey(postID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pinned comment ids: %w", err)
+	}
+	ids := make(map[int]bool, len(members))
+	for _, member := range members {
+		id, err := strconv.Atoi(member)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id in sorted set: %w", err)
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
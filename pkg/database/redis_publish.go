@@ -0,0 +1,215 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/search"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// scheduledPostsKey is a key of a sorted set with IDs of scheduled posts, scored by publish time.
+const scheduledPostsKey = "posts:scheduled"
+
+// maxPublishRetries is a maximum amount of attempts to publish a post concurrently changed by another request.
+const maxPublishRetries = 5
+
+// unpublishedPostsKey returns a key of a sorted set with IDs of drafts and scheduled posts of a user.
+func unpublishedPostsKey(userID int) string {
+	return fmt.Sprintf("user:%d:posts:unpublished", userID)
+}
+
+// postStatus parses a "status" field of a post hash, posts saved before drafts were added are published.
+func postStatus(m map[string]string) models.PostStatus {
+	if m["status"] == "" {
+		return models.PostPublished
+	}
+	return models.PostStatus(m["status"])
+}
+
+// postPublishAt parses a "publish_at" field of a post hash, published posts without it were published at creation.
+func postPublishAt(m map[string]string) (*time.Time, error) {
+	field := "publish_at"
+	if _, ok := m[field]; !ok {
+		if postStatus(m) != models.PostPublished {
+			return nil, nil
+		}
+		field = "created_at"
+	}
+	publishAt, err := optionalTime(m, field)
+	if err != nil {
+		return nil, err
+	}
+	return &publishAt, nil
+}
+
+// addPublishedPost adds a published post to sorted sets of posts and increments counters of its tags.
+func addPublishedPost(ctx context.Context, pipe redis.Pipeliner, postID, ownerID int, tags []string, lastActivityAt time.Time, score int) {
+	for _, setKey := range postSetKeys(ownerID, tags) {
+		pipe.ZAdd(ctx, setKey, &redis.Z{Score: float64(postID), Member: postID})
+		pipe.ZAdd(ctx, setKey+":by_comments", &redis.Z{Score: 0, Member: zMember(postID)})
+		pipe.ZAdd(ctx, setKey+":by_activity", &redis.Z{Score: float64(lastActivityAt.UnixMicro()), Member: zMember(postID)})
+		pipe.ZAdd(ctx, setKey+":by_score", &redis.Z{Score: float64(score), Member: zMember(postID)})
+	}
+	for _, tag := range tags {
+		pipe.ZIncrBy(ctx, "tags", 1, tag)
+	}
+}
+
+// PublishPost publishes a draft or a scheduled post.
+func (r *RepoRedis) PublishPost(ctx context.Context, postID int, publishedAt time.Time) error {
+	_, err := r.publishPost(ctx, postID, publishedAt)
+	return err
+}
+
+// publishPost publishes a post and adds it to lists, feeds and the search index. Returns false if the post is already published.
+// Post hash is watched, so concurrent calls (e.g. schedulers of several replicas) publish it once.
+func (r *RepoRedis) publishPost(ctx context.Context, postID int, publishedAt time.Time) (bool, error) {
+	key := fmt.Sprintf("post:%d", postID)
+	published := false
+	txf := func(tx *redis.Tx) error {
+		m, err := tx.HGetAll(ctx, key).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get post: %w", err)
+		}
+		if len(m) == 0 {
+			return repository.NewErrNotFound()
+		}
+		switch postStatus(m) {
+		case models.PostPublished:
+			return nil
+		case models.PostHeld:
+			return repository.NewErrConflict()
+		}
+		ownerID, err := strconv.Atoi(m["owner_id"])
+		if err != nil {
+			return fmt.Errorf("invalid owner_id: %w", err)
+		}
+		reactions, err := parseReactions(m)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, map[string]interface{}{
+				"status":           string(models.PostPublished),
+				"publish_at":       publishedAt.UnixMicro(),
+				"last_activity_at": publishedAt.UnixMicro(),
+			})
+			pipe.ZRem(ctx, scheduledPostsKey, zMember(postID))
+			pipe.ZRem(ctx, unpublishedPostsKey(ownerID), postID)
+			addPublishedPost(ctx, pipe, postID, ownerID, splitTags(m["tags"]), publishedAt, reactions.Score())
+			return nil
+		})
+		if err == nil {
+			published = true
+		}
+		return err
+	}
+	var err error
+	for i := 0; i < maxPublishRetries; i++ {
+		err = r.client.Watch(ctx, txf, key)
+		if !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+	if errors.Is(err, repository.NewErrNotFound()) || errors.Is(err, repository.NewErrConflict()) {
+		return false, err
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to publish post: %w", err)
+	}
+	if !published {
+		return false, nil
+	}
+
+	post, err := r.GetPostByID(ctx, postID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get published post: %w", err)
+	}
+	if !post.Hidden {
+		r.index.Add(postDoc(post))
+	}
+	if err = r.notifySearchChange(ctx, search.DocPost, postID); err != nil {
+		return false, err
+	}
+	if err = r.fanOutPost(ctx, post); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SchedulePost sets a publish time of a draft or a scheduled post.
+func (r *RepoRedis) SchedulePost(ctx context.Context, postID int, publishAt time.Time) error {
+	key := fmt.Sprintf("post:%d", postID)
+	txf := func(tx *redis.Tx) error {
+		m, err := tx.HMGet(ctx, key, "owner_id", "status").Result()
+		if err != nil {
+			return fmt.Errorf("failed to get post: %w", err)
+		}
+		if m[0] == nil {
+			return repository.NewErrNotFound()
+		}
+		status, _ := m[1].(string)
+		if status == "" || models.PostStatus(status) == models.PostPublished || models.PostStatus(status) == models.PostHeld {
+			return repository.NewErrConflict()
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, map[string]interface{}{
+				"status":     string(models.PostScheduled),
+				"publish_at": publishAt.UnixMicro(),
+			})
+			pipe.ZAdd(ctx, scheduledPostsKey, &redis.Z{Score: float64(publishAt.UnixMicro()), Member: zMember(postID)})
+			return nil
+		})
+		return err
+	}
+	var err error
+	for i := 0; i < maxPublishRetries; i++ {
+		err = r.client.Watch(ctx, txf, key)
+		if !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+	if errors.Is(err, repository.NewErrNotFound()) || errors.Is(err, repository.NewErrConflict()) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to schedule post: %w", err)
+	}
+	return nil
+}
+
+// PublishDuePosts publishes scheduled posts which are due.
+func (r *RepoRedis) PublishDuePosts(ctx context.Context, now time.Time) ([]int, error) {
+	zs, err := r.client.ZRangeByScoreWithScores(ctx, scheduledPostsKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.UnixMicro(), 10),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due posts: %w", err)
+	}
+
+	var ids []int
+	for _, z := range zs {
+		member, _ := z.Member.(string)
+		id, err := strconv.Atoi(member)
+		if err != nil {
+			return nil, fmt.Errorf("invalid post id: %w", err)
+		}
+		published, err := r.publishPost(ctx, id, time.UnixMicro(int64(z.Score)))
+		if err != nil {
+			return ids, err
+		}
+		if published {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// reactionFieldPrefix is a prefix of post and comment hash fields with amounts of reactions, e.g. "reactions:LIKE".
+const reactionFieldPrefix = "reactions:"
+
+// maxReactionRetries is a maximum amount of attempts to save a reaction concurrently changed by the same user.
+const maxReactionRetries = 5
+
+// reactionTargetKey returns a key of a post or a comment hash.
+func reactionTargetKey(target repository.Target) (string, error) {
+	switch target.Type {
+	case repository.TargetPost:
+		return fmt.Sprintf("post:%d", target.ID), nil
+	case repository.TargetComment:
+		return fmt.Sprintf("comment:%d", target.ID), nil
+	default:
+		return "", fmt.Errorf("unknown target type %q", target.Type)
+	}
+}
+
+// parseReactions parses reaction counters of a post or a comment hash.
+func parseReactions(m map[string]string) (models.Reactions, error) {
+	reactions := models.Reactions{}
+	for field := range m {
+		kind, ok := strings.CutPrefix(field, reactionFieldPrefix)
+		if !ok {
+			continue
+		}
+		count, err := optionalInt(m, field)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			reactions[models.ReactionKind(kind)] = int(count)
+		}
+	}
+	return reactions, nil
+}
+
+// React sets user`s reaction to a post or a comment replacing a previous one.
+func (r *RepoRedis) React(ctx context.Context, target repository.Target, userID int, kind models.ReactionKind) (models.Reactions, error) {
+	return r.setReaction(ctx, target, userID, kind)
+}
+
+// Unreact removes user`s reaction to a post or a comment.
+func (r *RepoRedis) Unreact(ctx context.Context, target repository.Target, userID int) (models.Reactions, error) {
+	return r.setReaction(ctx, target, userID, "")
+}
+
+// setReaction replaces user`s reaction to a target with a given kind, empty kind removes the reaction.
+// Users reactions hash is watched, so the counters are not changed twice by concurrent requests of the same user.
+func (r *RepoRedis) setReaction(ctx context.Context, target repository.Target, userID int, kind models.ReactionKind) (models.Reactions, error) {
+	key, err := reactionTargetKey(target)
+	if err != nil {
+		return nil, err
+	}
+	usersKey := key + ":reactions"
+	userField := strconv.Itoa(userID)
+
+	//find sorted sets ordered by score the target belongs to
+	fields, err := r.client.HMGet(ctx, key, "owner_id", "tags", "post_id", "status").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reaction target: %w", err)
+	}
+	if fields[0] == nil {
+		return nil, repository.NewErrNotFound()
+	}
+	var scoreKeys []string
+	if target.Type == repository.TargetPost {
+		ownerID, err := strconv.Atoi(fields[0].(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid owner_id: %w", err)
+		}
+		tags, _ := fields[1].(string)
+		//unpublished posts are not listed, their score is added on publishing
+		if status, _ := fields[3].(string); status == "" || models.PostStatus(status) == models.PostPublished {
+			for _, setKey := range postSetKeys(ownerID, splitTags(tags)) {
+				scoreKeys = append(scoreKeys, setKey+":by_score")
+			}
+		}
+	} else if postID, _ := fields[2].(string); postID != "" && postID != "0" {
+		//only top-level comments are sorted
+		scoreKeys = append(scoreKeys, fmt.Sprintf("post:%s:comments:by_score", postID))
+	}
+
+	txf := func(tx *redis.Tx) error {
+		old, err := tx.HGet(ctx, usersKey, userField).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return fmt.Errorf("failed to get reaction: %w", err)
+		}
+		oldKind := models.ReactionKind(old)
+		if oldKind == kind {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if oldKind != "" {
+				pipe.HIncrBy(ctx, key, reactionFieldPrefix+old, -1)
+			}
+			if kind != "" {
+				pipe.HSet(ctx, usersKey, userField, string(kind))
+				pipe.HIncrBy(ctx, key, reactionFieldPrefix+string(kind), 1)
+			} else {
+				pipe.HDel(ctx, usersKey, userField)
+			}
+			if delta := kind.Weight() - oldKind.Weight(); delta != 0 {
+				for _, setKey := range scoreKeys {
+					pipe.ZIncrBy(ctx, setKey, float64(delta), zMember(target.ID))
+				}
+			}
+			return nil
+		})
+		return err
+	}
+	for i := 0; i < maxReactionRetries; i++ {
+		err = r.client.Watch(ctx, txf, usersKey)
+		if !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save reaction: %w", err)
+	}
+
+	m, err := r.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions: %w", err)
+	}
+	return parseReactions(m)
+}
+
+// GetReaction returns a kind of user`s reaction to a post or a comment, empty if the user didn`t react.
+func (r *RepoRedis) GetReaction(ctx context.Context, target repository.Target, userID int) (models.ReactionKind, error) {
+	key, err := reactionTargetKey(target)
+	if err != nil {
+		return "", err
+	}
+	kind, err := r.client.HGet(ctx, key+":reactions", strconv.Itoa(userID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get reaction: %w", err)
+	}
+	return models.ReactionKind(kind), nil
+}
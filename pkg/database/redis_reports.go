@@ -0,0 +1,261 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/search"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// reportQueueKey is a key of a sorted set with IDs of open report cases, scored by report count.
+const reportQueueKey = "reports:queue"
+
+// reportCaseKey returns a key of a report case hash.
+// Besides case fields the hash has a "reason:<REASON>" counter for every reported reason.
+func reportCaseKey(caseID int) string {
+	return fmt.Sprintf("report_case:%d", caseID)
+}
+
+// reportDetailsKey returns a key of a list with non-empty details of case reports, oldest first.
+func reportDetailsKey(caseID int) string {
+	return fmt.Sprintf("report_case:%d:details", caseID)
+}
+
+// reportTargetKey returns a key of an open case ID of reported content.
+// A set of reporter IDs is stored at the key with ":reporters" suffix.
+func reportTargetKey(targetType models.ReportTarget, targetID int) string {
+	return fmt.Sprintf("report_target:%s:%d", targetType, targetID)
+}
+
+// AddReport adds a report to a case of the reported content, opening the case if there is no open one.
+// The target keys are watched, so a reporter is counted once even if they report concurrently.
+func (r *RepoRedis) AddReport(ctx context.Context, report *models.Report) (bool, error) {
+	targetKey := reportTargetKey(report.TargetType, report.TargetID)
+	reportersKey := targetKey + ":reporters"
+	added := false
+	txf := func(tx *redis.Tx) error {
+		caseID, err := tx.Get(ctx, targetKey).Int()
+		isNew := errors.Is(err, redis.Nil)
+		if err != nil && !isNew {
+			return fmt.Errorf("failed to get report case id: %w", err)
+		}
+		reported, err := tx.SIsMember(ctx, reportersKey, report.ReporterID).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check reporter: %w", err)
+		}
+		if reported {
+			return nil
+		}
+		if isNew {
+			id64, err := r.client.Incr(ctx, "counter:report_case").Result()
+			if err != nil {
+				return fmt.Errorf("failed to generate report case id: %w", err)
+			}
+			caseID = int(id64)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			caseKey := reportCaseKey(caseID)
+			if isNew {
+				pipe.Set(ctx, targetKey, caseID, 0)
+				pipe.HSet(ctx, caseKey, map[string]interface{}{
+					"target_type": string(report.TargetType),
+					"target_id":   report.TargetID,
+					"author_id":   report.AuthorID,
+				})
+			}
+			pipe.SAdd(ctx, reportersKey, report.ReporterID)
+			pipe.HIncrBy(ctx, caseKey, "report_count", 1)
+			pipe.HIncrBy(ctx, caseKey, "reason:"+string(report.Reason), 1)
+			pipe.HSet(ctx, caseKey, "last_reported_at", report.CreatedAt.UnixMicro())
+			if report.Details != "" {
+				pipe.RPush(ctx, reportDetailsKey(caseID), report.Details)
+			}
+			pipe.ZIncrBy(ctx, reportQueueKey, 1, zMember(caseID))
+			return nil
+		})
+		if err == nil {
+			added = true
+		}
+		return err
+	}
+	var err error
+	for i := 0; i < maxPublishRetries; i++ {
+		err = r.client.Watch(ctx, txf, targetKey, reportersKey)
+		if !errors.Is(err, redis.TxFailedErr) {
+			break
+		}
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to add report: %w", err)
+	}
+	return added, nil
+}
+
+// GetReportCases returns open report cases, most reported first.
+func (r *RepoRedis) GetReportCases(ctx context.Context, page repository.PageArgs) (cases []*models.ReportCase, hasNextPage bool, err error) {
+	ids, hasNextPage, err := r.zPage(ctx, reportQueueKey, true, page)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get report case ids: %w", err)
+	}
+
+	for _, id := range ids {
+		rc, err := r.GetReportCase(ctx, id)
+		if errors.Is(err, repository.NewErrNotFound()) {
+			//closed after ids were selected
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		cases = append(cases, rc)
+	}
+	return cases, hasNextPage, nil
+}
+
+// GetReportCase returns an open report case by its ID.
+func (r *RepoRedis) GetReportCase(ctx context.Context, caseID int) (*models.ReportCase, error) {
+	m, err := r.client.HGetAll(ctx, reportCaseKey(caseID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report case: %w", err)
+	}
+	if len(m) == 0 {
+		return nil, repository.NewErrNotFound()
+	}
+	details, err := r.client.LRange(ctx, reportDetailsKey(caseID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report details: %w", err)
+	}
+	return parseReportCase(caseID, m, details)
+}
+
+// parseReportCase parses a report case hash.
+func parseReportCase(caseID int, m map[string]string, details []string) (*models.ReportCase, error) {
+	targetID, err := strconv.Atoi(m["target_id"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid target_id: %w", err)
+	}
+	authorID, err := strconv.Atoi(m["author_id"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid author_id: %w", err)
+	}
+	reportCount, err := optionalInt(m, "report_count")
+	if err != nil {
+		return nil, err
+	}
+	lastReportedAt, err := optionalTime(m, "last_reported_at")
+	if err != nil {
+		return nil, err
+	}
+	rc := &models.ReportCase{
+		ID:             caseID,
+		TargetType:     models.ReportTarget(m["target_type"]),
+		TargetID:       targetID,
+		AuthorID:       authorID,
+		ReportCount:    int(reportCount),
+		Reasons:        make(map[models.ReportReason]int),
+		LastReportedAt: lastReportedAt,
+	}
+	for field, value := range m {
+		reason, ok := strings.CutPrefix(field, "reason:")
+		if !ok {
+			continue
+		}
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", field, err)
+		}
+		rc.Reasons[models.ReportReason(reason)] = count
+	}
+	if len(details) > 0 {
+		rc.Details = details
+	}
+	return rc, nil
+}
+
+// CloseReportCase deletes a report case with its reports.
+func (r *RepoRedis) CloseReportCase(ctx context.Context, caseID int) error {
+	m, err := r.client.HMGet(ctx, reportCaseKey(caseID), "target_type", "target_id").Result()
+	if err != nil {
+		return fmt.Errorf("failed to get report case: %w", err)
+	}
+	targetType, _ := m[0].(string)
+	targetIDStr, _ := m[1].(string)
+	if targetType == "" {
+		return repository.NewErrNotFound()
+	}
+	targetID, err := strconv.Atoi(targetIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid target_id: %w", err)
+	}
+
+	targetKey := reportTargetKey(models.ReportTarget(targetType), targetID)
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, reportCaseKey(caseID), reportDetailsKey(caseID), targetKey, targetKey+":reporters")
+		pipe.ZRem(ctx, reportQueueKey, zMember(caseID))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to close report case: %w", err)
+	}
+	return nil
+}
+
+// SetPostHidden hides or restores a post. Hidden posts and their comments are removed from the search index.
+func (r *RepoRedis) SetPostHidden(ctx context.Context, postID int, hidden bool) error {
+	if err := r.setHidden(ctx, fmt.Sprintf("post:%d", postID), hidden); err != nil {
+		return err
+	}
+	if err := r.refreshSearchDoc(ctx, search.DocPost, postID); err != nil {
+		return err
+	}
+	return r.notifySearchChange(ctx, search.DocPost, postID)
+}
+
+// SetCommentHidden hides or restores a comment. Hidden comments are removed from the search index.
+func (r *RepoRedis) SetCommentHidden(ctx context.Context, commentID int, hidden bool) error {
+	if err := r.setHidden(ctx, fmt.Sprintf("comment:%d", commentID), hidden); err != nil {
+		return err
+	}
+	if err := r.refreshSearchDoc(ctx, search.DocComment, commentID); err != nil {
+		return err
+	}
+	return r.notifySearchChange(ctx, search.DocComment, commentID)
+}
+
+// setHidden sets a "hidden" field of an existing hash.
+func (r *RepoRedis) setHidden(ctx context.Context, key string, hidden bool) error {
+	exists, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check %s: %w", key, err)
+	}
+	if exists == 0 {
+		return repository.NewErrNotFound()
+	}
+	if err = r.client.HSet(ctx, key, "hidden", hidden).Err(); err != nil {
+		return fmt.Errorf("failed to set %s hidden: %w", key, err)
+	}
+	return nil
+}
+
+// BanUser bans a user.
+func (r *RepoRedis) BanUser(ctx context.Context, userID int) error {
+	key := fmt.Sprintf("user:%d", userID)
+	exists, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check user: %w", err)
+	}
+	if exists == 0 {
+		return repository.NewErrNotFound()
+	}
+	if err = r.client.HSet(ctx, key, "banned", true).Err(); err != nil {
+		return fmt.Errorf("failed to ban user: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"ozon_test_task/pkg/search"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// searchChangesKey is a stream of posts and comments whose searchable state changed.
+// Every process follows it to apply writes of other replicas to its in-process search index.
+const searchChangesKey = "search:changes"
+
+// maxSearchChanges is an approximate length the search changes stream is trimmed to.
+const maxSearchChanges = 100000
+
+// searchChangesBlock is how long WatchSearchChanges waits for new changes in one read.
+const searchChangesBlock = time.Second
+
+// BuildSearchIndex adds all posts and comments stored in Redis to the in-process search index.
+// Should be called once on startup, later changes are applied by WatchSearchChanges.
+func (r *RepoRedis) BuildSearchIndex(ctx context.Context) error {
+	//changes made during the build are applied again by WatchSearchChanges, reapplying is harmless
+	last, err := r.client.XRevRangeN(ctx, searchChangesKey, "+", "-", 1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get last search change: %w", err)
+	}
+	r.searchChangesID = "0"
+	if len(last) > 0 {
+		r.searchChangesID = last[0].ID
+	}
+
+	postIDs, err := r.client.ZRange(ctx, "posts", 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get post ids: %w", err)
+	}
+	for _, idStr := range postIDs {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			return fmt.Errorf("invalid post id: %w", err)
+		}
+		if err = r.refreshPostDoc(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	//comments have no list of all ids, but ids are sequential
+	lastID, err := r.client.Get(ctx, "counter:comment").Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to get comments counter: %w", err)
+	}
+	for id := 1; id <= lastID; id++ {
+		if err = r.refreshCommentDoc(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WatchSearchChanges applies changes of posts and comments made by any process to the in-process search index until ctx is done.
+// Should be called after BuildSearchIndex. Errors are passed to onError and do not stop watching.
+func (r *RepoRedis) WatchSearchChanges(ctx context.Context, onError func(error)) {
+	lastID := r.searchChangesID
+	for ctx.Err() == nil {
+		streams, err := r.client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{searchChangesKey, lastID},
+			Block:   searchChangesBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+			continue
+		}
+		if err != nil {
+			onError(fmt.Errorf("failed to read search changes: %w", err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(searchChangesBlock):
+			}
+			continue
+		}
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				lastID = msg.ID
+				if err = r.applySearchChange(ctx, msg.Values); err != nil {
+					onError(err)
+				}
+			}
+		}
+	}
+}
+
+// applySearchChange refreshes a document of a search changes stream entry.
+func (r *RepoRedis) applySearchChange(ctx context.Context, values map[string]interface{}) error {
+	docType, _ := values["type"].(string)
+	idStr, _ := values["id"].(string)
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return fmt.Errorf("invalid search change id: %w", err)
+	}
+	return r.refreshSearchDoc(ctx, search.DocType(docType), id)
+}
+
+// notifySearchChange adds a changed post or comment to the search changes stream.
+func (r *RepoRedis) notifySearchChange(ctx context.Context, docType search.DocType, id int) error {
+	err := r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: searchChangesKey,
+		MaxLen: maxSearchChanges,
+		Approx: true,
+		Values: map[string]interface{}{"type": string(docType), "id": id},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to add search change: %w", err)
+	}
+	return nil
+}
+
+// refreshSearchDoc updates a post or a comment in the in-process search index.
+// Comments of a post are refreshed with it, because they are searchable only while the post is.
+func (r *RepoRedis) refreshSearchDoc(ctx context.Context, docType search.DocType, id int) error {
+	switch docType {
+	case search.DocPost:
+		if err := r.refreshPostDoc(ctx, id); err != nil {
+			return err
+		}
+		commentIDs, err := r.threadCommentIDs(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, commentID := range commentIDs {
+			if err = r.refreshCommentDoc(ctx, commentID); err != nil {
+				return err
+			}
+		}
+		return nil
+	case search.DocComment:
+		return r.refreshCommentDoc(ctx, id)
+	default:
+		return fmt.Errorf("unknown search document type %q", docType)
+	}
+}
+
+// refreshPostDoc loads a post and adds it to the in-process search index,
+// or removes it from the index if it is not searchable.
+func (r *RepoRedis) refreshPostDoc(ctx context.Context, postID int) error {
+	post, err := r.searchablePost(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		r.index.Remove(search.DocPost, postID)
+		return nil
+	}
+	r.index.Add(postDoc(post))
+	return nil
+}
+
+// refreshCommentDoc loads a comment and adds it to the in-process search index,
+// or removes it from the index if it is missing, hidden or its post is not searchable.
+func (r *RepoRedis) refreshCommentDoc(ctx context.Context, commentID int) error {
+	comment, err := r.GetCommentByID(ctx, commentID)
+	if err != nil && !errors.Is(err, repository.NewErrNotFound()) {
+		return fmt.Errorf("failed to get comment by id: %w", err)
+	}
+	if err != nil || comment.Hidden {
+		r.index.Remove(search.DocComment, commentID)
+		return nil
+	}
+	postID, err := r.GetCommentPostID(ctx, commentID)
+	if err != nil && !errors.Is(err, repository.NewErrNotFound()) {
+		return fmt.Errorf("failed to get comment post id: %w", err)
+	}
+	var post *models.Post
+	if err == nil {
+		if post, err = r.searchablePost(ctx, postID); err != nil {
+			return err
+		}
+	}
+	if post == nil {
+		r.index.Remove(search.DocComment, commentID)
+		return nil
+	}
+	r.index.Add(commentDoc(comment))
+	return nil
+}
+
+// searchablePost returns a post if it is published and not hidden, nil otherwise.
+func (r *RepoRedis) searchablePost(ctx context.Context, postID int) (*models.Post, error) {
+	post, err := r.GetPostByID(ctx, postID)
+	if errors.Is(err, repository.NewErrNotFound()) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get post by id: %w", err)
+	}
+	if post.Hidden || post.Status != models.PostPublished {
+		return nil, nil
+	}
+	return post, nil
+}
+
+// threadCommentIDs returns IDs of all comments of a post, including replies at any depth.
+func (r *RepoRedis) threadCommentIDs(ctx context.Context, postID int) ([]int, error) {
+	var ids []int
+	key := fmt.Sprintf("post:%d:comments", postID)
+	for queue := []string{key}; len(queue) > 0; queue = queue[1:] {
+		members, err := r.client.ZRange(ctx, queue[0], 0, -1).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comment ids: %w", err)
+		}
+		for _, member := range members {
+			id, err := strconv.Atoi(member)
+			if err != nil {
+				return nil, fmt.Errorf("invalid comment id: %w", err)
+			}
+			ids = append(ids, id)
+			queue = append(queue, fmt.Sprintf("comment:%d:replies", id))
+		}
+	}
+	return ids, nil
+}
+
+// Search finds posts and comments using the in-process search index.
+func (r *RepoRedis) Search(ctx context.Context, query repository.SearchQuery, page repository.PageArgs) (hits []*models.SearchHit, hasNextPage bool, err error) {
+	filter := search.Filter{OwnerID: query.AuthorID}
+	for _, t := range query.Types {
+		filter.Types = append(filter.Types, search.DocType(t))
+	}
+	if query.From != nil {
+		filter.From = *query.From
+	}
+	if query.To != nil {
+		filter.To = *query.To
+	}
+	terms := search.Terms(query.Text)
+	found := r.index.Search(terms, filter)
+
+	//skip hits before "after" one (including itself)
+	if page.After != nil {
+		start := sort.Search(len(found), func(i int) bool {
+			return found[i].Rank < page.After.Key || found[i].Rank == page.After.Key && found[i].Doc.SortID() < page.After.ID
+		})
+		found = found[start:]
+	}
+	if len(found) > page.Limit {
+		hasNextPage = true
+		found = found[:page.Limit]
+	}
+
+	for _, f := range found {
+		hit := &models.SearchHit{
+			Rank:    f.Rank,
+			Snippet: search.Snippet(strings.Join(f.Doc.Fields, "\n"), terms),
+		}
+		if f.Doc.Type == search.DocPost {
+			hit.Post, err = r.GetPostByID(ctx, f.Doc.ID)
+		} else {
+			hit.Comment, err = r.GetCommentByID(ctx, f.Doc.ID)
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get search hit: %w", err)
+		}
+		hits = append(hits, hit)
+	}
+	return hits, hasNextPage, nil
+}
+
+// postDoc returns a search document of a post.
+func postDoc(post *models.Post) *search.Doc {
+	return &search.Doc{
+		Type:      search.DocPost,
+		ID:        post.ID,
+		OwnerID:   post.Owner.ID,
+		CreatedAt: post.CreatedAt,
+		Fields:    []string{post.Title, post.Text},
+	}
+}
+
+// commentDoc returns a search document of a comment.
+func commentDoc(comment *models.Comment) *search.Doc {
+	return &search.Doc{
+		Type:      search.DocComment,
+		ID:        comment.ID,
+		OwnerID:   comment.Owner.ID,
+		CreatedAt: comment.CreatedAt,
+		Fields:    []string{comment.Text},
+	}
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"ozon_test_task/pkg/search"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func TestRepoRedis_WatchSearchChanges(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedisClient(t)
+	writer, reader := NewRepoRedis(client), NewRepoRedis(client)
+
+	err := client.HSet(ctx, "user:2", map[string]any{"login": "owner"}).Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.HSet(ctx, "post:1", map[string]any{"owner_id": 2, "title": "Hello", "text": "Text", "commentsallowed": "true"}).Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.ZAdd(ctx, "posts", &redis.Z{Score: 1, Member: 1}).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err = reader.BuildSearchIndex(ctx); err != nil {
+		t.Fatalf("BuildSearchIndex() error = %v", err)
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		reader.WatchSearchChanges(watchCtx, func(err error) { t.Errorf("WatchSearchChanges() error = %v", err) })
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	//waitHits waits until the reader index has the wanted amount of hits of the post
+	waitHits := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			got := len(reader.index.Search(search.Terms("hello"), search.Filter{}))
+			if got == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("reader index has %d hits, want %d", got, want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	waitHits(1)
+	if err = writer.SetPostHidden(ctx, 1, true); err != nil {
+		t.Fatalf("SetPostHidden() error = %v", err)
+	}
+	waitHits(0)
+	if err = writer.SetPostHidden(ctx, 1, false); err != nil {
+		t.Fatalf("SetPostHidden() error = %v", err)
+	}
+	waitHits(1)
+}
+
+func TestRepoRedis_SearchHiddenPostComments(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedisClient(t)
+	r := NewRepoRedis(client)
+
+	//the post is hidden before the index is built, its comment and reply must not be found
+	setFakeHashes(t, client, map[string]map[string]any{
+		"user:2":    {"login": "owner"},
+		"post:1":    {"owner_id": 2, "title": "Title", "text": "Text", "commentsallowed": "true", "hidden": 1},
+		"comment:1": {"owner_id": 2, "post_id": 1, "parent_id": 0, "text": "Hello", "created_at": 100},
+		"comment:2": {"owner_id": 2, "post_id": 0, "parent_id": 1, "text": "Hello again", "created_at": 200},
+	})
+	if err := client.Set(ctx, "counter:comment", 2, 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	for key, member := range map[string]int{"posts": 1, "post:1:comments": 1, "comment:1:replies": 2} {
+		if err := client.ZAdd(ctx, key, &redis.Z{Score: float64(member), Member: member}).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.BuildSearchIndex(ctx); err != nil {
+		t.Fatalf("BuildSearchIndex() error = %v", err)
+	}
+
+	hits := func() int {
+		return len(r.index.Search(search.Terms("hello"), search.Filter{Types: []search.DocType{search.DocComment}}))
+	}
+	if got := hits(); got != 0 {
+		t.Errorf("comments of a hidden post have %d hits, want 0", got)
+	}
+	if err := r.SetPostHidden(ctx, 1, false); err != nil {
+		t.Fatalf("SetPostHidden() error = %v", err)
+	}
+	if got := hits(); got != 2 {
+		t.Errorf("comments of a restored post have %d hits, want 2", got)
+	}
+	if err := r.SetPostHidden(ctx, 1, true); err != nil {
+		t.Fatalf("SetPostHidden() error = %v", err)
+	}
+	if got := hits(); got != 0 {
+		t.Errorf("comments of a hidden post have %d hits, want 0", got)
+	}
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozon_test_task/internal/app/graph/repository"
+	"ozon_test_task/internal/app/models"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// tagPostsKey returns a key of a sorted set with IDs of posts with a given tag.
+func tagPostsKey(tag string) string {
+	return fmt.Sprintf("tag:%s:posts", tag)
+}
+
+// userPostsKey returns a key of a sorted set with IDs of posts of a given user.
+func userPostsKey(userID int) string {
+	return fmt.Sprintf("user:%d:posts", userID)
+}
+
+// postSetKeys returns keys of sorted sets a post belongs to: all posts, posts of its owner and posts of each tag.
+// Each key has ":by_comments", ":by_activity" and ":by_score" variants.
+func postSetKeys(ownerID int, tags []string) []string {
+	keys := []string{"posts", userPostsKey(ownerID)}
+	for _, tag := range tags {
+		keys = append(keys, tagPostsKey(tag))
+	}
+	return keys
+}
+
+// splitTags parses a "tags" field of a post hash.
+func splitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	return strings.Split(tags, ",")
+}
+
+// GetTag returns a tag with amount of its posts.
+func (r *RepoRedis) GetTag(ctx context.Context, name string) (*models.Tag, error) {
+	count, err := r.client.ZScore(ctx, "tags", name).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, repository.NewErrNotFound()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return &models.Tag{Name: name, PostsCount: int(count)}, nil
+}
+
+// GetPopularTags returns tags with the most posts.
+func (r *RepoRedis) GetPopularTags(ctx context.Context, limit int) (tags []*models.Tag, err error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	zs, err := r.client.ZRevRangeWithScores(ctx, "tags", 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get popular tags: %w", err)
+	}
+	for _, z := range zs {
+		name, _ := z.Member.(string)
+		tags = append(tags, &models.Tag{Name: name, PostsCount: int(z.Score)})
+	}
+	return tags, nil
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"ozon_test_task/internal/app/graph/repository"
+	"testing"
+)
+
+func TestRepoRedis_GetPostByID(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newFakeRedisClient(t)
+	r := NewRepoRedis(client)
+
+	post, err := r.GetPostByID(ctx, 1)
+	if !errors.Is(err, repository.NewErrNotFound()) || post != nil {
+		t.Errorf("GetPostByID() of a missing post = %v, %v, want NotFound", post, err)
+	}
+
+	err = client.HSet(ctx, "user:2", map[string]any{"login": "owner"}).Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.HSet(ctx, "post:1", map[string]any{"owner_id": 2, "title": "Title", "text": "Text", "commentsallowed": "true"}).Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err = r.GetPostByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetPostByID() error = %v", err)
+	}
+	if post.ID != 1 || post.Owner.ID != 2 || post.Title != "Title" {
+		t.Errorf("GetPostByID() = %+v", post)
+	}
+}
@@ -0,0 +1,83 @@
+package markdown
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"html"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/russross/blackfriday/v2"
+)
+
+// Format is a markup format of a rendered text.
+type Format string
+
+const (
+	FormatPlain    Format = "PLAIN"
+	FormatMarkdown Format = "MARKDOWN"
+)
+
+// markdownExtensions are Markdown syntax extensions, GitHub-like tables, fenced code, autolinks and strikethrough.
+const markdownExtensions = blackfriday.NoIntraEmphasis | blackfriday.Tables | blackfriday.FencedCode |
+	blackfriday.Autolink | blackfriday.Strikethrough | blackfriday.SpaceHeadings | blackfriday.BackslashLineBreak
+
+// Renderer renders texts into sanitized HTML and caches results by a hash of a format and a text.
+// It is safe for concurrent use.
+type Renderer struct {
+	cache *lru.Cache[[sha256.Size]byte, string]
+}
+
+// NewRenderer creates a Renderer caching up to cacheSize rendered texts.
+func NewRenderer(cacheSize int) (*Renderer, error) {
+	cache, err := lru.New[[sha256.Size]byte, string](cacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create render cache: %w", err)
+	}
+	return &Renderer{cache: cache}, nil
+}
+
+// Render returns sanitized HTML of a text in a given format, unknown formats are rendered as plain text.
+func (r *Renderer) Render(format Format, text string) string {
+	key := sha256.Sum256([]byte(string(format) + "\x00" + text))
+	if rendered, ok := r.cache.Get(key); ok {
+		return rendered
+	}
+
+	var rendered string
+	if format == FormatMarkdown {
+		rendered = renderMarkdown(text)
+	} else {
+		rendered = renderPlain(text)
+	}
+	r.cache.Add(key, rendered)
+	return rendered
+}
+
+// renderMarkdown renders Markdown into sanitized HTML. Raw HTML in the text is skipped.
+func renderMarkdown(text string) string {
+	renderer := blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{
+		Flags: blackfriday.SkipHTML | blackfriday.Safelink,
+	})
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	out := blackfriday.Run([]byte(text), blackfriday.WithExtensions(markdownExtensions), blackfriday.WithRenderer(renderer))
+	return Sanitize(string(out))
+}
+
+// renderPlain renders plain text into escaped HTML paragraphs separated by blank lines, other line breaks are kept as <br>.
+func renderPlain(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var b strings.Builder
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+		lines := strings.Split(paragraph, "\n")
+		for i, line := range lines {
+			lines[i] = html.EscapeString(line)
+		}
+		b.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+	}
+	return b.String()
+}
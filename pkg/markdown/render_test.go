@@ -0,0 +1,89 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderer_Render(t *testing.T) {
+	r, err := NewRenderer(16)
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+		text   string
+		want   string
+	}{
+		{
+			name:   "plain text is escaped",
+			format: FormatPlain,
+			text:   "a <b>\r\nc\n\n\nd & e",
+			want:   "<p>a &lt;b&gt;<br>\nc</p>\n<p>d &amp; e</p>\n",
+		},
+		{
+			name:   "unknown format is plain",
+			format: Format("BBCODE"),
+			text:   "[b]x[/b]",
+			want:   "<p>[b]x[/b]</p>\n",
+		},
+		{
+			name:   "markdown",
+			format: FormatMarkdown,
+			text:   "# Title\n\nSome *em*, **strong** and ~~del~~.",
+			want:   "<h1>Title</h1>\n\n<p>Some <em>em</em>, <strong>strong</strong> and <del>del</del>.</p>\n",
+		},
+		{
+			name:   "markdown links",
+			format: FormatMarkdown,
+			text:   "[a](https://example.com) and https://go.dev",
+			want: `<p><a href="https://example.com" rel="nofollow noopener noreferrer">a</a> and ` +
+				`<a href="https://go.dev" rel="nofollow noopener noreferrer">https://go.dev</a></p>` + "\n",
+		},
+		{
+			name:   "markdown code is escaped",
+			format: FormatMarkdown,
+			text:   "```\n<script>alert(1)</script>\n```",
+			want:   "<pre><code>&lt;script&gt;alert(1)&lt;/script&gt;\n</code></pre>\n",
+		},
+		{
+			name:   "raw html in markdown is skipped",
+			format: FormatMarkdown,
+			text:   "a <img src=x onerror=alert(1)> <script>alert(1)</script>",
+			want:   "<p>a  alert(1)</p>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Render(tt.format, tt.text); got != tt.want {
+				t.Errorf("Render() got = %q, want %q", got, tt.want)
+			}
+			//cached result is the same
+			if got := r.Render(tt.format, tt.text); got != tt.want {
+				t.Errorf("Render() cached got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderer_Render_cacheKeyedByFormat(t *testing.T) {
+	r, err := NewRenderer(16)
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+	text := "*a*"
+	if got := r.Render(FormatMarkdown, text); !strings.Contains(got, "<em>") {
+		t.Errorf("Render() markdown got = %q", got)
+	}
+	if got := r.Render(FormatPlain, text); strings.Contains(got, "<em>") {
+		t.Errorf("Render() plain got = %q, want text as is", got)
+	}
+}
+
+func TestNewRenderer_invalidSize(t *testing.T) {
+	if _, err := NewRenderer(0); err == nil {
+		t.Errorf("NewRenderer() error = nil, want error")
+	}
+}
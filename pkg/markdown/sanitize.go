@@ -0,0 +1,126 @@
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"slices"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// allowedTags are tags kept by Sanitize with their allowed attributes, all other tags are removed with their attributes.
+var allowedTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil,
+	"em": nil, "strong": nil, "del": nil, "code": nil, "pre": nil, "blockquote": nil,
+	"ul": nil, "ol": {"start"}, "li": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"a":   {"href", "title"},
+	"img": {"src", "alt", "title"},
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil, "th": {"align"}, "td": {"align"},
+}
+
+// droppedTags are tags removed by Sanitize together with their content.
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true, "template": true,
+	"noscript": true, "textarea": true, "title": true, "svg": true, "math": true,
+}
+
+// voidTags are allowed tags without content and closing tags.
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// urlAttrs are attributes with URLs, only allowedSchemes or relative URLs are kept in them.
+var urlAttrs = map[string]bool{"href": true, "src": true}
+
+var allowedSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+// linkRel is a rel attribute added to every link, so user content doesn't pass reputation or window.opener.
+const linkRel = "nofollow noopener noreferrer"
+
+// Sanitize returns well-formed HTML with only allowlisted tags, attributes and URL schemes.
+// Text and attribute values are re-escaped, unclosed tags are closed and stray closing tags are removed.
+func Sanitize(s string) string {
+	var b strings.Builder
+	var open []string // stack of open allowed tags
+	dropDepth := 0    // nesting depth inside droppedTags
+	z := xhtml.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			break
+		}
+		token := z.Token()
+		name := token.Data
+		switch tt {
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			if droppedTags[name] {
+				if tt == xhtml.StartTagToken {
+					dropDepth++
+				}
+				continue
+			}
+			attrs, ok := allowedTags[name]
+			if !ok || dropDepth > 0 {
+				continue
+			}
+			writeStartTag(&b, name, attrs, token.Attr)
+			if !voidTags[name] {
+				open = append(open, name)
+			}
+		case xhtml.EndTagToken:
+			if droppedTags[name] {
+				if dropDepth > 0 {
+					dropDepth--
+				}
+				continue
+			}
+			i := slices.Index(open, name)
+			if dropDepth > 0 || i < 0 {
+				continue
+			}
+			//close tags opened inside the closed one
+			for j := len(open) - 1; j >= i; j-- {
+				b.WriteString("</" + open[j] + ">")
+			}
+			open = open[:i]
+		case xhtml.TextToken:
+			if dropDepth == 0 {
+				b.WriteString(html.EscapeString(name))
+			}
+		}
+	}
+	for j := len(open) - 1; j >= 0; j-- {
+		b.WriteString("</" + open[j] + ">")
+	}
+	return b.String()
+}
+
+// writeStartTag writes a start tag with allowed attributes only.
+func writeStartTag(b *strings.Builder, name string, allowed []string, attrs []xhtml.Attribute) {
+	b.WriteString("<" + name)
+	for _, attr := range attrs {
+		if attr.Namespace != "" || !slices.Contains(allowed, attr.Key) {
+			continue
+		}
+		if urlAttrs[attr.Key] && !isSafeURL(attr.Val) {
+			continue
+		}
+		b.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+	}
+	if name == "a" {
+		b.WriteString(` rel="` + linkRel + `"`)
+	}
+	b.WriteString(">")
+}
+
+// isSafeURL returns true for relative URLs and URLs with allowedSchemes.
+func isSafeURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		return u.Opaque == ""
+	}
+	return allowedSchemes[strings.ToLower(u.Scheme)]
+}
@@ -0,0 +1,65 @@
+package markdown
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "allowed tags are kept",
+			in:   `<p><em>a</em> <strong>b</strong> <code>c</code></p>`,
+			want: `<p><em>a</em> <strong>b</strong> <code>c</code></p>`,
+		},
+		{
+			name: "disallowed tags are removed with attributes, text is kept",
+			in:   `<div class="x" onclick="alert(1)"><span>text</span></div>`,
+			want: `text`,
+		},
+		{
+			name: "scripts are removed with content",
+			in:   `a<script>alert(1)<b>x</b></script>b<style>p{}</style>`,
+			want: `ab`,
+		},
+		{
+			name: "event handlers are removed",
+			in:   `<p onclick="alert(1)" style="color:red">a</p><img src="https://example.com/a.png" onerror="alert(1)">`,
+			want: `<p>a</p><img src="https://example.com/a.png">`,
+		},
+		{
+			name: "unsafe link schemes are removed",
+			in:   `<a href="JaVaScript:alert(1)">a</a><a href=" data:text/html,x">b</a><img src="vbscript:x" alt="c">`,
+			want: `<a rel="nofollow noopener noreferrer">a</a><a rel="nofollow noopener noreferrer">b</a><img alt="c">`,
+		},
+		{
+			name: "safe links get rel",
+			in:   `<a href="https://example.com/?a=1&amp;b=2" title="t" target="_blank">a</a><a href="/posts/1">b</a><a href="mailto:a@b.c">c</a>`,
+			want: `<a href="https://example.com/?a=1&amp;b=2" title="t" rel="nofollow noopener noreferrer">a</a>` +
+				`<a href="/posts/1" rel="nofollow noopener noreferrer">b</a><a href="mailto:a@b.c" rel="nofollow noopener noreferrer">c</a>`,
+		},
+		{
+			name: "text is escaped",
+			in:   `1 &lt; 2 &amp;&amp; "q"`,
+			want: `1 &lt; 2 &amp;&amp; &#34;q&#34;`,
+		},
+		{
+			name: "unclosed tags are closed, stray closing tags are removed",
+			in:   `<ul><li><em>a</li></ul></p></blockquote><p>b`,
+			want: `<ul><li><em>a</em></li></ul><p>b</p>`,
+		},
+		{
+			name: "void tags",
+			in:   `a<br/>b<hr><br></br>`,
+			want: `a<br>b<hr><br>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.in); got != tt.want {
+				t.Errorf("Sanitize() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
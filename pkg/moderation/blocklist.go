@@ -0,0 +1,138 @@
+package moderation
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Blocklist prefixes of entries.
+const (
+	holdPrefix  = "hold:" // entry holds matched texts instead of rejecting them.
+	regexPrefix = "re:"   // entry is a regular expression instead of a word.
+)
+
+// nonWordChar matches a character which is not a part of a word. Unlike \b it treats non-ASCII letters as word characters.
+const nonWordChar = `[^\p{L}\p{N}_]`
+
+// blockEntry is a parsed blocklist entry.
+type blockEntry struct {
+	pattern *regexp.Regexp
+	verdict Verdict
+	source  string // entry as written in the file, used in reasons.
+}
+
+// Blocklist rejects or holds texts matching words or regular expressions loaded from a file.
+// The file has an entry per line, empty lines and lines starting with "#" are skipped.
+// Words are matched case-insensitively as whole words, "re:" entries are Go regular expressions.
+// Matched texts are rejected unless an entry starts with "hold:", e.g. "hold:re:(?i)buy\s+now".
+type Blocklist struct {
+	path    string
+	entries atomic.Pointer[[]blockEntry]
+	modTime time.Time
+	size    int64
+}
+
+// NewBlocklist loads a blocklist from a file.
+func NewBlocklist(path string) (*Blocklist, error) {
+	b := &Blocklist{path: path}
+	if _, err := b.Reload(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Check implements Rule.
+func (b *Blocklist) Check(text string) (Verdict, string) {
+	verdict := Allow
+	reason := ""
+	for _, e := range *b.entries.Load() {
+		if e.verdict <= verdict || !e.pattern.MatchString(text) {
+			continue
+		}
+		verdict = e.verdict
+		reason = fmt.Sprintf("blocklisted %q", e.source)
+	}
+	return verdict, reason
+}
+
+// Reload reloads the file if it was modified since the last load and returns true if it was reloaded.
+// Entries are kept as is if the file can't be read or parsed.
+// Reload is not safe for concurrent use, Check is safe to call during it.
+func (b *Blocklist) Reload() (bool, error) {
+	f, err := os.Open(b.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open blocklist: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return false, fmt.Errorf("failed to stat blocklist: %w", err)
+	}
+	if b.entries.Load() != nil && info.ModTime().Equal(b.modTime) && info.Size() == b.size {
+		return false, nil
+	}
+
+	entries, err := parseBlocklist(f)
+	if err != nil {
+		return false, err
+	}
+	b.entries.Store(&entries)
+	b.modTime = info.ModTime()
+	b.size = info.Size()
+	return true, nil
+}
+
+// Watch reloads the file every interval until ctx is done. Reload errors are passed to onError.
+func (b *Blocklist) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := b.Reload(); err != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// parseBlocklist parses blocklist entries.
+func parseBlocklist(r io.Reader) ([]blockEntry, error) {
+	var entries []blockEntry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		source := strings.TrimSpace(scanner.Text())
+		if source == "" || strings.HasPrefix(source, "#") {
+			continue
+		}
+		entry := blockEntry{verdict: Reject, source: source}
+		s := source
+		if rest, ok := strings.CutPrefix(s, holdPrefix); ok {
+			entry.verdict = Hold
+			s = rest
+		}
+		expr := `(?i)(?:^|` + nonWordChar + `)` + regexp.QuoteMeta(s) + `(?:$|` + nonWordChar + `)`
+		if rest, ok := strings.CutPrefix(s, regexPrefix); ok {
+			expr = rest
+		}
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocklist entry on line %d: %w", line, err)
+		}
+		entry.pattern = pattern
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read blocklist: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,53 @@
+package moderation
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// linkPattern matches links with a scheme or starting with "www.".
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// LinkLimit gives Verdict to texts with more than Max links.
+type LinkLimit struct {
+	Max     int
+	Verdict Verdict
+}
+
+// Check implements Rule.
+func (l LinkLimit) Check(text string) (Verdict, string) {
+	links := linkPattern.FindAllStringIndex(text, l.Max+1)
+	if len(links) > l.Max {
+		return l.Verdict, fmt.Sprintf("more than %d links", l.Max)
+	}
+	return Allow, ""
+}
+
+// RepeatedChars gives Verdict to texts with a character repeated more than MaxRun times in a row, e.g. "!!!!!!!!!!!!".
+// Whitespace is not counted.
+type RepeatedChars struct {
+	MaxRun  int
+	Verdict Verdict
+}
+
+// Check implements Rule.
+func (r RepeatedChars) Check(text string) (Verdict, string) {
+	var prev rune
+	run := 0
+	for _, c := range text {
+		if c == prev {
+			run++
+		} else {
+			prev = c
+			run = 1
+		}
+		if run > r.MaxRun && !isSpace(c) {
+			return r.Verdict, fmt.Sprintf("a character repeated more than %d times", r.MaxRun)
+		}
+	}
+	return Allow, ""
+}
+
+func isSpace(c rune) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,181 @@
+package moderation
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeBlocklist(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write blocklist: %v", err)
+	}
+}
+
+func TestBlocklist_Check(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	writeBlocklist(t, path, "# comment\n\nspam\nhold:casino\nre:(?i)buy\\s+now\nhold:re:\\d{16}\nкупить\n")
+	b, err := NewBlocklist(path)
+	if err != nil {
+		t.Fatalf("NewBlocklist() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		text        string
+		wantVerdict Verdict
+	}{
+		{name: "clean", text: "Hello, world", wantVerdict: Allow},
+		{name: "word", text: "Some SPAM here", wantVerdict: Reject},
+		{name: "word is matched as a whole word", text: "spammer and antispam", wantVerdict: Allow},
+		{name: "non-ASCII word", text: "Купить сейчас", wantVerdict: Reject},
+		{name: "non-ASCII word is matched as a whole word", text: "покупить", wantVerdict: Allow},
+		{name: "hold word", text: "best casino", wantVerdict: Hold},
+		{name: "regex", text: "Buy   now!", wantVerdict: Reject},
+		{name: "hold regex", text: "card 1234567812345678", wantVerdict: Hold},
+		{name: "reject wins", text: "casino spam", wantVerdict: Reject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, reason := b.Check(tt.text)
+			if verdict != tt.wantVerdict {
+				t.Errorf("Check() verdict = %v, want %v", verdict, tt.wantVerdict)
+			}
+			if (verdict == Allow) != (reason == "") {
+				t.Errorf("Check() reason = %q with verdict %v", reason, verdict)
+			}
+		})
+	}
+}
+
+func TestBlocklist_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	writeBlocklist(t, path, "spam\n")
+	b, err := NewBlocklist(path)
+	if err != nil {
+		t.Fatalf("NewBlocklist() error = %v", err)
+	}
+
+	reloaded, err := b.Reload()
+	if err != nil || reloaded {
+		t.Errorf("Reload() of not modified file = %v, %v, want false, nil", reloaded, err)
+	}
+
+	writeBlocklist(t, path, "ham\n")
+	//mtime resolution of some file systems is coarse
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatalf("failed to change mtime: %v", err)
+	}
+	reloaded, err = b.Reload()
+	if err != nil || !reloaded {
+		t.Fatalf("Reload() of modified file = %v, %v, want true, nil", reloaded, err)
+	}
+	if v, _ := b.Check("spam"); v != Allow {
+		t.Errorf("Check() of removed entry = %v, want %v", v, Allow)
+	}
+	if v, _ := b.Check("ham"); v != Reject {
+		t.Errorf("Check() of added entry = %v, want %v", v, Reject)
+	}
+
+	//invalid file keeps previous entries
+	writeBlocklist(t, path, "re:(\n")
+	future = future.Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatalf("failed to change mtime: %v", err)
+	}
+	if _, err = b.Reload(); err == nil {
+		t.Errorf("Reload() of invalid file error = nil, want error")
+	}
+	if v, _ := b.Check("ham"); v != Reject {
+		t.Errorf("Check() after failed reload = %v, want %v", v, Reject)
+	}
+}
+
+func TestNewBlocklist_invalid(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewBlocklist(filepath.Join(dir, "absent.txt")); err == nil {
+		t.Errorf("NewBlocklist() of absent file error = nil, want error")
+	}
+	path := filepath.Join(dir, "blocklist.txt")
+	writeBlocklist(t, path, "ok\nre:[a-\n")
+	if _, err := NewBlocklist(path); err == nil {
+		t.Errorf("NewBlocklist() of invalid regex error = nil, want error")
+	}
+}
+
+func TestLinkLimit_Check(t *testing.T) {
+	rule := LinkLimit{Max: 2, Verdict: Hold}
+	tests := []struct {
+		name string
+		text string
+		want Verdict
+	}{
+		{name: "no links", text: "hello", want: Allow},
+		{name: "under limit", text: "see https://a.com and www.b.org", want: Allow},
+		{name: "over limit", text: "http://a.com HTTPS://b.com www.c.com", want: Hold},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := rule.Check(tt.text); got != tt.want {
+				t.Errorf("Check() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepeatedChars_Check(t *testing.T) {
+	rule := RepeatedChars{MaxRun: 3, Verdict: Hold}
+	tests := []struct {
+		name string
+		text string
+		want Verdict
+	}{
+		{name: "short runs", text: "aaa bbb !!!", want: Allow},
+		{name: "long run", text: "wow!!!!", want: Hold},
+		{name: "non-ASCII run", text: "ааааа", want: Hold},
+		{name: "whitespace is not counted", text: "a     b\n\n\n\n\nc", want: Allow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := rule.Check(tt.text); got != tt.want {
+				t.Errorf("Check() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipeline_Moderate(t *testing.T) {
+	p := NewPipeline(LinkLimit{Max: 0, Verdict: Hold}, RepeatedChars{MaxRun: 3, Verdict: Reject})
+	tests := []struct {
+		name  string
+		texts []string
+		want  Decision
+	}{
+		{
+			name:  "allowed",
+			texts: []string{"title", "text"},
+			want:  Decision{Verdict: Allow},
+		},
+		{
+			name:  "held",
+			texts: []string{"title", "see www.a.com"},
+			want:  Decision{Verdict: Hold, Reasons: []string{"more than 0 links"}},
+		},
+		{
+			name:  "most severe verdict with all reasons",
+			texts: []string{"www.a.com", "!!!!"},
+			want:  Decision{Verdict: Reject, Reasons: []string{"more than 0 links", "a character repeated more than 3 times"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Moderate(tt.texts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Moderate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
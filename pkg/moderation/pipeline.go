@@ -0,0 +1,66 @@
+package moderation
+
+// Verdict is an outcome of moderation. Verdicts are ordered by severity.
+type Verdict int
+
+const (
+	// Allow lets content be saved as is.
+	Allow Verdict = iota
+	// Hold saves content hidden from other users until a moderator approves it.
+	Hold
+	// Reject doesn't let content be saved.
+	Reject
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Hold:
+		return "HOLD"
+	case Reject:
+		return "REJECT"
+	default:
+		return "ALLOW"
+	}
+}
+
+// Decision is a result of moderation of a text.
+type Decision struct {
+	Verdict Verdict
+	// Reasons are human-readable descriptions of rules that held or rejected the text.
+	Reasons []string
+}
+
+// Rule checks a text. It returns Allow and an empty reason if the text passes the rule.
+// Rules must be safe for concurrent use.
+type Rule interface {
+	Check(text string) (Verdict, string)
+}
+
+// Pipeline checks texts with a list of rules.
+type Pipeline struct {
+	rules []Rule
+}
+
+// NewPipeline creates a Pipeline checking texts with given rules.
+func NewPipeline(rules ...Rule) *Pipeline {
+	return &Pipeline{rules: rules}
+}
+
+// Moderate checks texts (e.g. a post title and text) with all rules and returns the most severe verdict
+// with reasons of every rule which didn't allow them.
+func (p *Pipeline) Moderate(texts ...string) Decision {
+	var d Decision
+	for _, text := range texts {
+		for _, rule := range p.rules {
+			verdict, reason := rule.Check(text)
+			if verdict == Allow {
+				continue
+			}
+			if verdict > d.Verdict {
+				d.Verdict = verdict
+			}
+			d.Reasons = append(d.Reasons, reason)
+		}
+	}
+	return d
+}
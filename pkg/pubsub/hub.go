@@ -0,0 +1,57 @@
+// Package pubsub delivers messages to in-process subscribers of topics.
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// Hub delivers messages published to a topic to all its current subscribers.
+// Delivery is in-process only, messages published by other processes are not received.
+type Hub[K comparable, T any] struct {
+	mu     sync.Mutex
+	subs   map[K]map[chan T]struct{}
+	buffer int
+}
+
+// NewHub returns a new Hub, buffer is an amount of messages kept for a slow subscriber.
+func NewHub[K comparable, T any](buffer int) *Hub[K, T] {
+	return &Hub[K, T]{subs: make(map[K]map[chan T]struct{}), buffer: buffer}
+}
+
+// Subscribe returns a channel receiving messages of a topic until ctx is done, the channel is closed then.
+func (h *Hub[K, T]) Subscribe(ctx context.Context, topic K) <-chan T {
+	ch := make(chan T, h.buffer)
+
+	h.mu.Lock()
+	if h.subs[topic] == nil {
+		h.subs[topic] = make(map[chan T]struct{})
+	}
+	h.subs[topic][ch] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs[topic], ch)
+		if len(h.subs[topic]) == 0 {
+			delete(h.subs, topic)
+		}
+		close(ch)
+	}()
+	return ch
+}
+
+// Publish sends a message to subscribers of a topic without blocking.
+// The message is dropped for subscribers whose buffer is full.
+func (h *Hub[K, T]) Publish(topic K, msg T) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[topic] {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
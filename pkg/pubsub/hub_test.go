@@ -0,0 +1,50 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHub(t *testing.T) {
+	hub := NewHub[int, string](1)
+	ctx, cancel := context.WithCancel(context.Background())
+	first := hub.Subscribe(ctx, 1)
+	second := hub.Subscribe(context.Background(), 1)
+	other := hub.Subscribe(context.Background(), 2)
+
+	hub.Publish(1, "hello")
+	for _, ch := range []<-chan string{first, second} {
+		select {
+		case msg := <-ch:
+			if msg != "hello" {
+				t.Errorf("got %q, want %q", msg, "hello")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("message was not delivered")
+		}
+	}
+	select {
+	case msg := <-other:
+		t.Errorf("subscriber of another topic got %q", msg)
+	default:
+	}
+
+	//full buffer doesn`t block a publisher
+	hub.Publish(1, "one")
+	hub.Publish(1, "two")
+	if msg := <-second; msg != "one" {
+		t.Errorf("got %q, want %q", msg, "one")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-first:
+		for ok {
+			_, ok = <-first
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed after unsubscribe")
+	}
+	hub.Publish(1, "after unsubscribe")
+}
@@ -0,0 +1,194 @@
+package search
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BM25 ranking parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// DocType is a type of indexed document.
+type DocType string
+
+const (
+	DocPost    DocType = "POST"
+	DocComment DocType = "COMMENT"
+)
+
+// Doc is an indexed document.
+type Doc struct {
+	Type      DocType
+	ID        int
+	OwnerID   int
+	CreatedAt time.Time
+	// Fields are document texts by importance, terms of earlier fields weigh more (e.g. post title, then text).
+	Fields []string
+}
+
+// Filter narrows search results. Zero values mean "any".
+type Filter struct {
+	Types   []DocType
+	OwnerID int
+	From    time.Time
+	To      time.Time
+}
+
+// RankScale is a multiplier of BM25 scores to make integer ranks.
+const RankScale = 1e6
+
+// Hit is a matched document with its relevance rank (scaled BM25 score, bigger is better).
+type Hit struct {
+	Doc  *Doc
+	Rank int64
+}
+
+// SortID returns an ID unique among documents of all types, used as a tiebreaker for equal ranks.
+// Must match models.SearchHit.SortID.
+func (d *Doc) SortID() int {
+	if d.Type == DocPost {
+		return d.ID*2 + 1
+	}
+	return d.ID * 2
+}
+
+type docKey struct {
+	docType DocType
+	id      int
+}
+
+type indexedDoc struct {
+	doc    *Doc
+	length float64
+}
+
+// Index is an in-memory inverted index with BM25 ranking. It is safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[docKey]*indexedDoc
+	postings map[string]map[docKey]float64 // term -> document -> weighted term frequency.
+	totalLen float64
+}
+
+// NewIndex returns a new empty Index.
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[docKey]*indexedDoc),
+		postings: make(map[string]map[docKey]float64),
+	}
+}
+
+// Add adds a document to the index, replacing a previous version of it.
+func (idx *Index) Add(doc *Doc) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	key := docKey{docType: doc.Type, id: doc.ID}
+	idx.remove(key)
+
+	indexed := &indexedDoc{doc: doc}
+	for i, field := range doc.Fields {
+		weight := float64(len(doc.Fields) - i)
+		for _, t := range tokenize(field) {
+			if idx.postings[t.term] == nil {
+				idx.postings[t.term] = make(map[docKey]float64)
+			}
+			idx.postings[t.term][key] += weight
+			indexed.length++
+		}
+	}
+	idx.docs[key] = indexed
+	idx.totalLen += indexed.length
+}
+
+// Remove removes a document from the index if it exists.
+func (idx *Index) Remove(docType DocType, id int) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(docKey{docType: docType, id: id})
+}
+
+func (idx *Index) remove(key docKey) {
+	indexed, ok := idx.docs[key]
+	if !ok {
+		return
+	}
+	for _, field := range indexed.doc.Fields {
+		for _, t := range tokenize(field) {
+			delete(idx.postings[t.term], key)
+			if len(idx.postings[t.term]) == 0 {
+				delete(idx.postings, t.term)
+			}
+		}
+	}
+	idx.totalLen -= indexed.length
+	delete(idx.docs, key)
+}
+
+// Search returns documents containing all terms, sorted by rank descending.
+// Documents with equal ranks are sorted by SortID descending.
+func (idx *Index) Search(terms []string, filter Filter) []Hit {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if len(terms) == 0 || len(idx.docs) == 0 {
+		return nil
+	}
+	avgLen := idx.totalLen / float64(len(idx.docs))
+
+	//start with the rarest term to check less documents
+	sorted := slices.Clone(terms)
+	sort.Slice(sorted, func(i, j int) bool { return len(idx.postings[sorted[i]]) < len(idx.postings[sorted[j]]) })
+
+	var hits []Hit
+	for key := range idx.postings[sorted[0]] {
+		indexed := idx.docs[key]
+		if !matchFilter(indexed.doc, filter) {
+			continue
+		}
+		score := 0.0
+		for _, term := range sorted {
+			tf, ok := idx.postings[term][key]
+			if !ok {
+				score = -1
+				break
+			}
+			df := float64(len(idx.postings[term]))
+			idf := math.Log(1 + (float64(len(idx.docs))-df+0.5)/(df+0.5))
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*indexed.length/avgLen))
+		}
+		if score >= 0 {
+			hits = append(hits, Hit{Doc: indexed.doc, Rank: int64(math.Round(score * RankScale))})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hits[i].Doc.SortID() > hits[j].Doc.SortID()
+	})
+	return hits
+}
+
+func matchFilter(doc *Doc, filter Filter) bool {
+	if len(filter.Types) > 0 && !slices.Contains(filter.Types, doc.Type) {
+		return false
+	}
+	if filter.OwnerID != 0 && doc.OwnerID != filter.OwnerID {
+		return false
+	}
+	if !filter.From.IsZero() && doc.CreatedAt.Before(filter.From) {
+		return false
+	}
+	if !filter.To.IsZero() && doc.CreatedAt.After(filter.To) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package search
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIndex_Search(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(&Doc{Type: DocPost, ID: 1, OwnerID: 1, CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Fields: []string{"Go generics", "Generics are finally in Go."}})
+	idx.Add(&Doc{Type: DocPost, ID: 2, OwnerID: 2, CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		Fields: []string{"Rust", "Nothing about the other language."}})
+	idx.Add(&Doc{Type: DocComment, ID: 1, OwnerID: 2, CreatedAt: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		Fields: []string{"I like go, but not generics"}})
+
+	tests := []struct {
+		name   string
+		terms  []string
+		filter Filter
+		want   []int // sort IDs.
+	}{
+		{
+			name:  "all terms must match, title weighs more",
+			terms: []string{"go", "generics"},
+			want:  []int{3, 2},
+		},
+		{
+			name:   "type filter",
+			terms:  []string{"go"},
+			filter: Filter{Types: []DocType{DocComment}},
+			want:   []int{2},
+		},
+		{
+			name:   "owner filter",
+			terms:  []string{"go"},
+			filter: Filter{OwnerID: 1},
+			want:   []int{3},
+		},
+		{
+			name:   "date filter",
+			terms:  []string{"go"},
+			filter: Filter{From: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)},
+			want:   []int{2},
+		},
+		{
+			name:  "no match",
+			terms: []string{"python"},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := idx.Search(tt.terms, tt.filter)
+			var got []int
+			for _, h := range hits {
+				got = append(got, h.Doc.SortID())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Search() got = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Search() got = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	idx.Remove(DocPost, 1)
+	if hits := idx.Search([]string{"generics"}, Filter{}); len(hits) != 1 || hits[0].Doc.Type != DocComment {
+		t.Errorf("Search() after Remove() got = %v, want only a comment", hits)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	got := Snippet("Use <b>Go</b> & be happy", []string{"go"})
+	want := "Use &lt;b&gt;<mark>Go</mark>&lt;/b&gt; &amp; be happy"
+	if got != want {
+		t.Errorf("Snippet() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+// snippetWords is a maximum amount of words in a snippet.
+const snippetWords = 20
+
+// Snippet returns an HTML-escaped fragment of a text around the first matched term,
+// with all matched terms wrapped in <mark> tags.
+func Snippet(text string, terms []string) string {
+	matched := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		matched[t] = true
+	}
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return html.EscapeString(text)
+	}
+
+	//window around the first match
+	first := 0
+	for i, t := range tokens {
+		if matched[t.term] {
+			first = i
+			break
+		}
+	}
+	from := first - snippetWords/4
+	if from < 0 {
+		from = 0
+	}
+	to := from + snippetWords
+	if to > len(tokens) {
+		to = len(tokens)
+	}
+
+	sb := strings.Builder{}
+	start, end := tokens[from].start, tokens[to-1].end
+	if from > 0 {
+		sb.WriteString("… ")
+	}
+	pos := start
+	for _, t := range tokens[from:to] {
+		if !matched[t.term] {
+			continue
+		}
+		sb.WriteString(html.EscapeString(text[pos:t.start]))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(text[t.start:t.end]))
+		sb.WriteString("</mark>")
+		pos = t.end
+	}
+	sb.WriteString(html.EscapeString(text[pos:end]))
+	if to < len(tokens) {
+		sb.WriteString(" …")
+	}
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// token is a normalized word and its byte offsets in an original text.
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits a text into lower-cased words of letters and digits.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWordRune && start < 0 {
+			start = i
+		}
+		if !isWordRune && start >= 0 {
+			tokens = append(tokens, token{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// Terms returns unique normalized words of a text in order of appearance.
+func Terms(text string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, t := range tokenize(text) {
+		if !seen[t.term] {
+			seen[t.term] = true
+			terms = append(terms, t.term)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,56 @@
+package validation
+
+import "unicode"
+
+const zeroWidthJoiner = '\u200d'
+
+// Length returns an amount of user-perceived characters (grapheme clusters) in a text, so a letter written
+// with a combining accent, a flag or a family emoji count as one character.
+// It approximates Unicode text segmentation by joining combining marks, variation selectors, emoji modifiers
+// and tags to a preceding character, characters joined by ZWJ, pairs of regional indicators and CRLF.
+// Invalid UTF-8 sequences count as one character per byte.
+func Length(text string) int {
+	n := 0
+	var prev rune = -1
+	regionalRun := 0 // regional indicators in a row before the current rune.
+	for _, r := range text {
+		if prev == -1 || !extendsCluster(prev, r, regionalRun) {
+			n++
+		}
+		if isRegionalIndicator(r) {
+			regionalRun++
+		} else {
+			regionalRun = 0
+		}
+		prev = r
+	}
+	return n
+}
+
+// extendsCluster returns true if r continues a grapheme cluster of prev.
+func extendsCluster(prev, r rune, regionalRun int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return true
+	case prev == zeroWidthJoiner && !unicode.IsControl(r):
+		return true
+	case r == zeroWidthJoiner:
+		return true
+	case unicode.Is(unicode.Mn, r), unicode.Is(unicode.Me, r), unicode.Is(unicode.Mc, r):
+		return true
+	case r >= 0xfe00 && r <= 0xfe0f, r >= 0xe0100 && r <= 0xe01ef: // variation selectors
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff: // emoji skin tone modifiers
+		return true
+	case r >= 0xe0020 && r <= 0xe007f: // tags of subdivision flags
+		return true
+	case isRegionalIndicator(r):
+		// flags are pairs of regional indicators
+		return regionalRun%2 == 1
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
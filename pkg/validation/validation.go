@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits restrict a text field. Zero limits are not checked.
+type Limits struct {
+	// MinLength and MaxLength are amounts of user-perceived characters (see Length).
+	MinLength int
+	MaxLength int
+	// MaxRunes is an amount of code points, e.g. a width of a database column.
+	MaxRunes int
+	// Multiline allows line breaks and tabs, other control characters are always rejected.
+	Multiline bool
+}
+
+// FieldError is a validation error of an input field.
+type FieldError struct {
+	// Path is a dot-separated path of the field, e.g. "title" or "input.tags.1".
+	Path    string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Errors are validation errors of an input, one per invalid field.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validator collects validation errors of an input. The zero value is ready to use.
+type Validator struct {
+	errs Errors
+}
+
+// Text checks a text field by limits.
+func (v *Validator) Text(path, text string, limits Limits) {
+	if msg := checkText(text, limits); msg != "" {
+		v.Add(path, msg)
+	}
+}
+
+// Add adds an error of a field checked by a caller.
+func (v *Validator) Add(path, message string) {
+	v.errs = append(v.errs, &FieldError{Path: path, Message: message})
+}
+
+// Err returns collected errors as Errors, nil if the input is valid.
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// checkText returns a description of the first violated limit, empty if the text is valid.
+func checkText(text string, limits Limits) string {
+	if !utf8.ValidString(text) {
+		return "is not a valid UTF-8 text"
+	}
+	for _, r := range text {
+		if unicode.IsControl(r) && !(limits.Multiline && (r == '\n' || r == '\r' || r == '\t')) {
+			return "contains control characters"
+		}
+	}
+	length := Length(text)
+	if length < limits.MinLength {
+		if limits.MinLength == 1 {
+			return "must not be empty"
+		}
+		return fmt.Sprintf("must be at least %d characters long", limits.MinLength)
+	}
+	if limits.MaxLength > 0 && length > limits.MaxLength {
+		return fmt.Sprintf("must be at most %d characters long", limits.MaxLength)
+	}
+	if limits.MaxRunes > 0 && utf8.RuneCountInString(text) > limits.MaxRunes {
+		return fmt.Sprintf("must be at most %d code points long", limits.MaxRunes)
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{name: "empty", text: "", want: 0},
+		{name: "latin", text: "Hello", want: 5},
+		{name: "cyrillic", text: "Привет", want: 6},
+		{name: "combining accents", text: "e\u0301te\u0301", want: 3},
+		{name: "emoji with skin tone", text: "👍🏽", want: 1},
+		{name: "family emoji", text: "\U0001f468\u200d\U0001f469\u200d\U0001f467", want: 1},
+		{name: "flags", text: "🇷🇺🇫🇷", want: 2},
+		{name: "odd regional indicator", text: "🇷🇺🇫", want: 2},
+		{name: "variation selector", text: "❤️", want: 1},
+		{name: "CRLF", text: "a\r\nb", want: 3},
+		{name: "CJK", text: "日本語", want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Length(tt.text); got != tt.want {
+				t.Errorf("Length(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidator_Text(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		limits  Limits
+		wantMsg string
+	}{
+		{name: "valid", text: "Hello", limits: Limits{MinLength: 1, MaxLength: 5}},
+		{name: "characters are counted, not bytes", text: "Привет", limits: Limits{MaxLength: 6}},
+		{name: "too long", text: "Привет", limits: Limits{MaxLength: 5}, wantMsg: "must be at most 5 characters long"},
+		{name: "empty", text: "", limits: Limits{MinLength: 1}, wantMsg: "must not be empty"},
+		{name: "too short", text: "ab", limits: Limits{MinLength: 3}, wantMsg: "must be at least 3 characters long"},
+		{name: "too many code points", text: "e\u0301e\u0301", limits: Limits{MaxLength: 2, MaxRunes: 3}, wantMsg: "must be at most 3 code points long"},
+		{name: "invalid UTF-8", text: "ab\xff", limits: Limits{}, wantMsg: "is not a valid UTF-8 text"},
+		{name: "control character", text: "a\x00b", limits: Limits{Multiline: true}, wantMsg: "contains control characters"},
+		{name: "line break in a single line text", text: "a\nb", limits: Limits{}, wantMsg: "contains control characters"},
+		{name: "line break in a multiline text", text: "a\r\n\tb", limits: Limits{Multiline: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v Validator
+			v.Text("text", tt.text, tt.limits)
+			err := v.Err()
+			if tt.wantMsg == "" {
+				if err != nil {
+					t.Errorf("Err() = %v, want nil", err)
+				}
+				return
+			}
+			var errs Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Err() = %v, want Errors", err)
+			}
+			want := Errors{{Path: "text", Message: tt.wantMsg}}
+			if !reflect.DeepEqual(errs, want) {
+				t.Errorf("Err() = %v, want %v", errs, want)
+			}
+		})
+	}
+}
+
+func TestValidator_Err(t *testing.T) {
+	var v Validator
+	v.Text("title", "", Limits{MinLength: 1})
+	v.Text("text", "ok", Limits{MaxLength: 10})
+	v.Add("tags.1", "is too long")
+
+	err := v.Err()
+	want := Errors{
+		{Path: "title", Message: "must not be empty"},
+		{Path: "tags.1", Message: "is too long"},
+	}
+	if !reflect.DeepEqual(err, want) {
+		t.Fatalf("Err() = %v, want %v", err, want)
+	}
+	if err.Error() != "title: must not be empty; tags.1: is too long" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}